@@ -6,8 +6,11 @@ package main
 const (
 	envDatabaseName          = "DATABASE_NAME"
 	envDatabaseAccountName   = "DATABASE_ACCOUNT_NAME"
+	envCosmosDBEmulator      = "COSMOSDB_EMULATOR_ADDRESS"
 	envKeyVaultPrefix        = "KEYVAULT_PREFIX"
 	envDBTokenUrl            = "DBTOKEN_URL"
 	envOpenShiftVersions     = "OPENSHIFT_VERSIONS"
 	envInstallerImageDigests = "INSTALLER_IMAGE_DIGESTS"
+
+	envPortalSSHRecordingsStorageAccount = "PORTAL_SSH_RECORDINGS_STORAGE_ACCOUNT"
 )
@@ -0,0 +1,142 @@
+package main
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/database/emulator"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/metrics"
+	dbmetrics "github.com/Azure/ARO-RP/pkg/metrics/statsd/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient/azuresdk/azcore"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	utiltls "github.com/Azure/ARO-RP/pkg/util/tls"
+)
+
+// cosmosDBEmulatorAddress returns the address of the Cosmos DB emulator to use
+// instead of a database account.  The emulator is only used in development.
+func cosmosDBEmulatorAddress() string {
+	if !env.IsLocalDevelopmentMode() {
+		return ""
+	}
+	return os.Getenv(envCosmosDBEmulator)
+}
+
+// newMasterKeyAuthorizer returns an authorizer for the database account, or
+// for the Cosmos DB emulator if one is in use
+func newMasterKeyAuthorizer(ctx context.Context, log *logrus.Entry, token azcore.TokenCredential, clientOptions *policy.ClientOptions, subscriptionID, resourceGroup, databaseAccountName string) (cosmosdb.Authorizer, error) {
+	if cosmosDBEmulatorAddress() != "" {
+		return cosmosdb.NewMasterKeyAuthorizer(emulator.MasterKey)
+	}
+
+	return database.NewMasterKeyAuthorizer(ctx, log, token, clientOptions, subscriptionID, resourceGroup, databaseAccountName)
+}
+
+// newDatabaseClient returns a client for the database account, or for the
+// Cosmos DB emulator if one is in use
+func newDatabaseClient(log *logrus.Entry, _env env.Core, authorizer cosmosdb.Authorizer, m metrics.Emitter, aead encryption.AEAD, databaseAccountName string) (cosmosdb.DatabaseClient, error) {
+	address := cosmosDBEmulatorAddress()
+	if address == "" {
+		return database.NewDatabaseClient(log, _env, authorizer, m, aead, databaseAccountName)
+	}
+
+	log.Warnf("using the Cosmos DB emulator at %s", address)
+
+	h, err := database.NewJSONHandle(aead)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &http.Client{
+		Transport: dbmetrics.New(log, &http.Transport{
+			// disable HTTP/2 for now: https://github.com/golang/go/issues/36026
+			TLSNextProto:        map[string]func(string, *tls.Conn) http.RoundTripper{},
+			MaxIdleConnsPerHost: 20,
+			TLSClientConfig: &tls.Config{
+				// the emulator serves a self-signed certificate
+				InsecureSkipVerify: true, // #nosec G402
+			},
+		}, m),
+		Timeout: 30 * time.Second,
+	}
+
+	return cosmosdb.NewDatabaseClient(log, c, h, address, authorizer), nil
+}
+
+// cosmosDBEmulator serves an in-memory Cosmos DB emulator holding the
+// development database.  Run the RP, monitor and other components with
+// COSMOSDB_EMULATOR_ADDRESS set to the same address to use it instead of a
+// database account.  Its contents are lost when it exits.
+func cosmosDBEmulator(ctx context.Context, log *logrus.Entry) error {
+	if !env.IsLocalDevelopmentMode() {
+		return fmt.Errorf("the Cosmos DB emulator is only supported in development mode")
+	}
+
+	dbName, err := DBName(true)
+	if err != nil {
+		return err
+	}
+
+	address := os.Getenv(envCosmosDBEmulator)
+	if address == "" {
+		address = "localhost:8081"
+	}
+
+	key, certs, err := utiltls.GenerateKeyAndCertificate("localhost", nil, nil, false, false)
+	if err != nil {
+		return err
+	}
+
+	e := emulator.New(log.WithField("component", "cosmosdb-emulator"))
+
+	err = e.ProvisionDevelopmentDatabase(dbName)
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	l = tls.NewListener(l, &tls.Config{
+		Certificates: []tls.Certificate{
+			{
+				Certificate: [][]byte{certs[0].Raw},
+				PrivateKey:  key,
+			},
+		},
+		MinVersion: tls.VersionTLS12,
+	})
+
+	log.Printf("serving database %s on %s", dbName, address)
+
+	s := &http.Server{
+		Handler:           e,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.Close()
+	}()
+
+	err = s.Serve(l)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package main
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/dbsnapshot"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	"github.com/Azure/ARO-RP/pkg/util/keyvault"
+)
+
+const envTargetKeyVaultPrefix = "TARGET_KEYVAULT_PREFIX"
+
+// db exports the RP's Cosmos DB collections to a snapshot file, or imports
+// them from one.  `db export file` re-seals encrypted fields with the keys of
+// the service key vault named by TARGET_KEYVAULT_PREFIX, if it is set.  `db
+// import file overwrite` replaces documents which already exist with
+// different contents.  A file of "-" is stdout or stdin.
+func db(ctx context.Context, log *logrus.Entry) error {
+	op, file := strings.ToLower(flag.Arg(1)), flag.Arg(2)
+	overwrite := strings.EqualFold(flag.Arg(3), "overwrite")
+
+	switch {
+	case op == "export" && len(flag.Args()) == 3,
+		op == "import" && len(flag.Args()) == 3,
+		op == "import" && len(flag.Args()) == 4 && overwrite:
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	_env, err := env.NewCore(ctx, log, env.COMPONENT_TOOLING)
+	if err != nil {
+		return err
+	}
+
+	if err = env.ValidateVars(envKeyVaultPrefix, envDatabaseAccountName); err != nil {
+		return err
+	}
+
+	msiToken, err := _env.NewMSITokenCredential()
+	if err != nil {
+		return err
+	}
+
+	var source, target encryption.AEAD
+	if targetKeyVaultPrefix := os.Getenv(envTargetKeyVaultPrefix); op == "export" && targetKeyVaultPrefix != "" {
+		msiKVAuthorizer, err := _env.NewMSIAuthorizer(_env.Environment().KeyVaultScope)
+		if err != nil {
+			return err
+		}
+
+		serviceKeyvault := keyvault.NewManager(msiKVAuthorizer, keyvault.URI(_env, env.ServiceKeyvaultSuffix, os.Getenv(envKeyVaultPrefix)))
+		source, err = encryption.NewMulti(ctx, serviceKeyvault, env.EncryptionSecretV2Name, env.EncryptionSecretName)
+		if err != nil {
+			return err
+		}
+
+		targetKeyvault := keyvault.NewManager(msiKVAuthorizer, keyvault.URI(_env, env.ServiceKeyvaultSuffix, targetKeyVaultPrefix))
+		target, err = encryption.NewMulti(ctx, targetKeyvault, env.EncryptionSecretV2Name, env.EncryptionSecretName)
+		if err != nil {
+			return err
+		}
+	}
+
+	dbAccountName := os.Getenv(envDatabaseAccountName)
+	clientOptions := &policy.ClientOptions{
+		ClientOptions: _env.Environment().ManagedIdentityCredentialOptions().ClientOptions,
+	}
+	dbAuthorizer, err := newMasterKeyAuthorizer(ctx, log.WithField("component", "database"), msiToken, clientOptions, _env.SubscriptionID(), _env.ResourceGroup(), dbAccountName)
+	if err != nil {
+		return err
+	}
+
+	// the documents are read and written with their encrypted fields sealed
+	dbc, err := newDatabaseClient(log.WithField("component", "database"), _env, dbAuthorizer, &noop.Noop{}, nil, dbAccountName)
+	if err != nil {
+		return err
+	}
+
+	dbName, err := DBName(_env.IsLocalDevelopmentMode())
+	if err != nil {
+		return err
+	}
+
+	s, err := dbsnapshot.NewSnapshotter(log.WithField("component", "dbsnapshot"), dbc, dbName, source, target)
+	if err != nil {
+		return err
+	}
+
+	if op == "import" {
+		var r io.Reader = os.Stdin
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+
+		return s.Import(ctx, bufio.NewReader(r), overwrite)
+	}
+
+	if file == "-" {
+		return export(ctx, s, os.Stdout)
+	}
+
+	// the snapshot is not overwritten, and is readable only by its owner
+	// because it may have been re-keyed for a development environment
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = export(ctx, s, f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func export(ctx context.Context, s dbsnapshot.Snapshotter, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	err := s.Export(ctx, bw)
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
@@ -20,12 +20,16 @@ import (
 
 func usage() {
 	fmt.Fprint(flag.CommandLine.Output(), "usage:\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s cosmosdb-emulator\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s db export file\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s db import file [overwrite]\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s dbtoken\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s deploy config.yaml location\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s gateway\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s mirror [release_image...]\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s monitor\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s portal\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s reencrypt [check]\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s rp\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s operator {master,worker}\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s update-versions\n", os.Args[0])
@@ -48,6 +52,12 @@ func main() {
 
 	var err error
 	switch strings.ToLower(flag.Arg(0)) {
+	case "cosmosdb-emulator":
+		checkArgs(1)
+		err = cosmosDBEmulator(ctx, log)
+	case "db":
+		checkMinArgs(3)
+		err = db(ctx, log)
 	case "dbtoken":
 		checkArgs(1)
 		err = dbtoken(ctx, log)
@@ -72,6 +82,9 @@ func main() {
 	case "operator":
 		checkArgs(2)
 		err = operator(ctx, log)
+	case "reencrypt":
+		checkMinArgs(1)
+		err = reencrypt(ctx, log)
 	case "update-versions":
 		checkArgs(1)
 		err = updateOCPVersions(ctx, log)
@@ -18,10 +18,12 @@ import (
 	"github.com/Azure/ARO-RP/pkg/metrics/statsd"
 	"github.com/Azure/ARO-RP/pkg/metrics/statsd/golang"
 	pkgportal "github.com/Azure/ARO-RP/pkg/portal"
+	"github.com/Azure/ARO-RP/pkg/portal/ssh"
 	"github.com/Azure/ARO-RP/pkg/proxy"
 	"github.com/Azure/ARO-RP/pkg/util/encryption"
 	"github.com/Azure/ARO-RP/pkg/util/keyvault"
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
+	"github.com/Azure/ARO-RP/pkg/util/storage"
 	"github.com/Azure/ARO-RP/pkg/util/uuid"
 )
 
@@ -35,7 +37,8 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 		err := env.ValidateVars(
 			"MDM_ACCOUNT",
 			"MDM_NAMESPACE",
-			"PORTAL_HOSTNAME")
+			"PORTAL_HOSTNAME",
+			envPortalSSHRecordingsStorageAccount)
 
 		if err != nil {
 			return err
@@ -102,12 +105,12 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 		ClientOptions: _env.Environment().ManagedIdentityCredentialOptions().ClientOptions,
 	}
 	logrusEntry := log.WithField("component", "database")
-	dbAuthorizer, err := database.NewMasterKeyAuthorizer(ctx, logrusEntry, msiToken, clientOptions, _env.SubscriptionID(), _env.ResourceGroup(), dbAccountName)
+	dbAuthorizer, err := newMasterKeyAuthorizer(ctx, logrusEntry, msiToken, clientOptions, _env.SubscriptionID(), _env.ResourceGroup(), dbAccountName)
 	if err != nil {
 		return err
 	}
 
-	dbc, err := database.NewDatabaseClient(log.WithField("component", "database"), _env, dbAuthorizer, m, aead, dbAccountName)
+	dbc, err := newDatabaseClient(log.WithField("component", "database"), _env, dbAuthorizer, m, aead, dbAccountName)
 	if err != nil {
 		return err
 	}
@@ -126,6 +129,21 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 		return err
 	}
 
+	dbClusterAlerts, err := database.NewClusterAlerts(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	dbAdminAuditLog, err := database.NewAdminAuditLog(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	dbPendingActions, err := database.NewPendingActions(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
 	portalKeyvaultURI := keyvault.URI(_env, env.PortalKeyvaultSuffix, keyVaultPrefix)
 	portalKeyvault := keyvault.NewManager(msiKVAuthorizer, portalKeyvaultURI)
 
@@ -159,6 +177,20 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 		return err
 	}
 
+	// SSH sessions are recorded to a container in the given storage account
+	// in the RP resource group.  The account is required outside development.
+	var sshRecordingStore ssh.RecordingStore
+	if account := os.Getenv(envPortalSSHRecordingsStorageAccount); account != "" {
+		msiAuthorizer, err := _env.NewMSIAuthorizer(_env.Environment().ResourceManagerScope)
+		if err != nil {
+			return err
+		}
+
+		sshRecordingStore = ssh.NewRecordingStore(storage.NewManager(_env, _env.SubscriptionID(), msiAuthorizer), _env.ResourceGroup(), account)
+	} else {
+		log.Warnf("%s is not set, SSH sessions will not be recorded", envPortalSSHRecordingsStorageAccount)
+	}
+
 	clientID := os.Getenv("AZURE_PORTAL_CLIENT_ID")
 	verifier, err := oidc.NewVerifier(ctx, _env.Environment().ActiveDirectoryEndpoint+_env.TenantID()+"/v2.0", clientID)
 	if err != nil {
@@ -194,7 +226,7 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 
 	log.Printf("listening %s", address)
 
-	p := pkgportal.NewPortal(_env, audit, log.WithField("component", "portal"), log.WithField("component", "portal-access"), l, sshl, verifier, hostname, servingKey, servingCerts, clientID, clientKey, clientCerts, sessionKey, sshKey, groupIDs, elevatedGroupIDs, dbOpenShiftClusters, dbPortal, dialer, m)
+	p := pkgportal.NewPortal(_env, audit, log.WithField("component", "portal"), log.WithField("component", "portal-access"), l, sshl, verifier, hostname, servingKey, servingCerts, clientID, clientKey, clientCerts, sessionKey, sshKey, groupIDs, elevatedGroupIDs, dbOpenShiftClusters, dbPortal, dbClusterAlerts, dbAdminAuditLog, dbPendingActions, dialer, aead, sshRecordingStore, m)
 
 	return p.Run(ctx)
 }
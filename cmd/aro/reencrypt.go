@@ -0,0 +1,117 @@
+package main
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/metrics/statsd"
+	pkgreencrypt "github.com/Azure/ARO-RP/pkg/reencrypt"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	"github.com/Azure/ARO-RP/pkg/util/keyvault"
+)
+
+// reencrypt re-seals the encrypted fields of every OpenShiftCluster, Portal
+// and Gateway document with the primary encryption key.  `reencrypt check`
+// instead fails if any document still depends on another key, such as the
+// legacy secret.
+func reencrypt(ctx context.Context, log *logrus.Entry) error {
+	check := strings.EqualFold(flag.Arg(1), "check")
+	if len(flag.Args()) > 2 || (len(flag.Args()) == 2 && !check) {
+		usage()
+		os.Exit(2)
+	}
+
+	_env, err := env.NewCore(ctx, log, env.COMPONENT_REENCRYPT)
+	if err != nil {
+		return err
+	}
+
+	if !_env.IsLocalDevelopmentMode() {
+		if err = env.ValidateVars("MDM_ACCOUNT", "MDM_NAMESPACE"); err != nil {
+			return err
+		}
+	}
+
+	if err = env.ValidateVars(envKeyVaultPrefix, envDatabaseAccountName); err != nil {
+		return err
+	}
+
+	msiToken, err := _env.NewMSITokenCredential()
+	if err != nil {
+		return err
+	}
+
+	msiKVAuthorizer, err := _env.NewMSIAuthorizer(_env.Environment().KeyVaultScope)
+	if err != nil {
+		return err
+	}
+
+	m := statsd.New(ctx, log.WithField("component", "reencrypt"), _env, os.Getenv("MDM_ACCOUNT"), os.Getenv("MDM_NAMESPACE"), os.Getenv("MDM_STATSD_SOCKET"))
+
+	keyVaultPrefix := os.Getenv(envKeyVaultPrefix)
+	serviceKeyvaultURI := keyvault.URI(_env, env.ServiceKeyvaultSuffix, keyVaultPrefix)
+	serviceKeyvault := keyvault.NewManager(msiKVAuthorizer, serviceKeyvaultURI)
+
+	aead, err := encryption.NewMulti(ctx, serviceKeyvault, env.EncryptionSecretV2Name, env.EncryptionSecretName)
+	if err != nil {
+		return err
+	}
+
+	dbAccountName := os.Getenv(envDatabaseAccountName)
+	clientOptions := &policy.ClientOptions{
+		ClientOptions: _env.Environment().ManagedIdentityCredentialOptions().ClientOptions,
+	}
+	dbAuthorizer, err := newMasterKeyAuthorizer(ctx, log.WithField("component", "database"), msiToken, clientOptions, _env.SubscriptionID(), _env.ResourceGroup(), dbAccountName)
+	if err != nil {
+		return err
+	}
+
+	// the documents are read and written with their encrypted fields sealed;
+	// the reencrypter opens and re-seals them itself
+	dbc, err := newDatabaseClient(log.WithField("component", "database"), _env, dbAuthorizer, m, nil, dbAccountName)
+	if err != nil {
+		return err
+	}
+
+	dbName, err := DBName(_env.IsLocalDevelopmentMode())
+	if err != nil {
+		return err
+	}
+
+	dbKeyRotation, err := database.NewKeyRotation(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	r, err := pkgreencrypt.NewReencrypter(log.WithField("component", "reencrypt"), m, dbc, dbName, dbKeyRotation, aead)
+	if err != nil {
+		return err
+	}
+
+	if !check {
+		return r.Run(ctx)
+	}
+
+	stale, err := r.Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	if stale > 0 {
+		return fmt.Errorf("%d documents still depend on a key other than the primary key", stale)
+	}
+
+	log.Print("no documents depend on a key other than the primary key")
+	return nil
+}
@@ -39,6 +39,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/metrics/statsd/k8s"
 	"github.com/Azure/ARO-RP/pkg/util/clusterdata"
 	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	"github.com/Azure/ARO-RP/pkg/util/recover"
 )
 
 func rp(ctx context.Context, log, audit *logrus.Entry) error {
@@ -114,12 +115,12 @@ func rp(ctx context.Context, log, audit *logrus.Entry) error {
 		ClientOptions: _env.Environment().ManagedIdentityCredentialOptions().ClientOptions,
 	}
 	logrusEntry := log.WithField("component", "database")
-	dbAuthorizer, err := database.NewMasterKeyAuthorizer(ctx, logrusEntry, msiToken, clientOptions, _env.SubscriptionID(), _env.ResourceGroup(), dbAccountName)
+	dbAuthorizer, err := newMasterKeyAuthorizer(ctx, logrusEntry, msiToken, clientOptions, _env.SubscriptionID(), _env.ResourceGroup(), dbAccountName)
 	if err != nil {
 		return err
 	}
 
-	dbc, err := database.NewDatabaseClient(log.WithField("component", "database"), _env, dbAuthorizer, metrics, aead, dbAccountName)
+	dbc, err := newDatabaseClient(log.WithField("component", "database"), _env, dbAuthorizer, metrics, aead, dbAccountName)
 	if err != nil {
 		return err
 	}
@@ -163,6 +164,21 @@ func rp(ctx context.Context, log, audit *logrus.Entry) error {
 		return err
 	}
 
+	dbClusterAlerts, err := database.NewClusterAlerts(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	dbPendingActions, err := database.NewPendingActions(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	dbAdminAuditLog, err := database.NewAdminAuditLog(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
 	go database.EmitMetrics(ctx, log, dbOpenShiftClusters, metrics)
 
 	feAead, err := encryption.NewMulti(ctx, _env.ServiceKeyvault(), env.FrontendEncryptionSecretV2Name, env.FrontendEncryptionSecretName)
@@ -173,7 +189,7 @@ func rp(ctx context.Context, log, audit *logrus.Entry) error {
 	if err != nil {
 		return err
 	}
-	f, err := frontend.NewFrontend(ctx, audit, log.WithField("component", "frontend"), _env, dbAsyncOperations, dbClusterManagerConfiguration, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, api.APIs, metrics, clusterm, feAead, hiveClusterManager, adminactions.NewKubeActions, adminactions.NewAzureActions, clusterdata.NewParallelEnricher(metrics, _env))
+	f, err := frontend.NewFrontend(ctx, audit, log.WithField("component", "frontend"), _env, dbAsyncOperations, dbClusterManagerConfiguration, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, dbClusterAlerts, dbPendingActions, dbAdminAuditLog, api.APIs, metrics, clusterm, feAead, hiveClusterManager, adminactions.NewKubeActions, adminactions.NewAzureActions, clusterdata.NewParallelEnricher(metrics, _env))
 	if err != nil {
 		return err
 	}
@@ -193,6 +209,20 @@ func rp(ctx context.Context, log, audit *logrus.Entry) error {
 	doneB := make(chan struct{})
 	signal.Notify(sigterm, syscall.SIGTERM)
 
+	// During an RP upgrade, sigusr1 is sent before sigterm.  It drains the
+	// backend, which hands its work over to the new RP instances, and
+	// "/healthz/drained" reports when it is safe to stop.
+	sigusr1 := make(chan os.Signal, 1)
+	signal.Notify(sigusr1, syscall.SIGUSR1)
+	go func() {
+		defer recover.Panic(log)
+
+		<-sigusr1
+		log.Print("received SIGUSR1")
+		b.Drain()
+		f.Drain(b.Drained)
+	}()
+
 	log.Print("listening")
 	go b.Run(ctx, stop, doneB)
 	go f.Run(ctx, stop, doneF)
@@ -48,16 +48,20 @@ type OpenShiftClusterProperties struct {
 	// WorkerProfiles is used to store the worker profile data that was sent in the api request
 	WorkerProfiles []WorkerProfile `json:"workerProfiles,omitempty"`
 	// WorkerProfilesStatus is used to store the enriched worker profile data
-	WorkerProfilesStatus            []WorkerProfile   `json:"workerProfilesStatus,omitempty" swagger:"readOnly"`
-	APIServerProfile                APIServerProfile  `json:"apiserverProfile,omitempty"`
-	IngressProfiles                 []IngressProfile  `json:"ingressProfiles,omitempty"`
-	Install                         *Install          `json:"install,omitempty"`
-	StorageSuffix                   string            `json:"storageSuffix,omitempty"`
-	RegistryProfiles                []RegistryProfile `json:"registryProfiles,omitempty"`
-	ImageRegistryStorageAccountName string            `json:"imageRegistryStorageAccountName,omitempty"`
-	InfraID                         string            `json:"infraId,omitempty"`
-	HiveProfile                     HiveProfile       `json:"hiveProfile,omitempty"`
-	MaintenanceState                MaintenanceState  `json:"maintenanceState,omitempty"`
+	WorkerProfilesStatus            []WorkerProfile    `json:"workerProfilesStatus,omitempty" swagger:"readOnly"`
+	InfraProfile                    *InfraProfile      `json:"infraProfile,omitempty" mutable:"true"`
+	DeletionProtection              DeletionProtection `json:"deletionProtection,omitempty" mutable:"true"`
+	SoftDeleteProfile               *SoftDeleteProfile `json:"softDeleteProfile,omitempty" mutable:"true"`
+	PendingDeletion                 *PendingDeletion   `json:"pendingDeletion,omitempty" swagger:"readOnly"`
+	APIServerProfile                APIServerProfile   `json:"apiserverProfile,omitempty"`
+	IngressProfiles                 []IngressProfile   `json:"ingressProfiles,omitempty"`
+	Install                         *Install           `json:"install,omitempty"`
+	StorageSuffix                   string             `json:"storageSuffix,omitempty"`
+	RegistryProfiles                []RegistryProfile  `json:"registryProfiles,omitempty"`
+	ImageRegistryStorageAccountName string             `json:"imageRegistryStorageAccountName,omitempty"`
+	InfraID                         string             `json:"infraId,omitempty"`
+	HiveProfile                     HiveProfile        `json:"hiveProfile,omitempty"`
+	MaintenanceState                MaintenanceState   `json:"maintenanceState,omitempty"`
 }
 
 // ProvisioningState represents a provisioning state.
@@ -385,6 +389,36 @@ type WorkerProfile struct {
 	DiskEncryptionSetID string           `json:"diskEncryptionSetId,omitempty"`
 }
 
+// InfraProfile represents the dedicated infrastructure nodes which run the
+// router, the image registry and the monitoring stack.
+type InfraProfile struct {
+	VMSize     VMSize `json:"vmSize,omitempty"`
+	DiskSizeGB int    `json:"diskSizeGB,omitempty"`
+	Count      int    `json:"count,omitempty"`
+}
+
+// DeletionProtection determines if deletes of the cluster are rejected.
+type DeletionProtection string
+
+// DeletionProtection constants.
+const (
+	DeletionProtectionEnabled  DeletionProtection = "Enabled"
+	DeletionProtectionDisabled DeletionProtection = "Disabled"
+)
+
+// SoftDeleteProfile represents how long a deleted cluster is held before its
+// infrastructure is removed.
+type SoftDeleteProfile struct {
+	GracePeriodHours int `json:"gracePeriodHours,omitempty"`
+}
+
+// PendingDeletion represents a deleted cluster held for its soft delete grace
+// period.
+type PendingDeletion struct {
+	RequestTime int `json:"requestTime,omitempty"`
+	DeleteAfter int `json:"deleteAfter,omitempty"`
+}
+
 // APIServerProfile represents an API server profile.
 type APIServerProfile struct {
 	Visibility Visibility `json:"visibility,omitempty"`
@@ -147,6 +147,29 @@ func (c openShiftClusterConverter) ToExternal(oc *api.OpenShiftCluster) interfac
 		}
 	}
 
+	if oc.Properties.InfraProfile != nil {
+		out.Properties.InfraProfile = &InfraProfile{
+			VMSize:     VMSize(oc.Properties.InfraProfile.VMSize),
+			DiskSizeGB: oc.Properties.InfraProfile.DiskSizeGB,
+			Count:      oc.Properties.InfraProfile.Count,
+		}
+	}
+
+	out.Properties.DeletionProtection = DeletionProtection(oc.Properties.DeletionProtection)
+
+	if oc.Properties.SoftDeleteProfile != nil {
+		out.Properties.SoftDeleteProfile = &SoftDeleteProfile{
+			GracePeriodHours: oc.Properties.SoftDeleteProfile.GracePeriodHours,
+		}
+	}
+
+	if oc.Properties.PendingDeletion != nil {
+		out.Properties.PendingDeletion = &PendingDeletion{
+			RequestTime: oc.Properties.PendingDeletion.RequestTime,
+			DeleteAfter: oc.Properties.PendingDeletion.DeleteAfter,
+		}
+	}
+
 	if oc.Properties.IngressProfiles != nil {
 		out.Properties.IngressProfiles = make([]IngressProfile, 0, len(oc.Properties.IngressProfiles))
 		for _, p := range oc.Properties.IngressProfiles {
@@ -324,6 +347,21 @@ func (c openShiftClusterConverter) ToInternal(_oc interface{}, out *api.OpenShif
 			out.Properties.WorkerProfilesStatus[i].DiskEncryptionSetID = oc.Properties.WorkerProfilesStatus[i].DiskEncryptionSetID
 		}
 	}
+	out.Properties.InfraProfile = nil
+	if oc.Properties.InfraProfile != nil {
+		out.Properties.InfraProfile = &api.InfraProfile{
+			VMSize:     api.VMSize(oc.Properties.InfraProfile.VMSize),
+			DiskSizeGB: oc.Properties.InfraProfile.DiskSizeGB,
+			Count:      oc.Properties.InfraProfile.Count,
+		}
+	}
+	out.Properties.DeletionProtection = api.DeletionProtection(oc.Properties.DeletionProtection)
+	out.Properties.SoftDeleteProfile = nil
+	if oc.Properties.SoftDeleteProfile != nil {
+		out.Properties.SoftDeleteProfile = &api.SoftDeleteProfile{
+			GracePeriodHours: oc.Properties.SoftDeleteProfile.GracePeriodHours,
+		}
+	}
 	out.Properties.APIServerProfile.Visibility = api.Visibility(oc.Properties.APIServerProfile.Visibility)
 	out.Properties.APIServerProfile.URL = oc.Properties.APIServerProfile.URL
 	out.Properties.APIServerProfile.IP = oc.Properties.APIServerProfile.IP
@@ -356,6 +394,7 @@ func (c openShiftClusterConverter) ToInternal(_oc interface{}, out *api.OpenShif
 func (c openShiftClusterConverter) ExternalNoReadOnly(_oc interface{}) {
 	oc := _oc.(*OpenShiftCluster)
 	oc.Properties.WorkerProfilesStatus = nil
+	oc.Properties.PendingDeletion = nil
 	if oc.Properties.NetworkProfile.LoadBalancerProfile != nil {
 		oc.Properties.NetworkProfile.LoadBalancerProfile.EffectiveOutboundIPs = nil
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/api/util/immutable"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
 )
 
 type openShiftClusterStaticValidator struct{}
@@ -19,17 +20,32 @@ func (sv openShiftClusterStaticValidator) Static(_oc interface{}, _current *api.
 	}
 
 	oc := _oc.(*OpenShiftCluster)
-	return sv.validateDelta(oc, (&openShiftClusterConverter{}).ToExternal(_current).(*OpenShiftCluster))
+	return sv.validateDelta(oc, (&openShiftClusterConverter{}).ToExternal(_current).(*OpenShiftCluster), requireD2sV3Workers)
 }
 
-func (sv openShiftClusterStaticValidator) validateDelta(oc, current *OpenShiftCluster) error {
+func (sv openShiftClusterStaticValidator) validateDelta(oc, current *OpenShiftCluster, requireD2sV3Workers bool) error {
 	err := immutable.Validate("", oc, current)
 	if err != nil {
 		err := err.(*immutable.ValidationError)
 		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodePropertyChangeNotAllowed, err.Target, err.Message)
 	}
 
-	return validateMaintenanceTask(oc.Properties.MaintenanceTask)
+	err = validateMaintenanceTask(oc.Properties.MaintenanceTask)
+	if err != nil {
+		return err
+	}
+
+	err = validateInfraProfile("properties.infraProfile", oc.Properties.InfraProfile, requireD2sV3Workers)
+	if err != nil {
+		return err
+	}
+
+	err = validateDeletionProtection("properties.deletionProtection", oc.Properties.DeletionProtection)
+	if err != nil {
+		return err
+	}
+
+	return validateSoftDeleteProfile("properties.softDeleteProfile", oc.Properties.SoftDeleteProfile)
 }
 
 func validateMaintenanceTask(task MaintenanceTask) error {
@@ -45,3 +61,50 @@ func validateMaintenanceTask(task MaintenanceTask) error {
 
 	return nil
 }
+
+// minInfraCount is the number of infra nodes needed to keep two router and
+// image registry replicas on separate nodes
+const minInfraCount = 2
+
+func validateInfraProfile(path string, ip *InfraProfile, requireD2sV3Workers bool) error {
+	if ip == nil {
+		return nil
+	}
+
+	if !validate.VMSizeIsValid(api.VMSize(ip.VMSize), requireD2sV3Workers, false) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".vmSize", "The provided infra VM size '%s' is invalid.", ip.VMSize)
+	}
+	if !validate.DiskSizeIsValid(ip.DiskSizeGB) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".diskSizeGB", "The provided infra disk size '%d' is invalid.", ip.DiskSizeGB)
+	}
+	if ip.Count < minInfraCount {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".count", "The provided infra count '%d' is invalid: at least %d infra nodes are required.", ip.Count, minInfraCount)
+	}
+
+	return nil
+}
+
+func validateDeletionProtection(path string, dp DeletionProtection) error {
+	switch dp {
+	case "", DeletionProtectionEnabled, DeletionProtectionDisabled:
+		return nil
+	}
+
+	return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path, "The provided value '%s' is invalid.", dp)
+}
+
+// maxSoftDeleteGracePeriodHours bounds how long the infrastructure of a
+// deleted cluster, which the customer is no longer using, is kept
+const maxSoftDeleteGracePeriodHours = 7 * 24
+
+func validateSoftDeleteProfile(path string, sdp *SoftDeleteProfile) error {
+	if sdp == nil {
+		return nil
+	}
+
+	if sdp.GracePeriodHours < 1 || sdp.GracePeriodHours > maxSoftDeleteGracePeriodHours {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".gracePeriodHours", "The provided grace period '%d' is invalid: it must be between 1 and %d hours.", sdp.GracePeriodHours, maxSoftDeleteGracePeriodHours)
+	}
+
+	return nil
+}
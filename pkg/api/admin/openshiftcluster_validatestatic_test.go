@@ -715,6 +715,136 @@ func TestOpenShiftClusterStaticValidateDelta(t *testing.T) {
 				oc.Properties.MaintenanceTask = ""
 			},
 		},
+		{
+			name: "infraProfile can be added",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.InfraProfile = &InfraProfile{
+					VMSize:     VMSize("Standard_D2s_v3"),
+					DiskSizeGB: 128,
+					Count:      3,
+				}
+			},
+		},
+		{
+			name: "infraProfile can be removed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{
+					Properties: OpenShiftClusterProperties{
+						InfraProfile: &InfraProfile{
+							VMSize:     VMSize("Standard_D2s_v3"),
+							DiskSizeGB: 128,
+							Count:      3,
+						},
+					},
+				}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.InfraProfile = nil
+			},
+		},
+		{
+			name: "infraProfile with an unsupported vmSize is disallowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.InfraProfile = &InfraProfile{
+					VMSize:     VMSize("Standard_D1_v2"),
+					DiskSizeGB: 128,
+					Count:      3,
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.infraProfile.vmSize: The provided infra VM size 'Standard_D1_v2' is invalid.",
+		},
+		{
+			name: "infraProfile with a small disk is disallowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.InfraProfile = &InfraProfile{
+					VMSize:     VMSize("Standard_D2s_v3"),
+					DiskSizeGB: 64,
+					Count:      3,
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.infraProfile.diskSizeGB: The provided infra disk size '64' is invalid.",
+		},
+		{
+			name: "infraProfile with a single node is disallowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.InfraProfile = &InfraProfile{
+					VMSize:     VMSize("Standard_D2s_v3"),
+					DiskSizeGB: 128,
+					Count:      1,
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.infraProfile.count: The provided infra count '1' is invalid: at least 2 infra nodes are required.",
+		},
+		{
+			name: "deletionProtection can be enabled",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.DeletionProtection = DeletionProtectionEnabled
+			},
+		},
+		{
+			name: "deletionProtection with an invalid value is disallowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.DeletionProtection = "On"
+			},
+			wantErr: "400: InvalidParameter: properties.deletionProtection: The provided value 'On' is invalid.",
+		},
+		{
+			name: "softDeleteProfile can be added",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.SoftDeleteProfile = &SoftDeleteProfile{
+					GracePeriodHours: 24,
+				}
+			},
+		},
+		{
+			name: "softDeleteProfile with a grace period over a week is disallowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.SoftDeleteProfile = &SoftDeleteProfile{
+					GracePeriodHours: 169,
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.softDeleteProfile.gracePeriodHours: The provided grace period '169' is invalid: it must be between 1 and 168 hours.",
+		},
+		{
+			name: "pendingDeletion is ignored",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{
+					Properties: OpenShiftClusterProperties{
+						PendingDeletion: &PendingDeletion{
+							RequestTime: 1,
+							DeleteAfter: 2,
+						},
+					},
+				}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.PendingDeletion = nil
+			},
+		},
 		{
 			name: "maintenanceTask change to other values is disallowed",
 			oc: func() *OpenShiftCluster {
@@ -3,6 +3,10 @@ package admin
 // Copyright (c) Microsoft Corporation.
 // Licensed under the Apache License 2.0.
 
+import (
+	"time"
+)
+
 // OpenShiftVersionList represents a list of OpenShift versions that can be
 // installed.
 type OpenShiftVersionList struct {
@@ -27,4 +31,28 @@ type OpenShiftVersionProperties struct {
 	OpenShiftPullspec string `json:"openShiftPullspec,omitempty" mutable:"true"`
 	InstallerPullspec string `json:"installerPullspec,omitempty" mutable:"true"`
 	Enabled           bool   `json:"enabled" mutable:"true"`
+
+	// BlockedUpgradesFrom lists the versions from which upgrading to this
+	// version is not supported.
+	BlockedUpgradesFrom []string `json:"blockedUpgradesFrom,omitempty" mutable:"true"`
+
+	// GeneralAvailability is when the version becomes available for new
+	// installs.
+	GeneralAvailability *time.Time `json:"generalAvailability,omitempty" mutable:"true"`
+
+	// EndOfSupport is when the minor version of this version goes out of
+	// support.
+	EndOfSupport *time.Time `json:"endOfSupport,omitempty" mutable:"true"`
+
+	// AllowedRegions restricts new installs of the version to the given
+	// regions.
+	AllowedRegions []string `json:"allowedRegions,omitempty" mutable:"true"`
+
+	// MinimumMasterVMSize and MinimumWorkerVMSize are the smallest VM sizes
+	// which new installs of the version may use.
+	MinimumMasterVMSize VMSize `json:"minimumMasterVmSize,omitempty" mutable:"true"`
+	MinimumWorkerVMSize VMSize `json:"minimumWorkerVmSize,omitempty" mutable:"true"`
+
+	// DeprecatedForNewInstalls stops new installs of the version.
+	DeprecatedForNewInstalls bool `json:"deprecatedForNewInstalls,omitempty" mutable:"true"`
 }
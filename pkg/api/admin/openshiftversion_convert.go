@@ -21,9 +21,31 @@ func (openShiftVersionConverter) ToExternal(v *api.OpenShiftVersion) interface{}
 			OpenShiftPullspec: v.Properties.OpenShiftPullspec,
 			InstallerPullspec: v.Properties.InstallerPullspec,
 			Enabled:           v.Properties.Enabled,
+
+			MinimumMasterVMSize:      VMSize(v.Properties.MinimumMasterVMSize),
+			MinimumWorkerVMSize:      VMSize(v.Properties.MinimumWorkerVMSize),
+			DeprecatedForNewInstalls: v.Properties.DeprecatedForNewInstalls,
 		},
 	}
 
+	if v.Properties.BlockedUpgradesFrom != nil {
+		out.Properties.BlockedUpgradesFrom = append([]string(nil), v.Properties.BlockedUpgradesFrom...)
+	}
+
+	if v.Properties.GeneralAvailability != nil {
+		t := *v.Properties.GeneralAvailability
+		out.Properties.GeneralAvailability = &t
+	}
+
+	if v.Properties.EndOfSupport != nil {
+		t := *v.Properties.EndOfSupport
+		out.Properties.EndOfSupport = &t
+	}
+
+	if v.Properties.AllowedRegions != nil {
+		out.Properties.AllowedRegions = append([]string(nil), v.Properties.AllowedRegions...)
+	}
+
 	return out
 }
 
@@ -52,4 +74,25 @@ func (c openShiftVersionConverter) ToInternal(_new interface{}, out *api.OpenShi
 	out.Properties.InstallerPullspec = new.Properties.InstallerPullspec
 	out.Properties.OpenShiftPullspec = new.Properties.OpenShiftPullspec
 	out.Properties.Version = new.Properties.Version
+	out.Properties.BlockedUpgradesFrom = nil
+	if new.Properties.BlockedUpgradesFrom != nil {
+		out.Properties.BlockedUpgradesFrom = append([]string(nil), new.Properties.BlockedUpgradesFrom...)
+	}
+	out.Properties.GeneralAvailability = nil
+	if new.Properties.GeneralAvailability != nil {
+		t := *new.Properties.GeneralAvailability
+		out.Properties.GeneralAvailability = &t
+	}
+	out.Properties.EndOfSupport = nil
+	if new.Properties.EndOfSupport != nil {
+		t := *new.Properties.EndOfSupport
+		out.Properties.EndOfSupport = &t
+	}
+	out.Properties.AllowedRegions = nil
+	if new.Properties.AllowedRegions != nil {
+		out.Properties.AllowedRegions = append([]string(nil), new.Properties.AllowedRegions...)
+	}
+	out.Properties.MinimumMasterVMSize = api.VMSize(new.Properties.MinimumMasterVMSize)
+	out.Properties.MinimumWorkerVMSize = api.VMSize(new.Properties.MinimumWorkerVMSize)
+	out.Properties.DeprecatedForNewInstalls = new.Properties.DeprecatedForNewInstalls
 }
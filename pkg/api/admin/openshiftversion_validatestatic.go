@@ -4,12 +4,19 @@ package admin
 // Licensed under the Apache License 2.0.
 
 import (
+	"fmt"
 	"net/http"
+	"regexp"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/api/util/immutable"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/util/version"
 )
 
+// rxRegion matches Azure region names, e.g. "eastus" or "westeurope2"
+var rxRegion = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
 type openShiftVersionStaticValidator struct{}
 
 // Validate validates an OpenShift cluster
@@ -45,6 +52,35 @@ func (sv openShiftVersionStaticValidator) validate(new *OpenShiftVersion, isCrea
 	if new.Properties.OpenShiftPullspec == "" {
 		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.openShiftPullspec", "Must be provided")
 	}
+
+	for i, from := range new.Properties.BlockedUpgradesFrom {
+		v, err := version.ParseVersion(from)
+		if err != nil || v.String() != from {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, fmt.Sprintf("properties.blockedUpgradesFrom[%d]", i), "The provided version '%s' is invalid.", from)
+		}
+	}
+
+	if new.Properties.GeneralAvailability != nil && new.Properties.EndOfSupport != nil &&
+		!new.Properties.EndOfSupport.After(*new.Properties.GeneralAvailability) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.endOfSupport", "The provided end of support date must be after the general availability date.")
+	}
+
+	for i, region := range new.Properties.AllowedRegions {
+		if !rxRegion.MatchString(region) {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, fmt.Sprintf("properties.allowedRegions[%d]", i), "The provided region '%s' is invalid.", region)
+		}
+	}
+
+	if new.Properties.MinimumMasterVMSize != "" &&
+		!validate.VMSizeIsValid(api.VMSize(new.Properties.MinimumMasterVMSize), false, true) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.minimumMasterVmSize", "The provided vmSize '%s' is unsupported for master.", new.Properties.MinimumMasterVMSize)
+	}
+
+	if new.Properties.MinimumWorkerVMSize != "" &&
+		!validate.VMSizeIsValid(api.VMSize(new.Properties.MinimumWorkerVMSize), false, false) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.minimumWorkerVmSize", "The provided vmSize '%s' is unsupported for workers.", new.Properties.MinimumWorkerVMSize)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,30 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import "time"
+
+// AdminAuditEntry records a single admin API call made against a cluster
+type AdminAuditEntry struct {
+	MissingFields
+
+	// CallerIdentity is the client principal name of the caller, or its user
+	// agent if the principal name was not given
+	CallerIdentity string `json:"callerIdentity,omitempty"`
+
+	RequestID       string `json:"requestId,omitempty"`
+	ClientRequestID string `json:"clientRequestId,omitempty"`
+	CorrelationID   string `json:"correlationId,omitempty"`
+
+	Method     string              `json:"method,omitempty"`
+	Path       string              `json:"path,omitempty"`
+	Parameters map[string][]string `json:"parameters,omitempty"`
+
+	StatusCode int `json:"statusCode,omitempty"`
+
+	StartedAt time.Time `json:"startedAt,omitempty"`
+
+	// Duration is the time taken to serve the call, in seconds
+	Duration float64 `json:"duration,omitempty"`
+}
@@ -0,0 +1,42 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// AdminAuditEntryDocuments represents admin audit entry documents.
+// pkg/database/cosmosdb requires its definition.
+type AdminAuditEntryDocuments struct {
+	Count                    int                        `json:"_count,omitempty"`
+	ResourceID               string                     `json:"_rid,omitempty"`
+	AdminAuditEntryDocuments []*AdminAuditEntryDocument `json:"Documents,omitempty"`
+}
+
+func (c *AdminAuditEntryDocuments) String() string {
+	return encodeJSON(c)
+}
+
+// AdminAuditEntryDocument represents an admin audit entry document.
+// pkg/database/cosmosdb requires its definition.
+type AdminAuditEntryDocument struct {
+	MissingFields
+
+	ID          string                 `json:"id,omitempty"`
+	ResourceID  string                 `json:"_rid,omitempty"`
+	Timestamp   int                    `json:"_ts,omitempty"`
+	Self        string                 `json:"_self,omitempty"`
+	ETag        string                 `json:"_etag,omitempty" deep:"-"`
+	Attachments string                 `json:"_attachments,omitempty"`
+	TTL         int                    `json:"ttl,omitempty"`
+	LSN         int                    `json:"_lsn,omitempty"`
+	Metadata    map[string]interface{} `json:"_metadata,omitempty"`
+
+	// Key is the lower case resource ID of the cluster the call was made
+	// against.  It is also the partition key.
+	Key string `json:"key,omitempty"`
+
+	AdminAuditEntry *AdminAuditEntry `json:"adminAuditEntry,omitempty"`
+}
+
+func (c *AdminAuditEntryDocument) String() string {
+	return encodeJSON(c)
+}
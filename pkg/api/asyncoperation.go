@@ -21,4 +21,18 @@ type AsyncOperation struct {
 	EndTime   *time.Time `json:"endTime,omitempty" deep:"-"`
 
 	Error *CloudErrorBody `json:"error,omitempty"`
+
+	// Progress is only reported for admin operations
+	Progress *AsyncOperationProgress `json:"progress,omitempty" deep:"-"`
+}
+
+// AsyncOperationProgress reports the progress of an admin operation which
+// works through a list of items one at a time
+type AsyncOperationProgress struct {
+	MissingFields
+
+	Completed []string `json:"completed,omitempty"`
+	Current   string   `json:"current,omitempty"`
+	Step      string   `json:"step,omitempty"`
+	Pending   []string `json:"pending,omitempty"`
 }
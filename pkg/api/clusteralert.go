@@ -0,0 +1,36 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import "time"
+
+// ClusterAlertStatus is the status of an alert as reported by Alertmanager
+type ClusterAlertStatus string
+
+// ClusterAlertStatus constants
+const (
+	ClusterAlertStatusFiring   ClusterAlertStatus = "firing"
+	ClusterAlertStatusResolved ClusterAlertStatus = "resolved"
+)
+
+// ClusterAlert represents a platform alert forwarded by a cluster's
+// Alertmanager
+type ClusterAlert struct {
+	MissingFields
+
+	Name        string             `json:"name,omitempty"`
+	Status      ClusterAlertStatus `json:"status,omitempty"`
+	Severity    string             `json:"severity,omitempty"`
+	Namespace   string             `json:"namespace,omitempty"`
+	Summary     string             `json:"summary,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Fingerprint string             `json:"fingerprint,omitempty"`
+
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	StartsAt   time.Time `json:"startsAt,omitempty"`
+	EndsAt     time.Time `json:"endsAt,omitempty"`
+	ReceivedAt time.Time `json:"receivedAt,omitempty"`
+}
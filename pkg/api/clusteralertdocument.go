@@ -0,0 +1,42 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// ClusterAlertDocuments represents cluster alert documents.
+// pkg/database/cosmosdb requires its definition.
+type ClusterAlertDocuments struct {
+	Count                 int                     `json:"_count,omitempty"`
+	ResourceID            string                  `json:"_rid,omitempty"`
+	ClusterAlertDocuments []*ClusterAlertDocument `json:"Documents,omitempty"`
+}
+
+func (c *ClusterAlertDocuments) String() string {
+	return encodeJSON(c)
+}
+
+// ClusterAlertDocument represents a cluster alert document.
+// pkg/database/cosmosdb requires its definition.
+type ClusterAlertDocument struct {
+	MissingFields
+
+	ID          string                 `json:"id,omitempty"`
+	ResourceID  string                 `json:"_rid,omitempty"`
+	Timestamp   int                    `json:"_ts,omitempty"`
+	Self        string                 `json:"_self,omitempty"`
+	ETag        string                 `json:"_etag,omitempty" deep:"-"`
+	Attachments string                 `json:"_attachments,omitempty"`
+	TTL         int                    `json:"ttl,omitempty"`
+	LSN         int                    `json:"_lsn,omitempty"`
+	Metadata    map[string]interface{} `json:"_metadata,omitempty"`
+
+	// Key is the lower case resource ID of the cluster which raised the
+	// alert.  It is also the partition key.
+	Key string `json:"key,omitempty"`
+
+	ClusterAlert *ClusterAlert `json:"clusterAlert,omitempty"`
+}
+
+func (c *ClusterAlertDocument) String() string {
+	return encodeJSON(c)
+}
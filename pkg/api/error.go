@@ -104,6 +104,8 @@ const (
 	CloudErrorCodeRequestDisallowedByPolicy          = "RequestDisallowedByPolicy"
 	CloudErrorCodeInvalidNetworkAddress              = "InvalidNetworkAddress"
 	CloudErrorCodeThrottlingLimitExceeded            = "ThrottlingLimitExceeded"
+	CloudErrorCodeDeletionProtected                  = "DeletionProtected"
+	CloudErrorCodeDeletionBlocked                    = "DeletionBlocked"
 )
 
 // NewCloudError returns a new CloudError
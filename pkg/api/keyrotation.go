@@ -0,0 +1,30 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import "time"
+
+// KeyRotation records the progress of re-sealing the encrypted fields of one
+// database collection with the primary encryption key, so that an
+// interrupted run can be resumed
+type KeyRotation struct {
+	MissingFields
+
+	// Collection is the name of the collection being re-sealed
+	Collection string `json:"collection,omitempty"`
+
+	// Continuation is the continuation token of the first page of the
+	// collection which has not yet been processed
+	Continuation string `json:"continuation,omitempty"`
+
+	// Scanned is the number of documents examined so far
+	Scanned int `json:"scanned,omitempty"`
+
+	// Resealed is the number of documents which were found to depend on a
+	// key other than the primary key and were re-sealed
+	Resealed int `json:"resealed,omitempty"`
+
+	StartedAt   time.Time  `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
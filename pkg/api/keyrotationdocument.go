@@ -0,0 +1,38 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// KeyRotationDocuments represents key rotation documents.
+// pkg/database/cosmosdb requires its definition.
+type KeyRotationDocuments struct {
+	Count                int                    `json:"_count,omitempty"`
+	ResourceID           string                 `json:"_rid,omitempty"`
+	KeyRotationDocuments []*KeyRotationDocument `json:"Documents,omitempty"`
+}
+
+func (c *KeyRotationDocuments) String() string {
+	return encodeJSON(c)
+}
+
+// KeyRotationDocument represents a key rotation document.
+// pkg/database/cosmosdb requires its definition.
+type KeyRotationDocument struct {
+	MissingFields
+
+	ID          string                 `json:"id,omitempty"`
+	ResourceID  string                 `json:"_rid,omitempty"`
+	Timestamp   int                    `json:"_ts,omitempty"`
+	Self        string                 `json:"_self,omitempty"`
+	ETag        string                 `json:"_etag,omitempty" deep:"-"`
+	Attachments string                 `json:"_attachments,omitempty"`
+	TTL         int                    `json:"ttl,omitempty"`
+	LSN         int                    `json:"_lsn,omitempty"`
+	Metadata    map[string]interface{} `json:"_metadata,omitempty"`
+
+	KeyRotation *KeyRotation `json:"keyRotation,omitempty"`
+}
+
+func (c *KeyRotationDocument) String() string {
+	return encodeJSON(c)
+}
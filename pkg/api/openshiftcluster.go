@@ -101,6 +101,11 @@ type OpenShiftClusterProperties struct {
 	LastAdminUpdateError    string              `json:"lastAdminUpdateError,omitempty"`
 	MaintenanceTask         MaintenanceTask     `json:"maintenanceTask,omitempty"`
 
+	// MachineResize is the resize of machines worked by the backend as the
+	// ResizeMachines maintenance task.  It is kept once the resize ends so
+	// that a failed resize can be resumed.
+	MachineResize *MachineResize `json:"machineResize,omitempty"`
+
 	// Operator feature/option flags
 	OperatorFlags   OperatorFlags `json:"operatorFlags,omitempty"`
 	OperatorVersion string        `json:"operatorVersion,omitempty"`
@@ -132,6 +137,20 @@ type OpenShiftClusterProperties struct {
 	// WorkerProfilesStatus is used to store the enriched worker profile data
 	WorkerProfilesStatus []WorkerProfile `json:"workerProfilesStatus,omitempty" swagger:"readOnly"`
 
+	// InfraProfile is set by SREs to request dedicated infrastructure nodes
+	InfraProfile *InfraProfile `json:"infraProfile,omitempty"`
+
+	// DeletionProtection is set by customers to reject deletes of the cluster
+	DeletionProtection DeletionProtection `json:"deletionProtection,omitempty"`
+
+	// SoftDeleteProfile is set by SREs to hold deleted clusters for a grace
+	// period before their infrastructure is removed
+	SoftDeleteProfile *SoftDeleteProfile `json:"softDeleteProfile,omitempty"`
+
+	// PendingDeletion is non-nil only while a deleted cluster is held for its
+	// soft delete grace period
+	PendingDeletion *PendingDeletion `json:"pendingDeletion,omitempty"`
+
 	APIServerProfile APIServerProfile `json:"apiserverProfile,omitempty"`
 
 	IngressProfiles []IngressProfile `json:"ingressProfiles,omitempty"`
@@ -158,6 +177,10 @@ type OpenShiftClusterProperties struct {
 	// UserAdminKubeconfig is derived admin kubeConfig with shorter live span
 	UserAdminKubeconfig SecureBytes `json:"userAdminKubeconfig,omitempty"`
 
+	// AlertIngestionToken authenticates alerts forwarded by the cluster's
+	// Alertmanager to the RP
+	AlertIngestionToken SecureString `json:"alertIngestionToken,omitempty"`
+
 	RegistryProfiles []*RegistryProfile `json:"registryProfiles,omitempty"`
 
 	HiveProfile HiveProfile `json:"hiveProfile,omitempty"`
@@ -203,6 +226,10 @@ const (
 	MaintenanceTaskOperator   MaintenanceTask = "OperatorUpdate"
 	MaintenanceTaskRenewCerts MaintenanceTask = "CertificatesRenewal"
 
+	// MaintenanceTaskResizeMachines is only set by the admin resizemachines
+	// API, which records the resize in OpenShiftClusterProperties.MachineResize
+	MaintenanceTaskResizeMachines MaintenanceTask = "ResizeMachines"
+
 	//
 	// Maintenance tasks for updating customer maintenance signals
 	//
@@ -223,6 +250,7 @@ func (t MaintenanceTask) IsMaintenanceOngoingTask() bool {
 	result := (t == MaintenanceTaskEverything) ||
 		(t == MaintenanceTaskOperator) ||
 		(t == MaintenanceTaskRenewCerts) ||
+		(t == MaintenanceTaskResizeMachines) ||
 		(t == "")
 	return result
 }
@@ -685,6 +713,44 @@ func GetEnrichedWorkerProfiles(ocp OpenShiftClusterProperties) ([]WorkerProfile,
 	return ocp.WorkerProfiles, "workerProfiles"
 }
 
+// InfraProfile represents the dedicated infrastructure nodes which run the
+// router, the image registry and the monitoring stack
+type InfraProfile struct {
+	MissingFields
+
+	VMSize     VMSize `json:"vmSize,omitempty"`
+	DiskSizeGB int    `json:"diskSizeGB,omitempty"`
+	Count      int    `json:"count,omitempty"`
+}
+
+// DeletionProtection determines if deletes of the cluster are rejected
+type DeletionProtection string
+
+// DeletionProtection constants
+const (
+	DeletionProtectionEnabled  DeletionProtection = "Enabled"
+	DeletionProtectionDisabled DeletionProtection = "Disabled"
+)
+
+// SoftDeleteProfile represents how long a deleted cluster is held before its
+// infrastructure is removed
+type SoftDeleteProfile struct {
+	MissingFields
+
+	GracePeriodHours int `json:"gracePeriodHours,omitempty"`
+}
+
+// PendingDeletion represents a deleted cluster held for its soft delete grace
+// period.  The backend does not dequeue the cluster until DeleteAfter, before
+// which an SRE may restore it.
+type PendingDeletion struct {
+	MissingFields
+
+	// RequestTime and DeleteAfter are Unix times in seconds
+	RequestTime int `json:"requestTime,omitempty"`
+	DeleteAfter int `json:"deleteAfter,omitempty"`
+}
+
 // APIServerProfile represents an API server profile
 type APIServerProfile struct {
 	MissingFields
@@ -750,6 +816,23 @@ const (
 	ArchitectureVersionV2
 )
 
+// MachineResize represents the resize of the machines of a MachineSet, or of
+// the masters, one machine at a time
+type MachineResize struct {
+	MissingFields
+
+	MachineSet string `json:"machineSet,omitempty"`
+	VMSize     VMSize `json:"vmSize,omitempty"`
+
+	// Machines are the machines to resize, in order.  Completed are the
+	// machines which have been resized; Current is the machine being resized
+	// and Step is the step of its resize being run.
+	Machines  []string `json:"machines,omitempty"`
+	Completed []string `json:"completed,omitempty"`
+	Current   string   `json:"current,omitempty"`
+	Step      string   `json:"step,omitempty"`
+}
+
 // HiveProfile represents the hive related data of a cluster
 type HiveProfile struct {
 	MissingFields
@@ -40,6 +40,16 @@ type OpenShiftClusterDocument struct {
 	LeaseExpires int    `json:"leaseExpires,omitempty" deep:"-"`
 	Dequeues     int    `json:"dequeues,omitempty"`
 
+	// DequeueHistory records the dequeues counted by Dequeues
+	DequeueHistory []*DequeueRecord `json:"dequeueHistory,omitempty" deep:"-"`
+
+	// DeadLetter is set when the backend has stopped working the document
+	// because it was dequeued too many times.  The operation is failed, but
+	// the marker, and Dequeues, are kept until an SRE requeues the operation
+	// through the admin API.  Until then, any further operation on the
+	// document is failed as soon as it is dequeued.
+	DeadLetter *DeadLetter `json:"deadLetter,omitempty"`
+
 	AsyncOperationID string `json:"asyncOperationId,omitempty" deep:"-"`
 
 	OpenShiftCluster *OpenShiftCluster `json:"openShiftCluster,omitempty"`
@@ -50,3 +60,32 @@ type OpenShiftClusterDocument struct {
 func (c *OpenShiftClusterDocument) String() string {
 	return encodeJSON(c)
 }
+
+// DequeueRecord records a dequeue of an OpenShiftClusterDocument by a backend
+// worker, and how the worker ended if it did not release its lease cleanly
+type DequeueRecord struct {
+	MissingFields
+
+	LeaseOwner string `json:"leaseOwner,omitempty"`
+	StartTime  int    `json:"startTime,omitempty"`
+	EndTime    int    `json:"endTime,omitempty"`
+
+	Step  string `json:"step,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// DeadLetter records why the backend stopped working an
+// OpenShiftClusterDocument
+type DeadLetter struct {
+	MissingFields
+
+	Time              int               `json:"time,omitempty"`
+	ProvisioningState ProvisioningState `json:"provisioningState,omitempty"`
+	MaintenanceTask   MaintenanceTask   `json:"maintenanceTask,omitempty"`
+	Dequeues          int               `json:"dequeues,omitempty"`
+
+	LastStep  string `json:"lastStep,omitempty"`
+	LastError string `json:"lastError,omitempty"`
+
+	DequeueHistory []*DequeueRecord `json:"dequeueHistory,omitempty"`
+}
@@ -0,0 +1,39 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// OpenShiftClusterUpgradePaths represents the upgrades recommended for an
+// OpenShift cluster from its current version
+type OpenShiftClusterUpgradePaths struct {
+	MissingFields
+
+	CurrentVersion string         `json:"currentVersion,omitempty"`
+	UpgradePaths   []*UpgradePath `json:"upgradePaths,omitempty"`
+}
+
+// UpgradePath represents an upgrade from a cluster's current version to
+// another version
+type UpgradePath struct {
+	MissingFields
+
+	Version string `json:"version,omitempty"`
+
+	// Supported is false if the RP does not support the upgrade, in which case
+	// UnsupportedReason says why
+	Supported         bool   `json:"supported,omitempty"`
+	UnsupportedReason string `json:"unsupportedReason,omitempty"`
+
+	// Risks lists the known issues which may affect the upgrade.  Upgrades
+	// with risks are only recommended if the cluster is not exposed to them.
+	Risks []*UpgradeRisk `json:"risks,omitempty"`
+}
+
+// UpgradeRisk represents a known issue which may affect an upgrade
+type UpgradeRisk struct {
+	MissingFields
+
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
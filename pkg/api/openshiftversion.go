@@ -3,6 +3,10 @@ package api
 // Copyright (c) Microsoft Corporation.
 // Licensed under the Apache License 2.0.
 
+import (
+	"time"
+)
+
 // OpenShiftVersion represents an OpenShift version that can be installed
 type OpenShiftVersion struct {
 	MissingFields
@@ -26,4 +30,31 @@ type OpenShiftVersionProperties struct {
 	InstallerPullspec string `json:"installerPullspec,omitempty"`
 	Enabled           bool   `json:"enabled,omitempty"`
 	Default           bool   `json:"default,omitempty"`
+
+	// BlockedUpgradesFrom lists the versions from which the RP does not
+	// support upgrading to this version, even if the upgrade graph
+	// recommends it.
+	BlockedUpgradesFrom []string `json:"blockedUpgradesFrom,omitempty"`
+
+	// GeneralAvailability is when the version becomes available for new
+	// installs.  If unset, the version is available as soon as it is enabled.
+	GeneralAvailability *time.Time `json:"generalAvailability,omitempty"`
+
+	// EndOfSupport is when the minor version of this version goes out of
+	// support.  The version is not available for new installs after this
+	// date.
+	EndOfSupport *time.Time `json:"endOfSupport,omitempty"`
+
+	// AllowedRegions restricts new installs of the version to the given
+	// regions.  If empty, the version can be installed in all regions.
+	AllowedRegions []string `json:"allowedRegions,omitempty"`
+
+	// MinimumMasterVMSize and MinimumWorkerVMSize are the smallest VM sizes
+	// which new installs of the version may use, compared by core count.
+	MinimumMasterVMSize VMSize `json:"minimumMasterVmSize,omitempty"`
+	MinimumWorkerVMSize VMSize `json:"minimumWorkerVmSize,omitempty"`
+
+	// DeprecatedForNewInstalls stops new installs of the version while
+	// keeping it enabled, e.g. for existing clusters.
+	DeprecatedForNewInstalls bool `json:"deprecatedForNewInstalls,omitempty"`
 }
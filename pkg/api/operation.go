@@ -130,6 +130,16 @@ var OperationOpenShiftClusterGetDetectors = Operation{
 	Origin: "user,system",
 }
 
+var OperationOpenShiftClusterGetUpgradePaths = Operation{
+	Name: "Microsoft.RedHatOpenShift/openShiftClusters/upgradePaths/read",
+	Display: Display{
+		Provider:  "Azure Red Hat OpenShift",
+		Resource:  "openShiftClusters",
+		Operation: "Get upgrade paths of an OpenShift cluster",
+	},
+	Origin: "user,system",
+}
+
 var OperationListInstallVersions = Operation{
 	Name: "Microsoft.RedHatOpenShift/locations/listInstallVersions/read",
 	Display: Display{
@@ -0,0 +1,55 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import "time"
+
+// PendingActionState represents the state of a destructive admin action held
+// for approval
+type PendingActionState string
+
+// PendingActionState constants
+const (
+	PendingActionStatePending   PendingActionState = "Pending"
+	PendingActionStateRejected  PendingActionState = "Rejected"
+	PendingActionStateApproved  PendingActionState = "Approved"
+	PendingActionStateRunning   PendingActionState = "Running"
+	PendingActionStateSucceeded PendingActionState = "Succeeded"
+	PendingActionStateFailed    PendingActionState = "Failed"
+)
+
+// PendingAction is a destructive admin API call which was held until a second
+// SRE approves it in the SRE portal.  The call is recorded as it was made and
+// is replayed through the admin API once approved.
+//
+// RequestedBy is the caller-supplied X-Ms-Client-Principal-Name, which the RP
+// cannot verify.  An approving Reviewer is the portal user, as verified by
+// AAD, who must be in an elevated group and differ from RequestedBy.
+type PendingAction struct {
+	MissingFields
+
+	// Action is the name of the admin action, e.g. stopvm
+	Action string `json:"action,omitempty"`
+
+	Method   string `json:"method,omitempty"`
+	Path     string `json:"path,omitempty"`
+	RawQuery string `json:"rawQuery,omitempty"`
+	Body     []byte `json:"body,omitempty"`
+
+	State PendingActionState `json:"state,omitempty"`
+
+	RequestedBy string     `json:"requestedBy,omitempty"`
+	RequestedAt *time.Time `json:"requestedAt,omitempty"`
+
+	// ExpiresAt is the time after which the action can no longer be approved,
+	// or, once approved, run
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Reviewer is the identity which approved or rejected the action
+	Reviewer   string     `json:"reviewer,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+
+	// StatusCode is the HTTP status code returned when the action was run
+	StatusCode int `json:"statusCode,omitempty"`
+}
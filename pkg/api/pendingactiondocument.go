@@ -0,0 +1,42 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// PendingActionDocuments represents pending action documents.
+// pkg/database/cosmosdb requires its definition.
+type PendingActionDocuments struct {
+	Count                  int                      `json:"_count,omitempty"`
+	ResourceID             string                   `json:"_rid,omitempty"`
+	PendingActionDocuments []*PendingActionDocument `json:"Documents,omitempty"`
+}
+
+func (c *PendingActionDocuments) String() string {
+	return encodeJSON(c)
+}
+
+// PendingActionDocument represents a pending action document.
+// pkg/database/cosmosdb requires its definition.
+type PendingActionDocument struct {
+	MissingFields
+
+	ID          string                 `json:"id,omitempty"`
+	ResourceID  string                 `json:"_rid,omitempty"`
+	Timestamp   int                    `json:"_ts,omitempty"`
+	Self        string                 `json:"_self,omitempty"`
+	ETag        string                 `json:"_etag,omitempty" deep:"-"`
+	Attachments string                 `json:"_attachments,omitempty"`
+	TTL         int                    `json:"ttl,omitempty"`
+	LSN         int                    `json:"_lsn,omitempty"`
+	Metadata    map[string]interface{} `json:"_metadata,omitempty"`
+
+	// Key is the lower case resource ID of the cluster the action targets.
+	// It is also the partition key.
+	Key string `json:"key,omitempty"`
+
+	PendingAction *PendingAction `json:"pendingAction,omitempty"`
+}
+
+func (c *PendingActionDocument) String() string {
+	return encodeJSON(c)
+}
@@ -3,6 +3,10 @@ package api
 // Copyright (c) Microsoft Corporation.
 // Licensed under the Apache License 2.0.
 
+import (
+	"time"
+)
+
 // Portal represents a portal
 type Portal struct {
 	MissingFields
@@ -14,12 +18,30 @@ type Portal struct {
 
 	SSH        *SSH        `json:"ssh,omitempty"`
 	Kubeconfig *Kubeconfig `json:"kubeconfig,omitempty"`
+
+	// Elevation is set on documents which record a just-in-time elevation
+	// request rather than grant access themselves
+	Elevation *Elevation `json:"elevation,omitempty"`
+
+	// ElevationID is the ID of the just-in-time elevation which granted this
+	// access, if any
+	ElevationID string `json:"elevationId,omitempty"`
+
+	// RevokedAt is set when the access granted by this document has been
+	// revoked before its TTL expired
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	RevokedBy string     `json:"revokedBy,omitempty"`
 }
 
 type SSH struct {
 	MissingFields
 
-	Master        int  `json:"master"`
+	Master int `json:"master"`
+
+	// Machine is the name of the Machine object of the node being accessed.
+	// If set, it takes precedence over Master.
+	Machine string `json:"machine,omitempty"`
+
 	Authenticated bool `json:"authenticated,omitempty"`
 }
 
@@ -27,4 +49,66 @@ type Kubeconfig struct {
 	MissingFields
 
 	Elevated bool `json:"elevated,omitempty"`
+
+	// Scope restricts what the kubeconfig may do.  If nil, the kubeconfig has
+	// the full access of the SRE or elevated service kubeconfig.
+	Scope *KubeconfigScope `json:"scope,omitempty"`
+}
+
+// KubeconfigScopeType represents what a scoped portal kubeconfig may do.  All
+// scopes allow API discovery, /version and /openapi, but no other
+// non-resource paths.
+type KubeconfigScopeType string
+
+// KubeconfigScopeType constants
+const (
+	// KubeconfigScopeTypeReadOnly allows reading all resources except
+	// secrets, but not exec, attach, port-forward or proxy
+	KubeconfigScopeTypeReadOnly KubeconfigScopeType = "ReadOnly"
+
+	// KubeconfigScopeTypeLogsOnly allows reading pods and their logs only
+	KubeconfigScopeTypeLogsOnly KubeconfigScopeType = "LogsOnly"
+)
+
+// KubeconfigScope restricts a portal kubeconfig
+type KubeconfigScope struct {
+	MissingFields
+
+	Type KubeconfigScopeType `json:"type,omitempty"`
+
+	// Namespaces, if set, restricts the kubeconfig to resources in the given
+	// namespaces
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// ElevationState represents the state of a just-in-time elevation request
+type ElevationState string
+
+// ElevationState constants
+const (
+	ElevationStatePending  ElevationState = "Pending"
+	ElevationStateApproved ElevationState = "Approved"
+	ElevationStateDenied   ElevationState = "Denied"
+)
+
+// Elevation is a request by an SRE for elevated access to a single cluster,
+// which must be reviewed by a second SRE
+type Elevation struct {
+	MissingFields
+
+	Reason string `json:"reason,omitempty"`
+
+	// Duration is the lifetime in seconds of the access granted on approval
+	Duration int `json:"duration,omitempty"`
+
+	State ElevationState `json:"state,omitempty"`
+
+	RequestedAt *time.Time `json:"requestedAt,omitempty"`
+
+	// Reviewer is the username of the SRE who approved or denied the request
+	Reviewer   string     `json:"reviewer,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+
+	// ExpiresAt is when the access granted on approval ends
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
@@ -27,6 +27,10 @@ type OpenShiftClusterAdminKubeconfigConverter interface {
 	ToExternal(*OpenShiftCluster) interface{}
 }
 
+type OpenShiftClusterUpgradePathsConverter interface {
+	ToExternal(*OpenShiftClusterUpgradePaths) interface{}
+}
+
 type OpenShiftVersionConverter interface {
 	ToExternal(*OpenShiftVersion) interface{}
 	ToExternalList([]*OpenShiftVersion) interface{}
@@ -67,6 +71,7 @@ type Version struct {
 	OpenShiftClusterStaticValidator          OpenShiftClusterStaticValidator
 	OpenShiftClusterCredentialsConverter     OpenShiftClusterCredentialsConverter
 	OpenShiftClusterAdminKubeconfigConverter OpenShiftClusterAdminKubeconfigConverter
+	OpenShiftClusterUpgradePathsConverter    OpenShiftClusterUpgradePathsConverter
 	OpenShiftVersionConverter                OpenShiftVersionConverter
 	OpenShiftVersionStaticValidator          OpenShiftVersionStaticValidator
 	OperationList                            OperationList
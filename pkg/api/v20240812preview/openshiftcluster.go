@@ -72,8 +72,20 @@ type OpenShiftClusterProperties struct {
 
 	// The cluster ingress profiles.
 	IngressProfiles []IngressProfile `json:"ingressProfiles,omitempty"`
+
+	// Whether deletes of the cluster are rejected.
+	DeletionProtection DeletionProtection `json:"deletionProtection,omitempty" mutable:"true"`
 }
 
+// DeletionProtection determines if deletes of the cluster are rejected.
+type DeletionProtection string
+
+// DeletionProtection constants.
+const (
+	DeletionProtectionEnabled  DeletionProtection = "Enabled"
+	DeletionProtectionDisabled DeletionProtection = "Disabled"
+)
+
 // ProvisioningState represents a provisioning state.
 type ProvisioningState string
 
@@ -52,6 +52,7 @@ func (c openShiftClusterConverter) ToExternal(oc *api.OpenShiftCluster) interfac
 				URL:        oc.Properties.APIServerProfile.URL,
 				IP:         oc.Properties.APIServerProfile.IP,
 			},
+			DeletionProtection: DeletionProtection(oc.Properties.DeletionProtection),
 		},
 	}
 
@@ -246,6 +247,7 @@ func (c openShiftClusterConverter) ToInternal(_oc interface{}, out *api.OpenShif
 	if oc.Properties.APIServerProfile.IP != "" {
 		out.Properties.APIServerProfile.IP = oc.Properties.APIServerProfile.IP
 	}
+	out.Properties.DeletionProtection = api.DeletionProtection(oc.Properties.DeletionProtection)
 	out.Properties.IngressProfiles = nil
 	if oc.Properties.IngressProfiles != nil {
 		out.Properties.IngressProfiles = make([]api.IngressProfile, len(oc.Properties.IngressProfiles))
@@ -38,6 +38,7 @@ func ExampleOpenShiftClusterPutParameter() interface{} {
 	oc.Properties.IngressProfiles[0].IP = ""
 	oc.Properties.MasterProfile.EncryptionAtHost = EncryptionAtHostEnabled
 	oc.Properties.WorkerProfilesStatus = nil
+	oc.Properties.DeletionProtection = DeletionProtectionEnabled
 	oc.Properties.NetworkProfile.LoadBalancerProfile = &LoadBalancerProfile{
 		ManagedOutboundIPs: &ManagedOutboundIPs{
 			Count: 1,
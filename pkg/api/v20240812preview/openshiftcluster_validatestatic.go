@@ -110,6 +110,9 @@ func (sv openShiftClusterStaticValidator) validateProperties(path string, p *Ope
 	if err := sv.validateAPIServerProfile(path+".apiserverProfile", &p.APIServerProfile); err != nil {
 		return err
 	}
+	if err := sv.validateDeletionProtection(path+".deletionProtection", p.DeletionProtection); err != nil {
+		return err
+	}
 
 	if isCreate {
 		if len(p.WorkerProfilesStatus) != 0 {
@@ -353,6 +356,16 @@ func (sv openShiftClusterStaticValidator) validateWorkerProfile(path string, wp
 	return nil
 }
 
+func (sv openShiftClusterStaticValidator) validateDeletionProtection(path string, dp DeletionProtection) error {
+	switch dp {
+	case "", DeletionProtectionEnabled, DeletionProtectionDisabled:
+	default:
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path, "The provided value '%s' is invalid.", dp)
+	}
+
+	return nil
+}
+
 func (sv openShiftClusterStaticValidator) validateAPIServerProfile(path string, ap *APIServerProfile) error {
 	switch ap.Visibility {
 	case VisibilityPublic, VisibilityPrivate:
@@ -270,6 +270,19 @@ func TestOpenShiftClusterStaticValidateProperties(t *testing.T) {
 			},
 			wantErr: "400: InvalidParameter: properties.provisioningState: The provided provisioning state 'invalid' is invalid.",
 		},
+		{
+			name: "deletionProtection enabled",
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.DeletionProtection = DeletionProtectionEnabled
+			},
+		},
+		{
+			name: "deletionProtection invalid",
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.DeletionProtection = "invalid"
+			},
+			wantErr: "400: InvalidParameter: properties.deletionProtection: The provided value 'invalid' is invalid.",
+		},
 	}
 	createTests := []*validateTest{
 		{
@@ -974,6 +987,10 @@ func TestOpenShiftClusterStaticValidateDelta(t *testing.T) {
 			name:   "valid tags change",
 			modify: func(oc *OpenShiftCluster) { oc.Tags = Tags{"new": "value"} },
 		},
+		{
+			name:   "valid deletionProtection change",
+			modify: func(oc *OpenShiftCluster) { oc.Properties.DeletionProtection = DeletionProtectionEnabled },
+		},
 		{
 			name:    "provisioningState change",
 			modify:  func(oc *OpenShiftCluster) { oc.Properties.ProvisioningState = ProvisioningStateFailed },
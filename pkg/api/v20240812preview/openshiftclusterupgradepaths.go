@@ -0,0 +1,43 @@
+package v20240812preview
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// OpenShiftClusterUpgradePaths represents the upgrades recommended for an
+// OpenShift cluster from its current version.
+type OpenShiftClusterUpgradePaths struct {
+	// The current version of the cluster.
+	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// The upgrades recommended for the cluster.
+	UpgradePaths []UpgradePath `json:"upgradePaths,omitempty"`
+}
+
+// UpgradePath represents an upgrade from the cluster's current version to
+// another version.
+type UpgradePath struct {
+	// The version to upgrade to.
+	Version string `json:"version,omitempty"`
+
+	// Whether the upgrade is supported.
+	Supported bool `json:"supported"`
+
+	// The reason the upgrade is not supported.
+	UnsupportedReason string `json:"unsupportedReason,omitempty"`
+
+	// The known issues which may affect the upgrade.  The upgrade is only
+	// recommended if the cluster is not exposed to them.
+	Risks []UpgradeRisk `json:"risks,omitempty"`
+}
+
+// UpgradeRisk represents a known issue which may affect an upgrade.
+type UpgradeRisk struct {
+	// The name of the risk.
+	Name string `json:"name,omitempty"`
+
+	// A description of the risk.
+	Message string `json:"message,omitempty"`
+
+	// A link to more information about the risk.
+	URL string `json:"url,omitempty"`
+}
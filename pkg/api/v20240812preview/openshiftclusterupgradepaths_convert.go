@@ -0,0 +1,44 @@
+package v20240812preview
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+type openShiftClusterUpgradePathsConverter struct{}
+
+// ToExternal returns a new external representation of the internal object,
+// reading from the subset of the internal object's fields that appear in the
+// external representation.  ToExternal does not modify its argument; there is
+// no pointer aliasing between the passed and returned objects.
+func (openShiftClusterUpgradePathsConverter) ToExternal(p *api.OpenShiftClusterUpgradePaths) interface{} {
+	out := &OpenShiftClusterUpgradePaths{
+		CurrentVersion: p.CurrentVersion,
+		UpgradePaths:   make([]UpgradePath, 0, len(p.UpgradePaths)),
+	}
+
+	for _, path := range p.UpgradePaths {
+		outPath := UpgradePath{
+			Version:           path.Version,
+			Supported:         path.Supported,
+			UnsupportedReason: path.UnsupportedReason,
+		}
+
+		if path.Risks != nil {
+			outPath.Risks = make([]UpgradeRisk, 0, len(path.Risks))
+			for _, risk := range path.Risks {
+				outPath.Risks = append(outPath.Risks, UpgradeRisk{
+					Name:    risk.Name,
+					Message: risk.Message,
+					URL:     risk.URL,
+				})
+			}
+		}
+
+		out.UpgradePaths = append(out.UpgradePaths, outPath)
+	}
+
+	return out
+}
@@ -0,0 +1,34 @@
+package v20240812preview
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// ExampleOpenShiftClusterUpgradePathsResponse returns an example
+// OpenShiftClusterUpgradePaths object that the RP might return to an end-user
+func ExampleOpenShiftClusterUpgradePathsResponse() interface{} {
+	return &OpenShiftClusterUpgradePaths{
+		CurrentVersion: "4.13.20",
+		UpgradePaths: []UpgradePath{
+			{
+				Version:   "4.13.21",
+				Supported: true,
+			},
+			{
+				Version:   "4.14.1",
+				Supported: true,
+				Risks: []UpgradeRisk{
+					{
+						Name:    "ExampleRisk",
+						Message: "Clusters with example configuration may fail to upgrade.",
+						URL:     "https://issues.redhat.com/browse/EXAMPLE-1",
+					},
+				},
+			},
+			{
+				Version:           "4.14.2",
+				Supported:         false,
+				UnsupportedReason: "Upgrading from 4.13.20 to 4.14.2 is not supported by Azure Red Hat OpenShift.",
+			},
+		},
+	}
+}
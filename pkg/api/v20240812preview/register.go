@@ -21,6 +21,7 @@ func init() {
 		OpenShiftClusterStaticValidator:          openShiftClusterStaticValidator{},
 		OpenShiftClusterCredentialsConverter:     openShiftClusterCredentialsConverter{},
 		OpenShiftClusterAdminKubeconfigConverter: openShiftClusterAdminKubeconfigConverter{},
+		OpenShiftClusterUpgradePathsConverter:    openShiftClusterUpgradePathsConverter{},
 		OpenShiftVersionConverter:                openShiftVersionConverter{},
 		OperationList: api.OperationList{
 			Operations: []api.Operation{
@@ -43,6 +44,7 @@ func init() {
 				api.OperationSyncIdentityProvidersWrite,
 				api.OperationSyncIdentityProvidersDelete,
 				api.OperationOpenShiftClusterGetDetectors,
+				api.OperationOpenShiftClusterGetUpgradePaths,
 			},
 		},
 		SyncSetConverter:              syncSetConverter{},
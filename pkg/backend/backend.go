@@ -11,6 +11,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/env"
 	"github.com/Azure/ARO-RP/pkg/metrics"
@@ -22,6 +23,13 @@ import (
 const (
 	maxWorkers      = 100
 	maxDequeueCount = 5
+
+	// minQueueWorkers is the number of workers reserved for each of
+	// database.OpenShiftClustersQueueProvisioningStates.  The reservations sum
+	// to less than maxWorkers; the remaining workers are shared, so that, for
+	// example, a burst of creates can use the idle capacity but cannot starve
+	// deletes or admin updates.
+	minQueueWorkers = 10
 )
 
 type backend struct {
@@ -44,6 +52,17 @@ type backend struct {
 	workers  int32
 	stopping atomic.Value
 
+	// drain is closed when the backend starts draining, which workers in
+	// progress observe between steps
+	drain     chan struct{}
+	drainOnce sync.Once
+	draining  atomic.Value
+
+	// clusterWorkers and subscriptionWorkers count the OpenShiftCluster
+	// workers by provisioning state and by subscription; guarded by mu
+	clusterWorkers      map[api.ProvisioningState]int
+	subscriptionWorkers map[string]int
+
 	ocb *openShiftClusterBackend
 	sb  *subscriptionBackend
 }
@@ -53,8 +72,23 @@ type Runnable interface {
 	Run(context.Context, <-chan struct{}, chan<- struct{})
 }
 
+// Backend represents a runnable backend which can be drained ahead of an RP
+// upgrade
+type Backend interface {
+	Runnable
+
+	// Drain stops the backend dequeuing documents.  Workers in progress keep
+	// renewing their leases until they reach the end of a step, at which point
+	// they release their leases for another RP instance to resume the work.
+	Drain()
+
+	// Drained returns true once the backend is draining and all its workers
+	// have finished
+	Drained() bool
+}
+
 // NewBackend returns a new runnable backend
-func NewBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, aead encryption.AEAD, m metrics.Emitter) (Runnable, error) {
+func NewBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, aead encryption.AEAD, m metrics.Emitter) (Backend, error) {
 	b, err := newBackend(ctx, log, env, dbAsyncOperations, dbBilling, dbGateway, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, aead, m)
 	if err != nil {
 		return nil, err
@@ -85,9 +119,15 @@ func newBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsy
 		billing: billing,
 		aead:    aead,
 		m:       m,
+
+		clusterWorkers:      map[api.ProvisioningState]int{},
+		subscriptionWorkers: map[string]int{},
+
+		drain: make(chan struct{}),
 	}
 	b.cond = sync.NewCond(&b.mu)
 	b.stopping.Store(false)
+	b.draining.Store(false)
 	return b, nil
 }
 
@@ -110,7 +150,7 @@ func (b *backend) Run(ctx context.Context, stop <-chan struct{}, done chan<- str
 
 	for {
 		b.mu.Lock()
-		for atomic.LoadInt32(&b.workers) >= maxWorkers && !b.stopping.Load().(bool) {
+		for (atomic.LoadInt32(&b.workers) >= maxWorkers || b.draining.Load().(bool)) && !b.stopping.Load().(bool) {
 			b.cond.Wait()
 		}
 		b.mu.Unlock()
@@ -141,6 +181,19 @@ func (b *backend) Run(ctx context.Context, stop <-chan struct{}, done chan<- str
 	close(done)
 }
 
+func (b *backend) Drain() {
+	b.drainOnce.Do(func() {
+		b.baseLog.Print("draining")
+		b.draining.Store(true)
+		close(b.drain)
+		b.m.EmitGauge("backend.draining", 1, nil)
+	})
+}
+
+func (b *backend) Drained() bool {
+	return b.draining.Load().(bool) && atomic.LoadInt32(&b.workers) == 0
+}
+
 func (b *backend) waitForWorkerCompletion() {
 	b.mu.Lock()
 	for atomic.LoadInt32(&b.workers) > 0 {
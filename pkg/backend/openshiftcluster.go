@@ -5,8 +5,10 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -18,6 +20,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/cluster"
 	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 	"github.com/Azure/ARO-RP/pkg/env"
 	"github.com/Azure/ARO-RP/pkg/hive"
 	"github.com/Azure/ARO-RP/pkg/metrics"
@@ -25,8 +28,11 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/encryption"
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
 	"github.com/Azure/ARO-RP/pkg/util/recover"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
 )
 
+var errDequeueRecordNotFound = errors.New("dequeue record not found")
+
 type openShiftClusterBackend struct {
 	*backend
 
@@ -45,7 +51,7 @@ func newOpenShiftClusterBackend(b *backend) *openShiftClusterBackend {
 // succeeded in dequeuing anything - if this is false, the caller should sleep
 // before calling again
 func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
-	doc, err := ocb.dbOpenShiftClusters.Dequeue(ctx)
+	doc, err := ocb.dequeue(ctx)
 	if err != nil || doc == nil {
 		return false, err
 	}
@@ -56,14 +62,21 @@ func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
 	log = utillog.EnrichWithClusterVersion(log, doc.OpenShiftCluster.Properties.ClusterProfile.Version)
 	log = utillog.EnrichWithClusterDeploymentNamespace(log, doc.OpenShiftCluster.Properties.HiveProfile.Namespace)
 
+	if doc.DeadLetter != nil {
+		log.Errorf("dequeued while dead lettered, failing")
+		return true, ocb.deadLetter(ctx, log, doc)
+	}
+
 	if doc.Dequeues > maxDequeueCount {
-		err := fmt.Errorf("dequeued %d times, failing", doc.Dequeues)
-		return true, ocb.endLease(ctx, log, nil, doc, api.ProvisioningStateFailed, err)
+		log.Errorf("dequeued %d times, moving to dead letter", doc.Dequeues)
+		return true, ocb.deadLetter(ctx, log, doc)
 	}
 
 	log.Print("dequeued")
+	provisioningState, subscriptionID := doc.OpenShiftCluster.Properties.ProvisioningState, doc.PartitionKey
 	atomic.AddInt32(&ocb.workers, 1)
 	ocb.m.EmitGauge("backend.openshiftcluster.workers.count", int64(atomic.LoadInt32(&ocb.workers)), nil)
+	ocb.addWorker(provisioningState, subscriptionID, 1)
 
 	go func() {
 		defer recover.Panic(log)
@@ -73,20 +86,174 @@ func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
 		defer func() {
 			atomic.AddInt32(&ocb.workers, -1)
 			ocb.m.EmitGauge("backend.openshiftcluster.workers.count", int64(atomic.LoadInt32(&ocb.workers)), nil)
+			ocb.addWorker(provisioningState, subscriptionID, -1)
 			ocb.cond.Signal()
 
 			log.WithField("duration", time.Since(t).Seconds()).Print("done")
 		}()
 
-		err := ocb.handle(context.Background(), log, doc)
+		ctx, currentStep := steps.ContextWithCurrentStep(steps.ContextWithDrain(context.Background(), ocb.drain))
+
+		err := ocb.handle(ctx, log, doc)
 		if err != nil {
 			log.Error(err)
+			ocb.recordDequeueError(context.Background(), log, doc, currentStep(), err)
 		}
 	}()
 
 	return true, nil
 }
 
+// dequeue dequeues an OpenShiftClusterDocument whose provisioning state has a
+// spare worker.  The states with the fewest workers are preferred, so that no
+// state is starved by a burst of work in another, and within a state the
+// documents of the subscriptions with the fewest workers are preferred, so
+// that a burst of operations in one subscription does not delay the others.
+// Ties between states are broken in the order of
+// database.OpenShiftClustersQueueProvisioningStates.  The preference only
+// applies within a page of the dequeue query; see DequeueWithPriority.
+func (ocb *openShiftClusterBackend) dequeue(ctx context.Context) (*api.OpenShiftClusterDocument, error) {
+	return ocb.dbOpenShiftClusters.DequeueWithPriority(ctx, ocb.prioritize)
+}
+
+// prioritize returns the candidate documents which may be dequeued, in the
+// order in which they should be tried
+func (ocb *openShiftClusterBackend) prioritize(docs []*api.OpenShiftClusterDocument) []*api.OpenShiftClusterDocument {
+	ocb.mu.Lock()
+	defer ocb.mu.Unlock()
+
+	order := map[api.ProvisioningState]int{}
+	spare := map[api.ProvisioningState]bool{}
+	for i, provisioningState := range database.OpenShiftClustersQueueProvisioningStates {
+		order[provisioningState] = i
+		spare[provisioningState] = ocb.hasSpareWorker(provisioningState)
+	}
+
+	var candidates []*api.OpenShiftClusterDocument
+	for _, doc := range docs {
+		if spare[doc.OpenShiftCluster.Properties.ProvisioningState] {
+			candidates = append(candidates, doc)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		si, sj := candidates[i].OpenShiftCluster.Properties.ProvisioningState, candidates[j].OpenShiftCluster.Properties.ProvisioningState
+		if ocb.clusterWorkers[si] != ocb.clusterWorkers[sj] {
+			return ocb.clusterWorkers[si] < ocb.clusterWorkers[sj]
+		}
+		if si != sj {
+			return order[si] < order[sj]
+		}
+		return ocb.subscriptionWorkers[candidates[i].PartitionKey] < ocb.subscriptionWorkers[candidates[j].PartitionKey]
+	})
+
+	return candidates
+}
+
+// hasSpareWorker returns true if provisioningState may take another worker
+// without using a worker reserved for another state.  ocb.mu must be held.
+func (ocb *openShiftClusterBackend) hasSpareWorker(provisioningState api.ProvisioningState) bool {
+	var workers, reserved int
+	for _, s := range database.OpenShiftClustersQueueProvisioningStates {
+		workers += ocb.clusterWorkers[s]
+		if s != provisioningState && ocb.clusterWorkers[s] < minQueueWorkers {
+			reserved += minQueueWorkers - ocb.clusterWorkers[s]
+		}
+	}
+
+	return workers+reserved < maxWorkers
+}
+
+// addWorker adjusts the worker counts of provisioningState and subscriptionID
+// by delta
+func (ocb *openShiftClusterBackend) addWorker(provisioningState api.ProvisioningState, subscriptionID string, delta int) {
+	ocb.mu.Lock()
+	ocb.clusterWorkers[provisioningState] += delta
+	count := ocb.clusterWorkers[provisioningState]
+
+	ocb.subscriptionWorkers[subscriptionID] += delta
+	if ocb.subscriptionWorkers[subscriptionID] <= 0 {
+		delete(ocb.subscriptionWorkers, subscriptionID)
+	}
+	ocb.mu.Unlock()
+
+	ocb.m.EmitGauge("backend.openshiftcluster.workers.class.count", int64(count), map[string]string{
+		"provisioningState": string(provisioningState),
+	})
+}
+
+// deadLetter stops the backend working doc, which has been dequeued too many
+// times.  The asynchronous operation is failed and the document is moved to a
+// terminal provisioning state, as it would be had the operation returned an
+// error, but the DeadLetter marker is kept so that an SRE can investigate and
+// requeue the operation.
+//
+// A document which is already dead lettered is failed in the same way without
+// being worked.  Its marker is updated to refer to the latest operation, which
+// is the one a requeue restores, but keeps the dequeue history and error which
+// caused it to be dead lettered.
+func (ocb *openShiftClusterBackend) deadLetter(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument) error {
+	provisioningState := doc.OpenShiftCluster.Properties.ProvisioningState
+
+	doc, err := ocb.dbOpenShiftClusters.PatchWithLease(ctx, doc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		if doc.DeadLetter == nil {
+			doc.DeadLetter = &api.DeadLetter{
+				DequeueHistory: doc.DequeueHistory,
+			}
+
+			for _, r := range doc.DequeueHistory {
+				if r.Error != "" {
+					doc.DeadLetter.LastStep, doc.DeadLetter.LastError = r.Step, r.Error
+				}
+			}
+		}
+
+		doc.DeadLetter.Time = int(time.Now().Unix())
+		doc.DeadLetter.ProvisioningState = doc.OpenShiftCluster.Properties.ProvisioningState
+		doc.DeadLetter.MaintenanceTask = doc.OpenShiftCluster.Properties.MaintenanceTask
+		doc.DeadLetter.Dequeues = doc.Dequeues
+
+		doc.DequeueHistory = nil
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ocb.m.EmitGauge("backend.openshiftcluster.deadletter", 1, map[string]string{
+		"provisioningState": string(provisioningState),
+	})
+
+	return ocb.endLease(ctx, log, nil, doc, api.ProvisioningStateFailed, fmt.Errorf("dequeued %d times, moved to dead letter", doc.Dequeues))
+}
+
+// recordDequeueError records the error with which the worker that dequeued
+// doc ended, and the step it was running, in the DequeueHistory of doc
+func (ocb *openShiftClusterBackend) recordDequeueError(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument, step string, backendErr error) {
+	if len(doc.DequeueHistory) == 0 {
+		return
+	}
+	dequeue := doc.DequeueHistory[len(doc.DequeueHistory)-1]
+
+	_, err := ocb.dbOpenShiftClusters.Patch(ctx, doc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		for _, r := range doc.DequeueHistory {
+			if r.LeaseOwner == dequeue.LeaseOwner && r.StartTime == dequeue.StartTime {
+				r.EndTime = int(time.Now().Unix())
+				r.Step = step
+				r.Error = backendErr.Error()
+				return nil
+			}
+		}
+
+		// the document has been requeued or its lease ended cleanly since
+		return errDequeueRecordNotFound
+	})
+	if err != nil && err != errDequeueRecordNotFound && !cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		log.Error(err)
+	}
+}
+
 // handle is responsible for handling backend operation and lease
 func (ocb *openShiftClusterBackend) handle(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument) error {
 	ctx, cancel := context.WithCancel(ctx)
@@ -160,6 +327,11 @@ func (ocb *openShiftClusterBackend) handle(ctx context.Context, log *logrus.Entr
 
 		err = m.AdminUpdate(ctx)
 		if err != nil {
+			// pick up any progress the maintenance task recorded, so that it
+			// is reflected in the async operation
+			if latest, getErr := ocb.dbOpenShiftClusters.Get(ctx, doc.Key); getErr == nil {
+				doc = latest
+			}
 			// Customer will continue to see the cluster in an ongoing maintenance state
 			return ocb.endLease(ctx, log, stop, doc, api.ProvisioningStateFailed, err)
 		}
@@ -289,6 +461,12 @@ func (ocb *openShiftClusterBackend) updateAsyncOperation(ctx context.Context, lo
 }
 
 func (ocb *openShiftClusterBackend) endLease(ctx context.Context, log *logrus.Entry, stop func(), doc *api.OpenShiftClusterDocument, provisioningState api.ProvisioningState, backendErr error) error {
+	// If the backend is draining, the operation is neither finished nor
+	// failed: hand it over to another backend as it stands
+	if errors.Is(backendErr, steps.ErrDraining) {
+		return ocb.releaseLease(ctx, log, stop, doc)
+	}
+
 	var adminUpdateError *string
 	var failedProvisioningState api.ProvisioningState
 	initialProvisioningState := doc.OpenShiftCluster.Properties.ProvisioningState
@@ -328,6 +506,25 @@ func (ocb *openShiftClusterBackend) endLease(ctx context.Context, log *logrus.En
 	return err
 }
 
+func (ocb *openShiftClusterBackend) releaseLease(ctx context.Context, log *logrus.Entry, stop func(), doc *api.OpenShiftClusterDocument) error {
+	log.Print("draining, releasing lease")
+
+	if stop != nil {
+		stop()
+	}
+
+	_, err := ocb.dbOpenShiftClusters.ReleaseLease(ctx, doc.Key)
+	if err != nil {
+		return err
+	}
+
+	ocb.m.EmitGauge("backend.openshiftcluster.drained", 1, map[string]string{
+		"provisioningState": string(doc.OpenShiftCluster.Properties.ProvisioningState),
+	})
+
+	return nil
+}
+
 func (ocb *openShiftClusterBackend) asyncOperationResultLog(log *logrus.Entry, initialProvisioningState api.ProvisioningState, backendErr error) {
 	log = log.WithFields(logrus.Fields{
 		"LOGKIND":       "asyncqos",
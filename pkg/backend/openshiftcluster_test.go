@@ -28,6 +28,8 @@ import (
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
 	mock_cluster "github.com/Azure/ARO-RP/pkg/util/mocks/cluster"
 	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
 	testdatabase "github.com/Azure/ARO-RP/test/database"
 	"github.com/Azure/ARO-RP/test/util/deterministicuuid"
 	testlog "github.com/Azure/ARO-RP/test/util/log"
@@ -176,6 +178,45 @@ func TestBackendTry(t *testing.T) {
 				})
 			},
 		},
+		{
+			name: "StateCreating interrupted by draining releases the lease and stays in Creating",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(resourceID),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:       resourceID,
+						Name:     "resourceName",
+						Type:     "Microsoft.RedHatOpenShift/OpenShiftClusters",
+						Location: "location",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState: api.ProvisioningStateCreating,
+						},
+					},
+				})
+				f.AddSubscriptionDocuments(&api.SubscriptionDocument{
+					ID: mockSubID,
+				})
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(resourceID),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:       resourceID,
+						Name:     "resourceName",
+						Type:     "Microsoft.RedHatOpenShift/OpenShiftClusters",
+						Location: "location",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState: api.ProvisioningStateCreating,
+						},
+					},
+				})
+			},
+			mocks: func(manager *mock_cluster.MockInterface, dbOpenShiftClusters database.OpenShiftClusters) {
+				manager.EXPECT().Install(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+					return steps.ErrDraining
+				})
+			},
+		},
 		{
 			name: "StateAdminUpdating success sets the last ProvisioningState, clears LastAdminUpdateError and MaintenanceTask, and has maintenance state none",
 			fixture: func(f *testdatabase.Fixture) {
@@ -347,6 +388,269 @@ func TestBackendTry(t *testing.T) {
 	}
 }
 
+func TestBackendTryDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := fmt.Sprintf("/subscriptions/%s/resourcegroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/resourceName", mockSubID)
+	key := strings.ToLower(resourceID)
+	asyncOperationID := "11111111-1111-1111-1111-111111111111"
+
+	for _, tt := range []struct {
+		name          string
+		dequeues      int
+		deadLetter    *api.DeadLetter
+		wantDequeues  int
+		wantLastError string
+	}{
+		{
+			name:         "dequeued too many times",
+			dequeues:     maxDequeueCount,
+			wantDequeues: maxDequeueCount + 1,
+		},
+		{
+			name:     "retried while dead lettered",
+			dequeues: maxDequeueCount + 1,
+			deadLetter: &api.DeadLetter{
+				ProvisioningState: api.ProvisioningStateAdminUpdating,
+				Dequeues:          maxDequeueCount + 1,
+				LastError:         "boom",
+			},
+			wantDequeues:  maxDequeueCount + 2,
+			wantLastError: "boom",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			_env := mock_env.NewMockInterface(controller)
+			_env.EXPECT().LiveConfig().AnyTimes().Return(testliveconfig.NewTestLiveConfig(false, false, false))
+
+			m := mock_metrics.NewMockEmitter(controller)
+			m.EXPECT().EmitGauge("backend.openshiftcluster.deadletter", int64(1), map[string]string{
+				"provisioningState": string(api.ProvisioningStateUpdating),
+			})
+			m.EXPECT().EmitGauge(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+			dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+			dbAsyncOperations, _ := testdatabase.NewFakeAsyncOperations()
+			dbSubscriptions, _ := testdatabase.NewFakeSubscriptions()
+
+			f := testdatabase.NewFixture().WithOpenShiftClusters(dbOpenShiftClusters).WithAsyncOperations(dbAsyncOperations)
+			f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				Key: key,
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: resourceID,
+					Properties: api.OpenShiftClusterProperties{
+						ProvisioningState:     api.ProvisioningStateUpdating,
+						LastProvisioningState: api.ProvisioningStateSucceeded,
+					},
+				},
+				AsyncOperationID: asyncOperationID,
+				Dequeues:         tt.dequeues,
+				DeadLetter:       tt.deadLetter,
+			})
+			f.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+				ID:                  asyncOperationID,
+				OpenShiftClusterKey: key,
+				AsyncOperation: &api.AsyncOperation{
+					InitialProvisioningState: api.ProvisioningStateUpdating,
+					ProvisioningState:        api.ProvisioningStateUpdating,
+				},
+			})
+			err := f.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := newBackend(ctx, logrus.NewEntry(logrus.StandardLogger()), _env, dbAsyncOperations, nil, nil, dbOpenShiftClusters, dbSubscriptions, nil, nil, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b.ocb = &openShiftClusterBackend{
+				backend: b,
+				newManager: func(context.Context, *logrus.Entry, env.Interface, database.OpenShiftClusters, database.Gateway, database.OpenShiftVersions, encryption.AEAD, billing.Manager, *api.OpenShiftClusterDocument, *api.SubscriptionDocument, hive.ClusterManager, metrics.Emitter) (cluster.Interface, error) {
+					t.Fatal("dead lettered document was worked")
+					return nil, nil
+				},
+			}
+
+			worked, err := b.ocb.try(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !worked {
+				t.Fatal("didnt do work")
+			}
+
+			doc, err := dbOpenShiftClusters.Get(ctx, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if doc.OpenShiftCluster.Properties.ProvisioningState != api.ProvisioningStateFailed ||
+				doc.OpenShiftCluster.Properties.FailedProvisioningState != api.ProvisioningStateUpdating {
+				t.Errorf("unexpected provisioning state %s (failed %s)", doc.OpenShiftCluster.Properties.ProvisioningState, doc.OpenShiftCluster.Properties.FailedProvisioningState)
+			}
+			if doc.DeadLetter == nil ||
+				doc.DeadLetter.ProvisioningState != api.ProvisioningStateUpdating ||
+				doc.DeadLetter.Dequeues != tt.wantDequeues ||
+				doc.DeadLetter.LastError != tt.wantLastError {
+				t.Errorf("unexpected dead letter %#v", doc.DeadLetter)
+			}
+			if doc.Dequeues != tt.wantDequeues {
+				t.Errorf("unexpected dequeues %d", doc.Dequeues)
+			}
+			if doc.LeaseOwner != "" || doc.AsyncOperationID != "" {
+				t.Errorf("unexpected lease owner %q, async operation %q", doc.LeaseOwner, doc.AsyncOperationID)
+			}
+
+			asyncdoc, err := dbAsyncOperations.Get(ctx, asyncOperationID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if asyncdoc.AsyncOperation.ProvisioningState != api.ProvisioningStateFailed ||
+				asyncdoc.AsyncOperation.EndTime == nil ||
+				asyncdoc.AsyncOperation.Error == nil {
+				t.Errorf("unexpected async operation %#v", asyncdoc.AsyncOperation)
+			}
+		})
+	}
+}
+
+func TestBackendDequeue(t *testing.T) {
+	ctx := context.Background()
+
+	newDocument := func(subscriptionID, name string, provisioningState api.ProvisioningState) *api.OpenShiftClusterDocument {
+		resourceID := fmt.Sprintf("/subscriptions/%s/resourcegroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/%s", subscriptionID, name)
+		return &api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: provisioningState,
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name                string
+		docs                []*api.OpenShiftClusterDocument
+		clusterWorkers      map[api.ProvisioningState]int
+		subscriptionWorkers map[string]int
+		wantName            string
+	}{
+		{
+			name: "deletes are dequeued before creates",
+			docs: []*api.OpenShiftClusterDocument{
+				newDocument("00000000-0000-0000-0000-000000000000", "creating", api.ProvisioningStateCreating),
+				newDocument("00000000-0000-0000-0000-000000000000", "deleting", api.ProvisioningStateDeleting),
+			},
+			wantName: "deleting",
+		},
+		{
+			name: "admin updates are dequeued before creates",
+			docs: []*api.OpenShiftClusterDocument{
+				newDocument("00000000-0000-0000-0000-000000000000", "creating", api.ProvisioningStateCreating),
+				newDocument("00000000-0000-0000-0000-000000000000", "adminupdating", api.ProvisioningStateAdminUpdating),
+			},
+			wantName: "adminupdating",
+		},
+		{
+			name: "states with fewer workers are preferred",
+			docs: []*api.OpenShiftClusterDocument{
+				newDocument("00000000-0000-0000-0000-000000000000", "creating", api.ProvisioningStateCreating),
+				newDocument("00000000-0000-0000-0000-000000000000", "updating", api.ProvisioningStateUpdating),
+			},
+			clusterWorkers: map[api.ProvisioningState]int{
+				api.ProvisioningStateCreating: 50,
+			},
+			wantName: "updating",
+		},
+		{
+			name: "states may use the shared workers",
+			docs: []*api.OpenShiftClusterDocument{
+				newDocument("00000000-0000-0000-0000-000000000000", "creating", api.ProvisioningStateCreating),
+			},
+			clusterWorkers: map[api.ProvisioningState]int{
+				api.ProvisioningStateCreating: 69,
+			},
+			wantName: "creating",
+		},
+		{
+			name: "states may not use the workers reserved for other states",
+			docs: []*api.OpenShiftClusterDocument{
+				newDocument("00000000-0000-0000-0000-000000000000", "creating", api.ProvisioningStateCreating),
+			},
+			clusterWorkers: map[api.ProvisioningState]int{
+				api.ProvisioningStateCreating: 70,
+			},
+		},
+		{
+			name: "states may use their reserved workers when the shared workers are in use",
+			docs: []*api.OpenShiftClusterDocument{
+				newDocument("00000000-0000-0000-0000-000000000000", "creating", api.ProvisioningStateCreating),
+				newDocument("00000000-0000-0000-0000-000000000000", "deleting", api.ProvisioningStateDeleting),
+			},
+			clusterWorkers: map[api.ProvisioningState]int{
+				api.ProvisioningStateCreating: 70,
+				api.ProvisioningStateDeleting: 9,
+			},
+			wantName: "deleting",
+		},
+		{
+			name: "subscriptions with fewer workers are preferred",
+			docs: []*api.OpenShiftClusterDocument{
+				newDocument("00000000-0000-0000-0000-000000000000", "busy", api.ProvisioningStateCreating),
+				newDocument("11111111-1111-1111-1111-111111111111", "idle", api.ProvisioningStateCreating),
+			},
+			subscriptionWorkers: map[string]int{
+				"00000000-0000-0000-0000-000000000000": 3,
+			},
+			wantName: "idle",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+
+			f := testdatabase.NewFixture().WithOpenShiftClusters(dbOpenShiftClusters)
+			f.AddOpenShiftClusterDocuments(tt.docs...)
+			err := f.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b := &backend{
+				dbOpenShiftClusters: dbOpenShiftClusters,
+				clusterWorkers:      map[api.ProvisioningState]int{},
+				subscriptionWorkers: map[string]int{},
+			}
+			for k, v := range tt.clusterWorkers {
+				b.clusterWorkers[k] = v
+			}
+			for k, v := range tt.subscriptionWorkers {
+				b.subscriptionWorkers[k] = v
+			}
+
+			doc, err := newOpenShiftClusterBackend(b).dequeue(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var name string
+			if doc != nil {
+				name = doc.OpenShiftCluster.ID[strings.LastIndex(doc.OpenShiftCluster.ID, "/")+1:]
+			}
+			if name != tt.wantName {
+				t.Errorf("got %q, wanted %q", name, tt.wantName)
+			}
+		})
+	}
+}
+
 func TestAsyncOperationResultLog(t *testing.T) {
 	for _, tt := range []struct {
 		name                     string
@@ -52,6 +52,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action fixInfraID-fm]",
 				"[Action startVMs-fm]",
 				"[Condition apiServersReady-fm, timeout 30m0s]",
+				"[Action ensureAlertIngestionToken-fm]",
 				"[Action initializeOperatorDeployer-fm]",
 				"[Action ensureAROOperator-fm]",
 				"[Condition aroDeploymentReady-fm, timeout 20m0s]",
@@ -75,6 +76,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action fixInfraID-fm]",
 				"[Action startVMs-fm]",
 				"[Condition apiServersReady-fm, timeout 30m0s]",
+				"[Action ensureAlertIngestionToken-fm]",
 				"[Action initializeOperatorDeployer-fm]",
 			},
 		},
@@ -95,6 +97,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action fixInfraID-fm]",
 				"[Action startVMs-fm]",
 				"[Condition apiServersReady-fm, timeout 30m0s]",
+				"[Action ensureAlertIngestionToken-fm]",
 				"[Action initializeOperatorDeployer-fm]",
 				"[Action ensureAROOperator-fm]",
 				"[Condition aroDeploymentReady-fm, timeout 20m0s]",
@@ -135,6 +138,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action configureIngressCertificate-fm]",
 				"[Action populateRegistryStorageAccountName-fm]",
 				"[Action ensureMTUSize-fm]",
+				"[Action ensureAlertIngestionToken-fm]",
 				"[Action initializeOperatorDeployer-fm]",
 				"[Action ensureAROOperator-fm]",
 				"[Condition aroDeploymentReady-fm, timeout 20m0s]",
@@ -181,6 +185,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action configureIngressCertificate-fm]",
 				"[Action populateRegistryStorageAccountName-fm]",
 				"[Action ensureMTUSize-fm]",
+				"[Action ensureAlertIngestionToken-fm]",
 				"[Action initializeOperatorDeployer-fm]",
 				"[Action hiveCreateNamespace-fm]",
 				"[Action hiveEnsureResources-fm]",
@@ -223,6 +228,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action configureIngressCertificate-fm]",
 				"[Action populateRegistryStorageAccountName-fm]",
 				"[Action ensureMTUSize-fm]",
+				"[Action ensureAlertIngestionToken-fm]",
 				"[Action initializeOperatorDeployer-fm]",
 				"[Action ensureAROOperator-fm]",
 				"[Condition aroDeploymentReady-fm, timeout 20m0s]",
@@ -264,6 +270,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action configureIngressCertificate-fm]",
 				"[Action populateRegistryStorageAccountName-fm]",
 				"[Action ensureMTUSize-fm]",
+				"[Action ensureAlertIngestionToken-fm]",
 				"[Action initializeOperatorDeployer-fm]",
 				"[Action ensureAROOperator-fm]",
 				"[Condition aroDeploymentReady-fm, timeout 20m0s]",
@@ -300,6 +307,25 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action renewMDSDCertificate-fm]",
 			},
 		},
+		{
+			name: "Resize machines",
+			fixture: func() (*api.OpenShiftClusterDocument, bool) {
+				doc := baseClusterDoc()
+				doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateAdminUpdating
+				doc.OpenShiftCluster.Properties.MaintenanceTask = api.MaintenanceTaskResizeMachines
+				return doc, true
+			},
+			shouldRunSteps: []string{
+				"[Action initializeKubernetesClients-fm]",
+				"[Action ensureBillingRecord-fm]",
+				"[Action ensureDefaults-fm]",
+				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
+				"[Action fixInfraID-fm]",
+				"[Action startVMs-fm]",
+				"[Condition apiServersReady-fm, timeout 30m0s]",
+				"[Action resizeMachines-fm]",
+			},
+		},
 		{
 			name: "adminUpdate() does not adopt Hive-created clusters",
 			fixture: func() (*api.OpenShiftClusterDocument, bool) {
@@ -336,6 +362,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action configureIngressCertificate-fm]",
 				"[Action populateRegistryStorageAccountName-fm]",
 				"[Action ensureMTUSize-fm]",
+				"[Action ensureAlertIngestionToken-fm]",
 				"[Action initializeOperatorDeployer-fm]",
 				"[Action ensureAROOperator-fm]",
 				"[Condition aroDeploymentReady-fm, timeout 20m0s]",
@@ -0,0 +1,37 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// mutateAlertIngestionToken generates the token which the ARO operator
+// presents when forwarding platform alerts to the RP
+func mutateAlertIngestionToken(doc *api.OpenShiftClusterDocument) error {
+	if doc.OpenShiftCluster.Properties.AlertIngestionToken != "" {
+		return nil
+	}
+
+	token, err := randomString("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", 64)
+	if err != nil {
+		return err
+	}
+
+	doc.OpenShiftCluster.Properties.AlertIngestionToken = api.SecureString(token)
+
+	return nil
+}
+
+func (m *manager) ensureAlertIngestionToken(ctx context.Context) error {
+	updatedDoc, err := m.db.PatchWithLease(ctx, m.doc.Key, mutateAlertIngestionToken)
+	if err != nil {
+		return err
+	}
+	m.doc = updatedDoc
+
+	return nil
+}
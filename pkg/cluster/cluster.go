@@ -69,6 +69,7 @@ type manager struct {
 	spGraphClient         *utilgraph.GraphServiceClient
 	disks                 compute.DisksClient
 	virtualMachines       compute.VirtualMachinesClient
+	resourceSkus          compute.ResourceSkusClient
 	interfaces            network.InterfacesClient
 	publicIPAddresses     network.PublicIPAddressesClient
 	loadBalancers         network.LoadBalancersClient
@@ -164,6 +165,7 @@ func New(ctx context.Context, log *logrus.Entry, _env env.Interface, db database
 		metricsEmitter:        metricsEmitter,
 		disks:                 compute.NewDisksClient(_env.Environment(), r.SubscriptionID, fpAuthorizer),
 		virtualMachines:       compute.NewVirtualMachinesClient(_env.Environment(), r.SubscriptionID, fpAuthorizer),
+		resourceSkus:          compute.NewResourceSkusClient(_env.Environment(), r.SubscriptionID, fpAuthorizer),
 		interfaces:            network.NewInterfacesClient(_env.Environment(), r.SubscriptionID, fpAuthorizer),
 		publicIPAddresses:     network.NewPublicIPAddressesClient(_env.Environment(), r.SubscriptionID, fpAuthorizer),
 		loadBalancers:         network.NewLoadBalancersClient(_env.Environment(), r.SubscriptionID, fpAuthorizer),
@@ -8,12 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"sort"
 	"strings"
 	"time"
 
 	mgmtnetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-08-01/network"
-	mgmtfeatures "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-07-01/features"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -22,7 +20,6 @@ import (
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 	"github.com/Azure/ARO-RP/pkg/env"
 	"github.com/Azure/ARO-RP/pkg/util/acrtoken"
-	"github.com/Azure/ARO-RP/pkg/util/azureclient"
 	"github.com/Azure/ARO-RP/pkg/util/azureerrors"
 	"github.com/Azure/ARO-RP/pkg/util/dns"
 	"github.com/Azure/ARO-RP/pkg/util/rbac"
@@ -151,115 +148,9 @@ func (m *manager) disconnectSecurityGroup(ctx context.Context, resourceID string
 	return nil
 }
 
-// deleteOrder maps resource types to the deletion level.  We walk the levels
-// from lowest to highest, deleting all the resources in the given level in
-// parallel and waiting for completion before we proceed.  Any type not in the
-// map is considered to be at level 0.  Keys must be lower case.
-var deleteOrder = map[string]int{
-	"microsoft.compute/virtualmachines":                 -3, // first, and before microsoft.compute/disks, microsoft.network/networkinterfaces
-	"microsoft.network/privatelinkservices":             -3, // before microsoft.network/loadbalancers
-	"microsoft.network/privateendpoints":                -3, // before microsoft.network/networkinterfaces
-	"microsoft.compute/galleries/applications/versions": -2, // before microsoft.compute/galleries/applications
-	"microsoft.compute/galleries/images/versions":       -2, // before microsoft.compute/galleries/images
-	"microsoft.compute/galleries/applications":          -1, // before microsoft.compute/galleries
-	"microsoft.compute/galleries/images":                -1, // before microsoft.compute/galleries
-	"microsoft.compute/galleries/serviceArtifacts":      -1, // before microsoft.compute/galleries
-	"microsoft.network/networkinterfaces":               -1, // before microsoft.network/loadbalancers
-	"microsoft.network/privatednszones":                 1,  // after everything else: get other deletions underway first
-	"microsoft.compute/galleries":                       1,  // after everything else in case there are nested microsoft.compute/galleries resources
-}
-
-func (m *manager) deleteResources(ctx context.Context) error {
-	resourceGroup := stringutils.LastTokenByte(m.doc.OpenShiftCluster.Properties.ClusterProfile.ResourceGroupID, '/')
-
-	resources, err := m.resources.ListByResourceGroup(ctx, resourceGroup, "", "", nil)
-	if detailedErr, ok := err.(autorest.DetailedError); ok &&
-		(detailedErr.StatusCode == http.StatusNotFound ||
-			detailedErr.StatusCode == http.StatusForbidden) {
-		return nil
-	}
-	if err != nil {
-		return err
-	}
-
-	// group our resources by level
-	resourceMap := map[int][]*mgmtfeatures.GenericResourceExpanded{}
-	for i, resource := range resources {
-		level := deleteOrder[strings.ToLower(*resource.Type)]
-		resourceMap[level] = append(resourceMap[level], &resources[i])
-	}
-
-	levels := make([]int, 0, len(resourceMap))
-	for level := range resourceMap {
-		levels = append(levels, level)
-	}
-	sort.Ints(levels)
-
-	for _, level := range levels {
-		// ensure that resource deletion order is deterministic
-		sort.Slice(resourceMap[level], func(i, j int) bool {
-			return strings.Compare(
-				strings.ToLower(*resourceMap[level][i].ID),
-				strings.ToLower(*resourceMap[level][j].ID)) < 0
-		})
-
-		// asynchronously delete all resources in the level
-		futures := make([]mgmtfeatures.ResourcesDeleteByIDFuture, 0, len(resourceMap[level]))
-		for _, resource := range resourceMap[level] {
-			apiVersion := azureclient.APIVersion(*resource.Type)
-			if apiVersion == "" {
-				m.log.Warnf("skipping resource %s", *resource.ID)
-				continue
-			}
-
-			switch strings.ToLower(*resource.Type) {
-			case "microsoft.network/networksecuritygroups":
-				m.log.Printf("disconnecting network security group %s", *resource.ID)
-				err = m.disconnectSecurityGroup(ctx, *resource.ID)
-				if err != nil {
-					return err
-				}
-
-			case "microsoft.network/privatednszones":
-				m.log.Printf("deleting private DNS nested resources of %s", *resource.ID)
-				err = m.deletePrivateDNSVirtualNetworkLinks(ctx, *resource.ID)
-				if err != nil {
-					return err
-				}
-
-			case "microsoft.network/networkinterfaces":
-				err = m.deleteNic(ctx, *resource.Name)
-				if err != nil {
-					return err
-				}
-			}
-
-			m.log.Printf("deleting %s", *resource.ID)
-			future, err := m.resources.DeleteByID(ctx, *resource.ID, apiVersion)
-			if err != nil {
-				return deleteByIdCloudError(err)
-			}
-
-			futures = append(futures, future)
-		}
-
-		// wait for all the deletions to complete
-		for i, future := range futures {
-			m.log.Printf("waiting for deletion of %s", *resourceMap[level][i].ID)
-
-			err = future.WaitForCompletionRef(ctx, m.resources.Client())
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
-}
-
 func deleteByIdCloudError(err error) error {
 	detailedError, ok := err.(autorest.DetailedError)
-	if !ok {
+	if !ok || detailedError.Original == nil {
 		return err
 	}
 	switch {
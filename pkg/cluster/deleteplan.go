@@ -0,0 +1,380 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	mgmtfeatures "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-07-01/features"
+	"github.com/Azure/go-autorest/autorest"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+	"github.com/Azure/ARO-RP/pkg/util/azureerrors"
+	"github.com/Azure/ARO-RP/pkg/util/stringutils"
+)
+
+// deleteDependencies maps resource types to the resource types which must be
+// deleted before them.  A resource is deleted as soon as no resource of a type
+// it depends on remains.  Any type not in the map depends on
+// microsoft.compute/virtualmachines.  Keys and values must be lower case.
+var deleteDependencies = map[string][]string{
+	"microsoft.compute/virtualmachines":                 nil,
+	"microsoft.network/privatelinkservices":             nil,
+	"microsoft.network/privateendpoints":                nil,
+	"microsoft.compute/galleries/applications/versions": nil,
+	"microsoft.compute/galleries/images/versions":       nil,
+	"microsoft.compute/disks":                           {"microsoft.compute/virtualmachines"},
+	"microsoft.network/networkinterfaces":               {"microsoft.compute/virtualmachines", "microsoft.network/privateendpoints"},
+	"microsoft.network/loadbalancers":                   {"microsoft.network/networkinterfaces", "microsoft.network/privatelinkservices"},
+	"microsoft.network/publicipaddresses":               {"microsoft.network/loadbalancers", "microsoft.network/networkinterfaces"},
+	"microsoft.network/networksecuritygroups":           {"microsoft.network/networkinterfaces"},
+	"microsoft.compute/galleries/applications":          {"microsoft.compute/galleries/applications/versions"},
+	"microsoft.compute/galleries/images":                {"microsoft.compute/galleries/images/versions"},
+	"microsoft.compute/galleries":                       {"microsoft.compute/galleries/applications", "microsoft.compute/galleries/images", "microsoft.compute/galleries/serviceartifacts"},
+}
+
+// deleteLast lists the resource types which depend on every other resource in
+// the resource group: we get the other deletions underway first.
+var deleteLast = map[string]bool{
+	"microsoft.network/privatednszones": true,
+}
+
+const (
+	deleteMaxAttempts   = 5
+	deleteRetryInterval = 30 * time.Second
+)
+
+// deletePlanItem is a resource in the cluster resource group which is to be
+// deleted
+type deletePlanItem struct {
+	resource   *mgmtfeatures.GenericResourceExpanded
+	apiVersion string
+	dependsOn  []*deletePlanItem
+
+	deleted     bool
+	attempts    int
+	nextAttempt time.Time
+	err         error
+}
+
+// deletePlan orders the deletion of the resources in the cluster resource
+// group by their dependencies.  Deletions which Azure refuses are retried
+// once their retry interval has elapsed, while other deletions make progress;
+// if a resource still cannot be deleted, the plan reports it and the resources
+// which are waiting for it.
+type deletePlan struct {
+	items         []*deletePlanItem
+	maxAttempts   int
+	retryInterval time.Duration
+}
+
+func newDeletePlan(resources []mgmtfeatures.GenericResourceExpanded) *deletePlan {
+	p := &deletePlan{
+		items:         make([]*deletePlanItem, 0, len(resources)),
+		maxAttempts:   deleteMaxAttempts,
+		retryInterval: deleteRetryInterval,
+	}
+
+	byType := map[string][]*deletePlanItem{}
+	for i := range resources {
+		item := &deletePlanItem{
+			resource:   &resources[i],
+			apiVersion: azureclient.APIVersion(*resources[i].Type),
+		}
+		p.items = append(p.items, item)
+
+		t := strings.ToLower(*item.resource.Type)
+		byType[t] = append(byType[t], item)
+	}
+
+	// ensure that resource deletion order is deterministic
+	sort.Slice(p.items, func(i, j int) bool {
+		return strings.Compare(
+			strings.ToLower(*p.items[i].resource.ID),
+			strings.ToLower(*p.items[j].resource.ID)) < 0
+	})
+
+	for _, item := range p.items {
+		t := strings.ToLower(*item.resource.Type)
+
+		if deleteLast[t] {
+			for _, other := range p.items {
+				if !deleteLast[strings.ToLower(*other.resource.Type)] {
+					item.dependsOn = append(item.dependsOn, other)
+				}
+			}
+			continue
+		}
+
+		dependencies, found := deleteDependencies[t]
+		if !found {
+			dependencies = []string{"microsoft.compute/virtualmachines"}
+		}
+
+		for _, dependency := range dependencies {
+			item.dependsOn = append(item.dependsOn, byType[dependency]...)
+		}
+	}
+
+	return p
+}
+
+// ready returns the items which can be deleted at now: those which are not
+// yet deleted, have attempts remaining, are not waiting to retry a failed
+// attempt and whose dependencies are all deleted
+func (p *deletePlan) ready(now time.Time) []*deletePlanItem {
+	var ready []*deletePlanItem
+
+	for _, item := range p.items {
+		if item.deleted || item.attempts >= p.maxAttempts || item.nextAttempt.After(now) {
+			continue
+		}
+
+		blocked := false
+		for _, dependency := range item.dependsOn {
+			if !dependency.deleted {
+				blocked = true
+				break
+			}
+		}
+
+		if !blocked {
+			ready = append(ready, item)
+		}
+	}
+
+	return ready
+}
+
+// nextAttempt returns the earliest time after now at which an item may retry
+// a failed attempt, or the zero time if no item is waiting to do so
+func (p *deletePlan) nextAttempt(now time.Time) time.Time {
+	var next time.Time
+
+	for _, item := range p.items {
+		if item.deleted || item.attempts >= p.maxAttempts || !item.nextAttempt.After(now) {
+			continue
+		}
+
+		if next.IsZero() || item.nextAttempt.Before(next) {
+			next = item.nextAttempt
+		}
+	}
+
+	return next
+}
+
+// blockedBy returns the item which has run out of attempts that the given
+// item is (transitively) waiting for
+func (item *deletePlanItem) blockedBy() *deletePlanItem {
+	for _, dependency := range item.dependsOn {
+		if dependency.deleted {
+			continue
+		}
+		if dependency.err != nil {
+			return dependency
+		}
+		if blocker := dependency.blockedBy(); blocker != nil {
+			return blocker
+		}
+	}
+
+	return nil
+}
+
+func (m *manager) deleteResources(ctx context.Context) error {
+	resourceGroup := stringutils.LastTokenByte(m.doc.OpenShiftCluster.Properties.ClusterProfile.ResourceGroupID, '/')
+
+	resources, err := m.resources.ListByResourceGroup(ctx, resourceGroup, "", "", nil)
+	if detailedErr, ok := err.(autorest.DetailedError); ok &&
+		(detailedErr.StatusCode == http.StatusNotFound ||
+			detailedErr.StatusCode == http.StatusForbidden) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return m.executeDeletePlan(ctx, newDeletePlan(resources))
+}
+
+func (m *manager) executeDeletePlan(ctx context.Context, p *deletePlan) error {
+	for _, item := range p.items {
+		if item.apiVersion == "" {
+			m.log.Warnf("skipping resource %s", *item.resource.ID)
+			item.deleted = true
+		}
+	}
+
+	for {
+		now := time.Now()
+
+		ready := p.ready(now)
+		if len(ready) == 0 {
+			// wait for the next failed deletion to become due for retry, if
+			// any is; otherwise every remaining item is out of attempts or
+			// waiting for one which is
+			next := p.nextAttempt(now)
+			if next.IsZero() {
+				break
+			}
+
+			select {
+			case <-time.After(next.Sub(now)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+
+		// asynchronously delete all the resources which are ready
+		errs := make([]error, len(ready))
+		var g errgroup.Group
+		for i, item := range ready {
+			i, item := i, item // https://golang.org/doc/faq#closures_and_goroutines
+			g.Go(func() error {
+				errs[i] = m.deletePlanItem(ctx, item)
+				return nil
+			})
+		}
+		_ = g.Wait()
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		for i, item := range ready {
+			item.attempts++
+
+			if errs[i] == nil || azureerrors.IsNotFoundError(errs[i]) {
+				item.deleted = true
+				item.err = nil
+				continue
+			}
+
+			item.err = errs[i]
+			item.nextAttempt = time.Now().Add(p.retryInterval)
+			m.log.Warnf("deletion of %s failed (attempt %d of %d): %s", *item.resource.ID, item.attempts, p.maxAttempts, item.err)
+		}
+	}
+
+	return p.blockedError()
+}
+
+func (m *manager) deletePlanItem(ctx context.Context, item *deletePlanItem) error {
+	switch strings.ToLower(*item.resource.Type) {
+	case "microsoft.network/networksecuritygroups":
+		m.log.Printf("disconnecting network security group %s", *item.resource.ID)
+		err := m.disconnectSecurityGroup(ctx, *item.resource.ID)
+		if err != nil {
+			return err
+		}
+
+	case "microsoft.network/privatednszones":
+		m.log.Printf("deleting private DNS nested resources of %s", *item.resource.ID)
+		err := m.deletePrivateDNSVirtualNetworkLinks(ctx, *item.resource.ID)
+		if err != nil {
+			return err
+		}
+
+	case "microsoft.network/networkinterfaces":
+		m.log.Printf("deleting %s", *item.resource.ID)
+		return m.deleteNic(ctx, *item.resource.Name)
+	}
+
+	m.log.Printf("deleting %s", *item.resource.ID)
+	return m.resources.DeleteByIDAndWait(ctx, *item.resource.ID, item.apiVersion)
+}
+
+// blockedError returns nil if every resource in the plan was deleted.
+// Otherwise, if the first resource which could not be deleted failed for a
+// reason the customer can act on, it returns a CloudError which names every
+// such resource and the resources which are waiting for them.
+func (p *deletePlan) blockedError() error {
+	var blocked, waiting []*deletePlanItem
+	for _, item := range p.items {
+		switch {
+		case item.deleted:
+		case item.err != nil:
+			blocked = append(blocked, item)
+		default:
+			waiting = append(waiting, item)
+		}
+	}
+
+	if len(blocked) == 0 {
+		return nil
+	}
+
+	first := blocked[0]
+	cloudErr := deleteBlockedCloudError(first.err)
+	if cloudErr == nil {
+		return fmt.Errorf("deleting %s: %w", *first.resource.ID, first.err)
+	}
+
+	details := make([]api.CloudErrorBody, 0, len(blocked)+len(waiting))
+	for _, item := range blocked {
+		detail := api.CloudErrorBody{
+			Code:    api.CloudErrorCodeDeletionBlocked,
+			Target:  *item.resource.ID,
+			Message: item.err.Error(),
+		}
+		if itemErr := deleteBlockedCloudError(item.err); itemErr != nil {
+			detail.Code = itemErr.Code
+			detail.Message = itemErr.Message
+		}
+		details = append(details, detail)
+	}
+
+	for _, item := range waiting {
+		blocker := item.blockedBy()
+		if blocker == nil {
+			continue
+		}
+		details = append(details, api.CloudErrorBody{
+			Code:    api.CloudErrorCodeDeletionBlocked,
+			Target:  *item.resource.ID,
+			Message: fmt.Sprintf("The resource is waiting for the deletion of '%s'.", *blocker.resource.ID),
+		})
+	}
+
+	return &api.CloudError{
+		StatusCode: cloudErr.StatusCode,
+		CloudErrorBody: &api.CloudErrorBody{
+			Code:    cloudErr.Code,
+			Target:  *first.resource.ID,
+			Message: fmt.Sprintf("The resource '%s' in the cluster resource group could not be deleted: %s", *first.resource.ID, cloudErr.Message),
+			Details: details,
+		},
+	}
+}
+
+// deleteBlockedCloudError returns the CloudError describing why Azure refused
+// to delete a resource, or nil if the failure is not one the customer can act
+// on
+func deleteBlockedCloudError(err error) *api.CloudError {
+	if cloudErr, ok := deleteByIdCloudError(err).(*api.CloudError); ok {
+		return cloudErr
+	}
+
+	detailedErr, ok := err.(autorest.DetailedError)
+	if !ok || detailedErr.Original == nil {
+		return nil
+	}
+
+	statusCode, ok := detailedErr.StatusCode.(int)
+	if !ok || statusCode < http.StatusBadRequest || statusCode >= http.StatusInternalServerError {
+		return nil
+	}
+
+	return api.NewCloudError(statusCode, api.CloudErrorCodeDeletionBlocked,
+		"features.ResourcesClient#DeleteByID", detailedErr.Original.Error())
+}
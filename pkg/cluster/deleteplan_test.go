@@ -0,0 +1,226 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	mgmtfeatures "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-07-01/features"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	mock_features "github.com/Azure/ARO-RP/pkg/util/mocks/azureclient/mgmt/features"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestExecuteDeletePlan(t *testing.T) {
+	ctx := context.Background()
+	resourceGroupID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/cluster-rg"
+
+	vm := resourceGroupID + "/providers/Microsoft.Compute/virtualMachines/master-0"
+	disk := resourceGroupID + "/providers/Microsoft.Compute/disks/master-0_OSDisk"
+	lb := resourceGroupID + "/providers/Microsoft.Network/loadBalancers/cluster-internal"
+	pip := resourceGroupID + "/providers/Microsoft.Network/publicIPAddresses/cluster-pip-v4"
+
+	resources := []mgmtfeatures.GenericResourceExpanded{
+		{ID: to.StringPtr(pip), Type: to.StringPtr("Microsoft.Network/publicIPAddresses")},
+		{ID: to.StringPtr(lb), Type: to.StringPtr("Microsoft.Network/loadBalancers")},
+		{ID: to.StringPtr(disk), Type: to.StringPtr("Microsoft.Compute/disks")},
+		{ID: to.StringPtr(vm), Type: to.StringPtr("Microsoft.Compute/virtualMachines")},
+		{ID: to.StringPtr(resourceGroupID + "/providers/Microsoft.Unknown/things/thing"), Type: to.StringPtr("Microsoft.Unknown/things")},
+	}
+
+	inUseMessage := `Code="LoadBalancerInUseByVirtualMachineScaleSet" Message="Cannot delete load balancer since it is in use by a customer resource."`
+	inUse := autorest.DetailedError{
+		StatusCode: http.StatusBadRequest,
+		Original:   errors.New(inUseMessage),
+	}
+
+	lockedMessage := `Code="ScopeLocked" Message="The scope cannot perform delete operation because following scope(s) are locked."`
+	locked := autorest.DetailedError{
+		StatusCode: http.StatusConflict,
+		Original:   errors.New(lockedMessage),
+	}
+
+	internal := autorest.DetailedError{
+		StatusCode: http.StatusInternalServerError,
+		Original:   errors.New("random error"),
+	}
+
+	for _, tt := range []struct {
+		name         string
+		mocks        func(*mock_features.MockResourcesClient)
+		wantErr      string
+		wantCloudErr *api.CloudError
+	}{
+		{
+			name: "resources are deleted after the resources they depend on",
+			mocks: func(resources *mock_features.MockResourcesClient) {
+				deleteVM := resources.EXPECT().DeleteByIDAndWait(gomock.Any(), vm, gomock.Any()).Return(nil)
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), disk, gomock.Any()).After(deleteVM).Return(nil)
+				deleteLB := resources.EXPECT().DeleteByIDAndWait(gomock.Any(), lb, gomock.Any()).Return(nil)
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), pip, gomock.Any()).After(deleteLB).Return(nil)
+			},
+		},
+		{
+			name: "blocked deletion is retried",
+			mocks: func(resources *mock_features.MockResourcesClient) {
+				deleteVM := resources.EXPECT().DeleteByIDAndWait(gomock.Any(), vm, gomock.Any()).Return(nil)
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), disk, gomock.Any()).After(deleteVM).Return(nil)
+				deleteLBBlocked := resources.EXPECT().DeleteByIDAndWait(gomock.Any(), lb, gomock.Any()).Return(inUse)
+				deleteLB := resources.EXPECT().DeleteByIDAndWait(gomock.Any(), lb, gomock.Any()).After(deleteLBBlocked).Return(nil)
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), pip, gomock.Any()).After(deleteLB).Return(nil)
+			},
+		},
+		{
+			name: "resource which cannot be deleted is reported with the resources waiting for it",
+			mocks: func(resources *mock_features.MockResourcesClient) {
+				deleteVM := resources.EXPECT().DeleteByIDAndWait(gomock.Any(), vm, gomock.Any()).Return(nil)
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), disk, gomock.Any()).After(deleteVM).Return(nil)
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), lb, gomock.Any()).Times(2).Return(inUse)
+			},
+			wantCloudErr: &api.CloudError{
+				StatusCode: http.StatusBadRequest,
+				CloudErrorBody: &api.CloudErrorBody{
+					Code:    api.CloudErrorCodeDeletionBlocked,
+					Target:  lb,
+					Message: fmt.Sprintf("The resource '%s' in the cluster resource group could not be deleted: %s", lb, inUseMessage),
+					Details: []api.CloudErrorBody{
+						{
+							Code:    api.CloudErrorCodeDeletionBlocked,
+							Target:  lb,
+							Message: inUseMessage,
+						},
+						{
+							Code:    api.CloudErrorCodeDeletionBlocked,
+							Target:  pip,
+							Message: fmt.Sprintf("The resource is waiting for the deletion of '%s'.", lb),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "locked resource is reported as scope locked",
+			mocks: func(resources *mock_features.MockResourcesClient) {
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), vm, gomock.Any()).Times(2).Return(locked)
+				deleteLB := resources.EXPECT().DeleteByIDAndWait(gomock.Any(), lb, gomock.Any()).Return(nil)
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), pip, gomock.Any()).After(deleteLB).Return(nil)
+			},
+			wantCloudErr: &api.CloudError{
+				StatusCode: http.StatusConflict,
+				CloudErrorBody: &api.CloudErrorBody{
+					Code:    api.CloudErrorCodeScopeLocked,
+					Target:  vm,
+					Message: fmt.Sprintf("The resource '%s' in the cluster resource group could not be deleted: %s", vm, lockedMessage),
+					Details: []api.CloudErrorBody{
+						{
+							Code:    api.CloudErrorCodeScopeLocked,
+							Target:  vm,
+							Message: lockedMessage,
+						},
+						{
+							Code:    api.CloudErrorCodeDeletionBlocked,
+							Target:  disk,
+							Message: fmt.Sprintf("The resource is waiting for the deletion of '%s'.", vm),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "server error is not reported to the customer",
+			mocks: func(resources *mock_features.MockResourcesClient) {
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), vm, gomock.Any()).Times(2).Return(internal)
+				deleteLB := resources.EXPECT().DeleteByIDAndWait(gomock.Any(), lb, gomock.Any()).Return(nil)
+				resources.EXPECT().DeleteByIDAndWait(gomock.Any(), pip, gomock.Any()).After(deleteLB).Return(nil)
+			},
+			wantErr: fmt.Sprintf("deleting %s: %s", vm, internal.Error()),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			resourcesClient := mock_features.NewMockResourcesClient(controller)
+			tt.mocks(resourcesClient)
+
+			m := &manager{
+				log:       logrus.NewEntry(logrus.StandardLogger()),
+				resources: resourcesClient,
+			}
+
+			p := newDeletePlan(append([]mgmtfeatures.GenericResourceExpanded(nil), resources...))
+			p.maxAttempts = 2
+			p.retryInterval = 0
+
+			err := m.executeDeletePlan(ctx, p)
+
+			if tt.wantCloudErr != nil {
+				for _, diff := range deep.Equal(err, error(tt.wantCloudErr)) {
+					t.Error(diff)
+				}
+				return
+			}
+
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestExecuteDeletePlanRetryInterval(t *testing.T) {
+	ctx := context.Background()
+	resourceGroupID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/cluster-rg"
+
+	vm := resourceGroupID + "/providers/Microsoft.Compute/virtualMachines/master-0"
+	disk := resourceGroupID + "/providers/Microsoft.Compute/disks/master-0_OSDisk"
+	lb := resourceGroupID + "/providers/Microsoft.Network/loadBalancers/cluster-internal"
+
+	resources := []mgmtfeatures.GenericResourceExpanded{
+		{ID: to.StringPtr(lb), Type: to.StringPtr("Microsoft.Network/loadBalancers")},
+		{ID: to.StringPtr(disk), Type: to.StringPtr("Microsoft.Compute/disks")},
+		{ID: to.StringPtr(vm), Type: to.StringPtr("Microsoft.Compute/virtualMachines")},
+	}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// the load balancer cannot be deleted, but its attempts must be spread
+	// over the retry interval even though the other deletions progress
+	var attempts []time.Time
+	resourcesClient := mock_features.NewMockResourcesClient(controller)
+	deleteVM := resourcesClient.EXPECT().DeleteByIDAndWait(gomock.Any(), vm, gomock.Any()).Return(nil)
+	resourcesClient.EXPECT().DeleteByIDAndWait(gomock.Any(), disk, gomock.Any()).After(deleteVM).Return(nil)
+	resourcesClient.EXPECT().DeleteByIDAndWait(gomock.Any(), lb, gomock.Any()).Times(3).DoAndReturn(func(context.Context, string, string) error {
+		attempts = append(attempts, time.Now())
+		return errors.New("in use")
+	})
+
+	m := &manager{
+		log:       logrus.NewEntry(logrus.StandardLogger()),
+		resources: resourcesClient,
+	}
+
+	p := newDeletePlan(resources)
+	p.maxAttempts = 3
+	p.retryInterval = 20 * time.Millisecond
+
+	err := m.executeDeletePlan(ctx, p)
+	utilerror.AssertErrorMessage(t, err, fmt.Sprintf("deleting %s: in use", lb))
+
+	for i := 1; i < len(attempts); i++ {
+		if gap := attempts[i].Sub(attempts[i-1]); gap < p.retryInterval {
+			t.Errorf("attempt %d made %s after the previous one", i+1, gap)
+		}
+	}
+}
@@ -5,6 +5,7 @@ package cluster
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -47,6 +48,7 @@ func (m *manager) adminUpdate() []steps.Step {
 	isEverything := task == api.MaintenanceTaskEverything || task == ""
 	isOperator := task == api.MaintenanceTaskOperator
 	isRenewCerts := task == api.MaintenanceTaskRenewCerts
+	isResizeMachines := task == api.MaintenanceTaskResizeMachines
 
 	// Generic fix-up or setup actions that are fairly safe to always take, and
 	// don't require a running cluster
@@ -123,6 +125,12 @@ func (m *manager) adminUpdate() []steps.Step {
 		)
 	}
 
+	if isEverything || isOperator {
+		toRun = append(toRun,
+			steps.Action(m.ensureAlertIngestionToken), // must go before initializeOperatorDeployer
+		)
+	}
+
 	if isEverything || isOperator || isRenewCerts {
 		toRun = append(toRun,
 			steps.Action(m.initializeOperatorDeployer))
@@ -134,6 +142,12 @@ func (m *manager) adminUpdate() []steps.Step {
 		)
 	}
 
+	if isResizeMachines {
+		toRun = append(toRun,
+			steps.Action(m.resizeMachines),
+		)
+	}
+
 	// Update the ARO Operator
 	if (isEverything || isOperator) && m.shouldUpdateOperator() {
 		toRun = append(toRun,
@@ -271,6 +285,7 @@ func (m *manager) bootstrap() []steps.Step {
 		steps.Action(m.ensureACRToken),
 		steps.Action(m.ensureInfraID),
 		steps.Action(m.ensureSSHKey),
+		steps.Action(m.ensureAlertIngestionToken),
 		steps.Action(m.ensureStorageSuffix),
 		steps.Action(m.populateMTUSize),
 
@@ -403,7 +418,7 @@ func (m *manager) runSteps(ctx context.Context, s []steps.Step, metricsTopic str
 	} else {
 		_, err = steps.Run(ctx, m.log, 10*time.Second, s, nil)
 	}
-	if err != nil {
+	if err != nil && !errors.Is(err, steps.ErrDraining) {
 		m.gatherFailureLogs(ctx)
 	}
 	return err
@@ -519,7 +534,7 @@ func (m *manager) initializeKubernetesClients(ctx context.Context) error {
 // initializeKubernetesClients initializes clients which are used
 // once the cluster is up later on in the install process.
 func (m *manager) initializeOperatorDeployer(ctx context.Context) (err error) {
-	m.aroOperatorDeployer, err = deploy.New(m.log, m.env, m.doc.OpenShiftCluster, m.arocli, m.client, m.extensionscli, m.kubernetescli)
+	m.aroOperatorDeployer, err = deploy.New(m.log, m.env, m.doc.OpenShiftCluster, m.arocli, m.client, m.extensionscli, m.kubernetescli, m.resourceSkus)
 	return
 }
 
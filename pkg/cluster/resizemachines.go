@@ -0,0 +1,280 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubectl/pkg/drain"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/ready"
+	"github.com/Azure/ARO-RP/pkg/util/stringutils"
+)
+
+const (
+	// masterMachinePool is the MachineSet name given to resize the masters,
+	// which are not managed by a MachineSet
+	masterMachinePool = "master"
+
+	machineAPINamespace = "openshift-machine-api"
+
+	resizeStepCordon    = "Cordon"
+	resizeStepDrain     = "Drain"
+	resizeStepResize    = "Resize"
+	resizeStepWaitReady = "WaitForReady"
+	resizeStepUncordon  = "Uncordon"
+)
+
+var (
+	resizeNodeReadyInterval = 10 * time.Second
+	resizeNodeReadyTimeout  = 30 * time.Minute
+)
+
+// resizeMachines resizes the machines recorded in the MachineResize of the
+// cluster one at a time.  Each node is cordoned and drained before its VM is
+// resized, and is uncordoned once it is ready again.  Progress is recorded as
+// it is made, so that a resize which is interrupted, for example because the
+// backend restarted, or which is requested again after failing, resumes with
+// the machine it was resizing.  The resize
+// stops at the first failure, leaving the failing node cordoned for
+// investigation.
+func (m *manager) resizeMachines(ctx context.Context) error {
+	resize := m.doc.OpenShiftCluster.Properties.MachineResize
+	if resize == nil {
+		return errors.New("no machine resize requested")
+	}
+
+	if !strings.EqualFold(resize.MachineSet, masterMachinePool) {
+		// machines created by the MachineSet from now on get the new size
+		m.log.Infof("updating machineset %s", resize.MachineSet)
+		err := m.setMachineSetVMSize(ctx, resize.MachineSet, resize.VMSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	completed := map[string]struct{}{}
+	for _, machine := range resize.Completed {
+		completed[machine] = struct{}{}
+	}
+
+	for _, machine := range resize.Machines {
+		if _, ok := completed[machine]; ok {
+			continue
+		}
+
+		nodeName, err := m.machineNodeName(ctx, machine)
+		if err != nil {
+			return fmt.Errorf("%s: %w", machine, err)
+		}
+
+		for _, step := range []struct {
+			name string
+			f    func() error
+		}{
+			{
+				name: resizeStepCordon,
+				f:    func() error { return m.cordonNode(ctx, nodeName, true) },
+			},
+			{
+				name: resizeStepDrain,
+				f:    func() error { return m.drainNode(ctx, nodeName) },
+			},
+			{
+				name: resizeStepResize,
+				f:    func() error { return m.resizeMachine(ctx, machine, resize.VMSize) },
+			},
+			{
+				name: resizeStepWaitReady,
+				f:    func() error { return m.waitForNodeResized(ctx, nodeName, resize.VMSize) },
+			},
+			{
+				name: resizeStepUncordon,
+				f:    func() error { return m.cordonNode(ctx, nodeName, false) },
+			},
+		} {
+			err = m.setResizeProgress(ctx, func(resize *api.MachineResize) {
+				resize.Current, resize.Step = machine, step.name
+			})
+			if err != nil {
+				return err
+			}
+
+			m.log.Infof("%s: %s", machine, step.name)
+			err = step.f()
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", machine, step.name, err)
+			}
+		}
+
+		err = m.setResizeProgress(ctx, func(resize *api.MachineResize) {
+			resize.Completed = append(resize.Completed, machine)
+			resize.Current, resize.Step = "", ""
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *manager) setResizeProgress(ctx context.Context, f func(*api.MachineResize)) error {
+	var err error
+	m.doc, err = m.db.PatchWithLease(ctx, m.doc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		if doc.OpenShiftCluster.Properties.MachineResize == nil {
+			return errors.New("no machine resize requested")
+		}
+
+		f(doc.OpenShiftCluster.Properties.MachineResize)
+		return nil
+	})
+	return err
+}
+
+func (m *manager) machineNodeName(ctx context.Context, name string) (string, error) {
+	machine, err := m.maocli.MachineV1beta1().Machines(machineAPINamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	if machine.Status.NodeRef == nil {
+		return "", errors.New("machine has no node")
+	}
+
+	return machine.Status.NodeRef.Name, nil
+}
+
+func (m *manager) setMachineSetVMSize(ctx context.Context, name string, vmSize api.VMSize) error {
+	machineSet, err := m.maocli.MachineV1beta1().MachineSets(machineAPINamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	err = setProviderSpecVMSize(machineSet.Spec.Template.Spec.ProviderSpec.Value, vmSize)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.maocli.MachineV1beta1().MachineSets(machineAPINamespace).Update(ctx, machineSet, metav1.UpdateOptions{})
+	return err
+}
+
+// resizeMachine resizes the VM of the machine, unless a previous attempt has
+// already done so, and records the new size in the machine
+func (m *manager) resizeMachine(ctx context.Context, name string, vmSize api.VMSize) error {
+	resourceGroup := stringutils.LastTokenByte(m.doc.OpenShiftCluster.Properties.ClusterProfile.ResourceGroupID, '/')
+
+	vm, err := m.virtualMachines.Get(ctx, resourceGroup, name, "")
+	if err != nil {
+		return err
+	}
+
+	if vm.HardwareProfile == nil {
+		vm.HardwareProfile = &mgmtcompute.HardwareProfile{}
+	}
+
+	if !strings.EqualFold(string(vm.HardwareProfile.VMSize), string(vmSize)) {
+		vm.HardwareProfile.VMSize = mgmtcompute.VirtualMachineSizeTypes(vmSize)
+
+		err = m.virtualMachines.CreateOrUpdateAndWait(ctx, resourceGroup, name, vm)
+		if err != nil {
+			return err
+		}
+	}
+
+	machine, err := m.maocli.MachineV1beta1().Machines(machineAPINamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	err = setProviderSpecVMSize(machine.Spec.ProviderSpec.Value, vmSize)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.maocli.MachineV1beta1().Machines(machineAPINamespace).Update(ctx, machine, metav1.UpdateOptions{})
+	return err
+}
+
+// setProviderSpecVMSize sets the vmSize of an Azure provider spec, preserving
+// any fields unknown to us
+func setProviderSpecVMSize(value *runtime.RawExtension, vmSize api.VMSize) error {
+	if value == nil {
+		return errors.New("provider spec is missing")
+	}
+
+	var spec map[string]interface{}
+	err := json.Unmarshal(value.Raw, &spec)
+	if err != nil {
+		return err
+	}
+
+	spec["vmSize"] = string(vmSize)
+
+	value.Raw, err = json.Marshal(spec)
+	value.Object = nil
+	return err
+}
+
+func (m *manager) drainHelper(ctx context.Context, timeout time.Duration) *drain.Helper {
+	return &drain.Helper{
+		Ctx:                 ctx,
+		Client:              m.kubernetescli,
+		Force:               true,
+		GracePeriodSeconds:  -1,
+		IgnoreAllDaemonSets: true,
+		Timeout:             timeout,
+		DeleteEmptyDirData:  true,
+		DisableEviction:     true,
+		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
+			m.log.Printf("deleted pod %s/%s", pod.Namespace, pod.Name)
+		},
+		Out:    m.log.Writer(),
+		ErrOut: m.log.Writer(),
+	}
+}
+
+func (m *manager) cordonNode(ctx context.Context, nodeName string, cordon bool) error {
+	node, err := m.kubernetescli.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	return drain.RunCordonOrUncordon(m.drainHelper(ctx, time.Minute), node, cordon)
+}
+
+func (m *manager) drainNode(ctx context.Context, nodeName string) error {
+	return drain.RunNodeDrain(m.drainHelper(ctx, 3*time.Minute), nodeName)
+}
+
+// waitForNodeResized waits for the node to be ready and to report the new
+// instance type, so that a node which has not yet gone down for the resize
+// is not mistaken for one which has come back up
+func (m *manager) waitForNodeResized(ctx context.Context, nodeName string, vmSize api.VMSize) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, resizeNodeReadyTimeout)
+	defer cancel()
+
+	return wait.PollImmediateUntil(resizeNodeReadyInterval, func() (bool, error) {
+		node, err := m.kubernetescli.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			// the API server may briefly be unavailable while a master
+			// restarts
+			return false, nil
+		}
+
+		return ready.NodeIsReady(node) && strings.EqualFold(node.Labels[corev1.LabelInstanceTypeStable], string(vmSize)), nil
+	}, timeoutCtx.Done())
+}
@@ -0,0 +1,289 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/golang/mock/gomock"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machinefake "github.com/openshift/client-go/machine/clientset/versioned/fake"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	mock_compute "github.com/Azure/ARO-RP/pkg/util/mocks/azureclient/mgmt/compute"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestResizeMachines(t *testing.T) {
+	ctx := context.Background()
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroup/providers/microsoft.redhatopenshift/openshiftclusters/resourceName"
+	clusterRGName := "test-cluster"
+
+	resizeNodeReadyInterval = time.Millisecond
+
+	providerSpec := func(vmSize string) *kruntime.RawExtension {
+		return &kruntime.RawExtension{
+			Raw: []byte(fmt.Sprintf(`{"image":{"resourceID":"image"},"vmSize":"%s"}`, vmSize)),
+		}
+	}
+
+	machine := func(name string) *machinev1beta1.Machine {
+		return &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: machineAPINamespace,
+			},
+			Spec: machinev1beta1.MachineSpec{
+				ProviderSpec: machinev1beta1.ProviderSpec{
+					Value: providerSpec("Standard_D4s_v3"),
+				},
+			},
+			Status: machinev1beta1.MachineStatus{
+				NodeRef: &corev1.ObjectReference{
+					Name: name,
+				},
+			},
+		}
+	}
+
+	// nodes are ready and report the new size as soon as they are resized
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					corev1.LabelInstanceTypeStable: "Standard_D8s_v3",
+				},
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:   corev1.NodeReady,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+		}
+	}
+
+	vm := func(vmSize string) mgmtcompute.VirtualMachine {
+		return mgmtcompute.VirtualMachine{
+			VirtualMachineProperties: &mgmtcompute.VirtualMachineProperties{
+				HardwareProfile: &mgmtcompute.HardwareProfile{
+					VMSize: mgmtcompute.VirtualMachineSizeTypes(vmSize),
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name          string
+		completed     []string
+		mocks         func(*mock_compute.MockVirtualMachinesClient)
+		wantResize    *api.MachineResize
+		wantCordoned  []string
+		wantMachineVM map[string]string
+		wantErr       string
+	}{
+		{
+			name: "machines are resized in turn",
+			mocks: func(vmClient *mock_compute.MockVirtualMachinesClient) {
+				gomock.InOrder(
+					vmClient.EXPECT().Get(gomock.Any(), clusterRGName, "aro-worker-a-0", mgmtcompute.InstanceViewTypes("")).Return(vm("Standard_D4s_v3"), nil),
+					vmClient.EXPECT().CreateOrUpdateAndWait(gomock.Any(), clusterRGName, "aro-worker-a-0", vm("Standard_D8s_v3")).Return(nil),
+					vmClient.EXPECT().Get(gomock.Any(), clusterRGName, "aro-worker-a-1", mgmtcompute.InstanceViewTypes("")).Return(vm("Standard_D4s_v3"), nil),
+					vmClient.EXPECT().CreateOrUpdateAndWait(gomock.Any(), clusterRGName, "aro-worker-a-1", vm("Standard_D8s_v3")).Return(nil),
+				)
+			},
+			wantResize: &api.MachineResize{
+				MachineSet: "aro-worker-a",
+				VMSize:     "Standard_D8s_v3",
+				Machines:   []string{"aro-worker-a-0", "aro-worker-a-1"},
+				Completed:  []string{"aro-worker-a-0", "aro-worker-a-1"},
+			},
+			wantMachineVM: map[string]string{
+				"aro-worker-a-0": "Standard_D8s_v3",
+				"aro-worker-a-1": "Standard_D8s_v3",
+			},
+		},
+		{
+			name:      "resumed resize skips completed machines and VMs already resized",
+			completed: []string{"aro-worker-a-0"},
+			mocks: func(vmClient *mock_compute.MockVirtualMachinesClient) {
+				vmClient.EXPECT().Get(gomock.Any(), clusterRGName, "aro-worker-a-1", mgmtcompute.InstanceViewTypes("")).Return(vm("Standard_D8s_v3"), nil)
+			},
+			wantResize: &api.MachineResize{
+				MachineSet: "aro-worker-a",
+				VMSize:     "Standard_D8s_v3",
+				Machines:   []string{"aro-worker-a-0", "aro-worker-a-1"},
+				Completed:  []string{"aro-worker-a-0", "aro-worker-a-1"},
+			},
+			wantMachineVM: map[string]string{
+				"aro-worker-a-0": "Standard_D4s_v3",
+				"aro-worker-a-1": "Standard_D8s_v3",
+			},
+		},
+		{
+			name: "failed resize records where it stopped",
+			mocks: func(vmClient *mock_compute.MockVirtualMachinesClient) {
+				vmClient.EXPECT().Get(gomock.Any(), clusterRGName, "aro-worker-a-0", mgmtcompute.InstanceViewTypes("")).Return(vm("Standard_D4s_v3"), nil)
+				vmClient.EXPECT().CreateOrUpdateAndWait(gomock.Any(), clusterRGName, "aro-worker-a-0", vm("Standard_D8s_v3")).Return(errors.New("quota exceeded"))
+			},
+			wantResize: &api.MachineResize{
+				MachineSet: "aro-worker-a",
+				VMSize:     "Standard_D8s_v3",
+				Machines:   []string{"aro-worker-a-0", "aro-worker-a-1"},
+				Current:    "aro-worker-a-0",
+				Step:       resizeStepResize,
+			},
+			wantCordoned: []string{"aro-worker-a-0"},
+			wantMachineVM: map[string]string{
+				"aro-worker-a-0": "Standard_D4s_v3",
+				"aro-worker-a-1": "Standard_D4s_v3",
+			},
+			wantErr: "aro-worker-a-0: Resize: quota exceeded",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			vmClient := mock_compute.NewMockVirtualMachinesClient(controller)
+			tt.mocks(vmClient)
+
+			fakeOpenShiftClustersDatabase, _ := testdatabase.NewFakeOpenShiftClusters()
+			fixture := testdatabase.NewFixture().WithOpenShiftClusters(fakeOpenShiftClustersDatabase)
+			fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				Key: strings.ToLower(resourceID),
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: resourceID,
+					Properties: api.OpenShiftClusterProperties{
+						ProvisioningState: api.ProvisioningStateAdminUpdating,
+						MaintenanceTask:   api.MaintenanceTaskResizeMachines,
+						ClusterProfile: api.ClusterProfile{
+							ResourceGroupID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/" + clusterRGName,
+						},
+						MachineResize: &api.MachineResize{
+							MachineSet: "aro-worker-a",
+							VMSize:     "Standard_D8s_v3",
+							Machines:   []string{"aro-worker-a-0", "aro-worker-a-1"},
+							Completed:  tt.completed,
+						},
+					},
+				},
+			})
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc, err := fakeOpenShiftClustersDatabase.Dequeue(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := &manager{
+				log: logrus.NewEntry(logrus.StandardLogger()),
+				doc: doc,
+				db:  fakeOpenShiftClustersDatabase,
+				maocli: machinefake.NewSimpleClientset(
+					machine("aro-worker-a-0"),
+					machine("aro-worker-a-1"),
+					&machinev1beta1.MachineSet{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "aro-worker-a",
+							Namespace: machineAPINamespace,
+						},
+						Spec: machinev1beta1.MachineSetSpec{
+							Template: machinev1beta1.MachineTemplateSpec{
+								Spec: machinev1beta1.MachineSpec{
+									ProviderSpec: machinev1beta1.ProviderSpec{
+										Value: providerSpec("Standard_D4s_v3"),
+									},
+								},
+							},
+						},
+					},
+				),
+				kubernetescli:   fake.NewSimpleClientset(node("aro-worker-a-0"), node("aro-worker-a-1")),
+				virtualMachines: vmClient,
+			}
+
+			err = m.resizeMachines(ctx)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			doc, err = fakeOpenShiftClustersDatabase.Get(ctx, strings.ToLower(resourceID))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(doc.OpenShiftCluster.Properties.MachineResize, tt.wantResize) {
+				t.Errorf("unexpected machine resize %#v", doc.OpenShiftCluster.Properties.MachineResize)
+			}
+
+			machineSet, err := m.maocli.MachineV1beta1().MachineSets(machineAPINamespace).Get(ctx, "aro-worker-a", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if vmSize := providerSpecVMSize(t, machineSet.Spec.Template.Spec.ProviderSpec.Value); vmSize != "Standard_D8s_v3" {
+				t.Errorf("machineset vmSize %s", vmSize)
+			}
+
+			for name, wantVMSize := range tt.wantMachineVM {
+				machine, err := m.maocli.MachineV1beta1().Machines(machineAPINamespace).Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if vmSize := providerSpecVMSize(t, machine.Spec.ProviderSpec.Value); vmSize != wantVMSize {
+					t.Errorf("machine %s vmSize %s", name, vmSize)
+				}
+			}
+
+			nodes, err := m.kubernetescli.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var cordoned []string
+			for _, node := range nodes.Items {
+				if node.Spec.Unschedulable {
+					cordoned = append(cordoned, node.Name)
+				}
+			}
+
+			if !reflect.DeepEqual(cordoned, tt.wantCordoned) {
+				t.Errorf("cordoned nodes %v", cordoned)
+			}
+		})
+	}
+}
+
+func providerSpecVMSize(t *testing.T, value *kruntime.RawExtension) string {
+	var spec struct {
+		VMSize string `json:"vmSize"`
+	}
+
+	err := json.Unmarshal(value.Raw, &spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return spec.VMSize
+}
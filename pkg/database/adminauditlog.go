@@ -0,0 +1,84 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const (
+	AdminAuditLogListByKeyQuery = `SELECT * FROM AdminAuditLog doc WHERE doc.key = @key`
+)
+
+type adminAuditLog struct {
+	c             cosmosdb.AdminAuditEntryDocumentClient
+	uuidGenerator uuid.Generator
+}
+
+// AdminAuditLog is the database interface for AdminAuditEntryDocuments
+type AdminAuditLog interface {
+	Create(context.Context, *api.AdminAuditEntryDocument) (*api.AdminAuditEntryDocument, error)
+	ListByKey(context.Context, string) (*api.AdminAuditEntryDocuments, error)
+	NewUUID() string
+}
+
+// NewAdminAuditLog returns a new AdminAuditLog
+func NewAdminAuditLog(ctx context.Context, dbc cosmosdb.DatabaseClient, dbName string) (AdminAuditLog, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	documentClient := cosmosdb.NewAdminAuditEntryDocumentClient(collc, collAdminAuditLog)
+	return NewAdminAuditLogWithProvidedClient(documentClient, uuid.DefaultGenerator), nil
+}
+
+func NewAdminAuditLogWithProvidedClient(client cosmosdb.AdminAuditEntryDocumentClient, uuidGenerator uuid.Generator) AdminAuditLog {
+	return &adminAuditLog{
+		c:             client,
+		uuidGenerator: uuidGenerator,
+	}
+}
+
+func (c *adminAuditLog) NewUUID() string {
+	return c.uuidGenerator.Generate()
+}
+
+func (c *adminAuditLog) Create(ctx context.Context, doc *api.AdminAuditEntryDocument) (*api.AdminAuditEntryDocument, error) {
+	if doc.Key != strings.ToLower(doc.Key) {
+		return nil, fmt.Errorf("key %q is not lower case", doc.Key)
+	}
+
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	doc, err := c.c.Create(ctx, doc.Key, doc, nil)
+
+	if err, ok := err.(*cosmosdb.Error); ok && err.StatusCode == http.StatusConflict {
+		err.StatusCode = http.StatusPreconditionFailed
+	}
+
+	return doc, err
+}
+
+func (c *adminAuditLog) ListByKey(ctx context.Context, key string) (*api.AdminAuditEntryDocuments, error) {
+	if key != strings.ToLower(key) {
+		return nil, fmt.Errorf("key %q is not lower case", key)
+	}
+
+	return c.c.QueryAll(ctx, key, &cosmosdb.Query{
+		Query: AdminAuditLogListByKeyQuery,
+		Parameters: []cosmosdb.Parameter{
+			{
+				Name:  "@key",
+				Value: key,
+			},
+		},
+	}, nil)
+}
@@ -0,0 +1,93 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+const (
+	ClusterAlertsListByKeyQuery = `SELECT * FROM ClusterAlerts doc WHERE doc.key = @key`
+)
+
+type clusterAlerts struct {
+	c cosmosdb.ClusterAlertDocumentClient
+}
+
+// ClusterAlerts is the database interface for ClusterAlertDocuments
+type ClusterAlerts interface {
+	CreateOrUpdate(context.Context, *api.ClusterAlertDocument) (*api.ClusterAlertDocument, error)
+	ListByKey(context.Context, string) (*api.ClusterAlertDocuments, error)
+}
+
+// NewClusterAlerts returns a new ClusterAlerts
+func NewClusterAlerts(ctx context.Context, dbc cosmosdb.DatabaseClient, dbName string) (ClusterAlerts, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	documentClient := cosmosdb.NewClusterAlertDocumentClient(collc, collClusterAlerts)
+	return NewClusterAlertsWithProvidedClient(documentClient), nil
+}
+
+func NewClusterAlertsWithProvidedClient(client cosmosdb.ClusterAlertDocumentClient) ClusterAlerts {
+	return &clusterAlerts{
+		c: client,
+	}
+}
+
+// CreateOrUpdate stores an alert.  Alertmanager re-sends alerts while they are
+// firing and once more when they resolve, so an existing document with the
+// same ID is replaced.
+func (c *clusterAlerts) CreateOrUpdate(ctx context.Context, doc *api.ClusterAlertDocument) (*api.ClusterAlertDocument, error) {
+	if doc.Key != strings.ToLower(doc.Key) {
+		return nil, fmt.Errorf("key %q is not lower case", doc.Key)
+	}
+
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	var newDoc *api.ClusterAlertDocument
+	err := cosmosdb.RetryOnPreconditionFailed(func() error {
+		existing, err := c.c.Get(ctx, doc.Key, doc.ID, nil)
+		switch {
+		case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+			newDoc, err = c.c.Create(ctx, doc.Key, doc, nil)
+			if err, ok := err.(*cosmosdb.Error); ok && err.StatusCode == http.StatusConflict {
+				err.StatusCode = http.StatusPreconditionFailed
+			}
+			return err
+
+		case err != nil:
+			return err
+		}
+
+		doc.ETag = existing.ETag
+		newDoc, err = c.c.Replace(ctx, doc.Key, doc, nil)
+		return err
+	})
+
+	return newDoc, err
+}
+
+func (c *clusterAlerts) ListByKey(ctx context.Context, key string) (*api.ClusterAlertDocuments, error) {
+	if key != strings.ToLower(key) {
+		return nil, fmt.Errorf("key %q is not lower case", key)
+	}
+
+	return c.c.QueryAll(ctx, key, &cosmosdb.Query{
+		Query: ClusterAlertsListByKeyQuery,
+		Parameters: []cosmosdb.Parameter{
+			{
+				Name:  "@key",
+				Value: key,
+			},
+		},
+	}, nil)
+}
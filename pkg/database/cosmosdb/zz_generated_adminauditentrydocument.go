@@ -0,0 +1,313 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type adminAuditEntryDocumentClient struct {
+	*databaseClient
+	path string
+}
+
+// AdminAuditEntryDocumentClient is a adminAuditEntryDocument client
+type AdminAuditEntryDocumentClient interface {
+	Create(context.Context, string, *pkg.AdminAuditEntryDocument, *Options) (*pkg.AdminAuditEntryDocument, error)
+	List(*Options) AdminAuditEntryDocumentIterator
+	ListAll(context.Context, *Options) (*pkg.AdminAuditEntryDocuments, error)
+	Get(context.Context, string, string, *Options) (*pkg.AdminAuditEntryDocument, error)
+	Replace(context.Context, string, *pkg.AdminAuditEntryDocument, *Options) (*pkg.AdminAuditEntryDocument, error)
+	Delete(context.Context, string, *pkg.AdminAuditEntryDocument, *Options) error
+	Query(string, *Query, *Options) AdminAuditEntryDocumentRawIterator
+	QueryAll(context.Context, string, *Query, *Options) (*pkg.AdminAuditEntryDocuments, error)
+	ChangeFeed(*Options) AdminAuditEntryDocumentIterator
+}
+
+type adminAuditEntryDocumentChangeFeedIterator struct {
+	*adminAuditEntryDocumentClient
+	continuation string
+	options      *Options
+}
+
+type adminAuditEntryDocumentListIterator struct {
+	*adminAuditEntryDocumentClient
+	continuation string
+	done         bool
+	options      *Options
+}
+
+type adminAuditEntryDocumentQueryIterator struct {
+	*adminAuditEntryDocumentClient
+	partitionkey string
+	query        *Query
+	continuation string
+	done         bool
+	options      *Options
+}
+
+// AdminAuditEntryDocumentIterator is a adminAuditEntryDocument iterator
+type AdminAuditEntryDocumentIterator interface {
+	Next(context.Context, int) (*pkg.AdminAuditEntryDocuments, error)
+	Continuation() string
+}
+
+// AdminAuditEntryDocumentRawIterator is a adminAuditEntryDocument raw iterator
+type AdminAuditEntryDocumentRawIterator interface {
+	AdminAuditEntryDocumentIterator
+	NextRaw(context.Context, int, interface{}) error
+}
+
+// NewAdminAuditEntryDocumentClient returns a new adminAuditEntryDocument client
+func NewAdminAuditEntryDocumentClient(collc CollectionClient, collid string) AdminAuditEntryDocumentClient {
+	return &adminAuditEntryDocumentClient{
+		databaseClient: collc.(*collectionClient).databaseClient,
+		path:           collc.(*collectionClient).path + "/colls/" + collid,
+	}
+}
+
+func (c *adminAuditEntryDocumentClient) all(ctx context.Context, i AdminAuditEntryDocumentIterator) (*pkg.AdminAuditEntryDocuments, error) {
+	alladminAuditEntryDocuments := &pkg.AdminAuditEntryDocuments{}
+
+	for {
+		adminAuditEntryDocuments, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if adminAuditEntryDocuments == nil {
+			break
+		}
+
+		alladminAuditEntryDocuments.Count += adminAuditEntryDocuments.Count
+		alladminAuditEntryDocuments.ResourceID = adminAuditEntryDocuments.ResourceID
+		alladminAuditEntryDocuments.AdminAuditEntryDocuments = append(alladminAuditEntryDocuments.AdminAuditEntryDocuments, adminAuditEntryDocuments.AdminAuditEntryDocuments...)
+	}
+
+	return alladminAuditEntryDocuments, nil
+}
+
+func (c *adminAuditEntryDocumentClient) Create(ctx context.Context, partitionkey string, newadminAuditEntryDocument *pkg.AdminAuditEntryDocument, options *Options) (adminAuditEntryDocument *pkg.AdminAuditEntryDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.NoETag = true
+
+	err = c.setOptions(options, newadminAuditEntryDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/docs", "docs", c.path, http.StatusCreated, &newadminAuditEntryDocument, &adminAuditEntryDocument, headers)
+	return
+}
+
+func (c *adminAuditEntryDocumentClient) List(options *Options) AdminAuditEntryDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &adminAuditEntryDocumentListIterator{adminAuditEntryDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *adminAuditEntryDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.AdminAuditEntryDocuments, error) {
+	return c.all(ctx, c.List(options))
+}
+
+func (c *adminAuditEntryDocumentClient) Get(ctx context.Context, partitionkey, adminAuditEntryDocumentid string, options *Options) (adminAuditEntryDocument *pkg.AdminAuditEntryDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodGet, c.path+"/docs/"+adminAuditEntryDocumentid, "docs", c.path+"/docs/"+adminAuditEntryDocumentid, http.StatusOK, nil, &adminAuditEntryDocument, headers)
+	return
+}
+
+func (c *adminAuditEntryDocumentClient) Replace(ctx context.Context, partitionkey string, newadminAuditEntryDocument *pkg.AdminAuditEntryDocument, options *Options) (adminAuditEntryDocument *pkg.AdminAuditEntryDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, newadminAuditEntryDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/docs/"+newadminAuditEntryDocument.ID, "docs", c.path+"/docs/"+newadminAuditEntryDocument.ID, http.StatusOK, &newadminAuditEntryDocument, &adminAuditEntryDocument, headers)
+	return
+}
+
+func (c *adminAuditEntryDocumentClient) Delete(ctx context.Context, partitionkey string, adminAuditEntryDocument *pkg.AdminAuditEntryDocument, options *Options) (err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, adminAuditEntryDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodDelete, c.path+"/docs/"+adminAuditEntryDocument.ID, "docs", c.path+"/docs/"+adminAuditEntryDocument.ID, http.StatusNoContent, nil, nil, headers)
+	return
+}
+
+func (c *adminAuditEntryDocumentClient) Query(partitionkey string, query *Query, options *Options) AdminAuditEntryDocumentRawIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &adminAuditEntryDocumentQueryIterator{adminAuditEntryDocumentClient: c, partitionkey: partitionkey, query: query, options: options, continuation: continuation}
+}
+
+func (c *adminAuditEntryDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.AdminAuditEntryDocuments, error) {
+	return c.all(ctx, c.Query(partitionkey, query, options))
+}
+
+func (c *adminAuditEntryDocumentClient) ChangeFeed(options *Options) AdminAuditEntryDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &adminAuditEntryDocumentChangeFeedIterator{adminAuditEntryDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *adminAuditEntryDocumentClient) setOptions(options *Options, adminAuditEntryDocument *pkg.AdminAuditEntryDocument, headers http.Header) error {
+	if options == nil {
+		return nil
+	}
+
+	if adminAuditEntryDocument != nil && !options.NoETag {
+		if adminAuditEntryDocument.ETag == "" {
+			return ErrETagRequired
+		}
+		headers.Set("If-Match", adminAuditEntryDocument.ETag)
+	}
+	if len(options.PreTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Pre-Trigger-Include", strings.Join(options.PreTriggers, ","))
+	}
+	if len(options.PostTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Post-Trigger-Include", strings.Join(options.PostTriggers, ","))
+	}
+	if len(options.PartitionKeyRangeID) > 0 {
+		headers.Set("X-Ms-Documentdb-PartitionKeyRangeID", options.PartitionKeyRangeID)
+	}
+
+	return nil
+}
+
+func (i *adminAuditEntryDocumentChangeFeedIterator) Next(ctx context.Context, maxItemCount int) (adminAuditEntryDocuments *pkg.AdminAuditEntryDocuments, err error) {
+	headers := http.Header{}
+	headers.Set("A-IM", "Incremental feed")
+
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("If-None-Match", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &adminAuditEntryDocuments, headers)
+	if IsErrorStatusCode(err, http.StatusNotModified) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("Etag")
+
+	return
+}
+
+func (i *adminAuditEntryDocumentChangeFeedIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *adminAuditEntryDocumentListIterator) Next(ctx context.Context, maxItemCount int) (adminAuditEntryDocuments *pkg.AdminAuditEntryDocuments, err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &adminAuditEntryDocuments, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *adminAuditEntryDocumentListIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *adminAuditEntryDocumentQueryIterator) Next(ctx context.Context, maxItemCount int) (adminAuditEntryDocuments *pkg.AdminAuditEntryDocuments, err error) {
+	err = i.NextRaw(ctx, maxItemCount, &adminAuditEntryDocuments)
+	return
+}
+
+func (i *adminAuditEntryDocumentQueryIterator) NextRaw(ctx context.Context, maxItemCount int, raw interface{}) (err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	headers.Set("X-Ms-Documentdb-Isquery", "True")
+	headers.Set("Content-Type", "application/query+json")
+	if i.partitionkey != "" {
+		headers.Set("X-Ms-Documentdb-Partitionkey", `["`+i.partitionkey+`"]`)
+	} else {
+		headers.Set("X-Ms-Documentdb-Query-Enablecrosspartition", "True")
+	}
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodPost, i.path+"/docs", "docs", i.path, http.StatusOK, &i.query, &raw, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *adminAuditEntryDocumentQueryIterator) Continuation() string {
+	return i.continuation
+}
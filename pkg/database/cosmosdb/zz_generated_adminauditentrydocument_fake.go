@@ -0,0 +1,361 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fakeAdminAuditEntryDocumentTriggerHandler func(context.Context, *pkg.AdminAuditEntryDocument) error
+type fakeAdminAuditEntryDocumentQueryHandler func(AdminAuditEntryDocumentClient, *Query, *Options) AdminAuditEntryDocumentRawIterator
+
+var _ AdminAuditEntryDocumentClient = &FakeAdminAuditEntryDocumentClient{}
+
+// NewFakeAdminAuditEntryDocumentClient returns a FakeAdminAuditEntryDocumentClient
+func NewFakeAdminAuditEntryDocumentClient(h *codec.JsonHandle) *FakeAdminAuditEntryDocumentClient {
+	return &FakeAdminAuditEntryDocumentClient{
+		jsonHandle:               h,
+		adminAuditEntryDocuments: make(map[string]*pkg.AdminAuditEntryDocument),
+		triggerHandlers:          make(map[string]fakeAdminAuditEntryDocumentTriggerHandler),
+		queryHandlers:            make(map[string]fakeAdminAuditEntryDocumentQueryHandler),
+	}
+}
+
+// FakeAdminAuditEntryDocumentClient is a FakeAdminAuditEntryDocumentClient
+type FakeAdminAuditEntryDocumentClient struct {
+	lock                     sync.RWMutex
+	jsonHandle               *codec.JsonHandle
+	adminAuditEntryDocuments map[string]*pkg.AdminAuditEntryDocument
+	triggerHandlers          map[string]fakeAdminAuditEntryDocumentTriggerHandler
+	queryHandlers            map[string]fakeAdminAuditEntryDocumentQueryHandler
+	sorter                   func([]*pkg.AdminAuditEntryDocument)
+	etag                     int
+
+	// returns true if documents conflict
+	conflictChecker func(*pkg.AdminAuditEntryDocument, *pkg.AdminAuditEntryDocument) bool
+
+	// err, if not nil, is an error to return when attempting to communicate
+	// with this Client
+	err error
+}
+
+// SetError sets or unsets an error that will be returned on any
+// FakeAdminAuditEntryDocumentClient method invocation
+func (c *FakeAdminAuditEntryDocumentClient) SetError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = err
+}
+
+// SetSorter sets or unsets a sorter function which will be used to sort values
+// returned by List() for test stability
+func (c *FakeAdminAuditEntryDocumentClient) SetSorter(sorter func([]*pkg.AdminAuditEntryDocument)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sorter = sorter
+}
+
+// SetConflictChecker sets or unsets a function which can be used to validate
+// additional unique keys in a AdminAuditEntryDocument
+func (c *FakeAdminAuditEntryDocumentClient) SetConflictChecker(conflictChecker func(*pkg.AdminAuditEntryDocument, *pkg.AdminAuditEntryDocument) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conflictChecker = conflictChecker
+}
+
+// SetTriggerHandler sets or unsets a trigger handler
+func (c *FakeAdminAuditEntryDocumentClient) SetTriggerHandler(triggerName string, trigger fakeAdminAuditEntryDocumentTriggerHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.triggerHandlers[triggerName] = trigger
+}
+
+// SetQueryHandler sets or unsets a query handler
+func (c *FakeAdminAuditEntryDocumentClient) SetQueryHandler(queryName string, query fakeAdminAuditEntryDocumentQueryHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queryHandlers[queryName] = query
+}
+
+func (c *FakeAdminAuditEntryDocumentClient) deepCopy(adminAuditEntryDocument *pkg.AdminAuditEntryDocument) (*pkg.AdminAuditEntryDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.jsonHandle).Encode(adminAuditEntryDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	adminAuditEntryDocument = nil
+	err = codec.NewDecoderBytes(b, c.jsonHandle).Decode(&adminAuditEntryDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return adminAuditEntryDocument, nil
+}
+
+func (c *FakeAdminAuditEntryDocumentClient) apply(ctx context.Context, partitionkey string, adminAuditEntryDocument *pkg.AdminAuditEntryDocument, options *Options, isCreate bool) (*pkg.AdminAuditEntryDocument, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	adminAuditEntryDocument, err := c.deepCopy(adminAuditEntryDocument) // copy now because pretriggers can mutate adminAuditEntryDocument
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err := c.processPreTriggers(ctx, adminAuditEntryDocument, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingAdminAuditEntryDocument, exists := c.adminAuditEntryDocuments[adminAuditEntryDocument.ID]
+	if isCreate && exists {
+		return nil, &Error{
+			StatusCode: http.StatusConflict,
+			Message:    "Entity with the specified id already exists in the system",
+		}
+	}
+	if !isCreate {
+		if !exists {
+			return nil, &Error{StatusCode: http.StatusNotFound}
+		}
+
+		if adminAuditEntryDocument.ETag != existingAdminAuditEntryDocument.ETag {
+			return nil, &Error{StatusCode: http.StatusPreconditionFailed}
+		}
+	}
+
+	if c.conflictChecker != nil {
+		for _, adminAuditEntryDocumentToCheck := range c.adminAuditEntryDocuments {
+			if c.conflictChecker(adminAuditEntryDocumentToCheck, adminAuditEntryDocument) {
+				return nil, &Error{
+					StatusCode: http.StatusConflict,
+					Message:    "Entity with the specified id already exists in the system",
+				}
+			}
+		}
+	}
+
+	adminAuditEntryDocument.ETag = fmt.Sprint(c.etag)
+	c.etag++
+
+	c.adminAuditEntryDocuments[adminAuditEntryDocument.ID] = adminAuditEntryDocument
+
+	return c.deepCopy(adminAuditEntryDocument)
+}
+
+// Create creates a AdminAuditEntryDocument in the database
+func (c *FakeAdminAuditEntryDocumentClient) Create(ctx context.Context, partitionkey string, adminAuditEntryDocument *pkg.AdminAuditEntryDocument, options *Options) (*pkg.AdminAuditEntryDocument, error) {
+	return c.apply(ctx, partitionkey, adminAuditEntryDocument, options, true)
+}
+
+// Replace replaces a AdminAuditEntryDocument in the database
+func (c *FakeAdminAuditEntryDocumentClient) Replace(ctx context.Context, partitionkey string, adminAuditEntryDocument *pkg.AdminAuditEntryDocument, options *Options) (*pkg.AdminAuditEntryDocument, error) {
+	return c.apply(ctx, partitionkey, adminAuditEntryDocument, options, false)
+}
+
+// List returns a AdminAuditEntryDocumentIterator to list all AdminAuditEntryDocuments in the database
+func (c *FakeAdminAuditEntryDocumentClient) List(*Options) AdminAuditEntryDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeAdminAuditEntryDocumentErroringRawIterator(c.err)
+	}
+
+	adminAuditEntryDocuments := make([]*pkg.AdminAuditEntryDocument, 0, len(c.adminAuditEntryDocuments))
+	for _, adminAuditEntryDocument := range c.adminAuditEntryDocuments {
+		adminAuditEntryDocument, err := c.deepCopy(adminAuditEntryDocument)
+		if err != nil {
+			return NewFakeAdminAuditEntryDocumentErroringRawIterator(err)
+		}
+		adminAuditEntryDocuments = append(adminAuditEntryDocuments, adminAuditEntryDocument)
+	}
+
+	if c.sorter != nil {
+		c.sorter(adminAuditEntryDocuments)
+	}
+
+	return NewFakeAdminAuditEntryDocumentIterator(adminAuditEntryDocuments, 0)
+}
+
+// ListAll lists all AdminAuditEntryDocuments in the database
+func (c *FakeAdminAuditEntryDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.AdminAuditEntryDocuments, error) {
+	iter := c.List(options)
+	return iter.Next(ctx, -1)
+}
+
+// Get gets a AdminAuditEntryDocument from the database
+func (c *FakeAdminAuditEntryDocumentClient) Get(ctx context.Context, partitionkey string, id string, options *Options) (*pkg.AdminAuditEntryDocument, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	adminAuditEntryDocument, exists := c.adminAuditEntryDocuments[id]
+	if !exists {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+
+	return c.deepCopy(adminAuditEntryDocument)
+}
+
+// Delete deletes a AdminAuditEntryDocument from the database
+func (c *FakeAdminAuditEntryDocumentClient) Delete(ctx context.Context, partitionKey string, adminAuditEntryDocument *pkg.AdminAuditEntryDocument, options *Options) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	_, exists := c.adminAuditEntryDocuments[adminAuditEntryDocument.ID]
+	if !exists {
+		return &Error{StatusCode: http.StatusNotFound}
+	}
+
+	delete(c.adminAuditEntryDocuments, adminAuditEntryDocument.ID)
+	return nil
+}
+
+// ChangeFeed is unimplemented
+func (c *FakeAdminAuditEntryDocumentClient) ChangeFeed(*Options) AdminAuditEntryDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeAdminAuditEntryDocumentErroringRawIterator(c.err)
+	}
+
+	return NewFakeAdminAuditEntryDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+func (c *FakeAdminAuditEntryDocumentClient) processPreTriggers(ctx context.Context, adminAuditEntryDocument *pkg.AdminAuditEntryDocument, options *Options) error {
+	for _, triggerName := range options.PreTriggers {
+		if triggerHandler := c.triggerHandlers[triggerName]; triggerHandler != nil {
+			c.lock.Unlock()
+			err := triggerHandler(ctx, adminAuditEntryDocument)
+			c.lock.Lock()
+			if err != nil {
+				return err
+			}
+		} else {
+			return ErrNotImplemented
+		}
+	}
+
+	return nil
+}
+
+// Query calls a query handler to implement database querying
+func (c *FakeAdminAuditEntryDocumentClient) Query(name string, query *Query, options *Options) AdminAuditEntryDocumentRawIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeAdminAuditEntryDocumentErroringRawIterator(c.err)
+	}
+
+	if queryHandler := c.queryHandlers[query.Query]; queryHandler != nil {
+		c.lock.RUnlock()
+		i := queryHandler(c, query, options)
+		c.lock.RLock()
+		return i
+	}
+
+	return NewFakeAdminAuditEntryDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+// QueryAll calls a query handler to implement database querying
+func (c *FakeAdminAuditEntryDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.AdminAuditEntryDocuments, error) {
+	iter := c.Query("", query, options)
+	return iter.Next(ctx, -1)
+}
+
+func NewFakeAdminAuditEntryDocumentIterator(adminAuditEntryDocuments []*pkg.AdminAuditEntryDocument, continuation int) AdminAuditEntryDocumentRawIterator {
+	return &fakeAdminAuditEntryDocumentIterator{adminAuditEntryDocuments: adminAuditEntryDocuments, continuation: continuation}
+}
+
+type fakeAdminAuditEntryDocumentIterator struct {
+	adminAuditEntryDocuments []*pkg.AdminAuditEntryDocument
+	continuation             int
+	done                     bool
+}
+
+func (i *fakeAdminAuditEntryDocumentIterator) NextRaw(ctx context.Context, maxItemCount int, out interface{}) error {
+	return ErrNotImplemented
+}
+
+func (i *fakeAdminAuditEntryDocumentIterator) Next(ctx context.Context, maxItemCount int) (*pkg.AdminAuditEntryDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+
+	var adminAuditEntryDocuments []*pkg.AdminAuditEntryDocument
+	if maxItemCount == -1 {
+		adminAuditEntryDocuments = i.adminAuditEntryDocuments[i.continuation:]
+		i.continuation = len(i.adminAuditEntryDocuments)
+		i.done = true
+	} else {
+		max := i.continuation + maxItemCount
+		if max > len(i.adminAuditEntryDocuments) {
+			max = len(i.adminAuditEntryDocuments)
+		}
+		adminAuditEntryDocuments = i.adminAuditEntryDocuments[i.continuation:max]
+		i.continuation += max
+		i.done = i.Continuation() == ""
+	}
+
+	return &pkg.AdminAuditEntryDocuments{
+		AdminAuditEntryDocuments: adminAuditEntryDocuments,
+		Count:                    len(adminAuditEntryDocuments),
+	}, nil
+}
+
+func (i *fakeAdminAuditEntryDocumentIterator) Continuation() string {
+	if i.continuation >= len(i.adminAuditEntryDocuments) {
+		return ""
+	}
+	return fmt.Sprintf("%d", i.continuation)
+}
+
+// NewFakeAdminAuditEntryDocumentErroringRawIterator returns a AdminAuditEntryDocumentRawIterator which
+// whose methods return the given error
+func NewFakeAdminAuditEntryDocumentErroringRawIterator(err error) AdminAuditEntryDocumentRawIterator {
+	return &fakeAdminAuditEntryDocumentErroringRawIterator{err: err}
+}
+
+type fakeAdminAuditEntryDocumentErroringRawIterator struct {
+	err error
+}
+
+func (i *fakeAdminAuditEntryDocumentErroringRawIterator) Next(ctx context.Context, maxItemCount int) (*pkg.AdminAuditEntryDocuments, error) {
+	return nil, i.err
+}
+
+func (i *fakeAdminAuditEntryDocumentErroringRawIterator) NextRaw(context.Context, int, interface{}) error {
+	return i.err
+}
+
+func (i *fakeAdminAuditEntryDocumentErroringRawIterator) Continuation() string {
+	return ""
+}
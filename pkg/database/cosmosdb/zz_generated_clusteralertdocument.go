@@ -0,0 +1,313 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type clusterAlertDocumentClient struct {
+	*databaseClient
+	path string
+}
+
+// ClusterAlertDocumentClient is a clusterAlertDocument client
+type ClusterAlertDocumentClient interface {
+	Create(context.Context, string, *pkg.ClusterAlertDocument, *Options) (*pkg.ClusterAlertDocument, error)
+	List(*Options) ClusterAlertDocumentIterator
+	ListAll(context.Context, *Options) (*pkg.ClusterAlertDocuments, error)
+	Get(context.Context, string, string, *Options) (*pkg.ClusterAlertDocument, error)
+	Replace(context.Context, string, *pkg.ClusterAlertDocument, *Options) (*pkg.ClusterAlertDocument, error)
+	Delete(context.Context, string, *pkg.ClusterAlertDocument, *Options) error
+	Query(string, *Query, *Options) ClusterAlertDocumentRawIterator
+	QueryAll(context.Context, string, *Query, *Options) (*pkg.ClusterAlertDocuments, error)
+	ChangeFeed(*Options) ClusterAlertDocumentIterator
+}
+
+type clusterAlertDocumentChangeFeedIterator struct {
+	*clusterAlertDocumentClient
+	continuation string
+	options      *Options
+}
+
+type clusterAlertDocumentListIterator struct {
+	*clusterAlertDocumentClient
+	continuation string
+	done         bool
+	options      *Options
+}
+
+type clusterAlertDocumentQueryIterator struct {
+	*clusterAlertDocumentClient
+	partitionkey string
+	query        *Query
+	continuation string
+	done         bool
+	options      *Options
+}
+
+// ClusterAlertDocumentIterator is a clusterAlertDocument iterator
+type ClusterAlertDocumentIterator interface {
+	Next(context.Context, int) (*pkg.ClusterAlertDocuments, error)
+	Continuation() string
+}
+
+// ClusterAlertDocumentRawIterator is a clusterAlertDocument raw iterator
+type ClusterAlertDocumentRawIterator interface {
+	ClusterAlertDocumentIterator
+	NextRaw(context.Context, int, interface{}) error
+}
+
+// NewClusterAlertDocumentClient returns a new clusterAlertDocument client
+func NewClusterAlertDocumentClient(collc CollectionClient, collid string) ClusterAlertDocumentClient {
+	return &clusterAlertDocumentClient{
+		databaseClient: collc.(*collectionClient).databaseClient,
+		path:           collc.(*collectionClient).path + "/colls/" + collid,
+	}
+}
+
+func (c *clusterAlertDocumentClient) all(ctx context.Context, i ClusterAlertDocumentIterator) (*pkg.ClusterAlertDocuments, error) {
+	allclusterAlertDocuments := &pkg.ClusterAlertDocuments{}
+
+	for {
+		clusterAlertDocuments, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if clusterAlertDocuments == nil {
+			break
+		}
+
+		allclusterAlertDocuments.Count += clusterAlertDocuments.Count
+		allclusterAlertDocuments.ResourceID = clusterAlertDocuments.ResourceID
+		allclusterAlertDocuments.ClusterAlertDocuments = append(allclusterAlertDocuments.ClusterAlertDocuments, clusterAlertDocuments.ClusterAlertDocuments...)
+	}
+
+	return allclusterAlertDocuments, nil
+}
+
+func (c *clusterAlertDocumentClient) Create(ctx context.Context, partitionkey string, newclusterAlertDocument *pkg.ClusterAlertDocument, options *Options) (clusterAlertDocument *pkg.ClusterAlertDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.NoETag = true
+
+	err = c.setOptions(options, newclusterAlertDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/docs", "docs", c.path, http.StatusCreated, &newclusterAlertDocument, &clusterAlertDocument, headers)
+	return
+}
+
+func (c *clusterAlertDocumentClient) List(options *Options) ClusterAlertDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &clusterAlertDocumentListIterator{clusterAlertDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *clusterAlertDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.ClusterAlertDocuments, error) {
+	return c.all(ctx, c.List(options))
+}
+
+func (c *clusterAlertDocumentClient) Get(ctx context.Context, partitionkey, clusterAlertDocumentid string, options *Options) (clusterAlertDocument *pkg.ClusterAlertDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodGet, c.path+"/docs/"+clusterAlertDocumentid, "docs", c.path+"/docs/"+clusterAlertDocumentid, http.StatusOK, nil, &clusterAlertDocument, headers)
+	return
+}
+
+func (c *clusterAlertDocumentClient) Replace(ctx context.Context, partitionkey string, newclusterAlertDocument *pkg.ClusterAlertDocument, options *Options) (clusterAlertDocument *pkg.ClusterAlertDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, newclusterAlertDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/docs/"+newclusterAlertDocument.ID, "docs", c.path+"/docs/"+newclusterAlertDocument.ID, http.StatusOK, &newclusterAlertDocument, &clusterAlertDocument, headers)
+	return
+}
+
+func (c *clusterAlertDocumentClient) Delete(ctx context.Context, partitionkey string, clusterAlertDocument *pkg.ClusterAlertDocument, options *Options) (err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, clusterAlertDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodDelete, c.path+"/docs/"+clusterAlertDocument.ID, "docs", c.path+"/docs/"+clusterAlertDocument.ID, http.StatusNoContent, nil, nil, headers)
+	return
+}
+
+func (c *clusterAlertDocumentClient) Query(partitionkey string, query *Query, options *Options) ClusterAlertDocumentRawIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &clusterAlertDocumentQueryIterator{clusterAlertDocumentClient: c, partitionkey: partitionkey, query: query, options: options, continuation: continuation}
+}
+
+func (c *clusterAlertDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.ClusterAlertDocuments, error) {
+	return c.all(ctx, c.Query(partitionkey, query, options))
+}
+
+func (c *clusterAlertDocumentClient) ChangeFeed(options *Options) ClusterAlertDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &clusterAlertDocumentChangeFeedIterator{clusterAlertDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *clusterAlertDocumentClient) setOptions(options *Options, clusterAlertDocument *pkg.ClusterAlertDocument, headers http.Header) error {
+	if options == nil {
+		return nil
+	}
+
+	if clusterAlertDocument != nil && !options.NoETag {
+		if clusterAlertDocument.ETag == "" {
+			return ErrETagRequired
+		}
+		headers.Set("If-Match", clusterAlertDocument.ETag)
+	}
+	if len(options.PreTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Pre-Trigger-Include", strings.Join(options.PreTriggers, ","))
+	}
+	if len(options.PostTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Post-Trigger-Include", strings.Join(options.PostTriggers, ","))
+	}
+	if len(options.PartitionKeyRangeID) > 0 {
+		headers.Set("X-Ms-Documentdb-PartitionKeyRangeID", options.PartitionKeyRangeID)
+	}
+
+	return nil
+}
+
+func (i *clusterAlertDocumentChangeFeedIterator) Next(ctx context.Context, maxItemCount int) (clusterAlertDocuments *pkg.ClusterAlertDocuments, err error) {
+	headers := http.Header{}
+	headers.Set("A-IM", "Incremental feed")
+
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("If-None-Match", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &clusterAlertDocuments, headers)
+	if IsErrorStatusCode(err, http.StatusNotModified) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("Etag")
+
+	return
+}
+
+func (i *clusterAlertDocumentChangeFeedIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *clusterAlertDocumentListIterator) Next(ctx context.Context, maxItemCount int) (clusterAlertDocuments *pkg.ClusterAlertDocuments, err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &clusterAlertDocuments, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *clusterAlertDocumentListIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *clusterAlertDocumentQueryIterator) Next(ctx context.Context, maxItemCount int) (clusterAlertDocuments *pkg.ClusterAlertDocuments, err error) {
+	err = i.NextRaw(ctx, maxItemCount, &clusterAlertDocuments)
+	return
+}
+
+func (i *clusterAlertDocumentQueryIterator) NextRaw(ctx context.Context, maxItemCount int, raw interface{}) (err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	headers.Set("X-Ms-Documentdb-Isquery", "True")
+	headers.Set("Content-Type", "application/query+json")
+	if i.partitionkey != "" {
+		headers.Set("X-Ms-Documentdb-Partitionkey", `["`+i.partitionkey+`"]`)
+	} else {
+		headers.Set("X-Ms-Documentdb-Query-Enablecrosspartition", "True")
+	}
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodPost, i.path+"/docs", "docs", i.path, http.StatusOK, &i.query, &raw, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *clusterAlertDocumentQueryIterator) Continuation() string {
+	return i.continuation
+}
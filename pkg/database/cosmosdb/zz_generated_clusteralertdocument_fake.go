@@ -0,0 +1,361 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fakeClusterAlertDocumentTriggerHandler func(context.Context, *pkg.ClusterAlertDocument) error
+type fakeClusterAlertDocumentQueryHandler func(ClusterAlertDocumentClient, *Query, *Options) ClusterAlertDocumentRawIterator
+
+var _ ClusterAlertDocumentClient = &FakeClusterAlertDocumentClient{}
+
+// NewFakeClusterAlertDocumentClient returns a FakeClusterAlertDocumentClient
+func NewFakeClusterAlertDocumentClient(h *codec.JsonHandle) *FakeClusterAlertDocumentClient {
+	return &FakeClusterAlertDocumentClient{
+		jsonHandle:            h,
+		clusterAlertDocuments: make(map[string]*pkg.ClusterAlertDocument),
+		triggerHandlers:       make(map[string]fakeClusterAlertDocumentTriggerHandler),
+		queryHandlers:         make(map[string]fakeClusterAlertDocumentQueryHandler),
+	}
+}
+
+// FakeClusterAlertDocumentClient is a FakeClusterAlertDocumentClient
+type FakeClusterAlertDocumentClient struct {
+	lock                  sync.RWMutex
+	jsonHandle            *codec.JsonHandle
+	clusterAlertDocuments map[string]*pkg.ClusterAlertDocument
+	triggerHandlers       map[string]fakeClusterAlertDocumentTriggerHandler
+	queryHandlers         map[string]fakeClusterAlertDocumentQueryHandler
+	sorter                func([]*pkg.ClusterAlertDocument)
+	etag                  int
+
+	// returns true if documents conflict
+	conflictChecker func(*pkg.ClusterAlertDocument, *pkg.ClusterAlertDocument) bool
+
+	// err, if not nil, is an error to return when attempting to communicate
+	// with this Client
+	err error
+}
+
+// SetError sets or unsets an error that will be returned on any
+// FakeClusterAlertDocumentClient method invocation
+func (c *FakeClusterAlertDocumentClient) SetError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = err
+}
+
+// SetSorter sets or unsets a sorter function which will be used to sort values
+// returned by List() for test stability
+func (c *FakeClusterAlertDocumentClient) SetSorter(sorter func([]*pkg.ClusterAlertDocument)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sorter = sorter
+}
+
+// SetConflictChecker sets or unsets a function which can be used to validate
+// additional unique keys in a ClusterAlertDocument
+func (c *FakeClusterAlertDocumentClient) SetConflictChecker(conflictChecker func(*pkg.ClusterAlertDocument, *pkg.ClusterAlertDocument) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conflictChecker = conflictChecker
+}
+
+// SetTriggerHandler sets or unsets a trigger handler
+func (c *FakeClusterAlertDocumentClient) SetTriggerHandler(triggerName string, trigger fakeClusterAlertDocumentTriggerHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.triggerHandlers[triggerName] = trigger
+}
+
+// SetQueryHandler sets or unsets a query handler
+func (c *FakeClusterAlertDocumentClient) SetQueryHandler(queryName string, query fakeClusterAlertDocumentQueryHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queryHandlers[queryName] = query
+}
+
+func (c *FakeClusterAlertDocumentClient) deepCopy(clusterAlertDocument *pkg.ClusterAlertDocument) (*pkg.ClusterAlertDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.jsonHandle).Encode(clusterAlertDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterAlertDocument = nil
+	err = codec.NewDecoderBytes(b, c.jsonHandle).Decode(&clusterAlertDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return clusterAlertDocument, nil
+}
+
+func (c *FakeClusterAlertDocumentClient) apply(ctx context.Context, partitionkey string, clusterAlertDocument *pkg.ClusterAlertDocument, options *Options, isCreate bool) (*pkg.ClusterAlertDocument, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	clusterAlertDocument, err := c.deepCopy(clusterAlertDocument) // copy now because pretriggers can mutate clusterAlertDocument
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err := c.processPreTriggers(ctx, clusterAlertDocument, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingClusterAlertDocument, exists := c.clusterAlertDocuments[clusterAlertDocument.ID]
+	if isCreate && exists {
+		return nil, &Error{
+			StatusCode: http.StatusConflict,
+			Message:    "Entity with the specified id already exists in the system",
+		}
+	}
+	if !isCreate {
+		if !exists {
+			return nil, &Error{StatusCode: http.StatusNotFound}
+		}
+
+		if clusterAlertDocument.ETag != existingClusterAlertDocument.ETag {
+			return nil, &Error{StatusCode: http.StatusPreconditionFailed}
+		}
+	}
+
+	if c.conflictChecker != nil {
+		for _, clusterAlertDocumentToCheck := range c.clusterAlertDocuments {
+			if c.conflictChecker(clusterAlertDocumentToCheck, clusterAlertDocument) {
+				return nil, &Error{
+					StatusCode: http.StatusConflict,
+					Message:    "Entity with the specified id already exists in the system",
+				}
+			}
+		}
+	}
+
+	clusterAlertDocument.ETag = fmt.Sprint(c.etag)
+	c.etag++
+
+	c.clusterAlertDocuments[clusterAlertDocument.ID] = clusterAlertDocument
+
+	return c.deepCopy(clusterAlertDocument)
+}
+
+// Create creates a ClusterAlertDocument in the database
+func (c *FakeClusterAlertDocumentClient) Create(ctx context.Context, partitionkey string, clusterAlertDocument *pkg.ClusterAlertDocument, options *Options) (*pkg.ClusterAlertDocument, error) {
+	return c.apply(ctx, partitionkey, clusterAlertDocument, options, true)
+}
+
+// Replace replaces a ClusterAlertDocument in the database
+func (c *FakeClusterAlertDocumentClient) Replace(ctx context.Context, partitionkey string, clusterAlertDocument *pkg.ClusterAlertDocument, options *Options) (*pkg.ClusterAlertDocument, error) {
+	return c.apply(ctx, partitionkey, clusterAlertDocument, options, false)
+}
+
+// List returns a ClusterAlertDocumentIterator to list all ClusterAlertDocuments in the database
+func (c *FakeClusterAlertDocumentClient) List(*Options) ClusterAlertDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeClusterAlertDocumentErroringRawIterator(c.err)
+	}
+
+	clusterAlertDocuments := make([]*pkg.ClusterAlertDocument, 0, len(c.clusterAlertDocuments))
+	for _, clusterAlertDocument := range c.clusterAlertDocuments {
+		clusterAlertDocument, err := c.deepCopy(clusterAlertDocument)
+		if err != nil {
+			return NewFakeClusterAlertDocumentErroringRawIterator(err)
+		}
+		clusterAlertDocuments = append(clusterAlertDocuments, clusterAlertDocument)
+	}
+
+	if c.sorter != nil {
+		c.sorter(clusterAlertDocuments)
+	}
+
+	return NewFakeClusterAlertDocumentIterator(clusterAlertDocuments, 0)
+}
+
+// ListAll lists all ClusterAlertDocuments in the database
+func (c *FakeClusterAlertDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.ClusterAlertDocuments, error) {
+	iter := c.List(options)
+	return iter.Next(ctx, -1)
+}
+
+// Get gets a ClusterAlertDocument from the database
+func (c *FakeClusterAlertDocumentClient) Get(ctx context.Context, partitionkey string, id string, options *Options) (*pkg.ClusterAlertDocument, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	clusterAlertDocument, exists := c.clusterAlertDocuments[id]
+	if !exists {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+
+	return c.deepCopy(clusterAlertDocument)
+}
+
+// Delete deletes a ClusterAlertDocument from the database
+func (c *FakeClusterAlertDocumentClient) Delete(ctx context.Context, partitionKey string, clusterAlertDocument *pkg.ClusterAlertDocument, options *Options) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	_, exists := c.clusterAlertDocuments[clusterAlertDocument.ID]
+	if !exists {
+		return &Error{StatusCode: http.StatusNotFound}
+	}
+
+	delete(c.clusterAlertDocuments, clusterAlertDocument.ID)
+	return nil
+}
+
+// ChangeFeed is unimplemented
+func (c *FakeClusterAlertDocumentClient) ChangeFeed(*Options) ClusterAlertDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeClusterAlertDocumentErroringRawIterator(c.err)
+	}
+
+	return NewFakeClusterAlertDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+func (c *FakeClusterAlertDocumentClient) processPreTriggers(ctx context.Context, clusterAlertDocument *pkg.ClusterAlertDocument, options *Options) error {
+	for _, triggerName := range options.PreTriggers {
+		if triggerHandler := c.triggerHandlers[triggerName]; triggerHandler != nil {
+			c.lock.Unlock()
+			err := triggerHandler(ctx, clusterAlertDocument)
+			c.lock.Lock()
+			if err != nil {
+				return err
+			}
+		} else {
+			return ErrNotImplemented
+		}
+	}
+
+	return nil
+}
+
+// Query calls a query handler to implement database querying
+func (c *FakeClusterAlertDocumentClient) Query(name string, query *Query, options *Options) ClusterAlertDocumentRawIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeClusterAlertDocumentErroringRawIterator(c.err)
+	}
+
+	if queryHandler := c.queryHandlers[query.Query]; queryHandler != nil {
+		c.lock.RUnlock()
+		i := queryHandler(c, query, options)
+		c.lock.RLock()
+		return i
+	}
+
+	return NewFakeClusterAlertDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+// QueryAll calls a query handler to implement database querying
+func (c *FakeClusterAlertDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.ClusterAlertDocuments, error) {
+	iter := c.Query("", query, options)
+	return iter.Next(ctx, -1)
+}
+
+func NewFakeClusterAlertDocumentIterator(clusterAlertDocuments []*pkg.ClusterAlertDocument, continuation int) ClusterAlertDocumentRawIterator {
+	return &fakeClusterAlertDocumentIterator{clusterAlertDocuments: clusterAlertDocuments, continuation: continuation}
+}
+
+type fakeClusterAlertDocumentIterator struct {
+	clusterAlertDocuments []*pkg.ClusterAlertDocument
+	continuation          int
+	done                  bool
+}
+
+func (i *fakeClusterAlertDocumentIterator) NextRaw(ctx context.Context, maxItemCount int, out interface{}) error {
+	return ErrNotImplemented
+}
+
+func (i *fakeClusterAlertDocumentIterator) Next(ctx context.Context, maxItemCount int) (*pkg.ClusterAlertDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+
+	var clusterAlertDocuments []*pkg.ClusterAlertDocument
+	if maxItemCount == -1 {
+		clusterAlertDocuments = i.clusterAlertDocuments[i.continuation:]
+		i.continuation = len(i.clusterAlertDocuments)
+		i.done = true
+	} else {
+		max := i.continuation + maxItemCount
+		if max > len(i.clusterAlertDocuments) {
+			max = len(i.clusterAlertDocuments)
+		}
+		clusterAlertDocuments = i.clusterAlertDocuments[i.continuation:max]
+		i.continuation += max
+		i.done = i.Continuation() == ""
+	}
+
+	return &pkg.ClusterAlertDocuments{
+		ClusterAlertDocuments: clusterAlertDocuments,
+		Count:                 len(clusterAlertDocuments),
+	}, nil
+}
+
+func (i *fakeClusterAlertDocumentIterator) Continuation() string {
+	if i.continuation >= len(i.clusterAlertDocuments) {
+		return ""
+	}
+	return fmt.Sprintf("%d", i.continuation)
+}
+
+// NewFakeClusterAlertDocumentErroringRawIterator returns a ClusterAlertDocumentRawIterator which
+// whose methods return the given error
+func NewFakeClusterAlertDocumentErroringRawIterator(err error) ClusterAlertDocumentRawIterator {
+	return &fakeClusterAlertDocumentErroringRawIterator{err: err}
+}
+
+type fakeClusterAlertDocumentErroringRawIterator struct {
+	err error
+}
+
+func (i *fakeClusterAlertDocumentErroringRawIterator) Next(ctx context.Context, maxItemCount int) (*pkg.ClusterAlertDocuments, error) {
+	return nil, i.err
+}
+
+func (i *fakeClusterAlertDocumentErroringRawIterator) NextRaw(context.Context, int, interface{}) error {
+	return i.err
+}
+
+func (i *fakeClusterAlertDocumentErroringRawIterator) Continuation() string {
+	return ""
+}
@@ -0,0 +1,313 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type keyRotationDocumentClient struct {
+	*databaseClient
+	path string
+}
+
+// KeyRotationDocumentClient is a keyRotationDocument client
+type KeyRotationDocumentClient interface {
+	Create(context.Context, string, *pkg.KeyRotationDocument, *Options) (*pkg.KeyRotationDocument, error)
+	List(*Options) KeyRotationDocumentIterator
+	ListAll(context.Context, *Options) (*pkg.KeyRotationDocuments, error)
+	Get(context.Context, string, string, *Options) (*pkg.KeyRotationDocument, error)
+	Replace(context.Context, string, *pkg.KeyRotationDocument, *Options) (*pkg.KeyRotationDocument, error)
+	Delete(context.Context, string, *pkg.KeyRotationDocument, *Options) error
+	Query(string, *Query, *Options) KeyRotationDocumentRawIterator
+	QueryAll(context.Context, string, *Query, *Options) (*pkg.KeyRotationDocuments, error)
+	ChangeFeed(*Options) KeyRotationDocumentIterator
+}
+
+type keyRotationDocumentChangeFeedIterator struct {
+	*keyRotationDocumentClient
+	continuation string
+	options      *Options
+}
+
+type keyRotationDocumentListIterator struct {
+	*keyRotationDocumentClient
+	continuation string
+	done         bool
+	options      *Options
+}
+
+type keyRotationDocumentQueryIterator struct {
+	*keyRotationDocumentClient
+	partitionkey string
+	query        *Query
+	continuation string
+	done         bool
+	options      *Options
+}
+
+// KeyRotationDocumentIterator is a keyRotationDocument iterator
+type KeyRotationDocumentIterator interface {
+	Next(context.Context, int) (*pkg.KeyRotationDocuments, error)
+	Continuation() string
+}
+
+// KeyRotationDocumentRawIterator is a keyRotationDocument raw iterator
+type KeyRotationDocumentRawIterator interface {
+	KeyRotationDocumentIterator
+	NextRaw(context.Context, int, interface{}) error
+}
+
+// NewKeyRotationDocumentClient returns a new keyRotationDocument client
+func NewKeyRotationDocumentClient(collc CollectionClient, collid string) KeyRotationDocumentClient {
+	return &keyRotationDocumentClient{
+		databaseClient: collc.(*collectionClient).databaseClient,
+		path:           collc.(*collectionClient).path + "/colls/" + collid,
+	}
+}
+
+func (c *keyRotationDocumentClient) all(ctx context.Context, i KeyRotationDocumentIterator) (*pkg.KeyRotationDocuments, error) {
+	allkeyRotationDocuments := &pkg.KeyRotationDocuments{}
+
+	for {
+		keyRotationDocuments, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if keyRotationDocuments == nil {
+			break
+		}
+
+		allkeyRotationDocuments.Count += keyRotationDocuments.Count
+		allkeyRotationDocuments.ResourceID = keyRotationDocuments.ResourceID
+		allkeyRotationDocuments.KeyRotationDocuments = append(allkeyRotationDocuments.KeyRotationDocuments, keyRotationDocuments.KeyRotationDocuments...)
+	}
+
+	return allkeyRotationDocuments, nil
+}
+
+func (c *keyRotationDocumentClient) Create(ctx context.Context, partitionkey string, newkeyRotationDocument *pkg.KeyRotationDocument, options *Options) (keyRotationDocument *pkg.KeyRotationDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.NoETag = true
+
+	err = c.setOptions(options, newkeyRotationDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/docs", "docs", c.path, http.StatusCreated, &newkeyRotationDocument, &keyRotationDocument, headers)
+	return
+}
+
+func (c *keyRotationDocumentClient) List(options *Options) KeyRotationDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &keyRotationDocumentListIterator{keyRotationDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *keyRotationDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.KeyRotationDocuments, error) {
+	return c.all(ctx, c.List(options))
+}
+
+func (c *keyRotationDocumentClient) Get(ctx context.Context, partitionkey, keyRotationDocumentid string, options *Options) (keyRotationDocument *pkg.KeyRotationDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodGet, c.path+"/docs/"+keyRotationDocumentid, "docs", c.path+"/docs/"+keyRotationDocumentid, http.StatusOK, nil, &keyRotationDocument, headers)
+	return
+}
+
+func (c *keyRotationDocumentClient) Replace(ctx context.Context, partitionkey string, newkeyRotationDocument *pkg.KeyRotationDocument, options *Options) (keyRotationDocument *pkg.KeyRotationDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, newkeyRotationDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/docs/"+newkeyRotationDocument.ID, "docs", c.path+"/docs/"+newkeyRotationDocument.ID, http.StatusOK, &newkeyRotationDocument, &keyRotationDocument, headers)
+	return
+}
+
+func (c *keyRotationDocumentClient) Delete(ctx context.Context, partitionkey string, keyRotationDocument *pkg.KeyRotationDocument, options *Options) (err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, keyRotationDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodDelete, c.path+"/docs/"+keyRotationDocument.ID, "docs", c.path+"/docs/"+keyRotationDocument.ID, http.StatusNoContent, nil, nil, headers)
+	return
+}
+
+func (c *keyRotationDocumentClient) Query(partitionkey string, query *Query, options *Options) KeyRotationDocumentRawIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &keyRotationDocumentQueryIterator{keyRotationDocumentClient: c, partitionkey: partitionkey, query: query, options: options, continuation: continuation}
+}
+
+func (c *keyRotationDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.KeyRotationDocuments, error) {
+	return c.all(ctx, c.Query(partitionkey, query, options))
+}
+
+func (c *keyRotationDocumentClient) ChangeFeed(options *Options) KeyRotationDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &keyRotationDocumentChangeFeedIterator{keyRotationDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *keyRotationDocumentClient) setOptions(options *Options, keyRotationDocument *pkg.KeyRotationDocument, headers http.Header) error {
+	if options == nil {
+		return nil
+	}
+
+	if keyRotationDocument != nil && !options.NoETag {
+		if keyRotationDocument.ETag == "" {
+			return ErrETagRequired
+		}
+		headers.Set("If-Match", keyRotationDocument.ETag)
+	}
+	if len(options.PreTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Pre-Trigger-Include", strings.Join(options.PreTriggers, ","))
+	}
+	if len(options.PostTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Post-Trigger-Include", strings.Join(options.PostTriggers, ","))
+	}
+	if len(options.PartitionKeyRangeID) > 0 {
+		headers.Set("X-Ms-Documentdb-PartitionKeyRangeID", options.PartitionKeyRangeID)
+	}
+
+	return nil
+}
+
+func (i *keyRotationDocumentChangeFeedIterator) Next(ctx context.Context, maxItemCount int) (keyRotationDocuments *pkg.KeyRotationDocuments, err error) {
+	headers := http.Header{}
+	headers.Set("A-IM", "Incremental feed")
+
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("If-None-Match", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &keyRotationDocuments, headers)
+	if IsErrorStatusCode(err, http.StatusNotModified) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("Etag")
+
+	return
+}
+
+func (i *keyRotationDocumentChangeFeedIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *keyRotationDocumentListIterator) Next(ctx context.Context, maxItemCount int) (keyRotationDocuments *pkg.KeyRotationDocuments, err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &keyRotationDocuments, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *keyRotationDocumentListIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *keyRotationDocumentQueryIterator) Next(ctx context.Context, maxItemCount int) (keyRotationDocuments *pkg.KeyRotationDocuments, err error) {
+	err = i.NextRaw(ctx, maxItemCount, &keyRotationDocuments)
+	return
+}
+
+func (i *keyRotationDocumentQueryIterator) NextRaw(ctx context.Context, maxItemCount int, raw interface{}) (err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	headers.Set("X-Ms-Documentdb-Isquery", "True")
+	headers.Set("Content-Type", "application/query+json")
+	if i.partitionkey != "" {
+		headers.Set("X-Ms-Documentdb-Partitionkey", `["`+i.partitionkey+`"]`)
+	} else {
+		headers.Set("X-Ms-Documentdb-Query-Enablecrosspartition", "True")
+	}
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodPost, i.path+"/docs", "docs", i.path, http.StatusOK, &i.query, &raw, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *keyRotationDocumentQueryIterator) Continuation() string {
+	return i.continuation
+}
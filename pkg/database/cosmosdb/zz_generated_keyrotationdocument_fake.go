@@ -0,0 +1,361 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fakeKeyRotationDocumentTriggerHandler func(context.Context, *pkg.KeyRotationDocument) error
+type fakeKeyRotationDocumentQueryHandler func(KeyRotationDocumentClient, *Query, *Options) KeyRotationDocumentRawIterator
+
+var _ KeyRotationDocumentClient = &FakeKeyRotationDocumentClient{}
+
+// NewFakeKeyRotationDocumentClient returns a FakeKeyRotationDocumentClient
+func NewFakeKeyRotationDocumentClient(h *codec.JsonHandle) *FakeKeyRotationDocumentClient {
+	return &FakeKeyRotationDocumentClient{
+		jsonHandle:           h,
+		keyRotationDocuments: make(map[string]*pkg.KeyRotationDocument),
+		triggerHandlers:      make(map[string]fakeKeyRotationDocumentTriggerHandler),
+		queryHandlers:        make(map[string]fakeKeyRotationDocumentQueryHandler),
+	}
+}
+
+// FakeKeyRotationDocumentClient is a FakeKeyRotationDocumentClient
+type FakeKeyRotationDocumentClient struct {
+	lock                 sync.RWMutex
+	jsonHandle           *codec.JsonHandle
+	keyRotationDocuments map[string]*pkg.KeyRotationDocument
+	triggerHandlers      map[string]fakeKeyRotationDocumentTriggerHandler
+	queryHandlers        map[string]fakeKeyRotationDocumentQueryHandler
+	sorter               func([]*pkg.KeyRotationDocument)
+	etag                 int
+
+	// returns true if documents conflict
+	conflictChecker func(*pkg.KeyRotationDocument, *pkg.KeyRotationDocument) bool
+
+	// err, if not nil, is an error to return when attempting to communicate
+	// with this Client
+	err error
+}
+
+// SetError sets or unsets an error that will be returned on any
+// FakeKeyRotationDocumentClient method invocation
+func (c *FakeKeyRotationDocumentClient) SetError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = err
+}
+
+// SetSorter sets or unsets a sorter function which will be used to sort values
+// returned by List() for test stability
+func (c *FakeKeyRotationDocumentClient) SetSorter(sorter func([]*pkg.KeyRotationDocument)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sorter = sorter
+}
+
+// SetConflictChecker sets or unsets a function which can be used to validate
+// additional unique keys in a KeyRotationDocument
+func (c *FakeKeyRotationDocumentClient) SetConflictChecker(conflictChecker func(*pkg.KeyRotationDocument, *pkg.KeyRotationDocument) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conflictChecker = conflictChecker
+}
+
+// SetTriggerHandler sets or unsets a trigger handler
+func (c *FakeKeyRotationDocumentClient) SetTriggerHandler(triggerName string, trigger fakeKeyRotationDocumentTriggerHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.triggerHandlers[triggerName] = trigger
+}
+
+// SetQueryHandler sets or unsets a query handler
+func (c *FakeKeyRotationDocumentClient) SetQueryHandler(queryName string, query fakeKeyRotationDocumentQueryHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queryHandlers[queryName] = query
+}
+
+func (c *FakeKeyRotationDocumentClient) deepCopy(keyRotationDocument *pkg.KeyRotationDocument) (*pkg.KeyRotationDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.jsonHandle).Encode(keyRotationDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	keyRotationDocument = nil
+	err = codec.NewDecoderBytes(b, c.jsonHandle).Decode(&keyRotationDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyRotationDocument, nil
+}
+
+func (c *FakeKeyRotationDocumentClient) apply(ctx context.Context, partitionkey string, keyRotationDocument *pkg.KeyRotationDocument, options *Options, isCreate bool) (*pkg.KeyRotationDocument, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	keyRotationDocument, err := c.deepCopy(keyRotationDocument) // copy now because pretriggers can mutate keyRotationDocument
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err := c.processPreTriggers(ctx, keyRotationDocument, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingKeyRotationDocument, exists := c.keyRotationDocuments[keyRotationDocument.ID]
+	if isCreate && exists {
+		return nil, &Error{
+			StatusCode: http.StatusConflict,
+			Message:    "Entity with the specified id already exists in the system",
+		}
+	}
+	if !isCreate {
+		if !exists {
+			return nil, &Error{StatusCode: http.StatusNotFound}
+		}
+
+		if keyRotationDocument.ETag != existingKeyRotationDocument.ETag {
+			return nil, &Error{StatusCode: http.StatusPreconditionFailed}
+		}
+	}
+
+	if c.conflictChecker != nil {
+		for _, keyRotationDocumentToCheck := range c.keyRotationDocuments {
+			if c.conflictChecker(keyRotationDocumentToCheck, keyRotationDocument) {
+				return nil, &Error{
+					StatusCode: http.StatusConflict,
+					Message:    "Entity with the specified id already exists in the system",
+				}
+			}
+		}
+	}
+
+	keyRotationDocument.ETag = fmt.Sprint(c.etag)
+	c.etag++
+
+	c.keyRotationDocuments[keyRotationDocument.ID] = keyRotationDocument
+
+	return c.deepCopy(keyRotationDocument)
+}
+
+// Create creates a KeyRotationDocument in the database
+func (c *FakeKeyRotationDocumentClient) Create(ctx context.Context, partitionkey string, keyRotationDocument *pkg.KeyRotationDocument, options *Options) (*pkg.KeyRotationDocument, error) {
+	return c.apply(ctx, partitionkey, keyRotationDocument, options, true)
+}
+
+// Replace replaces a KeyRotationDocument in the database
+func (c *FakeKeyRotationDocumentClient) Replace(ctx context.Context, partitionkey string, keyRotationDocument *pkg.KeyRotationDocument, options *Options) (*pkg.KeyRotationDocument, error) {
+	return c.apply(ctx, partitionkey, keyRotationDocument, options, false)
+}
+
+// List returns a KeyRotationDocumentIterator to list all KeyRotationDocuments in the database
+func (c *FakeKeyRotationDocumentClient) List(*Options) KeyRotationDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeKeyRotationDocumentErroringRawIterator(c.err)
+	}
+
+	keyRotationDocuments := make([]*pkg.KeyRotationDocument, 0, len(c.keyRotationDocuments))
+	for _, keyRotationDocument := range c.keyRotationDocuments {
+		keyRotationDocument, err := c.deepCopy(keyRotationDocument)
+		if err != nil {
+			return NewFakeKeyRotationDocumentErroringRawIterator(err)
+		}
+		keyRotationDocuments = append(keyRotationDocuments, keyRotationDocument)
+	}
+
+	if c.sorter != nil {
+		c.sorter(keyRotationDocuments)
+	}
+
+	return NewFakeKeyRotationDocumentIterator(keyRotationDocuments, 0)
+}
+
+// ListAll lists all KeyRotationDocuments in the database
+func (c *FakeKeyRotationDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.KeyRotationDocuments, error) {
+	iter := c.List(options)
+	return iter.Next(ctx, -1)
+}
+
+// Get gets a KeyRotationDocument from the database
+func (c *FakeKeyRotationDocumentClient) Get(ctx context.Context, partitionkey string, id string, options *Options) (*pkg.KeyRotationDocument, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	keyRotationDocument, exists := c.keyRotationDocuments[id]
+	if !exists {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+
+	return c.deepCopy(keyRotationDocument)
+}
+
+// Delete deletes a KeyRotationDocument from the database
+func (c *FakeKeyRotationDocumentClient) Delete(ctx context.Context, partitionKey string, keyRotationDocument *pkg.KeyRotationDocument, options *Options) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	_, exists := c.keyRotationDocuments[keyRotationDocument.ID]
+	if !exists {
+		return &Error{StatusCode: http.StatusNotFound}
+	}
+
+	delete(c.keyRotationDocuments, keyRotationDocument.ID)
+	return nil
+}
+
+// ChangeFeed is unimplemented
+func (c *FakeKeyRotationDocumentClient) ChangeFeed(*Options) KeyRotationDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeKeyRotationDocumentErroringRawIterator(c.err)
+	}
+
+	return NewFakeKeyRotationDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+func (c *FakeKeyRotationDocumentClient) processPreTriggers(ctx context.Context, keyRotationDocument *pkg.KeyRotationDocument, options *Options) error {
+	for _, triggerName := range options.PreTriggers {
+		if triggerHandler := c.triggerHandlers[triggerName]; triggerHandler != nil {
+			c.lock.Unlock()
+			err := triggerHandler(ctx, keyRotationDocument)
+			c.lock.Lock()
+			if err != nil {
+				return err
+			}
+		} else {
+			return ErrNotImplemented
+		}
+	}
+
+	return nil
+}
+
+// Query calls a query handler to implement database querying
+func (c *FakeKeyRotationDocumentClient) Query(name string, query *Query, options *Options) KeyRotationDocumentRawIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeKeyRotationDocumentErroringRawIterator(c.err)
+	}
+
+	if queryHandler := c.queryHandlers[query.Query]; queryHandler != nil {
+		c.lock.RUnlock()
+		i := queryHandler(c, query, options)
+		c.lock.RLock()
+		return i
+	}
+
+	return NewFakeKeyRotationDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+// QueryAll calls a query handler to implement database querying
+func (c *FakeKeyRotationDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.KeyRotationDocuments, error) {
+	iter := c.Query("", query, options)
+	return iter.Next(ctx, -1)
+}
+
+func NewFakeKeyRotationDocumentIterator(keyRotationDocuments []*pkg.KeyRotationDocument, continuation int) KeyRotationDocumentRawIterator {
+	return &fakeKeyRotationDocumentIterator{keyRotationDocuments: keyRotationDocuments, continuation: continuation}
+}
+
+type fakeKeyRotationDocumentIterator struct {
+	keyRotationDocuments []*pkg.KeyRotationDocument
+	continuation         int
+	done                 bool
+}
+
+func (i *fakeKeyRotationDocumentIterator) NextRaw(ctx context.Context, maxItemCount int, out interface{}) error {
+	return ErrNotImplemented
+}
+
+func (i *fakeKeyRotationDocumentIterator) Next(ctx context.Context, maxItemCount int) (*pkg.KeyRotationDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+
+	var keyRotationDocuments []*pkg.KeyRotationDocument
+	if maxItemCount == -1 {
+		keyRotationDocuments = i.keyRotationDocuments[i.continuation:]
+		i.continuation = len(i.keyRotationDocuments)
+		i.done = true
+	} else {
+		max := i.continuation + maxItemCount
+		if max > len(i.keyRotationDocuments) {
+			max = len(i.keyRotationDocuments)
+		}
+		keyRotationDocuments = i.keyRotationDocuments[i.continuation:max]
+		i.continuation += max
+		i.done = i.Continuation() == ""
+	}
+
+	return &pkg.KeyRotationDocuments{
+		KeyRotationDocuments: keyRotationDocuments,
+		Count:                len(keyRotationDocuments),
+	}, nil
+}
+
+func (i *fakeKeyRotationDocumentIterator) Continuation() string {
+	if i.continuation >= len(i.keyRotationDocuments) {
+		return ""
+	}
+	return fmt.Sprintf("%d", i.continuation)
+}
+
+// NewFakeKeyRotationDocumentErroringRawIterator returns a KeyRotationDocumentRawIterator which
+// whose methods return the given error
+func NewFakeKeyRotationDocumentErroringRawIterator(err error) KeyRotationDocumentRawIterator {
+	return &fakeKeyRotationDocumentErroringRawIterator{err: err}
+}
+
+type fakeKeyRotationDocumentErroringRawIterator struct {
+	err error
+}
+
+func (i *fakeKeyRotationDocumentErroringRawIterator) Next(ctx context.Context, maxItemCount int) (*pkg.KeyRotationDocuments, error) {
+	return nil, i.err
+}
+
+func (i *fakeKeyRotationDocumentErroringRawIterator) NextRaw(context.Context, int, interface{}) error {
+	return i.err
+}
+
+func (i *fakeKeyRotationDocumentErroringRawIterator) Continuation() string {
+	return ""
+}
@@ -0,0 +1,313 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type pendingActionDocumentClient struct {
+	*databaseClient
+	path string
+}
+
+// PendingActionDocumentClient is a pendingActionDocument client
+type PendingActionDocumentClient interface {
+	Create(context.Context, string, *pkg.PendingActionDocument, *Options) (*pkg.PendingActionDocument, error)
+	List(*Options) PendingActionDocumentIterator
+	ListAll(context.Context, *Options) (*pkg.PendingActionDocuments, error)
+	Get(context.Context, string, string, *Options) (*pkg.PendingActionDocument, error)
+	Replace(context.Context, string, *pkg.PendingActionDocument, *Options) (*pkg.PendingActionDocument, error)
+	Delete(context.Context, string, *pkg.PendingActionDocument, *Options) error
+	Query(string, *Query, *Options) PendingActionDocumentRawIterator
+	QueryAll(context.Context, string, *Query, *Options) (*pkg.PendingActionDocuments, error)
+	ChangeFeed(*Options) PendingActionDocumentIterator
+}
+
+type pendingActionDocumentChangeFeedIterator struct {
+	*pendingActionDocumentClient
+	continuation string
+	options      *Options
+}
+
+type pendingActionDocumentListIterator struct {
+	*pendingActionDocumentClient
+	continuation string
+	done         bool
+	options      *Options
+}
+
+type pendingActionDocumentQueryIterator struct {
+	*pendingActionDocumentClient
+	partitionkey string
+	query        *Query
+	continuation string
+	done         bool
+	options      *Options
+}
+
+// PendingActionDocumentIterator is a pendingActionDocument iterator
+type PendingActionDocumentIterator interface {
+	Next(context.Context, int) (*pkg.PendingActionDocuments, error)
+	Continuation() string
+}
+
+// PendingActionDocumentRawIterator is a pendingActionDocument raw iterator
+type PendingActionDocumentRawIterator interface {
+	PendingActionDocumentIterator
+	NextRaw(context.Context, int, interface{}) error
+}
+
+// NewPendingActionDocumentClient returns a new pendingActionDocument client
+func NewPendingActionDocumentClient(collc CollectionClient, collid string) PendingActionDocumentClient {
+	return &pendingActionDocumentClient{
+		databaseClient: collc.(*collectionClient).databaseClient,
+		path:           collc.(*collectionClient).path + "/colls/" + collid,
+	}
+}
+
+func (c *pendingActionDocumentClient) all(ctx context.Context, i PendingActionDocumentIterator) (*pkg.PendingActionDocuments, error) {
+	allpendingActionDocuments := &pkg.PendingActionDocuments{}
+
+	for {
+		pendingActionDocuments, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if pendingActionDocuments == nil {
+			break
+		}
+
+		allpendingActionDocuments.Count += pendingActionDocuments.Count
+		allpendingActionDocuments.ResourceID = pendingActionDocuments.ResourceID
+		allpendingActionDocuments.PendingActionDocuments = append(allpendingActionDocuments.PendingActionDocuments, pendingActionDocuments.PendingActionDocuments...)
+	}
+
+	return allpendingActionDocuments, nil
+}
+
+func (c *pendingActionDocumentClient) Create(ctx context.Context, partitionkey string, newpendingActionDocument *pkg.PendingActionDocument, options *Options) (pendingActionDocument *pkg.PendingActionDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.NoETag = true
+
+	err = c.setOptions(options, newpendingActionDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/docs", "docs", c.path, http.StatusCreated, &newpendingActionDocument, &pendingActionDocument, headers)
+	return
+}
+
+func (c *pendingActionDocumentClient) List(options *Options) PendingActionDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &pendingActionDocumentListIterator{pendingActionDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *pendingActionDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.PendingActionDocuments, error) {
+	return c.all(ctx, c.List(options))
+}
+
+func (c *pendingActionDocumentClient) Get(ctx context.Context, partitionkey, pendingActionDocumentid string, options *Options) (pendingActionDocument *pkg.PendingActionDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodGet, c.path+"/docs/"+pendingActionDocumentid, "docs", c.path+"/docs/"+pendingActionDocumentid, http.StatusOK, nil, &pendingActionDocument, headers)
+	return
+}
+
+func (c *pendingActionDocumentClient) Replace(ctx context.Context, partitionkey string, newpendingActionDocument *pkg.PendingActionDocument, options *Options) (pendingActionDocument *pkg.PendingActionDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, newpendingActionDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/docs/"+newpendingActionDocument.ID, "docs", c.path+"/docs/"+newpendingActionDocument.ID, http.StatusOK, &newpendingActionDocument, &pendingActionDocument, headers)
+	return
+}
+
+func (c *pendingActionDocumentClient) Delete(ctx context.Context, partitionkey string, pendingActionDocument *pkg.PendingActionDocument, options *Options) (err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, pendingActionDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodDelete, c.path+"/docs/"+pendingActionDocument.ID, "docs", c.path+"/docs/"+pendingActionDocument.ID, http.StatusNoContent, nil, nil, headers)
+	return
+}
+
+func (c *pendingActionDocumentClient) Query(partitionkey string, query *Query, options *Options) PendingActionDocumentRawIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &pendingActionDocumentQueryIterator{pendingActionDocumentClient: c, partitionkey: partitionkey, query: query, options: options, continuation: continuation}
+}
+
+func (c *pendingActionDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.PendingActionDocuments, error) {
+	return c.all(ctx, c.Query(partitionkey, query, options))
+}
+
+func (c *pendingActionDocumentClient) ChangeFeed(options *Options) PendingActionDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &pendingActionDocumentChangeFeedIterator{pendingActionDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *pendingActionDocumentClient) setOptions(options *Options, pendingActionDocument *pkg.PendingActionDocument, headers http.Header) error {
+	if options == nil {
+		return nil
+	}
+
+	if pendingActionDocument != nil && !options.NoETag {
+		if pendingActionDocument.ETag == "" {
+			return ErrETagRequired
+		}
+		headers.Set("If-Match", pendingActionDocument.ETag)
+	}
+	if len(options.PreTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Pre-Trigger-Include", strings.Join(options.PreTriggers, ","))
+	}
+	if len(options.PostTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Post-Trigger-Include", strings.Join(options.PostTriggers, ","))
+	}
+	if len(options.PartitionKeyRangeID) > 0 {
+		headers.Set("X-Ms-Documentdb-PartitionKeyRangeID", options.PartitionKeyRangeID)
+	}
+
+	return nil
+}
+
+func (i *pendingActionDocumentChangeFeedIterator) Next(ctx context.Context, maxItemCount int) (pendingActionDocuments *pkg.PendingActionDocuments, err error) {
+	headers := http.Header{}
+	headers.Set("A-IM", "Incremental feed")
+
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("If-None-Match", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &pendingActionDocuments, headers)
+	if IsErrorStatusCode(err, http.StatusNotModified) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("Etag")
+
+	return
+}
+
+func (i *pendingActionDocumentChangeFeedIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *pendingActionDocumentListIterator) Next(ctx context.Context, maxItemCount int) (pendingActionDocuments *pkg.PendingActionDocuments, err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &pendingActionDocuments, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *pendingActionDocumentListIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *pendingActionDocumentQueryIterator) Next(ctx context.Context, maxItemCount int) (pendingActionDocuments *pkg.PendingActionDocuments, err error) {
+	err = i.NextRaw(ctx, maxItemCount, &pendingActionDocuments)
+	return
+}
+
+func (i *pendingActionDocumentQueryIterator) NextRaw(ctx context.Context, maxItemCount int, raw interface{}) (err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	headers.Set("X-Ms-Documentdb-Isquery", "True")
+	headers.Set("Content-Type", "application/query+json")
+	if i.partitionkey != "" {
+		headers.Set("X-Ms-Documentdb-Partitionkey", `["`+i.partitionkey+`"]`)
+	} else {
+		headers.Set("X-Ms-Documentdb-Query-Enablecrosspartition", "True")
+	}
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodPost, i.path+"/docs", "docs", i.path, http.StatusOK, &i.query, &raw, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *pendingActionDocumentQueryIterator) Continuation() string {
+	return i.continuation
+}
@@ -0,0 +1,361 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fakePendingActionDocumentTriggerHandler func(context.Context, *pkg.PendingActionDocument) error
+type fakePendingActionDocumentQueryHandler func(PendingActionDocumentClient, *Query, *Options) PendingActionDocumentRawIterator
+
+var _ PendingActionDocumentClient = &FakePendingActionDocumentClient{}
+
+// NewFakePendingActionDocumentClient returns a FakePendingActionDocumentClient
+func NewFakePendingActionDocumentClient(h *codec.JsonHandle) *FakePendingActionDocumentClient {
+	return &FakePendingActionDocumentClient{
+		jsonHandle:             h,
+		pendingActionDocuments: make(map[string]*pkg.PendingActionDocument),
+		triggerHandlers:        make(map[string]fakePendingActionDocumentTriggerHandler),
+		queryHandlers:          make(map[string]fakePendingActionDocumentQueryHandler),
+	}
+}
+
+// FakePendingActionDocumentClient is a FakePendingActionDocumentClient
+type FakePendingActionDocumentClient struct {
+	lock                   sync.RWMutex
+	jsonHandle             *codec.JsonHandle
+	pendingActionDocuments map[string]*pkg.PendingActionDocument
+	triggerHandlers        map[string]fakePendingActionDocumentTriggerHandler
+	queryHandlers          map[string]fakePendingActionDocumentQueryHandler
+	sorter                 func([]*pkg.PendingActionDocument)
+	etag                   int
+
+	// returns true if documents conflict
+	conflictChecker func(*pkg.PendingActionDocument, *pkg.PendingActionDocument) bool
+
+	// err, if not nil, is an error to return when attempting to communicate
+	// with this Client
+	err error
+}
+
+// SetError sets or unsets an error that will be returned on any
+// FakePendingActionDocumentClient method invocation
+func (c *FakePendingActionDocumentClient) SetError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = err
+}
+
+// SetSorter sets or unsets a sorter function which will be used to sort values
+// returned by List() for test stability
+func (c *FakePendingActionDocumentClient) SetSorter(sorter func([]*pkg.PendingActionDocument)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sorter = sorter
+}
+
+// SetConflictChecker sets or unsets a function which can be used to validate
+// additional unique keys in a PendingActionDocument
+func (c *FakePendingActionDocumentClient) SetConflictChecker(conflictChecker func(*pkg.PendingActionDocument, *pkg.PendingActionDocument) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conflictChecker = conflictChecker
+}
+
+// SetTriggerHandler sets or unsets a trigger handler
+func (c *FakePendingActionDocumentClient) SetTriggerHandler(triggerName string, trigger fakePendingActionDocumentTriggerHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.triggerHandlers[triggerName] = trigger
+}
+
+// SetQueryHandler sets or unsets a query handler
+func (c *FakePendingActionDocumentClient) SetQueryHandler(queryName string, query fakePendingActionDocumentQueryHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queryHandlers[queryName] = query
+}
+
+func (c *FakePendingActionDocumentClient) deepCopy(pendingActionDocument *pkg.PendingActionDocument) (*pkg.PendingActionDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.jsonHandle).Encode(pendingActionDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingActionDocument = nil
+	err = codec.NewDecoderBytes(b, c.jsonHandle).Decode(&pendingActionDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return pendingActionDocument, nil
+}
+
+func (c *FakePendingActionDocumentClient) apply(ctx context.Context, partitionkey string, pendingActionDocument *pkg.PendingActionDocument, options *Options, isCreate bool) (*pkg.PendingActionDocument, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	pendingActionDocument, err := c.deepCopy(pendingActionDocument) // copy now because pretriggers can mutate pendingActionDocument
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err := c.processPreTriggers(ctx, pendingActionDocument, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingPendingActionDocument, exists := c.pendingActionDocuments[pendingActionDocument.ID]
+	if isCreate && exists {
+		return nil, &Error{
+			StatusCode: http.StatusConflict,
+			Message:    "Entity with the specified id already exists in the system",
+		}
+	}
+	if !isCreate {
+		if !exists {
+			return nil, &Error{StatusCode: http.StatusNotFound}
+		}
+
+		if pendingActionDocument.ETag != existingPendingActionDocument.ETag {
+			return nil, &Error{StatusCode: http.StatusPreconditionFailed}
+		}
+	}
+
+	if c.conflictChecker != nil {
+		for _, pendingActionDocumentToCheck := range c.pendingActionDocuments {
+			if c.conflictChecker(pendingActionDocumentToCheck, pendingActionDocument) {
+				return nil, &Error{
+					StatusCode: http.StatusConflict,
+					Message:    "Entity with the specified id already exists in the system",
+				}
+			}
+		}
+	}
+
+	pendingActionDocument.ETag = fmt.Sprint(c.etag)
+	c.etag++
+
+	c.pendingActionDocuments[pendingActionDocument.ID] = pendingActionDocument
+
+	return c.deepCopy(pendingActionDocument)
+}
+
+// Create creates a PendingActionDocument in the database
+func (c *FakePendingActionDocumentClient) Create(ctx context.Context, partitionkey string, pendingActionDocument *pkg.PendingActionDocument, options *Options) (*pkg.PendingActionDocument, error) {
+	return c.apply(ctx, partitionkey, pendingActionDocument, options, true)
+}
+
+// Replace replaces a PendingActionDocument in the database
+func (c *FakePendingActionDocumentClient) Replace(ctx context.Context, partitionkey string, pendingActionDocument *pkg.PendingActionDocument, options *Options) (*pkg.PendingActionDocument, error) {
+	return c.apply(ctx, partitionkey, pendingActionDocument, options, false)
+}
+
+// List returns a PendingActionDocumentIterator to list all PendingActionDocuments in the database
+func (c *FakePendingActionDocumentClient) List(*Options) PendingActionDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakePendingActionDocumentErroringRawIterator(c.err)
+	}
+
+	pendingActionDocuments := make([]*pkg.PendingActionDocument, 0, len(c.pendingActionDocuments))
+	for _, pendingActionDocument := range c.pendingActionDocuments {
+		pendingActionDocument, err := c.deepCopy(pendingActionDocument)
+		if err != nil {
+			return NewFakePendingActionDocumentErroringRawIterator(err)
+		}
+		pendingActionDocuments = append(pendingActionDocuments, pendingActionDocument)
+	}
+
+	if c.sorter != nil {
+		c.sorter(pendingActionDocuments)
+	}
+
+	return NewFakePendingActionDocumentIterator(pendingActionDocuments, 0)
+}
+
+// ListAll lists all PendingActionDocuments in the database
+func (c *FakePendingActionDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.PendingActionDocuments, error) {
+	iter := c.List(options)
+	return iter.Next(ctx, -1)
+}
+
+// Get gets a PendingActionDocument from the database
+func (c *FakePendingActionDocumentClient) Get(ctx context.Context, partitionkey string, id string, options *Options) (*pkg.PendingActionDocument, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	pendingActionDocument, exists := c.pendingActionDocuments[id]
+	if !exists {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+
+	return c.deepCopy(pendingActionDocument)
+}
+
+// Delete deletes a PendingActionDocument from the database
+func (c *FakePendingActionDocumentClient) Delete(ctx context.Context, partitionKey string, pendingActionDocument *pkg.PendingActionDocument, options *Options) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	_, exists := c.pendingActionDocuments[pendingActionDocument.ID]
+	if !exists {
+		return &Error{StatusCode: http.StatusNotFound}
+	}
+
+	delete(c.pendingActionDocuments, pendingActionDocument.ID)
+	return nil
+}
+
+// ChangeFeed is unimplemented
+func (c *FakePendingActionDocumentClient) ChangeFeed(*Options) PendingActionDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakePendingActionDocumentErroringRawIterator(c.err)
+	}
+
+	return NewFakePendingActionDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+func (c *FakePendingActionDocumentClient) processPreTriggers(ctx context.Context, pendingActionDocument *pkg.PendingActionDocument, options *Options) error {
+	for _, triggerName := range options.PreTriggers {
+		if triggerHandler := c.triggerHandlers[triggerName]; triggerHandler != nil {
+			c.lock.Unlock()
+			err := triggerHandler(ctx, pendingActionDocument)
+			c.lock.Lock()
+			if err != nil {
+				return err
+			}
+		} else {
+			return ErrNotImplemented
+		}
+	}
+
+	return nil
+}
+
+// Query calls a query handler to implement database querying
+func (c *FakePendingActionDocumentClient) Query(name string, query *Query, options *Options) PendingActionDocumentRawIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakePendingActionDocumentErroringRawIterator(c.err)
+	}
+
+	if queryHandler := c.queryHandlers[query.Query]; queryHandler != nil {
+		c.lock.RUnlock()
+		i := queryHandler(c, query, options)
+		c.lock.RLock()
+		return i
+	}
+
+	return NewFakePendingActionDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+// QueryAll calls a query handler to implement database querying
+func (c *FakePendingActionDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.PendingActionDocuments, error) {
+	iter := c.Query("", query, options)
+	return iter.Next(ctx, -1)
+}
+
+func NewFakePendingActionDocumentIterator(pendingActionDocuments []*pkg.PendingActionDocument, continuation int) PendingActionDocumentRawIterator {
+	return &fakePendingActionDocumentIterator{pendingActionDocuments: pendingActionDocuments, continuation: continuation}
+}
+
+type fakePendingActionDocumentIterator struct {
+	pendingActionDocuments []*pkg.PendingActionDocument
+	continuation           int
+	done                   bool
+}
+
+func (i *fakePendingActionDocumentIterator) NextRaw(ctx context.Context, maxItemCount int, out interface{}) error {
+	return ErrNotImplemented
+}
+
+func (i *fakePendingActionDocumentIterator) Next(ctx context.Context, maxItemCount int) (*pkg.PendingActionDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+
+	var pendingActionDocuments []*pkg.PendingActionDocument
+	if maxItemCount == -1 {
+		pendingActionDocuments = i.pendingActionDocuments[i.continuation:]
+		i.continuation = len(i.pendingActionDocuments)
+		i.done = true
+	} else {
+		max := i.continuation + maxItemCount
+		if max > len(i.pendingActionDocuments) {
+			max = len(i.pendingActionDocuments)
+		}
+		pendingActionDocuments = i.pendingActionDocuments[i.continuation:max]
+		i.continuation += max
+		i.done = i.Continuation() == ""
+	}
+
+	return &pkg.PendingActionDocuments{
+		PendingActionDocuments: pendingActionDocuments,
+		Count:                  len(pendingActionDocuments),
+	}, nil
+}
+
+func (i *fakePendingActionDocumentIterator) Continuation() string {
+	if i.continuation >= len(i.pendingActionDocuments) {
+		return ""
+	}
+	return fmt.Sprintf("%d", i.continuation)
+}
+
+// NewFakePendingActionDocumentErroringRawIterator returns a PendingActionDocumentRawIterator which
+// whose methods return the given error
+func NewFakePendingActionDocumentErroringRawIterator(err error) PendingActionDocumentRawIterator {
+	return &fakePendingActionDocumentErroringRawIterator{err: err}
+}
+
+type fakePendingActionDocumentErroringRawIterator struct {
+	err error
+}
+
+func (i *fakePendingActionDocumentErroringRawIterator) Next(ctx context.Context, maxItemCount int) (*pkg.PendingActionDocuments, error) {
+	return nil, i.err
+}
+
+func (i *fakePendingActionDocumentErroringRawIterator) NextRaw(context.Context, int, interface{}) error {
+	return i.err
+}
+
+func (i *fakePendingActionDocumentErroringRawIterator) Continuation() string {
+	return ""
+}
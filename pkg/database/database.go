@@ -26,13 +26,17 @@ import (
 )
 
 const (
+	collAdminAuditLog     = "AdminAuditLog"
 	collAsyncOperations   = "AsyncOperations"
 	collBilling           = "Billing"
+	collClusterAlerts     = "ClusterAlerts"
 	collClusterManager    = "ClusterManagerConfigurations"
 	collGateway           = "Gateway"
+	collKeyRotation       = "KeyRotation"
 	collMonitors          = "Monitors"
 	collOpenShiftClusters = "OpenShiftClusters"
 	collOpenShiftVersion  = "OpenShiftVersions"
+	collPendingActions    = "PendingActions"
 	collPortal            = "Portal"
 	collSubscriptions     = "Subscriptions"
 )
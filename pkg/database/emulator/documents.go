@@ -0,0 +1,379 @@
+package emulator
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// serveDocuments serves requests for the documents of coll.  Documents are
+// keyed by their partition key and ID.
+func (e *Emulator) serveDocuments(r *http.Request, coll *node, id string) (*response, error) {
+	docs := coll.children["docs"]
+
+	switch {
+	case id == "" && r.Method == http.MethodPost && strings.EqualFold(r.Header.Get("X-Ms-Documentdb-Isquery"), "True"):
+		return e.queryDocuments(r, coll)
+
+	case id == "" && r.Method == http.MethodPost:
+		return e.createDocument(r, coll)
+
+	case id == "" && r.Method == http.MethodGet && strings.EqualFold(r.Header.Get("A-Im"), "Incremental feed"):
+		return e.changeFeed(r, coll)
+
+	case id == "" && r.Method == http.MethodGet:
+		var items []interface{}
+		for _, doc := range docs.list() {
+			items = append(items, doc.res)
+		}
+		return documentsPage(r, coll, items)
+	}
+
+	pk, err := partitionKeyHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := docs.get(documentKey(pk, id))
+	if doc == nil {
+		return nil, errorf(http.StatusNotFound, "entity with the specified id does not exist in the system")
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return &response{statusCode: http.StatusOK, body: doc.res, headers: etagHeader(doc)}, nil
+
+	case http.MethodPut:
+		return e.replaceDocument(r, coll, doc, pk, id)
+
+	case http.MethodDelete:
+		err = checkIfMatch(r, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		docs.delete(documentKey(pk, id))
+		return &response{statusCode: http.StatusNoContent}, nil
+	}
+
+	return nil, errorf(http.StatusMethodNotAllowed, "method not allowed")
+}
+
+func (e *Emulator) createDocument(r *http.Request, coll *node) (*response, error) {
+	pk, err := partitionKeyHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := readResource(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.runTriggers(r, coll, res, "Create")
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkPartitionKey(coll, res, pk)
+	if err != nil {
+		return nil, err
+	}
+
+	key := documentKey(pk, res["id"].(string))
+	if coll.children["docs"].get(key) != nil {
+		return nil, errorf(http.StatusConflict, "entity with the specified id already exists in the system")
+	}
+
+	return e.putDocument(coll, key, res, http.StatusCreated)
+}
+
+func (e *Emulator) replaceDocument(r *http.Request, coll *node, old *node, pk, id string) (*response, error) {
+	err := checkIfMatch(r, old)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := readResource(r)
+	if err != nil {
+		return nil, err
+	}
+	if res["id"] != id {
+		return nil, errorf(http.StatusBadRequest, "the id of the document does not match the request")
+	}
+
+	err = e.runTriggers(r, coll, res, "Replace")
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkPartitionKey(coll, res, pk)
+	if err != nil {
+		return nil, err
+	}
+
+	res["_rid"] = old.res["_rid"]
+
+	return e.putDocument(coll, documentKey(pk, id), res, http.StatusOK)
+}
+
+// putDocument stores res under key, once it has checked the unique keys of
+// the collection
+func (e *Emulator) putDocument(coll *node, key string, res map[string]interface{}, statusCode int) (*response, error) {
+	pk, _ := lookup(res, coll.coll.partitionKeyPath)
+
+	for _, uniqueKey := range coll.coll.uniqueKeys {
+		for _, other := range coll.children["docs"].list() {
+			if other.res["id"] == res["id"] {
+				continue
+			}
+			if otherPK, _ := lookup(other.res, coll.coll.partitionKeyPath); !equal(otherPK, pk) {
+				continue
+			}
+
+			if uniqueKeyConflicts(uniqueKey, res, other.res) {
+				return nil, errorf(http.StatusConflict, "unique index constraint violation")
+			}
+		}
+	}
+
+	self, _ := coll.res["_self"].(string)
+	e.stamp("docs", self+"docs/"+res["id"].(string), res)
+
+	coll.coll.lsn++
+	res["_lsn"] = coll.coll.lsn
+
+	doc := &node{res: res}
+	coll.children["docs"].put(key, doc)
+
+	return &response{statusCode: statusCode, body: res, headers: etagHeader(doc)}, nil
+}
+
+// uniqueKeyConflicts returns true if every path of uniqueKey has the same
+// value in one and two.  As in the RP's fake clients, documents which do not
+// set a path never conflict.
+func uniqueKeyConflicts(uniqueKey [][]string, one, two map[string]interface{}) bool {
+	for _, path := range uniqueKey {
+		v1, found1 := lookup(one, path)
+		v2, found2 := lookup(two, path)
+		if !found1 || !found2 || v1 == "" || !equal(v1, v2) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runTriggers runs the pre-triggers requested by r on res
+func (e *Emulator) runTriggers(r *http.Request, coll *node, res map[string]interface{}, operation string) error {
+	if r.Header.Get("X-Ms-Documentdb-Post-Trigger-Include") != "" {
+		return errorf(http.StatusBadRequest, "post-triggers are not supported by the emulator")
+	}
+
+	include := r.Header.Get("X-Ms-Documentdb-Pre-Trigger-Include")
+	if include == "" {
+		return nil
+	}
+
+	for _, id := range strings.Split(include, ",") {
+		trigger := coll.children["triggers"].get(id)
+		if trigger == nil {
+			return errorf(http.StatusBadRequest, "trigger %q does not exist", id)
+		}
+
+		if trigger.res["triggerType"] != "Pre" {
+			return errorf(http.StatusBadRequest, "trigger %q is not a pre-trigger", id)
+		}
+
+		if op := trigger.res["triggerOperation"]; op != "All" && op != operation {
+			return errorf(http.StatusBadRequest, "trigger %q does not apply to %s operations", id, operation)
+		}
+
+		f, found := triggers[id]
+		if !found {
+			return errorf(http.StatusBadRequest, "trigger %q is not supported by the emulator", id)
+		}
+
+		f(e.now(), res)
+	}
+
+	return nil
+}
+
+func (e *Emulator) queryDocuments(r *http.Request, coll *node) (*response, error) {
+	var body struct {
+		Query      string `json:"query"`
+		Parameters []struct {
+			Name  string      `json:"name"`
+			Value interface{} `json:"value"`
+		} `json:"parameters"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "the request body must be a query")
+	}
+
+	q, err := parseQuery(body.Query)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "%s", err)
+	}
+
+	params := map[string]interface{}{}
+	for _, p := range body.Parameters {
+		params[p.Name] = p.Value
+	}
+
+	var docs []map[string]interface{}
+	for _, doc := range coll.children["docs"].list() {
+		if r.Header.Get("X-Ms-Documentdb-Partitionkey") != "" {
+			pk, err := partitionKeyHeader(r)
+			if err != nil {
+				return nil, err
+			}
+			if v, _ := lookup(doc.res, coll.coll.partitionKeyPath); v != pk {
+				continue
+			}
+		}
+
+		docs = append(docs, doc.res)
+	}
+
+	results, err := q.run(docs, params, e.now())
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "%s", err)
+	}
+
+	if q.count {
+		return &response{statusCode: http.StatusOK, body: map[string]interface{}{
+			"_rid":      rid(coll),
+			"Documents": results,
+			"_count":    len(results),
+		}}, nil
+	}
+
+	return documentsPage(r, coll, results)
+}
+
+// changeFeed returns the documents which have been created or replaced since
+// the continuation in the If-None-Match header, which is the _lsn of the
+// last document returned
+func (e *Emulator) changeFeed(r *http.Request, coll *node) (*response, error) {
+	var since int
+	switch ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch {
+	case "":
+	case "*":
+		since = coll.coll.lsn
+	default:
+		var err error
+		since, err = strconv.Atoi(strings.Trim(ifNoneMatch, `"`))
+		if err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid change feed continuation")
+		}
+	}
+
+	var changed []*node
+	for _, doc := range coll.children["docs"].list() {
+		if lsn(doc) > since {
+			changed = append(changed, doc)
+		}
+	}
+	sortByLSN(changed)
+
+	if max, err := strconv.Atoi(r.Header.Get("X-Ms-Max-Item-Count")); err == nil && max > 0 && len(changed) > max {
+		changed = changed[:max]
+	}
+
+	if len(changed) == 0 {
+		return &response{statusCode: http.StatusNotModified, headers: http.Header{
+			"Etag": []string{strconv.Itoa(since)},
+		}}, nil
+	}
+
+	items := make([]interface{}, 0, len(changed))
+	for _, doc := range changed {
+		items = append(items, doc.res)
+	}
+
+	return &response{
+		statusCode: http.StatusOK,
+		body: map[string]interface{}{
+			"_rid":      rid(coll),
+			"Documents": items,
+			"_count":    len(items),
+		},
+		headers: http.Header{
+			"Etag": []string{strconv.Itoa(lsn(changed[len(changed)-1]))},
+		},
+	}, nil
+}
+
+func documentsPage(r *http.Request, coll *node, items []interface{}) (*response, error) {
+	items, headers, err := page(r, items)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response{
+		statusCode: http.StatusOK,
+		body: map[string]interface{}{
+			"_rid":      rid(coll),
+			"Documents": items,
+			"_count":    len(items),
+		},
+		headers: headers,
+	}, nil
+}
+
+// purge deletes the documents of coll whose time to live has passed.  As in
+// Cosmos DB, the ttl of a document only applies if the collection has a
+// default time to live.
+func (e *Emulator) purge(coll *node) {
+	if coll.coll.defaultTTL == nil {
+		return
+	}
+
+	now := e.now().Unix()
+	docs := coll.children["docs"]
+
+	for _, key := range append([]string{}, docs.keys...) {
+		doc := docs.get(key)
+
+		ttl := int64(*coll.coll.defaultTTL)
+		if v, ok := normalize(doc.res["ttl"]).(float64); ok {
+			ttl = int64(v)
+		}
+
+		ts, _ := normalize(doc.res["_ts"]).(float64)
+		if ttl > 0 && int64(ts)+ttl <= now {
+			docs.delete(key)
+		}
+	}
+}
+
+// partitionKeyHeader returns the partition key in the header of r, which the
+// generated clients send as a JSON array holding a single string
+func partitionKeyHeader(r *http.Request) (string, error) {
+	var pk []string
+	err := json.Unmarshal([]byte(r.Header.Get("X-Ms-Documentdb-Partitionkey")), &pk)
+	if err != nil || len(pk) != 1 {
+		return "", errorf(http.StatusBadRequest, "the partition key supplied in the x-ms-partitionkey header is invalid")
+	}
+
+	return pk[0], nil
+}
+
+func checkPartitionKey(coll *node, res map[string]interface{}, pk string) error {
+	if v, _ := lookup(res, coll.coll.partitionKeyPath); v != pk {
+		return errorf(http.StatusBadRequest, "the partition key extracted from the document doesn't match the one specified in the header")
+	}
+	return nil
+}
+
+func documentKey(pk, id string) string {
+	return pk + "\x00" + id
+}
@@ -0,0 +1,639 @@
+package emulator
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+// MasterKey is the master key accepted by the emulator.  It is the well-known
+// key of the Azure Cosmos DB emulator.
+const MasterKey = "C2y6yDjf5/R+ob0N8A7Cgv30VRDJIWEHLM+4QDU5DE2nQ9nDuVTqobD4b8mGGyPMbIZnqyMsEcaGQy67XIw/Jw=="
+
+// Emulator is an in-memory implementation of the subset of the Cosmos DB REST
+// API which is used by pkg/database/cosmosdb: databases, collections,
+// documents (including queries and the change feed), partition key ranges,
+// triggers, users and permissions.  It is intended for running the RP
+// offline, and is not a faithful model of Cosmos DB's consistency,
+// throughput or indexing behaviour.
+type Emulator struct {
+	log *logrus.Entry
+
+	mu   sync.Mutex
+	root *node
+
+	masterKey []byte
+	now       func() time.Time
+}
+
+// node is a resource: the account, a database, collection, user, permission,
+// trigger or document
+type node struct {
+	res      map[string]interface{}
+	children map[string]*ordered
+
+	// coll is set on collections
+	coll *collection
+}
+
+type collection struct {
+	partitionKeyPath []string
+	uniqueKeys       [][][]string
+	defaultTTL       *int
+	lsn              int
+}
+
+// ordered holds the resources of a feed in the order in which they were
+// created
+type ordered struct {
+	keys  []string
+	items map[string]*node
+}
+
+func newOrdered() *ordered {
+	return &ordered{items: map[string]*node{}}
+}
+
+func (o *ordered) get(key string) *node { return o.items[key] }
+
+func (o *ordered) put(key string, n *node) {
+	if _, found := o.items[key]; !found {
+		o.keys = append(o.keys, key)
+	}
+	o.items[key] = n
+}
+
+func (o *ordered) delete(key string) {
+	if _, found := o.items[key]; !found {
+		return
+	}
+	delete(o.items, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (o *ordered) list() []*node {
+	nodes := make([]*node, 0, len(o.keys))
+	for _, k := range o.keys {
+		nodes = append(nodes, o.items[k])
+	}
+	return nodes
+}
+
+// feeds describes each type of resource: the feeds it may contain, the key
+// under which lists of it are returned and the links it carries
+var feeds = map[string]struct {
+	children []string
+	listKey  string
+	links    map[string]string
+}{
+	"":            {children: []string{"dbs"}},
+	"dbs":         {children: []string{"colls", "users"}, listKey: "Databases", links: map[string]string{"_colls": "colls/", "_users": "users/"}},
+	"colls":       {children: []string{"docs", "triggers"}, listKey: "DocumentCollections", links: map[string]string{"_docs": "docs/", "_sprocs": "sprocs/", "_triggers": "triggers/", "_udfs": "udfs/", "_conflicts": "conflicts/"}},
+	"users":       {children: []string{"permissions"}, listKey: "Users", links: map[string]string{"_permissions": "permissions/"}},
+	"permissions": {listKey: "Permissions"},
+	"triggers":    {listKey: "Triggers"},
+	"docs":        {listKey: "Documents", links: map[string]string{"_attachments": "attachments/"}},
+}
+
+// New returns a new, empty Emulator
+func New(log *logrus.Entry) *Emulator {
+	masterKey, err := base64.StdEncoding.DecodeString(MasterKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Emulator{
+		log:       log,
+		root:      newNode("", nil),
+		masterKey: masterKey,
+		now:       time.Now,
+	}
+}
+
+func newNode(typ string, res map[string]interface{}) *node {
+	n := &node{res: res, children: map[string]*ordered{}}
+	for _, child := range feeds[typ].children {
+		n.children[child] = newOrdered()
+	}
+	return n
+}
+
+// statusError is an error returned to the client as a Cosmos DB error body
+type statusError struct {
+	statusCode int
+	message    string
+}
+
+func (err *statusError) Error() string { return err.message }
+
+func errorf(statusCode int, format string, a ...interface{}) error {
+	return &statusError{statusCode: statusCode, message: fmt.Sprintf(format, a...)}
+}
+
+// response is a successful response
+type response struct {
+	statusCode int
+	body       interface{}
+	headers    http.Header
+}
+
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resp, err := e.serve(r)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err, ok := err.(*statusError); ok {
+			statusCode = err.statusCode
+		}
+
+		if statusCode >= http.StatusInternalServerError {
+			e.log.Errorf("%s %s: %s", r.Method, r.URL.Path, err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"code":    strings.ReplaceAll(http.StatusText(statusCode), " ", ""),
+			"message": err.Error(),
+		})
+		return
+	}
+
+	for k, v := range resp.headers {
+		w.Header()[k] = v
+	}
+
+	if resp.body == nil {
+		w.WriteHeader(resp.statusCode)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.statusCode)
+	_ = json.NewEncoder(w).Encode(resp.body)
+}
+
+func (e *Emulator) serve(r *http.Request) (*response, error) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) == 1 && segments[0] == "" {
+		return nil, errorf(http.StatusNotFound, "resource not found")
+	}
+
+	// a path with an odd number of segments addresses a feed, and one with
+	// an even number addresses an item in a feed
+	var resourceType, resourceLink string
+	if len(segments)%2 == 1 {
+		resourceType = segments[len(segments)-1]
+		resourceLink = strings.Join(segments[:len(segments)-1], "/")
+	} else {
+		resourceType = segments[len(segments)-2]
+		resourceLink = strings.Join(segments, "/")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.authorize(r, resourceType, resourceLink)
+	if err != nil {
+		return nil, err
+	}
+
+	// walk to the parent of the addressed feed
+	stop := len(segments) - 1
+	if len(segments)%2 == 0 {
+		stop--
+	}
+
+	parent, parentType := e.root, ""
+	for i := 0; i < stop; i += 2 {
+		typ, id := segments[i], segments[i+1]
+		if !contains(feeds[parentType].children, typ) || typ == "docs" {
+			return nil, errorf(http.StatusNotFound, "resource not found")
+		}
+
+		n := parent.children[typ].get(id)
+		if n == nil {
+			return nil, errorf(http.StatusNotFound, "resource not found")
+		}
+
+		parent, parentType = n, typ
+	}
+
+	feed := resourceType
+	var id string
+	if len(segments)%2 == 0 {
+		id = segments[len(segments)-1]
+	}
+
+	switch {
+	case parentType == "colls" && feed == "pkranges" && id == "" && r.Method == http.MethodGet:
+		return e.partitionKeyRanges(parent)
+	case parentType == "colls" && feed == "docs":
+		e.purge(parent)
+		return e.serveDocuments(r, parent, id)
+	case !contains(feeds[parentType].children, feed):
+		return nil, errorf(http.StatusNotFound, "resource not found")
+	}
+
+	path := strings.Join(segments, "/")
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		return e.create(r, parent, feed, path)
+	case id == "" && r.Method == http.MethodGet:
+		return e.list(parent, feed)
+	case r.Method == http.MethodGet:
+		return e.get(parent, feed, id)
+	case r.Method == http.MethodPost, r.Method == http.MethodPut:
+		return e.replace(r, parent, feed, id, path)
+	case r.Method == http.MethodDelete:
+		return e.delete(r, parent, feed, id)
+	}
+
+	return nil, errorf(http.StatusMethodNotAllowed, "method not allowed")
+}
+
+func (e *Emulator) create(r *http.Request, parent *node, feed, path string) (*response, error) {
+	res, err := readResource(r)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := res["id"].(string)
+	if parent.children[feed].get(id) != nil {
+		return nil, errorf(http.StatusConflict, "resource with specified id already exists")
+	}
+
+	n, err := e.newResource(feed, path+"/"+id, res, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	parent.children[feed].put(id, n)
+
+	return &response{statusCode: http.StatusCreated, body: n.res, headers: etagHeader(n)}, nil
+}
+
+// newResource returns a new node of type feed for res, which has been
+// validated by readResource
+func (e *Emulator) newResource(feed, self string, res map[string]interface{}, defaultTTL *int) (*node, error) {
+	n := newNode(feed, res)
+
+	switch feed {
+	case "colls":
+		n.coll = &collection{defaultTTL: defaultTTL}
+
+		if pk, ok := res["partitionKey"].(map[string]interface{}); ok {
+			if paths, ok := pk["paths"].([]interface{}); ok && len(paths) == 1 {
+				n.coll.partitionKeyPath = splitPath(fmt.Sprint(paths[0]))
+			}
+		}
+		if n.coll.partitionKeyPath == nil {
+			return nil, errorf(http.StatusBadRequest, "collection must have a partition key with a single path")
+		}
+
+		if ukp, ok := res["uniqueKeyPolicy"].(map[string]interface{}); ok {
+			uks, _ := ukp["uniqueKeys"].([]interface{})
+			for _, uk := range uks {
+				var key [][]string
+				paths, _ := uk.(map[string]interface{})["paths"].([]interface{})
+				for _, p := range paths {
+					key = append(key, splitPath(fmt.Sprint(p)))
+				}
+				n.coll.uniqueKeys = append(n.coll.uniqueKeys, key)
+			}
+		}
+
+	case "permissions":
+		sig := make([]byte, 32)
+		_, err := rand.Read(sig)
+		if err != nil {
+			return nil, err
+		}
+		res["_token"] = "type=resource&ver=1.0&sig=" + base64.StdEncoding.EncodeToString(sig)
+	}
+
+	e.stamp(feed, self, res)
+
+	return n, nil
+}
+
+// stamp sets the system properties of res
+func (e *Emulator) stamp(feed, self string, res map[string]interface{}) {
+	if _, found := res["_rid"]; !found {
+		rid := make([]byte, 8)
+		_, _ = rand.Read(rid)
+		res["_rid"] = base64.StdEncoding.EncodeToString(rid)
+	}
+	res["_self"] = self + "/"
+	res["_ts"] = e.now().Unix()
+	res["_etag"] = `"` + uuid.DefaultGenerator.Generate() + `"`
+
+	for k, v := range feeds[feed].links {
+		res[k] = v
+	}
+}
+
+func (e *Emulator) list(parent *node, feed string) (*response, error) {
+	items := []interface{}{}
+	for _, n := range parent.children[feed].list() {
+		items = append(items, n.res)
+	}
+
+	return &response{statusCode: http.StatusOK, body: map[string]interface{}{
+		"_rid":              rid(parent),
+		feeds[feed].listKey: items,
+		"_count":            len(items),
+	}}, nil
+}
+
+func (e *Emulator) get(parent *node, feed, id string) (*response, error) {
+	n := parent.children[feed].get(id)
+	if n == nil {
+		return nil, errorf(http.StatusNotFound, "resource not found")
+	}
+
+	return &response{statusCode: http.StatusOK, body: n.res, headers: etagHeader(n)}, nil
+}
+
+// replace replaces a resource.  The generated clients replace resources other
+// than documents with POST, expecting StatusCreated.
+func (e *Emulator) replace(r *http.Request, parent *node, feed, id, path string) (*response, error) {
+	old := parent.children[feed].get(id)
+	if old == nil {
+		return nil, errorf(http.StatusNotFound, "resource not found")
+	}
+
+	err := checkIfMatch(r, old)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := readResource(r)
+	if err != nil {
+		return nil, err
+	}
+	if res["id"] != id {
+		return nil, errorf(http.StatusBadRequest, "the id of the resource does not match the request")
+	}
+
+	res["_rid"] = old.res["_rid"]
+
+	var defaultTTL *int
+	if old.coll != nil {
+		defaultTTL = old.coll.defaultTTL
+	}
+
+	n, err := e.newResource(feed, path, res, defaultTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	// the resource keeps its children
+	n.children = old.children
+	if old.coll != nil {
+		n.coll.lsn = old.coll.lsn
+	}
+
+	parent.children[feed].put(id, n)
+
+	statusCode := http.StatusOK
+	if r.Method == http.MethodPost {
+		statusCode = http.StatusCreated
+	}
+
+	return &response{statusCode: statusCode, body: n.res, headers: etagHeader(n)}, nil
+}
+
+func (e *Emulator) delete(r *http.Request, parent *node, feed, id string) (*response, error) {
+	n := parent.children[feed].get(id)
+	if n == nil {
+		return nil, errorf(http.StatusNotFound, "resource not found")
+	}
+
+	err := checkIfMatch(r, n)
+	if err != nil {
+		return nil, err
+	}
+
+	parent.children[feed].delete(id)
+
+	return &response{statusCode: http.StatusNoContent}, nil
+}
+
+// partitionKeyRanges returns a single range covering the collection
+func (e *Emulator) partitionKeyRanges(coll *node) (*response, error) {
+	return &response{statusCode: http.StatusOK, body: map[string]interface{}{
+		"_rid": rid(coll),
+		"PartitionKeyRanges": []interface{}{
+			map[string]interface{}{
+				"id":           "0",
+				"_rid":         rid(coll),
+				"_etag":        coll.res["_etag"],
+				"minInclusive": "",
+				"maxExclusive": "FF",
+				"status":       "online",
+			},
+		},
+		"_count": 1,
+	}}, nil
+}
+
+// authorize checks the Authorization header of r.  A master key signature
+// allows any request; a resource token returned by a permission allows
+// requests within the permitted resource.
+func (e *Emulator) authorize(r *http.Request, resourceType, resourceLink string) error {
+	authorization, err := url.QueryUnescape(r.Header.Get("Authorization"))
+	if err != nil {
+		return errorf(http.StatusUnauthorized, "invalid authorization header")
+	}
+
+	fields := map[string]string{}
+	for _, field := range strings.Split(authorization, "&") {
+		k, v, _ := strings.Cut(field, "=")
+		fields[k] = v
+	}
+
+	switch fields["type"] {
+	case "master":
+		h := hmac.New(sha256.New, e.masterKey)
+		fmt.Fprintf(h, "%s\n%s\n%s\n%s\n\n", strings.ToLower(r.Method), resourceType, resourceLink, strings.ToLower(r.Header.Get("x-ms-date")))
+
+		sig, err := base64.StdEncoding.DecodeString(fields["sig"])
+		if err != nil || !hmac.Equal(sig, h.Sum(nil)) {
+			return errorf(http.StatusUnauthorized, "the input authorization token can't serve the request")
+		}
+		return nil
+
+	case "resource":
+		for _, db := range e.root.children["dbs"].list() {
+			for _, user := range db.children["users"].list() {
+				for _, perm := range user.children["permissions"].list() {
+					if perm.res["_token"] != authorization {
+						continue
+					}
+
+					resource, _ := perm.res["resource"].(string)
+					path := strings.Trim(r.URL.Path, "/")
+					if path != resource && !strings.HasPrefix(path, resource+"/") {
+						return errorf(http.StatusForbidden, "the resource token does not permit access to the resource")
+					}
+
+					if perm.res["permissionMode"] == "Read" && r.Method != http.MethodGet &&
+						!(r.Method == http.MethodPost && strings.EqualFold(r.Header.Get("X-Ms-Documentdb-Isquery"), "True")) {
+						return errorf(http.StatusForbidden, "the resource token permits read access only")
+					}
+
+					return nil
+				}
+			}
+		}
+	}
+
+	return errorf(http.StatusUnauthorized, "the input authorization token can't serve the request")
+}
+
+// readResource decodes the JSON object in the body of r.  Numbers are kept as
+// json.Number so that documents are stored unchanged.
+func readResource(r *http.Request) (map[string]interface{}, error) {
+	d := json.NewDecoder(r.Body)
+	d.UseNumber()
+
+	var res map[string]interface{}
+	err := d.Decode(&res)
+	if err != nil || res == nil {
+		return nil, errorf(http.StatusBadRequest, "the request body must be a JSON object")
+	}
+
+	id, ok := res["id"].(string)
+	if !ok || id == "" || len(id) > 255 || strings.ContainsAny(id, `/\?#`) {
+		return nil, errorf(http.StatusBadRequest, "the resource has an invalid id")
+	}
+
+	return res, nil
+}
+
+func checkIfMatch(r *http.Request, n *node) error {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != n.res["_etag"] {
+		return errorf(http.StatusPreconditionFailed, "operation cannot be performed because one of the specified precondition is not met")
+	}
+	return nil
+}
+
+func etagHeader(n *node) http.Header {
+	return http.Header{"Etag": []string{fmt.Sprint(n.res["_etag"])}}
+}
+
+func rid(n *node) interface{} {
+	if n.res == nil {
+		return ""
+	}
+	return n.res["_rid"]
+}
+
+// splitPath splits a JSON path such as /a/b
+func splitPath(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+// lookup returns the value at path in doc
+func lookup(doc map[string]interface{}, path []string) (interface{}, bool) {
+	var v interface{} = doc
+	for _, el := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok = m[el]
+		if !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// continuation returns the offset encoded in the continuation header of r
+func continuation(r *http.Request) (int, error) {
+	c := r.Header.Get("X-Ms-Continuation")
+	if c == "" {
+		return 0, nil
+	}
+
+	offset, err := strconv.Atoi(c)
+	if err != nil || offset < 0 {
+		return 0, errorf(http.StatusBadRequest, "invalid continuation token")
+	}
+
+	return offset, nil
+}
+
+// page returns the page of items at the continuation of r, and the headers
+// to return with it
+func page(r *http.Request, items []interface{}) ([]interface{}, http.Header, error) {
+	offset, err := continuation(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+
+	max, err := strconv.Atoi(r.Header.Get("X-Ms-Max-Item-Count"))
+	if err != nil || max <= 0 {
+		max = len(items)
+	}
+
+	end := offset + max
+	if end > len(items) {
+		end = len(items)
+	}
+
+	headers := http.Header{}
+	if end < len(items) {
+		headers.Set("X-Ms-Continuation", strconv.Itoa(end))
+	}
+
+	return items[offset:end], headers, nil
+}
+
+// sortByLSN sorts documents by their _lsn
+func sortByLSN(docs []*node) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		return lsn(docs[i]) < lsn(docs[j])
+	})
+}
+
+func lsn(n *node) int {
+	f, _ := normalize(n.res["_lsn"]).(float64)
+	return int(f)
+}
@@ -0,0 +1,363 @@
+package emulator_test
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/database/emulator"
+)
+
+const (
+	dbName         = "ARO"
+	subscriptionID = "00000000-0000-0000-0000-000000000000"
+)
+
+// newDatabaseClient returns a client for a new emulator serving the
+// development database.  The emulator is stopped when the test ends.
+func newDatabaseClient(t *testing.T, authorizer cosmosdb.Authorizer) (cosmosdb.DatabaseClient, func(cosmosdb.Authorizer) cosmosdb.DatabaseClient) {
+	log := logrus.NewEntry(logrus.StandardLogger())
+
+	e := emulator.New(log)
+	err := e.ProvisionDevelopmentDatabase(dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewTLSServer(e)
+	t.Cleanup(ts.Close)
+
+	h, err := database.NewJSONHandle(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newClient := func(authorizer cosmosdb.Authorizer) cosmosdb.DatabaseClient {
+		return cosmosdb.NewDatabaseClient(log, ts.Client(), h, strings.TrimPrefix(ts.URL, "https://"), authorizer)
+	}
+
+	return newClient(authorizer), newClient
+}
+
+func masterKeyAuthorizer(t *testing.T) cosmosdb.Authorizer {
+	authorizer, err := cosmosdb.NewMasterKeyAuthorizer(emulator.MasterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return authorizer
+}
+
+func clusterDocument(name string, provisioningState api.ProvisioningState) *api.OpenShiftClusterDocument {
+	key := strings.ToLower("/subscriptions/" + subscriptionID + "/resourceGroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/" + name)
+
+	return &api.OpenShiftClusterDocument{
+		ID:                        name,
+		Key:                       key,
+		PartitionKey:              subscriptionID,
+		ClusterResourceGroupIDKey: "/subscriptions/" + subscriptionID + "/resourcegroups/" + name,
+		ClientIDKey:               "client-" + name,
+		OpenShiftCluster: &api.OpenShiftCluster{
+			ID:   key,
+			Name: name,
+			Properties: api.OpenShiftClusterProperties{
+				ProvisioningState: provisioningState,
+			},
+		},
+	}
+}
+
+func TestOpenShiftClusters(t *testing.T) {
+	ctx := context.Background()
+
+	dbc, _ := newDatabaseClient(t, masterKeyAuthorizer(t))
+
+	dbOpenShiftClusters, err := database.NewOpenShiftClusters(ctx, dbc, dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the constructor tolerates its triggers already existing
+	_, err = database.NewOpenShiftClusters(ctx, dbc, dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, doc := range []*api.OpenShiftClusterDocument{
+		clusterDocument("creating", api.ProvisioningStateCreating),
+		clusterDocument("succeeded", api.ProvisioningStateSucceeded),
+	} {
+		_, err = dbOpenShiftClusters.Create(ctx, doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("unique keys are enforced", func(t *testing.T) {
+		doc := clusterDocument("duplicate", api.ProvisioningStateSucceeded)
+		doc.ClientIDKey = "client-creating"
+
+		// OpenShiftClusters.Create reports conflicts as precondition failures
+		_, err := dbOpenShiftClusters.Create(ctx, doc)
+		if !cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("documents can be listed by prefix", func(t *testing.T) {
+		i, err := dbOpenShiftClusters.ListByPrefix(subscriptionID, "/subscriptions/"+subscriptionID+"/resourcegroups/resourcegroup/", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		docs, err := i.Next(ctx, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs.OpenShiftClusterDocuments) != 2 {
+			t.Errorf("got %d documents", len(docs.OpenShiftClusterDocuments))
+		}
+	})
+
+	var changeFeed cosmosdb.OpenShiftClusterDocumentIterator
+
+	t.Run("the change feed returns created documents", func(t *testing.T) {
+		changeFeed = dbOpenShiftClusters.ChangeFeed()
+
+		docs, err := changeFeed.Next(ctx, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs.OpenShiftClusterDocuments) != 2 {
+			t.Errorf("got %d documents", len(docs.OpenShiftClusterDocuments))
+		}
+
+		docs, err = changeFeed.Next(ctx, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docs != nil {
+			t.Errorf("got %d unexpected documents", len(docs.OpenShiftClusterDocuments))
+		}
+	})
+
+	t.Run("queued documents can be dequeued and leased", func(t *testing.T) {
+		n, err := dbOpenShiftClusters.QueueLength(ctx, "OpenShiftClusters")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("got queue length %d", n)
+		}
+
+		doc, err := dbOpenShiftClusters.Dequeue(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc == nil || doc.ID != "creating" {
+			t.Fatalf("got %#v", doc)
+		}
+		if doc.LeaseOwner == "" || doc.Dequeues != 1 {
+			t.Errorf("got lease owner %q, dequeues %d", doc.LeaseOwner, doc.Dequeues)
+		}
+		if expires := doc.LeaseExpires - int(time.Now().Unix()); expires < 55 || expires > 60 {
+			t.Errorf("got lease expiring in %ds", expires)
+		}
+
+		n, err = dbOpenShiftClusters.QueueLength(ctx, "OpenShiftClusters")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 0 {
+			t.Errorf("got queue length %d", n)
+		}
+
+		doc, err = dbOpenShiftClusters.Dequeue(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc != nil {
+			t.Errorf("dequeued leased document %q", doc.ID)
+		}
+
+		doc, err = dbOpenShiftClusters.EndLease(ctx, clusterDocument("creating", "").Key, api.ProvisioningStateSucceeded, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.LeaseOwner != "" || doc.LeaseExpires != 0 {
+			t.Errorf("got lease owner %q, expiry %d", doc.LeaseOwner, doc.LeaseExpires)
+		}
+	})
+
+	t.Run("the change feed returns replaced documents", func(t *testing.T) {
+		docs, err := changeFeed.Next(ctx, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs.OpenShiftClusterDocuments) != 1 || docs.OpenShiftClusterDocuments[0].ID != "creating" {
+			t.Errorf("got %#v", docs)
+		}
+	})
+
+	t.Run("replacing a stale document fails", func(t *testing.T) {
+		doc, err := dbOpenShiftClusters.Get(ctx, clusterDocument("succeeded", "").Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = dbOpenShiftClusters.Patch(ctx, doc.Key, func(doc *api.OpenShiftClusterDocument) error { return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = cosmosdb.NewOpenShiftClusterDocumentClient(cosmosdb.NewCollectionClient(dbc, dbName), "OpenShiftClusters").Replace(ctx, subscriptionID, doc, &cosmosdb.Options{})
+		if !cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) {
+			t.Error(err)
+		}
+	})
+}
+
+func TestSubscriptions(t *testing.T) {
+	ctx := context.Background()
+
+	dbc, _ := newDatabaseClient(t, masterKeyAuthorizer(t))
+
+	dbSubscriptions, err := database.NewSubscriptions(ctx, dbc, dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = dbSubscriptions.Create(ctx, &api.SubscriptionDocument{
+		ID:       subscriptionID,
+		Deleting: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := dbSubscriptions.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc == nil {
+		t.Fatal("nothing dequeued")
+	}
+
+	doc, err = dbSubscriptions.EndLease(ctx, doc.ID, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expires := doc.LeaseExpires - int(time.Now().Unix()); expires < 595 || expires > 600 {
+		t.Errorf("got lease expiring in %ds", expires)
+	}
+}
+
+func TestBilling(t *testing.T) {
+	ctx := context.Background()
+
+	dbc, _ := newDatabaseClient(t, masterKeyAuthorizer(t))
+
+	dbBilling, err := database.NewBilling(ctx, dbc, dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := dbBilling.Create(ctx, &api.BillingDocument{
+		ID:                        "id",
+		Key:                       "key",
+		ClusterResourceGroupIDKey: "resourcegroup",
+		Billing:                   &api.Billing{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Billing.CreationTime == 0 || doc.Billing.DeletionTime != 0 {
+		t.Errorf("got creation time %d, deletion time %d", doc.Billing.CreationTime, doc.Billing.DeletionTime)
+	}
+
+	doc, err = dbBilling.MarkForDeletion(ctx, doc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Billing.DeletionTime == 0 {
+		t.Error("deletion time not set")
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	dbc, newClient := newDatabaseClient(t, masterKeyAuthorizer(t))
+
+	userc := cosmosdb.NewUserClient(dbc, dbName)
+	_, err := userc.Create(ctx, &cosmosdb.User{ID: "gateway"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	permission, err := cosmosdb.NewPermissionClient(userc, "gateway").Create(ctx, &cosmosdb.Permission{
+		ID:             "gateway",
+		PermissionMode: cosmosdb.PermissionModeRead,
+		Resource:       "dbs/" + dbName + "/colls/Gateway",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbGateway, err := database.NewGateway(ctx, dbc, dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = dbGateway.Create(ctx, &api.GatewayDocument{ID: "id", Gateway: &api.Gateway{ID: "id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenGateway, err := database.NewGateway(ctx, newClient(cosmosdb.NewTokenAuthorizer(permission.Token)), dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = tokenGateway.Get(ctx, "id")
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = tokenGateway.Create(ctx, &api.GatewayDocument{ID: "other", Gateway: &api.Gateway{ID: "other"}})
+	if !cosmosdb.IsErrorStatusCode(err, http.StatusForbidden) {
+		t.Error(err)
+	}
+
+	tokenSubscriptions, err := database.NewSubscriptions(ctx, newClient(cosmosdb.NewTokenAuthorizer(permission.Token)), dbName)
+	if !cosmosdb.IsErrorStatusCode(err, http.StatusForbidden) {
+		t.Error(err)
+	}
+	if tokenSubscriptions != nil {
+		t.Error("unexpected subscriptions client")
+	}
+
+	badKey, err := cosmosdb.NewMasterKeyAuthorizer("YmFkIGtleQ==")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = database.NewGateway(ctx, newClient(badKey), dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cosmosdb.NewUserClient(newClient(badKey), dbName).Get(ctx, "gateway")
+	if !cosmosdb.IsErrorStatusCode(err, http.StatusUnauthorized) {
+		t.Error(err)
+	}
+}
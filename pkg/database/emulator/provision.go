@@ -0,0 +1,110 @@
+package emulator
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/deploy/assets"
+)
+
+// ProvisionDevelopmentDatabase creates the database dbName with the
+// collections of the development deployment template, in the same way as
+// deploying databases-development.json to a database account does
+func (e *Emulator) ProvisionDevelopmentDatabase(dbName string) error {
+	b, err := assets.EmbeddedFiles.ReadFile("databases-development.json")
+	if err != nil {
+		return err
+	}
+
+	var template struct {
+		Resources []struct {
+			Type       string `json:"type"`
+			Properties struct {
+				Resource json.RawMessage `json:"resource"`
+			} `json:"properties"`
+		} `json:"resources"`
+	}
+
+	err = json.Unmarshal(b, &template)
+	if err != nil {
+		return err
+	}
+
+	var collections []*cosmosdb.Collection
+	defaultTTLs := map[string]*int{}
+
+	for _, r := range template.Resources {
+		if r.Type != "Microsoft.DocumentDB/databaseAccounts/sqlDatabases/containers" {
+			continue
+		}
+
+		var coll *cosmosdb.Collection
+		err = json.Unmarshal(r.Properties.Resource, &coll)
+		if err != nil {
+			return err
+		}
+
+		var ttl struct {
+			DefaultTTL *int `json:"defaultTtl"`
+		}
+		err = json.Unmarshal(r.Properties.Resource, &ttl)
+		if err != nil {
+			return err
+		}
+
+		collections = append(collections, coll)
+		defaultTTLs[coll.ID] = ttl.DefaultTTL
+	}
+
+	return e.Provision(dbName, collections, defaultTTLs)
+}
+
+// Provision creates the database dbName with collections, if it does not
+// already exist.  defaultTTLs holds the default time to live of collections
+// which have one, which the Collection type cannot express.
+func (e *Emulator) Provision(dbName string, collections []*cosmosdb.Collection, defaultTTLs map[string]*int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	dbs := e.root.children["dbs"]
+	if dbs.get(dbName) != nil {
+		return nil
+	}
+
+	db, err := e.newResource("dbs", "dbs/"+dbName, map[string]interface{}{"id": dbName}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, coll := range collections {
+		var res map[string]interface{}
+		err = convert(coll, &res)
+		if err != nil {
+			return err
+		}
+
+		n, err := e.newResource("colls", "dbs/"+dbName+"/colls/"+coll.ID, res, defaultTTLs[coll.ID])
+		if err != nil {
+			return fmt.Errorf("%s: %w", coll.ID, err)
+		}
+
+		db.children["colls"].put(coll.ID, n)
+	}
+
+	dbs.put(dbName, db)
+
+	return nil
+}
+
+func convert(in, out interface{}) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, out)
+}
@@ -0,0 +1,1008 @@
+package emulator
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// The emulator implements the subset of the Cosmos DB SQL dialect which the RP
+// uses:
+//
+//	SELECT [TOP n] {* | VALUE COUNT(1) | VALUE expr} FROM coll [[AS] alias]
+//	  [WHERE expr] [ORDER BY expr [ASC|DESC]]
+//
+// Expressions support property paths, @parameters, literals, comparisons,
+// IN, AND, OR, NOT, arithmetic, the ?? coalescing operator and a handful of
+// system functions.  As in Cosmos DB, an expression which refers to a missing
+// property or combines values of the wrong types is undefined, and a document
+// only matches a WHERE clause which evaluates to true.
+
+// undefined is the value of an expression which Cosmos DB treats as undefined
+type undefined struct{}
+
+type query struct {
+	top       int
+	count     bool
+	value     expr
+	alias     string
+	where     expr
+	orderBy   expr
+	orderDesc bool
+}
+
+// run returns the results of q over docs, which are in insertion order.  now
+// is the value of GetCurrentTimestamp().
+func (q *query) run(docs []map[string]interface{}, params map[string]interface{}, now time.Time) ([]interface{}, error) {
+	var matched []map[string]interface{}
+
+	for _, doc := range docs {
+		if q.where != nil {
+			v, err := q.where.eval(q.scope(doc, params, now))
+			if err != nil {
+				return nil, err
+			}
+			if v != true {
+				continue
+			}
+		}
+
+		matched = append(matched, doc)
+	}
+
+	if q.count {
+		return []interface{}{len(matched)}, nil
+	}
+
+	if q.orderBy != nil {
+		keys := make([]interface{}, len(matched))
+		for i, doc := range matched {
+			var err error
+			keys[i], err = q.orderBy.eval(q.scope(doc, params, now))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		idx := make([]int, len(matched))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(i, j int) bool {
+			if q.orderDesc {
+				return less(keys[idx[j]], keys[idx[i]])
+			}
+			return less(keys[idx[i]], keys[idx[j]])
+		})
+
+		sorted := make([]map[string]interface{}, len(matched))
+		for i, j := range idx {
+			sorted[i] = matched[j]
+		}
+		matched = sorted
+	}
+
+	if q.top > 0 && len(matched) > q.top {
+		matched = matched[:q.top]
+	}
+
+	results := make([]interface{}, 0, len(matched))
+	for _, doc := range matched {
+		if q.value == nil {
+			results = append(results, doc)
+			continue
+		}
+
+		v, err := q.value.eval(q.scope(doc, params, now))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := v.(undefined); !ok {
+			results = append(results, v)
+		}
+	}
+
+	return results, nil
+}
+
+func (q *query) scope(doc map[string]interface{}, params map[string]interface{}, now time.Time) *scope {
+	return &scope{alias: q.alias, doc: doc, params: params, now: now}
+}
+
+type scope struct {
+	alias  string
+	doc    map[string]interface{}
+	params map[string]interface{}
+	now    time.Time
+}
+
+type expr interface {
+	eval(*scope) (interface{}, error)
+}
+
+type literal struct{ v interface{} }
+
+func (e literal) eval(*scope) (interface{}, error) { return e.v, nil }
+
+type param struct{ name string }
+
+func (e param) eval(s *scope) (interface{}, error) {
+	v, found := s.params[e.name]
+	if !found {
+		return nil, fmt.Errorf("parameter %s is not defined", e.name)
+	}
+	return normalize(v), nil
+}
+
+// path is a property path rooted at the alias of the collection
+type path struct {
+	root     string
+	elements []string
+}
+
+func (e path) eval(s *scope) (interface{}, error) {
+	if !strings.EqualFold(e.root, s.alias) {
+		return nil, fmt.Errorf("identifier %s could not be resolved", e.root)
+	}
+
+	var v interface{} = s.doc
+	for _, el := range e.elements {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return undefined{}, nil
+		}
+
+		v, ok = m[el]
+		if !ok {
+			return undefined{}, nil
+		}
+	}
+
+	return normalize(v), nil
+}
+
+type unary struct {
+	op string
+	e  expr
+}
+
+func (e unary) eval(s *scope) (interface{}, error) {
+	v, err := e.e.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "NOT":
+		if b, ok := v.(bool); ok {
+			return !b, nil
+		}
+	case "-":
+		if f, ok := v.(float64); ok {
+			return -f, nil
+		}
+	}
+
+	return undefined{}, nil
+}
+
+type binary struct {
+	op   string
+	l, r expr
+}
+
+func (e binary) eval(s *scope) (interface{}, error) {
+	l, err := e.l.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "??":
+		if _, ok := l.(undefined); ok {
+			return e.r.eval(s)
+		}
+		return l, nil
+	case "AND":
+		if l == false {
+			return false, nil
+		}
+	case "OR":
+		if l == true {
+			return true, nil
+		}
+	}
+
+	r, err := e.r.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "AND":
+		if r == false {
+			return false, nil
+		}
+		if l == true && r == true {
+			return true, nil
+		}
+		return undefined{}, nil
+
+	case "OR":
+		if r == true {
+			return true, nil
+		}
+		if l == false && r == false {
+			return false, nil
+		}
+		return undefined{}, nil
+
+	case "=", "!=":
+		if !sameType(l, r) {
+			return undefined{}, nil
+		}
+		return equal(l, r) == (e.op == "="), nil
+
+	case "<", "<=", ">", ">=":
+		if !orderable(l, r) {
+			return undefined{}, nil
+		}
+		switch e.op {
+		case "<":
+			return less(l, r), nil
+		case "<=":
+			return !less(r, l), nil
+		case ">":
+			return less(r, l), nil
+		default:
+			return !less(l, r), nil
+		}
+
+	case "+", "-", "*", "/", "%":
+		lf, lok := l.(float64)
+		rf, rok := r.(float64)
+		if !lok || !rok {
+			return undefined{}, nil
+		}
+		switch e.op {
+		case "+":
+			return lf + rf, nil
+		case "-":
+			return lf - rf, nil
+		case "*":
+			return lf * rf, nil
+		case "/":
+			if rf == 0 {
+				return undefined{}, nil
+			}
+			return lf / rf, nil
+		default:
+			if int64(rf) == 0 {
+				return undefined{}, nil
+			}
+			return float64(int64(lf) % int64(rf)), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported operator %s", e.op)
+}
+
+type in struct {
+	e    expr
+	list []expr
+	not  bool
+}
+
+func (e in) eval(s *scope) (interface{}, error) {
+	v, err := e.e.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := v.(undefined); ok {
+		return undefined{}, nil
+	}
+
+	for _, le := range e.list {
+		lv, err := le.eval(s)
+		if err != nil {
+			return nil, err
+		}
+
+		if sameType(v, lv) && equal(v, lv) {
+			return !e.not, nil
+		}
+	}
+
+	return e.not, nil
+}
+
+type call struct {
+	name string
+	args []expr
+}
+
+func (e call) eval(s *scope) (interface{}, error) {
+	args := make([]interface{}, len(e.args))
+	for i, a := range e.args {
+		var err error
+		args[i], err = a.eval(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch e.name {
+	case "GETCURRENTTIMESTAMP":
+		return float64(s.now.UnixMilli()), nil
+
+	case "IS_DEFINED":
+		_, ok := args[0].(undefined)
+		return !ok, nil
+
+	case "IS_NULL":
+		return args[0] == nil, nil
+
+	case "LOWER", "UPPER":
+		str, ok := args[0].(string)
+		if !ok {
+			return undefined{}, nil
+		}
+		if e.name == "LOWER" {
+			return strings.ToLower(str), nil
+		}
+		return strings.ToUpper(str), nil
+
+	case "STARTSWITH", "ENDSWITH", "CONTAINS":
+		str, ok1 := args[0].(string)
+		sub, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return undefined{}, nil
+		}
+		if len(args) == 3 && args[2] == true {
+			str, sub = strings.ToLower(str), strings.ToLower(sub)
+		}
+		switch e.name {
+		case "STARTSWITH":
+			return strings.HasPrefix(str, sub), nil
+		case "ENDSWITH":
+			return strings.HasSuffix(str, sub), nil
+		default:
+			return strings.Contains(str, sub), nil
+		}
+
+	case "ARRAY_CONTAINS":
+		arr, ok := args[0].([]interface{})
+		if !ok {
+			return undefined{}, nil
+		}
+		for _, v := range arr {
+			v = normalize(v)
+			if sameType(v, args[1]) && equal(v, args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return nil, fmt.Errorf("unsupported function %s", e.name)
+}
+
+// functions maps the supported system functions to their number of arguments
+var functions = map[string][2]int{
+	"GETCURRENTTIMESTAMP": {0, 0},
+	"IS_DEFINED":          {1, 1},
+	"IS_NULL":             {1, 1},
+	"LOWER":               {1, 1},
+	"UPPER":               {1, 1},
+	"STARTSWITH":          {2, 3},
+	"ENDSWITH":            {2, 3},
+	"CONTAINS":            {2, 3},
+	"ARRAY_CONTAINS":      {2, 2},
+}
+
+// normalize converts the numbers of a decoded document to float64 so that
+// they can be compared
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return undefined{}
+		}
+		return f
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return v
+}
+
+// sameType returns true if l and r are of the same JSON type
+func sameType(l, r interface{}) bool {
+	if _, ok := l.(undefined); ok {
+		return false
+	}
+	if _, ok := r.(undefined); ok {
+		return false
+	}
+	if l == nil || r == nil {
+		return l == nil && r == nil
+	}
+	return reflect.TypeOf(l) == reflect.TypeOf(r)
+}
+
+func equal(l, r interface{}) bool {
+	return reflect.DeepEqual(l, r)
+}
+
+func orderable(l, r interface{}) bool {
+	switch l.(type) {
+	case float64, string, bool:
+		return sameType(l, r)
+	}
+	return false
+}
+
+// less orders values for ORDER BY: undefined, null, booleans, numbers and
+// then strings, as Cosmos DB does
+func less(l, r interface{}) bool {
+	rank := func(v interface{}) int {
+		switch v.(type) {
+		case undefined:
+			return 0
+		case nil:
+			return 1
+		case bool:
+			return 2
+		case float64:
+			return 3
+		case string:
+			return 4
+		}
+		return 5
+	}
+
+	if rank(l) != rank(r) {
+		return rank(l) < rank(r)
+	}
+
+	switch l := l.(type) {
+	case bool:
+		return !l && r.(bool)
+	case float64:
+		return l < r.(float64)
+	case string:
+		return l < r.(string)
+	}
+
+	return false
+}
+
+// parseQuery parses the query text sql
+func parseQuery(sql string) (*query, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	q, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("syntax error near %q", p.peek().text)
+	}
+
+	return q, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenParam
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+var symbols = []string{"??", "!=", "<>", "<=", ">=", "(", ")", ",", ".", "[", "]", "*", "=", "<", ">", "+", "-", "/", "%"}
+
+func tokenize(sql string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(sql); {
+		c := rune(sql[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '"' || c == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(sql) && rune(sql[j]) != c; j++ {
+				if sql[j] == '\\' && j+1 < len(sql) {
+					j++
+				}
+				b.WriteByte(sql[j])
+			}
+			if j == len(sql) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: b.String()})
+			i = j + 1
+
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(sql) && (unicode.IsDigit(rune(sql[j])) || sql[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: sql[i:j]})
+			i = j
+
+		case c == '@' || c == '_' || unicode.IsLetter(c):
+			j := i + 1
+			for j < len(sql) && (sql[j] == '_' || unicode.IsLetter(rune(sql[j])) || unicode.IsDigit(rune(sql[j]))) {
+				j++
+			}
+			kind := tokenIdent
+			if c == '@' {
+				kind = tokenParam
+			}
+			tokens = append(tokens, token{kind: kind, text: sql[i:j]})
+			i = j
+
+		default:
+			var found bool
+			for _, s := range symbols {
+				if strings.HasPrefix(sql[i:], s) {
+					tokens = append(tokens, token{kind: tokenSymbol, text: s})
+					i += len(s)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool { return p.pos == len(p.tokens) }
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: tokenSymbol}
+	}
+	return p.tokens[p.pos]
+}
+
+// keyword consumes the next token if it is the keyword kw
+func (p *parser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokenIdent && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// symbol consumes the next token if it is the symbol s
+func (p *parser) symbol(s string) bool {
+	if t := p.peek(); t.kind == tokenSymbol && t.text == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.symbol(s) && !p.keyword(s) {
+		return fmt.Errorf("expected %s near %q", s, p.peek().text)
+	}
+	return nil
+}
+
+func (p *parser) ident() (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return "", fmt.Errorf("expected identifier near %q", t.text)
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *parser) query() (*query, error) {
+	q := &query{}
+
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+
+	if p.keyword("TOP") {
+		t := p.peek()
+		n, err := strconv.Atoi(t.text)
+		if t.kind != tokenNumber || err != nil {
+			return nil, fmt.Errorf("invalid TOP %q", t.text)
+		}
+		p.pos++
+		q.top = n
+	}
+
+	switch {
+	case p.symbol("*"):
+	case p.keyword("VALUE"):
+		if t := p.peek(); t.kind == tokenIdent && strings.EqualFold(t.text, "COUNT") {
+			p.pos++
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			if _, err := p.expr(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			q.count = true
+		} else {
+			v, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			q.value = v
+		}
+	default:
+		return nil, fmt.Errorf("unsupported projection near %q", p.peek().text)
+	}
+
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+
+	coll, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	q.alias = coll
+
+	p.keyword("AS")
+	if t := p.peek(); t.kind == tokenIdent && !strings.EqualFold(t.text, "WHERE") && !strings.EqualFold(t.text, "ORDER") {
+		q.alias = t.text
+		p.pos++
+	}
+
+	if p.keyword("WHERE") {
+		q.where, err = p.expr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.keyword("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		q.orderBy, err = p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.keyword("DESC") {
+			q.orderDesc = true
+		} else {
+			p.keyword("ASC")
+		}
+	}
+
+	return q, nil
+}
+
+// expr parses an expression.  Precedence, from lowest to highest, is ??,
+// OR, AND, NOT, comparison and IN, + and -, and then *, / and %.
+func (p *parser) expr() (expr, error) {
+	l, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.symbol("??") {
+		r, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: "??", l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *parser) or() (expr, error) {
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("OR") {
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: "OR", l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *parser) and() (expr, error) {
+	l, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("AND") {
+		r, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: "AND", l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *parser) not() (expr, error) {
+	if p.keyword("NOT") {
+		e, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: "NOT", e: e}, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *parser) comparison() (expr, error) {
+	l, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"=", "!=", "<>", "<=", ">=", "<", ">"} {
+		if p.symbol(op) {
+			r, err := p.additive()
+			if err != nil {
+				return nil, err
+			}
+			if op == "<>" {
+				op = "!="
+			}
+			return binary{op: op, l: l, r: r}, nil
+		}
+	}
+
+	not := p.keyword("NOT")
+	if p.keyword("IN") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+
+		e := in{e: l, not: not}
+		for {
+			le, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			e.list = append(e.list, le)
+
+			if !p.symbol(",") {
+				break
+			}
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		return e, nil
+	}
+	if not {
+		return nil, fmt.Errorf("expected IN near %q", p.peek().text)
+	}
+
+	return l, nil
+}
+
+func (p *parser) additive() (expr, error) {
+	l, err := p.multiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var op string
+		switch {
+		case p.symbol("+"):
+			op = "+"
+		case p.symbol("-"):
+			op = "-"
+		default:
+			return l, nil
+		}
+
+		r, err := p.multiplicative()
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: op, l: l, r: r}
+	}
+}
+
+func (p *parser) multiplicative() (expr, error) {
+	l, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var op string
+		switch {
+		case p.symbol("*"):
+			op = "*"
+		case p.symbol("/"):
+			op = "/"
+		case p.symbol("%"):
+			op = "%"
+		default:
+			return l, nil
+		}
+
+		r, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: op, l: l, r: r}
+	}
+}
+
+func (p *parser) unary() (expr, error) {
+	if p.symbol("-") {
+		e, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: "-", e: e}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *parser) primary() (expr, error) {
+	t := p.peek()
+
+	switch t.kind {
+	case tokenNumber:
+		p.pos++
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, err
+		}
+		return literal{f}, nil
+
+	case tokenString:
+		p.pos++
+		return literal{t.text}, nil
+
+	case tokenParam:
+		p.pos++
+		return param{t.text}, nil
+
+	case tokenSymbol:
+		if p.symbol("(") {
+			e, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+		return nil, fmt.Errorf("syntax error near %q", t.text)
+	}
+
+	p.pos++
+
+	switch strings.ToLower(t.text) {
+	case "true":
+		return literal{true}, nil
+	case "false":
+		return literal{false}, nil
+	case "null":
+		return literal{nil}, nil
+	case "undefined":
+		return literal{undefined{}}, nil
+	}
+
+	if p.symbol("(") {
+		name := strings.ToUpper(t.text)
+		arity, found := functions[name]
+		if !found {
+			return nil, fmt.Errorf("unsupported function %s", t.text)
+		}
+
+		e := call{name: name}
+		if !p.symbol(")") {
+			for {
+				a, err := p.expr()
+				if err != nil {
+					return nil, err
+				}
+				e.args = append(e.args, a)
+
+				if !p.symbol(",") {
+					break
+				}
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(e.args) < arity[0] || len(e.args) > arity[1] {
+			return nil, fmt.Errorf("wrong number of arguments to %s", t.text)
+		}
+
+		return e, nil
+	}
+
+	e := path{root: t.text}
+	for {
+		switch {
+		case p.symbol("."):
+			el, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			e.elements = append(e.elements, el)
+
+		case p.symbol("["):
+			el := p.peek()
+			if el.kind != tokenString {
+				return nil, fmt.Errorf("expected property name near %q", el.text)
+			}
+			p.pos++
+			e.elements = append(e.elements, el.text)
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+
+		default:
+			return e, nil
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package emulator
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	var docs []map[string]interface{}
+	for _, doc := range []string{
+		`{"id": "a", "state": "Creating", "leaseExpires": 100, "tags": ["x"]}`,
+		`{"id": "b", "state": "Succeeded", "deleting": true}`,
+		`{"id": "c", "state": "Deleting", "leaseExpires": 300, "nested": {"key": "/Prefix/c"}}`,
+	} {
+		d := json.NewDecoder(strings.NewReader(doc))
+		d.UseNumber()
+
+		var m map[string]interface{}
+		err := d.Decode(&m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, m)
+	}
+
+	now := time.Unix(200, 0)
+
+	for _, tt := range []struct {
+		name    string
+		query   string
+		params  map[string]interface{}
+		wantIDs []string
+		want    []interface{}
+		wantErr string
+	}{
+		{
+			name:    "select all",
+			query:   `SELECT * FROM c`,
+			wantIDs: []string{"a", "b", "c"},
+		},
+		{
+			name:    "in and coalesce",
+			query:   `SELECT * FROM Docs doc WHERE doc.state IN ("Creating", "Deleting") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000`,
+			wantIDs: []string{"a"},
+		},
+		{
+			name:    "coalesced boolean",
+			query:   `SELECT * FROM Docs doc WHERE (doc.deleting ?? false)`,
+			wantIDs: []string{"b"},
+		},
+		{
+			name:    "parameter",
+			query:   `SELECT * FROM Docs doc WHERE doc.id = @id`,
+			params:  map[string]interface{}{"@id": "c"},
+			wantIDs: []string{"c"},
+		},
+		{
+			name:    "function on nested path",
+			query:   `SELECT * FROM Docs doc WHERE STARTSWITH(LOWER(doc.nested.key), @prefix)`,
+			params:  map[string]interface{}{"@prefix": "/prefix/"},
+			wantIDs: []string{"c"},
+		},
+		{
+			name:    "undefined comparisons do not match",
+			query:   `SELECT * FROM Docs doc WHERE NOT (doc.leaseExpires > 0)`,
+			wantIDs: nil,
+		},
+		{
+			name:    "order and top",
+			query:   `SELECT TOP 2 * FROM Docs doc ORDER BY doc.id DESC`,
+			wantIDs: []string{"c", "b"},
+		},
+		{
+			name:    "array contains",
+			query:   `SELECT * FROM Docs doc WHERE ARRAY_CONTAINS(doc.tags, "x") OR IS_DEFINED(doc.nested)`,
+			wantIDs: []string{"a", "c"},
+		},
+		{
+			name:  "count",
+			query: `SELECT VALUE COUNT(1) FROM Docs doc WHERE IS_DEFINED(doc.leaseExpires)`,
+			want:  []interface{}{2},
+		},
+		{
+			name:  "value",
+			query: `SELECT VALUE doc.id FROM Docs doc WHERE doc.state = "Succeeded"`,
+			want:  []interface{}{"b"},
+		},
+		{
+			name:    "missing parameter",
+			query:   `SELECT * FROM Docs doc WHERE doc.id = @id`,
+			wantErr: "@id",
+		},
+		{
+			name:    "syntax error",
+			query:   `SELECT * FROM Docs doc WHERE`,
+			wantErr: "syntax error",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := parseQuery(tt.query)
+			if err == nil {
+				var results []interface{}
+				results, err = q.run(docs, tt.params, now)
+				if err == nil && tt.wantErr == "" {
+					if tt.want == nil {
+						var ids []string
+						for _, r := range results {
+							ids = append(ids, r.(map[string]interface{})["id"].(string))
+						}
+						if !reflect.DeepEqual(ids, tt.wantIDs) {
+							t.Errorf("got ids %v", ids)
+						}
+					} else if !reflect.DeepEqual(results, tt.want) {
+						t.Errorf("got %#v", results)
+					}
+				}
+			}
+
+			if tt.wantErr == "" && err != nil ||
+				tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("got error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package emulator
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// The emulator cannot run JavaScript, so the pre-triggers which the RP
+// registers are implemented natively and selected by their ID.  The trigger
+// must still have been created in the collection, as in Cosmos DB.
+var triggers = map[string]func(now time.Time, doc map[string]interface{}){
+	"renewLease": func(now time.Time, doc map[string]interface{}) {
+		doc["leaseExpires"] = now.Unix() + 60
+	},
+	"retryLater": func(now time.Time, doc map[string]interface{}) {
+		doc["leaseExpires"] = now.Unix() + 600
+	},
+	"setCreationBillingTimeStamp": func(now time.Time, doc map[string]interface{}) {
+		setBillingTimeStamp(now, doc, "creationTime")
+	},
+	"setDeletionBillingTimeStamp": func(now time.Time, doc map[string]interface{}) {
+		setBillingTimeStamp(now, doc, "deletionTime")
+	},
+}
+
+func setBillingTimeStamp(now time.Time, doc map[string]interface{}, field string) {
+	billing, ok := doc["billing"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if v := normalize(billing[field]); v == nil || v == float64(0) || v == false || v == "" {
+		billing[field] = now.Unix()
+	}
+}
@@ -0,0 +1,64 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+type keyRotation struct {
+	c cosmosdb.KeyRotationDocumentClient
+}
+
+// KeyRotation is the database interface for KeyRotationDocuments
+type KeyRotation interface {
+	Create(context.Context, *api.KeyRotationDocument) (*api.KeyRotationDocument, error)
+	Get(context.Context, string) (*api.KeyRotationDocument, error)
+	Update(context.Context, *api.KeyRotationDocument) (*api.KeyRotationDocument, error)
+}
+
+// NewKeyRotation returns a new KeyRotation
+func NewKeyRotation(ctx context.Context, dbc cosmosdb.DatabaseClient, dbName string) (KeyRotation, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	documentClient := cosmosdb.NewKeyRotationDocumentClient(collc, collKeyRotation)
+	return NewKeyRotationWithProvidedClient(documentClient), nil
+}
+
+func NewKeyRotationWithProvidedClient(client cosmosdb.KeyRotationDocumentClient) KeyRotation {
+	return &keyRotation{
+		c: client,
+	}
+}
+
+func (c *keyRotation) Create(ctx context.Context, doc *api.KeyRotationDocument) (*api.KeyRotationDocument, error) {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	return c.c.Create(ctx, doc.ID, doc, nil)
+}
+
+func (c *keyRotation) Get(ctx context.Context, id string) (*api.KeyRotationDocument, error) {
+	if id != strings.ToLower(id) {
+		return nil, fmt.Errorf("id %q is not lower case", id)
+	}
+
+	return c.c.Get(ctx, id, id, nil)
+}
+
+// Update replaces doc, failing with StatusPreconditionFailed if it has been
+// changed since it was read
+func (c *keyRotation) Update(ctx context.Context, doc *api.KeyRotationDocument) (*api.KeyRotationDocument, error) {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	return c.c.Replace(ctx, doc.ID, doc, &cosmosdb.Options{})
+}
@@ -25,5 +25,24 @@ func EmitMetrics(ctx context.Context, log *logrus.Entry, dbOpenShiftClusters Ope
 		} else {
 			m.EmitGauge("database.openshiftclusters.queue.length", int64(i), nil)
 		}
+
+		for _, provisioningState := range OpenShiftClustersQueueProvisioningStates {
+			i, err := dbOpenShiftClusters.QueueLengthByProvisioningState(ctx, "OpenShiftClusters", provisioningState)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+
+			m.EmitGauge("database.openshiftclusters.queue.class.length", int64(i), map[string]string{
+				"provisioningState": string(provisioningState),
+			})
+		}
+
+		i, err = dbOpenShiftClusters.DeadLetterCount(ctx, "OpenShiftClusters")
+		if err != nil {
+			log.Error(err)
+		} else {
+			m.EmitGauge("database.openshiftclusters.deadletter.count", int64(i), nil)
+		}
 	}
 }
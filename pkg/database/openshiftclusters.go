@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/azure"
 
@@ -17,14 +18,32 @@ import (
 )
 
 const (
-	OpenShiftClustersDequeueQuery       = `SELECT * FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState IN ("Creating", "Deleting", "Updating", "AdminUpdating") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000`
-	OpenShiftClustersQueueLengthQuery   = `SELECT VALUE COUNT(1) FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState IN ("Creating", "Deleting", "Updating", "AdminUpdating") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000`
-	OpenShiftClustersGetQuery           = `SELECT * FROM OpenShiftClusters doc WHERE doc.key = @key`
-	OpenshiftClustersPrefixQuery        = `SELECT * FROM OpenShiftClusters doc WHERE STARTSWITH(doc.key, @prefix)`
-	OpenshiftClustersClientIdQuery      = `SELECT * FROM OpenShiftClusters doc WHERE doc.clientIdKey = @clientID`
-	OpenshiftClustersResourceGroupQuery = `SELECT * FROM OpenShiftClusters doc WHERE doc.clusterResourceGroupIdKey = @resourceGroupID`
+	OpenShiftClustersDequeueQuery            = `SELECT * FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState IN ("Creating", "Deleting", "Updating", "AdminUpdating") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000 AND (doc.openShiftCluster.properties.pendingDeletion.deleteAfter ?? 0) < GetCurrentTimestamp() / 1000`
+	OpenShiftClustersQueueLengthQuery        = `SELECT VALUE COUNT(1) FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState IN ("Creating", "Deleting", "Updating", "AdminUpdating") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000 AND (doc.openShiftCluster.properties.pendingDeletion.deleteAfter ?? 0) < GetCurrentTimestamp() / 1000`
+	OpenShiftClustersQueueLengthByStateQuery = `SELECT VALUE COUNT(1) FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState = @provisioningState AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000 AND (doc.openShiftCluster.properties.pendingDeletion.deleteAfter ?? 0) < GetCurrentTimestamp() / 1000`
+	OpenShiftClustersDeadLetterQuery         = `SELECT * FROM OpenShiftClusters doc WHERE IS_DEFINED(doc.deadLetter)`
+	OpenShiftClustersDeadLetterCountQuery    = `SELECT VALUE COUNT(1) FROM OpenShiftClusters doc WHERE IS_DEFINED(doc.deadLetter)`
+	OpenShiftClustersGetQuery                = `SELECT * FROM OpenShiftClusters doc WHERE doc.key = @key`
+	OpenshiftClustersPrefixQuery             = `SELECT * FROM OpenShiftClusters doc WHERE STARTSWITH(doc.key, @prefix)`
+	OpenshiftClustersClientIdQuery           = `SELECT * FROM OpenShiftClusters doc WHERE doc.clientIdKey = @clientID`
+	OpenshiftClustersResourceGroupQuery      = `SELECT * FROM OpenShiftClusters doc WHERE doc.clusterResourceGroupIdKey = @resourceGroupID`
 )
 
+// OpenShiftClustersQueueProvisioningStates lists the provisioning states of
+// the OpenShiftClusters which are queued for the backend, highest priority
+// first.  It must match the states in OpenShiftClustersDequeueQuery and
+// OpenShiftClustersQueueLengthQuery.
+var OpenShiftClustersQueueProvisioningStates = []api.ProvisioningState{
+	api.ProvisioningStateDeleting,
+	api.ProvisioningStateAdminUpdating,
+	api.ProvisioningStateCreating,
+	api.ProvisioningStateUpdating,
+}
+
+// maxDequeueHistory is the number of dequeues recorded in the
+// DequeueHistory of a document
+const maxDequeueHistory = 10
+
 type OpenShiftClusterDocumentMutator func(*api.OpenShiftClusterDocument) error
 
 type openShiftClusters struct {
@@ -39,6 +58,8 @@ type OpenShiftClusters interface {
 	Create(context.Context, *api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error)
 	Get(context.Context, string) (*api.OpenShiftClusterDocument, error)
 	QueueLength(context.Context, string) (int, error)
+	QueueLengthByProvisioningState(context.Context, string, api.ProvisioningState) (int, error)
+	DeadLetterCount(context.Context, string) (int, error)
 	Patch(context.Context, string, OpenShiftClusterDocumentMutator) (*api.OpenShiftClusterDocument, error)
 	PatchWithLease(context.Context, string, OpenShiftClusterDocumentMutator) (*api.OpenShiftClusterDocument, error)
 	Update(context.Context, *api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error)
@@ -48,8 +69,11 @@ type OpenShiftClusters interface {
 	ListAll(context.Context) (*api.OpenShiftClusterDocuments, error)
 	ListByPrefix(string, string, string) (cosmosdb.OpenShiftClusterDocumentIterator, error)
 	Dequeue(context.Context) (*api.OpenShiftClusterDocument, error)
+	DequeueWithPriority(context.Context, func([]*api.OpenShiftClusterDocument) []*api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error)
 	Lease(context.Context, string) (*api.OpenShiftClusterDocument, error)
 	EndLease(context.Context, string, api.ProvisioningState, api.ProvisioningState, *string) (*api.OpenShiftClusterDocument, error)
+	ReleaseLease(context.Context, string) (*api.OpenShiftClusterDocument, error)
+	ListDeadLetters(context.Context) (*api.OpenShiftClusterDocuments, error)
 	GetByClientID(ctx context.Context, partitionKey, clientID string) (*api.OpenShiftClusterDocuments, error)
 	GetByClusterResourceGroupID(ctx context.Context, partitionKey, resourceGroupID string) (*api.OpenShiftClusterDocuments, error)
 	NewUUID() string
@@ -155,6 +179,34 @@ func (c *openShiftClusters) Get(ctx context.Context, key string) (*api.OpenShift
 // QueueLength returns OpenShiftClusters un-queued document count.
 // If error occurs, 0 is returned with error message
 func (c *openShiftClusters) QueueLength(ctx context.Context, collid string) (int, error) {
+	return c.queueLength(ctx, collid, &cosmosdb.Query{
+		Query: OpenShiftClustersQueueLengthQuery,
+	})
+}
+
+// QueueLengthByProvisioningState returns the un-queued document count of
+// OpenShiftClusters in provisioningState.
+func (c *openShiftClusters) QueueLengthByProvisioningState(ctx context.Context, collid string, provisioningState api.ProvisioningState) (int, error) {
+	return c.queueLength(ctx, collid, &cosmosdb.Query{
+		Query: OpenShiftClustersQueueLengthByStateQuery,
+		Parameters: []cosmosdb.Parameter{
+			{
+				Name:  "@provisioningState",
+				Value: string(provisioningState),
+			},
+		},
+	})
+}
+
+// DeadLetterCount returns the count of OpenShiftClusters which the backend
+// has stopped working because they were dequeued too many times
+func (c *openShiftClusters) DeadLetterCount(ctx context.Context, collid string) (int, error) {
+	return c.queueLength(ctx, collid, &cosmosdb.Query{
+		Query: OpenShiftClustersDeadLetterCountQuery,
+	})
+}
+
+func (c *openShiftClusters) queueLength(ctx context.Context, collid string, query *cosmosdb.Query) (int, error) {
 	partitions, err := c.collc.PartitionKeyRanges(ctx, collid)
 	if err != nil {
 		return 0, err
@@ -162,9 +214,7 @@ func (c *openShiftClusters) QueueLength(ctx context.Context, collid string) (int
 
 	var countTotal int
 	for _, r := range partitions.PartitionKeyRanges {
-		result := c.c.Query("", &cosmosdb.Query{
-			Query: OpenShiftClustersQueueLengthQuery,
-		}, &cosmosdb.Options{
+		result := c.c.Query("", query, &cosmosdb.Options{
 			PartitionKeyRangeID: r.ID,
 		})
 		// because we aggregate count we don't expect pagination in this query result,
@@ -275,9 +325,27 @@ func (c *openShiftClusters) ListByPrefix(subscriptionID, prefix, continuation st
 }
 
 func (c *openShiftClusters) Dequeue(ctx context.Context) (*api.OpenShiftClusterDocument, error) {
-	i := c.c.Query("", &cosmosdb.Query{
+	return c.dequeue(ctx, &cosmosdb.Query{
 		Query: OpenShiftClustersDequeueQuery,
 	}, nil)
+}
+
+// DequeueWithPriority dequeues an OpenShiftClusterDocument.  prioritize is
+// called on each page of candidate documents and returns those which may be
+// dequeued, in the order in which they should be leased.
+//
+// The query is not ordered: pages are read with the largest page size the
+// database allows and each is prioritized on its own, so a document on a later
+// page is only considered once no document on an earlier page could be leased.
+// Priority is therefore exact only while the queue fits on one page.
+func (c *openShiftClusters) DequeueWithPriority(ctx context.Context, prioritize func([]*api.OpenShiftClusterDocument) []*api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error) {
+	return c.dequeue(ctx, &cosmosdb.Query{
+		Query: OpenShiftClustersDequeueQuery,
+	}, prioritize)
+}
+
+func (c *openShiftClusters) dequeue(ctx context.Context, query *cosmosdb.Query, prioritize func([]*api.OpenShiftClusterDocument) []*api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error) {
+	i := c.c.Query("", query, nil)
 
 	for {
 		docs, err := i.Next(ctx, -1)
@@ -288,9 +356,20 @@ func (c *openShiftClusters) Dequeue(ctx context.Context) (*api.OpenShiftClusterD
 			return nil, nil
 		}
 
+		if prioritize != nil {
+			docs.OpenShiftClusterDocuments = prioritize(docs.OpenShiftClusterDocuments)
+		}
+
 		for _, doc := range docs.OpenShiftClusterDocuments {
 			doc.LeaseOwner = c.uuid
 			doc.Dequeues++
+			doc.DequeueHistory = append(doc.DequeueHistory, &api.DequeueRecord{
+				LeaseOwner: c.uuid,
+				StartTime:  int(time.Now().Unix()),
+			})
+			if len(doc.DequeueHistory) > maxDequeueHistory {
+				doc.DequeueHistory = doc.DequeueHistory[len(doc.DequeueHistory)-maxDequeueHistory:]
+			}
 			doc, err = c.update(ctx, doc, &cosmosdb.Options{PreTriggers: []string{"renewLease"}})
 			if cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) { // someone else got there first
 				continue
@@ -317,6 +396,7 @@ func (c *openShiftClusters) EndLease(ctx context.Context, key string, provisioni
 
 		if provisioningState != api.ProvisioningStateFailed {
 			doc.Dequeues = 0
+			doc.DequeueHistory = nil
 		}
 		// If EndLease is called while cluster is still in terminal phase,
 		// we clean AsyncOperationID. Otherwise it just handover between backends.
@@ -334,6 +414,30 @@ func (c *openShiftClusters) EndLease(ctx context.Context, key string, provisioni
 	}, nil)
 }
 
+// ReleaseLease releases the lease on a document without changing its
+// provisioning state, so that another backend resumes working it.  The dequeue
+// which took the lease is not counted towards the dequeue limit.
+func (c *openShiftClusters) ReleaseLease(ctx context.Context, key string) (*api.OpenShiftClusterDocument, error) {
+	return c.patchWithLease(ctx, key, func(doc *api.OpenShiftClusterDocument) error {
+		doc.LeaseOwner = ""
+		doc.LeaseExpires = 0
+
+		if doc.Dequeues > 0 {
+			doc.Dequeues--
+		}
+
+		return nil
+	}, nil)
+}
+
+// ListDeadLetters returns the documents which the backend has stopped
+// working because they were dequeued too many times
+func (c *openShiftClusters) ListDeadLetters(ctx context.Context) (*api.OpenShiftClusterDocuments, error) {
+	return c.c.QueryAll(ctx, "", &cosmosdb.Query{
+		Query: OpenShiftClustersDeadLetterQuery,
+	}, nil)
+}
+
 func (c *openShiftClusters) partitionKey(key string) (string, error) {
 	r, err := azure.ParseResourceID(key)
 	return r.SubscriptionID, err
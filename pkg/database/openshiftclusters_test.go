@@ -0,0 +1,40 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestOpenShiftClustersQueueProvisioningStates(t *testing.T) {
+	rxStates := regexp.MustCompile(`provisioningState IN \(([^)]*)\)`)
+
+	var want []string
+	for _, provisioningState := range OpenShiftClustersQueueProvisioningStates {
+		want = append(want, `"`+string(provisioningState)+`"`)
+	}
+	sort.Strings(want)
+
+	for _, query := range []string{
+		OpenShiftClustersDequeueQuery,
+		OpenShiftClustersQueueLengthQuery,
+	} {
+		m := rxStates.FindStringSubmatch(query)
+		if m == nil {
+			t.Fatal(query)
+		}
+
+		got := strings.Split(m[1], ", ")
+		sort.Strings(got)
+
+		for _, l := range deep.Equal(got, want) {
+			t.Error(l)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const (
+	PendingActionsListByKeyQuery = `SELECT * FROM PendingActions doc WHERE doc.key = @key`
+)
+
+type pendingActions struct {
+	c             cosmosdb.PendingActionDocumentClient
+	uuidGenerator uuid.Generator
+}
+
+// PendingActions is the database interface for PendingActionDocuments
+type PendingActions interface {
+	Create(context.Context, *api.PendingActionDocument) (*api.PendingActionDocument, error)
+	Get(context.Context, string, string) (*api.PendingActionDocument, error)
+	Patch(context.Context, string, string, func(*api.PendingActionDocument) error) (*api.PendingActionDocument, error)
+	ListByKey(context.Context, string) (*api.PendingActionDocuments, error)
+	NewUUID() string
+}
+
+// NewPendingActions returns a new PendingActions
+func NewPendingActions(ctx context.Context, dbc cosmosdb.DatabaseClient, dbName string) (PendingActions, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	documentClient := cosmosdb.NewPendingActionDocumentClient(collc, collPendingActions)
+	return NewPendingActionsWithProvidedClient(documentClient, uuid.DefaultGenerator), nil
+}
+
+func NewPendingActionsWithProvidedClient(client cosmosdb.PendingActionDocumentClient, uuidGenerator uuid.Generator) PendingActions {
+	return &pendingActions{
+		c:             client,
+		uuidGenerator: uuidGenerator,
+	}
+}
+
+func (c *pendingActions) NewUUID() string {
+	return c.uuidGenerator.Generate()
+}
+
+func (c *pendingActions) Create(ctx context.Context, doc *api.PendingActionDocument) (*api.PendingActionDocument, error) {
+	if doc.Key != strings.ToLower(doc.Key) {
+		return nil, fmt.Errorf("key %q is not lower case", doc.Key)
+	}
+
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	doc, err := c.c.Create(ctx, doc.Key, doc, nil)
+
+	if err, ok := err.(*cosmosdb.Error); ok && err.StatusCode == http.StatusConflict {
+		err.StatusCode = http.StatusPreconditionFailed
+	}
+
+	return doc, err
+}
+
+func (c *pendingActions) Get(ctx context.Context, key, id string) (*api.PendingActionDocument, error) {
+	if key != strings.ToLower(key) {
+		return nil, fmt.Errorf("key %q is not lower case", key)
+	}
+
+	if id != strings.ToLower(id) {
+		return nil, fmt.Errorf("id %q is not lower case", id)
+	}
+
+	return c.c.Get(ctx, key, id, nil)
+}
+
+func (c *pendingActions) Patch(ctx context.Context, key, id string, f func(*api.PendingActionDocument) error) (*api.PendingActionDocument, error) {
+	var doc *api.PendingActionDocument
+
+	err := cosmosdb.RetryOnPreconditionFailed(func() (err error) {
+		doc, err = c.Get(ctx, key, id)
+		if err != nil {
+			return
+		}
+
+		err = f(doc)
+		if err != nil {
+			return
+		}
+
+		doc, err = c.c.Replace(ctx, doc.Key, doc, nil)
+		return
+	})
+
+	return doc, err
+}
+
+func (c *pendingActions) ListByKey(ctx context.Context, key string) (*api.PendingActionDocuments, error) {
+	if key != strings.ToLower(key) {
+		return nil, fmt.Errorf("key %q is not lower case", key)
+	}
+
+	return c.c.QueryAll(ctx, key, &cosmosdb.Query{
+		Query: PendingActionsListByKeyQuery,
+		Parameters: []cosmosdb.Parameter{
+			{
+				Name:  "@key",
+				Value: key,
+			},
+		},
+	}, nil)
+}
@@ -14,6 +14,11 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/uuid"
 )
 
+const (
+	PortalListByResourceIDQuery = `SELECT * FROM Portals doc WHERE doc.portal.id = @resourceID`
+	PortalListByUsernameQuery   = `SELECT * FROM Portals doc WHERE doc.portal.username = @username`
+)
+
 type portals struct {
 	c             cosmosdb.PortalDocumentClient
 	uuidGenerator uuid.Generator
@@ -24,6 +29,8 @@ type Portal interface {
 	Create(context.Context, *api.PortalDocument) (*api.PortalDocument, error)
 	Get(context.Context, string) (*api.PortalDocument, error)
 	Patch(context.Context, string, func(*api.PortalDocument) error) (*api.PortalDocument, error)
+	ListByResourceID(context.Context, string) (*api.PortalDocuments, error)
+	ListByUsername(context.Context, string) (*api.PortalDocuments, error)
 	NewUUID() string
 }
 
@@ -88,3 +95,31 @@ func (c *portals) Patch(ctx context.Context, id string, f func(*api.PortalDocume
 
 	return doc, err
 }
+
+func (c *portals) ListByResourceID(ctx context.Context, resourceID string) (*api.PortalDocuments, error) {
+	if resourceID != strings.ToLower(resourceID) {
+		return nil, fmt.Errorf("resourceID %q is not lower case", resourceID)
+	}
+
+	return c.c.QueryAll(ctx, "", &cosmosdb.Query{
+		Query: PortalListByResourceIDQuery,
+		Parameters: []cosmosdb.Parameter{
+			{
+				Name:  "@resourceID",
+				Value: resourceID,
+			},
+		},
+	}, nil)
+}
+
+func (c *portals) ListByUsername(ctx context.Context, username string) (*api.PortalDocuments, error) {
+	return c.c.QueryAll(ctx, "", &cosmosdb.Query{
+		Query: PortalListByUsernameQuery,
+		Parameters: []cosmosdb.Parameter{
+			{
+				Name:  "@username",
+				Value: username,
+			},
+		},
+	}, nil)
+}
@@ -0,0 +1,295 @@
+package dbsnapshot
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+// pageSize is the number of documents read from a collection at a time
+const pageSize = 100
+
+// collection adapts the document client of a collection so that the
+// collections can be exported and imported in the same way.  Documents are
+// held as stored, with their encrypted fields sealed.
+type collection interface {
+	name() string
+	list() pager
+	newDocument() interface{}
+	id(interface{}) string
+	etag(interface{}) string
+	create(context.Context, interface{}) error
+	get(ctx context.Context, doc interface{}) (interface{}, error)
+	replace(context.Context, interface{}) error
+}
+
+// pager returns the documents of a collection a page at a time.  next returns
+// nil once there are no further pages.
+type pager interface {
+	next(context.Context) ([]interface{}, error)
+}
+
+type openShiftClusters struct {
+	c cosmosdb.OpenShiftClusterDocumentClient
+}
+
+func (openShiftClusters) name() string { return "OpenShiftClusters" }
+
+func (c openShiftClusters) list() pager {
+	return openShiftClusterPager{c.c.List(nil)}
+}
+
+func (openShiftClusters) newDocument() interface{} { return &api.OpenShiftClusterDocument{} }
+
+func (openShiftClusters) id(doc interface{}) string { return doc.(*api.OpenShiftClusterDocument).ID }
+
+func (openShiftClusters) etag(doc interface{}) string {
+	return doc.(*api.OpenShiftClusterDocument).ETag
+}
+
+func (c openShiftClusters) create(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.OpenShiftClusterDocument)
+	_, err := c.c.Create(ctx, d.PartitionKey, d, nil)
+	return err
+}
+
+func (c openShiftClusters) get(ctx context.Context, doc interface{}) (interface{}, error) {
+	d := doc.(*api.OpenShiftClusterDocument)
+	return c.c.Get(ctx, d.PartitionKey, d.ID, nil)
+}
+
+func (c openShiftClusters) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.OpenShiftClusterDocument)
+	_, err := c.c.Replace(ctx, d.PartitionKey, d, &cosmosdb.Options{})
+	return err
+}
+
+type openShiftClusterPager struct {
+	i cosmosdb.OpenShiftClusterDocumentIterator
+}
+
+func (p openShiftClusterPager) next(ctx context.Context) ([]interface{}, error) {
+	docs, err := p.i.Next(ctx, pageSize)
+	if err != nil || docs == nil {
+		return nil, err
+	}
+
+	page := make([]interface{}, 0, len(docs.OpenShiftClusterDocuments))
+	for _, doc := range docs.OpenShiftClusterDocuments {
+		page = append(page, doc)
+	}
+
+	return page, nil
+}
+
+type subscriptions struct {
+	c cosmosdb.SubscriptionDocumentClient
+}
+
+func (subscriptions) name() string { return "Subscriptions" }
+
+func (c subscriptions) list() pager {
+	return subscriptionPager{c.c.List(nil)}
+}
+
+func (subscriptions) newDocument() interface{} { return &api.SubscriptionDocument{} }
+
+func (subscriptions) id(doc interface{}) string { return doc.(*api.SubscriptionDocument).ID }
+
+func (subscriptions) etag(doc interface{}) string { return doc.(*api.SubscriptionDocument).ETag }
+
+func (c subscriptions) create(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.SubscriptionDocument)
+	_, err := c.c.Create(ctx, d.ID, d, nil)
+	return err
+}
+
+func (c subscriptions) get(ctx context.Context, doc interface{}) (interface{}, error) {
+	d := doc.(*api.SubscriptionDocument)
+	return c.c.Get(ctx, d.ID, d.ID, nil)
+}
+
+func (c subscriptions) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.SubscriptionDocument)
+	_, err := c.c.Replace(ctx, d.ID, d, &cosmosdb.Options{})
+	return err
+}
+
+type subscriptionPager struct {
+	i cosmosdb.SubscriptionDocumentIterator
+}
+
+func (p subscriptionPager) next(ctx context.Context) ([]interface{}, error) {
+	docs, err := p.i.Next(ctx, pageSize)
+	if err != nil || docs == nil {
+		return nil, err
+	}
+
+	page := make([]interface{}, 0, len(docs.SubscriptionDocuments))
+	for _, doc := range docs.SubscriptionDocuments {
+		page = append(page, doc)
+	}
+
+	return page, nil
+}
+
+// billing documents are created without the setCreationBillingTimeStamp
+// pre-trigger, so that imported documents keep their original timestamps
+type billing struct {
+	c cosmosdb.BillingDocumentClient
+}
+
+func (billing) name() string { return "Billing" }
+
+func (c billing) list() pager {
+	return billingPager{c.c.List(nil)}
+}
+
+func (billing) newDocument() interface{} { return &api.BillingDocument{} }
+
+func (billing) id(doc interface{}) string { return doc.(*api.BillingDocument).ID }
+
+func (billing) etag(doc interface{}) string { return doc.(*api.BillingDocument).ETag }
+
+func (c billing) create(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.BillingDocument)
+	_, err := c.c.Create(ctx, d.ID, d, nil)
+	return err
+}
+
+func (c billing) get(ctx context.Context, doc interface{}) (interface{}, error) {
+	d := doc.(*api.BillingDocument)
+	return c.c.Get(ctx, d.ID, d.ID, nil)
+}
+
+func (c billing) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.BillingDocument)
+	_, err := c.c.Replace(ctx, d.ID, d, &cosmosdb.Options{})
+	return err
+}
+
+type billingPager struct {
+	i cosmosdb.BillingDocumentIterator
+}
+
+func (p billingPager) next(ctx context.Context) ([]interface{}, error) {
+	docs, err := p.i.Next(ctx, pageSize)
+	if err != nil || docs == nil {
+		return nil, err
+	}
+
+	page := make([]interface{}, 0, len(docs.BillingDocuments))
+	for _, doc := range docs.BillingDocuments {
+		page = append(page, doc)
+	}
+
+	return page, nil
+}
+
+type openShiftVersions struct {
+	c cosmosdb.OpenShiftVersionDocumentClient
+}
+
+func (openShiftVersions) name() string { return "OpenShiftVersions" }
+
+func (c openShiftVersions) list() pager {
+	return openShiftVersionPager{c.c.List(nil)}
+}
+
+func (openShiftVersions) newDocument() interface{} { return &api.OpenShiftVersionDocument{} }
+
+func (openShiftVersions) id(doc interface{}) string { return doc.(*api.OpenShiftVersionDocument).ID }
+
+func (openShiftVersions) etag(doc interface{}) string {
+	return doc.(*api.OpenShiftVersionDocument).ETag
+}
+
+func (c openShiftVersions) create(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.OpenShiftVersionDocument)
+	_, err := c.c.Create(ctx, d.ID, d, nil)
+	return err
+}
+
+func (c openShiftVersions) get(ctx context.Context, doc interface{}) (interface{}, error) {
+	d := doc.(*api.OpenShiftVersionDocument)
+	return c.c.Get(ctx, d.ID, d.ID, nil)
+}
+
+func (c openShiftVersions) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.OpenShiftVersionDocument)
+	_, err := c.c.Replace(ctx, d.ID, d, &cosmosdb.Options{})
+	return err
+}
+
+type openShiftVersionPager struct {
+	i cosmosdb.OpenShiftVersionDocumentIterator
+}
+
+func (p openShiftVersionPager) next(ctx context.Context) ([]interface{}, error) {
+	docs, err := p.i.Next(ctx, pageSize)
+	if err != nil || docs == nil {
+		return nil, err
+	}
+
+	page := make([]interface{}, 0, len(docs.OpenShiftVersionDocuments))
+	for _, doc := range docs.OpenShiftVersionDocuments {
+		page = append(page, doc)
+	}
+
+	return page, nil
+}
+
+type gateways struct {
+	c cosmosdb.GatewayDocumentClient
+}
+
+func (gateways) name() string { return "Gateway" }
+
+func (c gateways) list() pager {
+	return gatewayPager{c.c.List(nil)}
+}
+
+func (gateways) newDocument() interface{} { return &api.GatewayDocument{} }
+
+func (gateways) id(doc interface{}) string { return doc.(*api.GatewayDocument).ID }
+
+func (gateways) etag(doc interface{}) string { return doc.(*api.GatewayDocument).ETag }
+
+func (c gateways) create(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.GatewayDocument)
+	_, err := c.c.Create(ctx, d.ID, d, nil)
+	return err
+}
+
+func (c gateways) get(ctx context.Context, doc interface{}) (interface{}, error) {
+	d := doc.(*api.GatewayDocument)
+	return c.c.Get(ctx, d.ID, d.ID, nil)
+}
+
+func (c gateways) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.GatewayDocument)
+	_, err := c.c.Replace(ctx, d.ID, d, &cosmosdb.Options{})
+	return err
+}
+
+type gatewayPager struct {
+	i cosmosdb.GatewayDocumentIterator
+}
+
+func (p gatewayPager) next(ctx context.Context) ([]interface{}, error) {
+	docs, err := p.i.Next(ctx, pageSize)
+	if err != nil || docs == nil {
+		return nil, err
+	}
+
+	page := make([]interface{}, 0, len(docs.GatewayDocuments))
+	for _, doc := range docs.GatewayDocuments {
+		page = append(page, doc)
+	}
+
+	return page, nil
+}
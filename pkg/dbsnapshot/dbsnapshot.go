@@ -0,0 +1,365 @@
+package dbsnapshot
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ugorji/go/codec"
+
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+// Snapshotter exports the OpenShiftClusters, Subscriptions, Billing,
+// OpenShiftVersions and Gateway collections to a stream of newline delimited
+// JSON records, and imports them again.  Encrypted fields remain sealed in the
+// stream.
+type Snapshotter interface {
+	// Export writes one record per document to w
+	Export(context.Context, io.Writer) error
+
+	// Import creates the documents read from r.  Documents which already
+	// exist with the same contents are skipped, so that an import can be
+	// re-run.  Documents which already exist with different contents are
+	// replaced if overwrite is set, and otherwise cause Import to fail once
+	// every record has been processed.
+	Import(ctx context.Context, r io.Reader, overwrite bool) error
+}
+
+// record is a line of a snapshot.  Document holds the document as stored,
+// including its system properties.
+type record struct {
+	Collection string          `json:"collection"`
+	Document   json.RawMessage `json:"document"`
+}
+
+// systemProperties are set by Cosmos DB and are removed from documents before
+// they are imported or compared
+var systemProperties = []string{"_rid", "_self", "_etag", "_attachments", "_ts", "_lsn", "_metadata"}
+
+type snapshotter struct {
+	log *logrus.Entry
+
+	collections []collection
+
+	// plain is the handle of the document clients, which leaves encrypted
+	// fields sealed.  If the snapshot is re-keyed, source opens encrypted
+	// fields and target seals them again for the target environment.
+	plain  *codec.JsonHandle
+	source *codec.JsonHandle
+	target *codec.JsonHandle
+}
+
+// NewSnapshotter returns a new Snapshotter.  dbc must have been created
+// without an AEAD, so that documents are read and written with their
+// encrypted fields sealed.  If target is set, exported documents have their
+// encrypted fields opened with source and re-sealed with target, for import
+// into an environment with different encryption keys.
+func NewSnapshotter(log *logrus.Entry, dbc cosmosdb.DatabaseClient, dbName string, source, target encryption.AEAD) (Snapshotter, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	return newSnapshotter(log, source, target, []collection{
+		openShiftClusters{c: cosmosdb.NewOpenShiftClusterDocumentClient(collc, openShiftClusters{}.name())},
+		subscriptions{c: cosmosdb.NewSubscriptionDocumentClient(collc, subscriptions{}.name())},
+		billing{c: cosmosdb.NewBillingDocumentClient(collc, billing{}.name())},
+		openShiftVersions{c: cosmosdb.NewOpenShiftVersionDocumentClient(collc, openShiftVersions{}.name())},
+		gateways{c: cosmosdb.NewGatewayDocumentClient(collc, gateways{}.name())},
+	})
+}
+
+func newSnapshotter(log *logrus.Entry, source, target encryption.AEAD, collections []collection) (*snapshotter, error) {
+	plain, err := database.NewJSONHandle(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &snapshotter{
+		log: log,
+
+		collections: collections,
+
+		plain: plain,
+	}
+
+	if target != nil {
+		if source == nil {
+			return nil, errors.New("re-keying requires a source AEAD")
+		}
+
+		s.source, err = database.NewJSONHandle(source)
+		if err != nil {
+			return nil, err
+		}
+
+		s.target, err = database.NewJSONHandle(target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (s *snapshotter) Export(ctx context.Context, w io.Writer) error {
+	e := json.NewEncoder(w)
+
+	for _, c := range s.collections {
+		var exported int
+
+		p := c.list()
+		for {
+			docs, err := p.next(ctx)
+			if err != nil {
+				return fmt.Errorf("%s: %w", c.name(), err)
+			}
+			if docs == nil {
+				break
+			}
+
+			for _, doc := range docs {
+				b, err := s.export(c, doc)
+				if err != nil {
+					return fmt.Errorf("%s: %s: %w", c.name(), c.id(doc), err)
+				}
+
+				err = e.Encode(&record{
+					Collection: c.name(),
+					Document:   b,
+				})
+				if err != nil {
+					return err
+				}
+
+				exported++
+			}
+		}
+
+		s.log.Infof("%s: exported %d documents", c.name(), exported)
+	}
+
+	return nil
+}
+
+// export returns doc, which is held as stored, encoded for a snapshot
+func (s *snapshotter) export(c collection, doc interface{}) ([]byte, error) {
+	if s.target == nil {
+		return encode(s.plain, doc)
+	}
+
+	opened := c.newDocument()
+	err := convert(s.plain, doc, s.source, opened)
+	if err != nil {
+		return nil, err
+	}
+
+	return encode(s.target, opened)
+}
+
+func (s *snapshotter) Import(ctx context.Context, r io.Reader, overwrite bool) error {
+	collections := map[string]collection{}
+	for _, c := range s.collections {
+		collections[c.name()] = c
+	}
+
+	type counts struct {
+		created, unchanged, replaced, conflicting int
+	}
+	results := map[string]*counts{}
+
+	d := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var rec record
+		err := d.Decode(&rec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		c, found := collections[rec.Collection]
+		if !found {
+			return fmt.Errorf("line %d: unknown collection %q", line, rec.Collection)
+		}
+
+		if results[c.name()] == nil {
+			results[c.name()] = &counts{}
+		}
+
+		result, err := s.importDocument(ctx, c, rec.Document, overwrite)
+		if err != nil {
+			return fmt.Errorf("line %d: %s: %w", line, c.name(), err)
+		}
+
+		switch result {
+		case created:
+			results[c.name()].created++
+		case unchanged:
+			results[c.name()].unchanged++
+		case replaced:
+			results[c.name()].replaced++
+		case conflicting:
+			results[c.name()].conflicting++
+		}
+	}
+
+	var conflicts int
+	for _, c := range s.collections {
+		if n := results[c.name()]; n != nil {
+			s.log.Infof("%s: %d documents created, %d unchanged, %d replaced, %d conflicting", c.name(), n.created, n.unchanged, n.replaced, n.conflicting)
+			conflicts += n.conflicting
+		}
+	}
+
+	if conflicts > 0 {
+		return fmt.Errorf("%d documents already exist with different contents", conflicts)
+	}
+
+	return nil
+}
+
+type importResult int
+
+const (
+	created importResult = iota
+	unchanged
+	replaced
+	conflicting
+)
+
+// importDocument creates the document b in c.  If the document already exists,
+// it is left alone if its contents are the same as b and is replaced with b if
+// overwrite is set.
+func (s *snapshotter) importDocument(ctx context.Context, c collection, b []byte, overwrite bool) (importResult, error) {
+	fields, err := documentFields(b)
+	if err != nil {
+		return 0, err
+	}
+
+	doc, err := s.decodeFields(c, fields)
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.create(ctx, doc)
+	if err == nil {
+		return created, nil
+	}
+	if !cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
+		return 0, fmt.Errorf("%s: %w", c.id(doc), err)
+	}
+
+	var result importResult
+	err = cosmosdb.RetryOnPreconditionFailed(func() error {
+		existing, err := c.get(ctx, doc)
+		if err != nil {
+			return err
+		}
+
+		b, err := encode(s.plain, existing)
+		if err != nil {
+			return err
+		}
+
+		existingFields, err := documentFields(b)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case reflect.DeepEqual(existingFields, fields):
+			result = unchanged
+			return nil
+
+		case !overwrite:
+			s.log.Warnf("%s: %s: already exists with different contents", c.name(), c.id(doc))
+			result = conflicting
+			return nil
+		}
+
+		// the replacement carries the ETag of the existing document, so that
+		// a document which is updated concurrently is compared again
+		replacementFields := map[string]interface{}{"_etag": c.etag(existing)}
+		for k, v := range fields {
+			replacementFields[k] = v
+		}
+		replacement, err := s.decodeFields(c, replacementFields)
+		if err != nil {
+			return err
+		}
+
+		result = replaced
+		return c.replace(ctx, replacement)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", c.id(doc), err)
+	}
+
+	return result, nil
+}
+
+// decodeFields returns a new document of c holding fields
+func (s *snapshotter) decodeFields(c collection, fields map[string]interface{}) (interface{}, error) {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := c.newDocument()
+	err = codec.NewDecoderBytes(b, s.plain).Decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// documentFields returns the fields of the JSON document b, without its
+// system properties.  Numbers are kept as they were encoded.
+func documentFields(b []byte) (map[string]interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var fields map[string]interface{}
+	err := d.Decode(&fields)
+	if err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, errors.New("empty document")
+	}
+
+	for _, k := range systemProperties {
+		delete(fields, k)
+	}
+
+	return fields, nil
+}
+
+func encode(h *codec.JsonHandle, in interface{}) ([]byte, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, h).Encode(in)
+	return b, err
+}
+
+// convert encodes in with inHandle and decodes the result into out with
+// outHandle
+func convert(inHandle *codec.JsonHandle, in interface{}, outHandle *codec.JsonHandle, out interface{}) error {
+	b, err := encode(inHandle, in)
+	if err != nil {
+		return err
+	}
+
+	return codec.NewDecoderBytes(b, outHandle).Decode(out)
+}
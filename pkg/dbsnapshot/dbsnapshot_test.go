@@ -0,0 +1,269 @@
+package dbsnapshot
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ugorji/go/codec"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+type fakeCollections struct {
+	clusters      cosmosdb.OpenShiftClusterDocumentClient
+	subscriptions cosmosdb.SubscriptionDocumentClient
+	billing       cosmosdb.BillingDocumentClient
+	versions      cosmosdb.OpenShiftVersionDocumentClient
+	gateways      cosmosdb.GatewayDocumentClient
+}
+
+func newFakeCollections(t *testing.T) *fakeCollections {
+	plain, err := database.NewJSONHandle(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &fakeCollections{
+		clusters:      cosmosdb.NewFakeOpenShiftClusterDocumentClient(plain),
+		subscriptions: cosmosdb.NewFakeSubscriptionDocumentClient(plain),
+		billing:       cosmosdb.NewFakeBillingDocumentClient(plain),
+		versions:      cosmosdb.NewFakeOpenShiftVersionDocumentClient(plain),
+		gateways:      cosmosdb.NewFakeGatewayDocumentClient(plain),
+	}
+}
+
+func (f *fakeCollections) snapshotter(t *testing.T, source, target encryption.AEAD) *snapshotter {
+	s, err := newSnapshotter(logrus.NewEntry(logrus.StandardLogger()), source, target, []collection{
+		openShiftClusters{c: f.clusters},
+		subscriptions{c: f.subscriptions},
+		billing{c: f.billing},
+		openShiftVersions{c: f.versions},
+		gateways{c: f.gateways},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestSnapshotter(t *testing.T) {
+	ctx := context.Background()
+
+	sourceAEAD, err := encryption.NewAES256SHA512(ctx, bytes.Repeat([]byte{1}, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetAEAD, err := encryption.NewAES256SHA512(ctx, bytes.Repeat([]byte{2}, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sourceHandle, err := database.NewJSONHandle(sourceAEAD)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetHandle, err := database.NewJSONHandle(targetAEAD)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := database.NewJSONHandle(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// populate returns source collections holding a cluster, whose kubeconfig
+	// is sealed with the source key, and a document in each other collection
+	populate := func(t *testing.T) *fakeCollections {
+		f := newFakeCollections(t)
+
+		cluster := &api.OpenShiftClusterDocument{}
+		err := convert(sourceHandle, &api.OpenShiftClusterDocument{
+			ID:           "cluster",
+			Key:          "/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster",
+			PartitionKey: "sub",
+			OpenShiftCluster: &api.OpenShiftCluster{
+				Name: "cluster",
+				Properties: api.OpenShiftClusterProperties{
+					AdminKubeconfig: api.SecureBytes("kubeconfig"),
+				},
+			},
+		}, plain, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = f.clusters.Create(ctx, "sub", cluster, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = f.subscriptions.Create(ctx, "sub", &api.SubscriptionDocument{ID: "sub", Subscription: &api.Subscription{State: api.SubscriptionStateRegistered}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = f.billing.Create(ctx, "cluster", &api.BillingDocument{ID: "cluster", Key: cluster.Key, Billing: &api.Billing{CreationTime: 1672574400}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = f.versions.Create(ctx, "version", &api.OpenShiftVersionDocument{ID: "version", OpenShiftVersion: &api.OpenShiftVersion{Properties: api.OpenShiftVersionProperties{Version: "4.12.25"}}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = f.gateways.Create(ctx, "gateway", &api.GatewayDocument{ID: "gateway", Gateway: &api.Gateway{ID: cluster.Key}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return f
+	}
+
+	// kubeconfig opens the kubeconfig of the imported cluster with h
+	kubeconfig := func(t *testing.T, f *fakeCollections, h *codec.JsonHandle) string {
+		doc, err := f.clusters.Get(ctx, "sub", "cluster", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		opened := &api.OpenShiftClusterDocument{}
+		err = convert(plain, doc, h, opened)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(opened.OpenShiftCluster.Properties.AdminKubeconfig)
+	}
+
+	t.Run("export and import", func(t *testing.T) {
+		source := populate(t)
+
+		buf := &bytes.Buffer{}
+		err := source.snapshotter(t, nil, nil).Export(ctx, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lines := strings.Count(buf.String(), "\n"); lines != 5 {
+			t.Fatalf("exported %d records", lines)
+		}
+
+		target := newFakeCollections(t)
+		s := target.snapshotter(t, nil, nil)
+
+		for i := 0; i < 2; i++ {
+			// a second import finds every document unchanged
+			err = s.Import(ctx, bytes.NewReader(buf.Bytes()), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if kubeconfig(t, target, sourceHandle) != "kubeconfig" {
+			t.Error("unexpected kubeconfig")
+		}
+
+		billing, err := target.billing.Get(ctx, "cluster", "cluster", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if billing.Billing.CreationTime != 1672574400 {
+			t.Errorf("creation time %d", billing.Billing.CreationTime)
+		}
+	})
+
+	t.Run("conflicting documents", func(t *testing.T) {
+		source := populate(t)
+
+		buf := &bytes.Buffer{}
+		err := source.snapshotter(t, nil, nil).Export(ctx, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		target := newFakeCollections(t)
+		existing, err := target.subscriptions.Create(ctx, "sub", &api.SubscriptionDocument{ID: "sub", Subscription: &api.Subscription{State: api.SubscriptionStateSuspended}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := target.snapshotter(t, nil, nil)
+
+		err = s.Import(ctx, bytes.NewReader(buf.Bytes()), false)
+		if err == nil || err.Error() != "1 documents already exist with different contents" {
+			t.Fatal(err)
+		}
+
+		doc, err := target.subscriptions.Get(ctx, "sub", "sub", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.ETag != existing.ETag {
+			t.Error("conflicting document was replaced")
+		}
+
+		// the remaining documents were imported regardless
+		_, err = target.gateways.Get(ctx, "gateway", "gateway", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = s.Import(ctx, bytes.NewReader(buf.Bytes()), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		doc, err = target.subscriptions.Get(ctx, "sub", "sub", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.Subscription.State != api.SubscriptionStateRegistered {
+			t.Errorf("state %s", doc.Subscription.State)
+		}
+	})
+
+	t.Run("re-keyed export", func(t *testing.T) {
+		source := populate(t)
+
+		buf := &bytes.Buffer{}
+		err := source.snapshotter(t, sourceAEAD, targetAEAD).Export(ctx, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(buf.String(), "kubeconfig") {
+			t.Fatal("snapshot holds plaintext")
+		}
+
+		target := newFakeCollections(t)
+		err = target.snapshotter(t, nil, nil).Import(ctx, bytes.NewReader(buf.Bytes()), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if kubeconfig(t, target, targetHandle) != "kubeconfig" {
+			t.Error("unexpected kubeconfig")
+		}
+	})
+
+	t.Run("unknown collection", func(t *testing.T) {
+		err := newFakeCollections(t).snapshotter(t, nil, nil).Import(ctx, strings.NewReader(`{"collection":"Unknown","document":{"id":"x"}}`+"\n"), false)
+		if err == nil || err.Error() != `line 1: unknown collection "Unknown"` {
+			t.Error(err)
+		}
+	})
+}
@@ -86,6 +86,7 @@ type Configuration struct {
 	PortalAccessGroupIDs               []string               `json:"portalAccessGroupIds,omitempty" value:"required"`
 	PortalClientID                     *string                `json:"portalClientId,omitempty" value:"required"`
 	PortalElevatedGroupIDs             []string               `json:"portalElevatedGroupIds,omitempty" value:"required"`
+	PortalSSHRecordingsStorageAccount  *string                `json:"portalSshRecordingsStorageAccount,omitempty" value:"required"`
 	RPFeatures                         []string               `json:"rpFeatures,omitempty"`
 	RPImagePrefix                      *string                `json:"rpImagePrefix,omitempty" value:"required"`
 	RPMDMAccount                       *string                `json:"rpMdmAccount,omitempty" value:"required"`
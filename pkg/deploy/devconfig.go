@@ -95,12 +95,13 @@ func DevConfig(_env env.Core) (*Config, error) {
 				GatewayResourceGroupName: os.Getenv("USER") + "-gwy-" + _env.Location(),
 				RPResourceGroupName:      os.Getenv("USER") + "-aro-" + _env.Location(),
 				Configuration: &Configuration{
-					AzureCloudName:              &_env.Environment().ActualCloudName,
-					DatabaseAccountName:         to.StringPtr(os.Getenv("USER") + "-aro-" + _env.Location()),
-					GatewayStorageAccountDomain: to.StringPtr(os.Getenv("USER") + "gwy" + _env.Location() + ".blob." + _env.Environment().StorageEndpointSuffix),
-					KeyvaultDNSSuffix:           &_env.Environment().KeyVaultDNSSuffix,
-					KeyvaultPrefix:              &keyvaultPrefix,
-					StorageAccountDomain:        to.StringPtr(os.Getenv("USER") + "aro" + _env.Location() + ".blob." + _env.Environment().StorageEndpointSuffix),
+					AzureCloudName:                    &_env.Environment().ActualCloudName,
+					DatabaseAccountName:               to.StringPtr(os.Getenv("USER") + "-aro-" + _env.Location()),
+					GatewayStorageAccountDomain:       to.StringPtr(os.Getenv("USER") + "gwy" + _env.Location() + ".blob." + _env.Environment().StorageEndpointSuffix),
+					KeyvaultDNSSuffix:                 &_env.Environment().KeyVaultDNSSuffix,
+					KeyvaultPrefix:                    &keyvaultPrefix,
+					PortalSSHRecordingsStorageAccount: to.StringPtr(os.Getenv("USER") + "ssh" + _env.Location()),
+					StorageAccountDomain:              to.StringPtr(os.Getenv("USER") + "aro" + _env.Location() + ".blob." + _env.Environment().StorageEndpointSuffix),
 				},
 			},
 		},
@@ -261,6 +261,7 @@ echo "configuring aro-rp service"
 cat >/etc/sysconfig/aro-rp <<EOF
 ACR_RESOURCE_ID='$ACRRESOURCEID'
 ADMIN_API_CLIENT_CERT_COMMON_NAME='$ADMINAPICLIENTCERTCOMMONNAME'
+ARO_ALERT_INGESTION_URL='https://rp.$LOCATION.$RPPARENTDOMAINNAME'
 ARM_API_CLIENT_CERT_COMMON_NAME='$ARMAPICLIENTCERTCOMMONNAME'
 AZURE_ARM_CLIENT_ID='$ARMCLIENTID'
 AZURE_FP_CLIENT_ID='$FPCLIENTID'
@@ -302,6 +303,7 @@ ExecStart=/usr/bin/docker run \
   --cap-drop net_raw \
   -e ACR_RESOURCE_ID \
   -e ADMIN_API_CLIENT_CERT_COMMON_NAME \
+  -e ARO_ALERT_INGESTION_URL \
   -e ARM_API_CLIENT_CERT_COMMON_NAME \
   -e AZURE_ARM_CLIENT_ID \
   -e AZURE_FP_CLIENT_ID \
@@ -458,6 +460,7 @@ KEYVAULT_PREFIX='$KEYVAULTPREFIX'
 MDM_ACCOUNT='$RPMDMACCOUNT'
 MDM_NAMESPACE=Portal
 PORTAL_HOSTNAME='$LOCATION.admin.$RPPARENTDOMAINNAME'
+PORTAL_SSH_RECORDINGS_STORAGE_ACCOUNT='$PORTALSSHRECORDINGSSTORAGEACCOUNT'
 RPIMAGE='$RPIMAGE'
 EOF
 
@@ -483,6 +486,7 @@ ExecStart=/usr/bin/docker run \
   -e MDM_ACCOUNT \
   -e MDM_NAMESPACE \
   -e PORTAL_HOSTNAME \
+  -e PORTAL_SSH_RECORDINGS_STORAGE_ACCOUNT \
   -m 2g \
   -p 444:8444 \
   -p 2222:2222 \
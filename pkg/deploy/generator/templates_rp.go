@@ -66,6 +66,7 @@ func (g *generator) rpTemplate() *arm.Template {
 			"portalAccessGroupIds",
 			"portalClientId",
 			"portalElevatedGroupIds",
+			"portalSshRecordingsStorageAccount",
 			"rpFeatures",
 			"rpImage",
 			"rpMdmAccount",
@@ -173,6 +174,9 @@ func (g *generator) rpTemplate() *arm.Template {
 			g.rpLBInternal(),
 			g.rpVMSS(),
 			g.rpStorageAccount(),
+		)
+		t.Resources = append(t.Resources, g.rpPortalSSHRecordingsStorageAccount()...)
+		t.Resources = append(t.Resources,
 			g.rpLBAlert(30.0, 2, "rp-availability-alert", "PT5M", "PT15M", "DipAvailability"), // triggers on all 3 RPs being down for 10min, can't be >=0.3 due to deploys going down to 32% at times.
 			g.rpLBAlert(67.0, 3, "rp-degraded-alert", "PT15M", "PT6H", "DipAvailability"),     // 1/3 backend down for 1h or 2/3 down for 3h in the last 6h
 			g.rpLBAlert(33.0, 2, "rp-vnet-alert", "PT5M", "PT5M", "VipAvailability"))          // this will trigger only if the Azure network infrastructure between the loadBalancers and VMs is down for 3.5min
@@ -14,6 +14,18 @@ import (
 
 const (
 	rpVMSSPrefix = "rp-vmss-"
+
+	// rpDrainAndStopScript drains the RP, waits for its backend to hand over
+	// its work and then stops it.  An RP which predates drain mode returns 404
+	// on /healthz/drained and would exit on SIGUSR1, so it is stopped straight
+	// away as before.
+	rpDrainAndStopScript = `case "$(curl -ks -o /dev/null -w '%{http_code}' https://localhost/healthz/drained)" in
+200|503)
+  docker kill --signal=SIGUSR1 aro-rp
+  timeout 3600 sh -c 'until curl -ksf -o /dev/null https://localhost/healthz/drained; do sleep 10; done'
+  ;;
+esac
+systemctl stop aro-rp`
 )
 
 func (d *deployer) UpgradeRP(ctx context.Context) error {
@@ -76,18 +88,18 @@ func (d *deployer) rpRemoveOldScaleset(ctx context.Context, vmssName string) err
 		return err
 	}
 
-	d.log.Printf("stopping scaleset %s", vmssName)
+	d.log.Printf("draining and stopping scaleset %s", vmssName)
 	errors := make(chan error, len(scalesetVMs))
 	for _, vm := range scalesetVMs {
 		go func(id string) {
 			errors <- d.vmssvms.RunCommandAndWait(ctx, d.config.RPResourceGroupName, vmssName, id, mgmtcompute.RunCommandInput{
 				CommandID: to.StringPtr("RunShellScript"),
-				Script:    &[]string{"systemctl stop aro-rp"},
+				Script:    &[]string{rpDrainAndStopScript},
 			})
 		}(*vm.InstanceID) // https://golang.org/doc/faq#closures_and_goroutines
 	}
 
-	d.log.Print("waiting for instances to stop")
+	d.log.Print("waiting for instances to drain and stop")
 	for range scalesetVMs {
 		err := <-errors
 		if err != nil {
@@ -28,6 +28,7 @@ const (
 	COMPONENT_PORTAL              ServiceComponent = "PORTAL"
 	COMPONENT_UPDATE_OCP_VERSIONS ServiceComponent = "UPDATE_OCP_VERSIONS"
 	COMPONENT_DEPLOY              ServiceComponent = "DEPLOY"
+	COMPONENT_REENCRYPT           ServiceComponent = "REENCRYPT"
 	COMPONENT_TOOLING             ServiceComponent = "TOOLING"
 )
 
@@ -34,6 +34,14 @@ const (
 	FeatureRequireD2sV3Workers
 	FeatureDisableReadinessDelay
 	FeatureEnableOCMEndpoints
+
+	// FeatureRequireApproval* hold the corresponding destructive admin action
+	// until a second identity approves it
+	FeatureRequireApprovalDeleteManagedResource
+	FeatureRequireApprovalEtcdRecovery
+	FeatureRequireApprovalKubernetesObjectsDelete
+	FeatureRequireApprovalRedeployVM
+	FeatureRequireApprovalStopVM
 )
 
 const (
@@ -6,9 +6,9 @@ import (
 	"fmt"
 )
 
-const _FeatureName = "FeatureDisableDenyAssignmentsFeatureDisableSignedCertificatesFeatureEnableDevelopmentAuthorizerFeatureRequireD2sV3WorkersFeatureDisableReadinessDelayFeatureEnableOCMEndpoints"
+const _FeatureName = "FeatureDisableDenyAssignmentsFeatureDisableSignedCertificatesFeatureEnableDevelopmentAuthorizerFeatureRequireD2sV3WorkersFeatureDisableReadinessDelayFeatureEnableOCMEndpointsFeatureRequireApprovalDeleteManagedResourceFeatureRequireApprovalEtcdRecoveryFeatureRequireApprovalKubernetesObjectsDeleteFeatureRequireApprovalRedeployVMFeatureRequireApprovalStopVM"
 
-var _FeatureIndex = [...]uint8{0, 29, 61, 95, 121, 149, 174}
+var _FeatureIndex = [...]uint16{0, 29, 61, 95, 121, 149, 174, 217, 251, 296, 328, 356}
 
 func (i Feature) String() string {
 	if i < 0 || i >= Feature(len(_FeatureIndex)-1) {
@@ -17,7 +17,7 @@ func (i Feature) String() string {
 	return _FeatureName[_FeatureIndex[i]:_FeatureIndex[i+1]]
 }
 
-var _FeatureValues = []Feature{0, 1, 2, 3, 4, 5}
+var _FeatureValues = []Feature{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
 
 var _FeatureNameToValueMap = map[string]Feature{
 	_FeatureName[0:29]:    0,
@@ -26,6 +26,11 @@ var _FeatureNameToValueMap = map[string]Feature{
 	_FeatureName[95:121]:  3,
 	_FeatureName[121:149]: 4,
 	_FeatureName[149:174]: 5,
+	_FeatureName[174:217]: 6,
+	_FeatureName[217:251]: 7,
+	_FeatureName[251:296]: 8,
+	_FeatureName[296:328]: 9,
+	_FeatureName[328:356]: 10,
 }
 
 // FeatureString retrieves an enum value from the enum constants string name.
@@ -0,0 +1,130 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+// adminAuditLogWriteTimeout bounds the write of an audit entry, which is made
+// after the call has been served and so cannot use the request context
+const adminAuditLogWriteTimeout = 10 * time.Second
+
+// adminAuditLog persists every admin API call made against a cluster, other
+// than reads of the audit log itself, so that it can be queried per cluster
+func (f *frontend) adminAuditLog(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.dbAdminAuditLog == nil ||
+			(r.Method == http.MethodGet && filepath.Base(r.URL.Path) == "auditlog") {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		// handlers rewrite r.URL.Path, so take a copy first
+		method, path, query := r.Method, r.URL.Path, r.URL.Query()
+		resourceID := strings.ToLower(adminResourceID(r))
+		startedAt := f.now().UTC()
+
+		rw := &statusRecordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		h.ServeHTTP(rw, r)
+
+		entry := &api.AdminAuditEntry{
+			Method:     method,
+			Path:       path,
+			StatusCode: rw.statusCode,
+			StartedAt:  startedAt,
+			Duration:   f.now().Sub(startedAt).Seconds(),
+		}
+
+		if len(query) > 0 {
+			entry.Parameters = query
+		}
+
+		if correlationData, ok := r.Context().Value(middleware.ContextKeyCorrelationData).(*api.CorrelationData); ok {
+			entry.CallerIdentity = correlationData.ClientPrincipalName
+			entry.RequestID = correlationData.RequestID
+			entry.ClientRequestID = correlationData.ClientRequestID
+			entry.CorrelationID = correlationData.CorrelationID
+		}
+		if entry.CallerIdentity == "" {
+			entry.CallerIdentity = r.UserAgent()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), adminAuditLogWriteTimeout)
+		defer cancel()
+
+		_, err := f.dbAdminAuditLog.Create(ctx, &api.AdminAuditEntryDocument{
+			ID:              f.dbAdminAuditLog.NewUUID(),
+			Key:             resourceID,
+			AdminAuditEntry: entry,
+		})
+		if err != nil {
+			if log, ok := r.Context().Value(middleware.ContextKeyLog).(*logrus.Entry); ok {
+				log.Error(err)
+			}
+		}
+	})
+}
+
+func (f *frontend) getAdminAuditLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._getAdminAuditLog(ctx, r)
+
+	adminReply(log, w, nil, b, err)
+}
+
+// _getAdminAuditLog returns the admin API calls made against a cluster, most
+// recent first.  The since query parameter limits the calls to those started
+// at or after the given RFC3339 time.
+func (f *frontend) _getAdminAuditLog(ctx context.Context, r *http.Request) ([]byte, error) {
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "since", "The provided since '%s' is invalid.", v)
+		}
+	}
+
+	docs, err := f.dbAdminAuditLog.ListByKey(ctx, strings.ToLower(adminResourceID(r)))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*api.AdminAuditEntry, 0, len(docs.AdminAuditEntryDocuments))
+	for _, doc := range docs.AdminAuditEntryDocuments {
+		if doc.AdminAuditEntry == nil || doc.AdminAuditEntry.StartedAt.Before(since) {
+			continue
+		}
+		entries = append(entries, doc.AdminAuditEntry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].StartedAt.After(entries[j].StartedAt) })
+
+	return json.MarshalIndent(entries, "", "    ")
+}
+
+// adminResourceID returns the resource ID of the cluster an admin API call is
+// made against
+func adminResourceID(r *http.Request) string {
+	return "/subscriptions/" + chi.URLParam(r, "subscriptionId") +
+		"/resourcegroups/" + chi.URLParam(r, "resourceGroupName") +
+		"/providers/" + chi.URLParam(r, "resourceProviderNamespace") +
+		"/" + chi.URLParam(r, "resourceType") +
+		"/" + chi.URLParam(r, "resourceName")
+}
@@ -0,0 +1,267 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	mock_adminactions "github.com/Azure/ARO-RP/pkg/util/mocks/adminactions"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminAuditLog(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	mockTenantID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	key := strings.ToLower(resourceID)
+	otherKey := strings.ToLower(testdatabase.GetResourcePath(mockSubID, "otherResourceName"))
+	vmName := "aro-worker-australiasoutheast-7tcq7"
+
+	ctx := context.Background()
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-2 * time.Hour)
+	earliest := now.Add(-48 * time.Hour)
+
+	clusterFixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			Key: key,
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+				Properties: api.OpenShiftClusterProperties{
+					ClusterProfile: api.ClusterProfile{
+						ResourceGroupID: fmt.Sprintf("/subscriptions/%s/resourceGroups/test-cluster", mockSubID),
+					},
+				},
+			},
+		})
+
+		f.AddSubscriptionDocuments(&api.SubscriptionDocument{
+			ID: mockSubID,
+			Subscription: &api.Subscription{
+				State: api.SubscriptionStateRegistered,
+				Properties: &api.SubscriptionProperties{
+					TenantID: mockTenantID,
+				},
+			},
+		})
+	}
+
+	historyDocs := []*api.AdminAuditEntryDocument{
+		{
+			ID:  "08080808-0808-0808-0808-080808080101",
+			Key: key,
+			AdminAuditEntry: &api.AdminAuditEntry{
+				CallerIdentity: "alice",
+				Method:         http.MethodPost,
+				Path:           "/admin" + key + "/redeployvm",
+				Parameters:     map[string][]string{"vmName": {vmName}},
+				StatusCode:     http.StatusOK,
+				StartedAt:      earliest,
+				Duration:       12.5,
+			},
+		},
+		{
+			ID:  "08080808-0808-0808-0808-080808080102",
+			Key: key,
+			AdminAuditEntry: &api.AdminAuditEntry{
+				CallerIdentity: "bob",
+				Method:         http.MethodPost,
+				Path:           "/admin" + key + "/cordonnode",
+				Parameters:     map[string][]string{"vmName": {vmName}, "shouldCordon": {"true"}},
+				StatusCode:     http.StatusOK,
+				StartedAt:      earlier,
+				Duration:       0.5,
+			},
+		},
+		{
+			ID:  "08080808-0808-0808-0808-080808080103",
+			Key: otherKey,
+			AdminAuditEntry: &api.AdminAuditEntry{
+				CallerIdentity: "carol",
+				Method:         http.MethodGet,
+				Path:           "/admin" + otherKey + "/resources",
+				StatusCode:     http.StatusOK,
+				StartedAt:      earlier,
+			},
+		},
+	}
+
+	type test struct {
+		name           string
+		method         string
+		path           string
+		fixture        func(*testdatabase.Fixture)
+		mocks          func(*mock_adminactions.MockAzureActions)
+		wantDocs       []*api.AdminAuditEntryDocument
+		wantStatusCode int
+		wantResponse   interface{}
+		wantError      string
+	}
+
+	for _, tt := range []*test{
+		{
+			name:    "admin action is recorded",
+			method:  http.MethodPost,
+			path:    "/stopvm?vmName=" + vmName + "&deallocateVM=false",
+			fixture: clusterFixture,
+			mocks: func(a *mock_adminactions.MockAzureActions) {
+				a.EXPECT().VMStopAndWait(gomock.Any(), vmName, false).Return(nil)
+			},
+			wantDocs: []*api.AdminAuditEntryDocument{
+				{
+					ID:  "08080808-0808-0808-0808-080808080001",
+					Key: key,
+					AdminAuditEntry: &api.AdminAuditEntry{
+						CallerIdentity: "alice",
+						Method:         http.MethodPost,
+						Path:           "/admin" + key + "/stopvm",
+						Parameters:     map[string][]string{"vmName": {vmName}, "deallocateVM": {"false"}},
+						StatusCode:     http.StatusOK,
+						StartedAt:      now,
+					},
+				},
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "failed admin action is recorded with its status code",
+			method: http.MethodPost,
+			path:   "/stopvm?vmName=" + vmName + "&deallocateVM=false",
+			wantDocs: []*api.AdminAuditEntryDocument{
+				{
+					ID:  "08080808-0808-0808-0808-080808080001",
+					Key: key,
+					AdminAuditEntry: &api.AdminAuditEntry{
+						CallerIdentity: "alice",
+						Method:         http.MethodPost,
+						Path:           "/admin" + key + "/stopvm",
+						Parameters:     map[string][]string{"vmName": {vmName}, "deallocateVM": {"false"}},
+						StatusCode:     http.StatusNotFound,
+						StartedAt:      now,
+					},
+				},
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+		{
+			name:   "list audit log for cluster, most recent first",
+			method: http.MethodGet,
+			path:   "/auditlog",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddAdminAuditEntryDocuments(historyDocs...)
+			},
+			wantDocs:       historyDocs,
+			wantStatusCode: http.StatusOK,
+			wantResponse: &[]*api.AdminAuditEntry{
+				historyDocs[1].AdminAuditEntry,
+				historyDocs[0].AdminAuditEntry,
+			},
+		},
+		{
+			name:   "list audit log since a given time",
+			method: http.MethodGet,
+			path:   "/auditlog?since=" + now.Add(-24*time.Hour).Format(time.RFC3339),
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddAdminAuditEntryDocuments(historyDocs...)
+			},
+			wantDocs:       historyDocs,
+			wantStatusCode: http.StatusOK,
+			wantResponse: &[]*api.AdminAuditEntry{
+				historyDocs[1].AdminAuditEntry,
+			},
+		},
+		{
+			name:           "list empty audit log",
+			method:         http.MethodGet,
+			path:           "/auditlog",
+			wantStatusCode: http.StatusOK,
+			wantResponse:   &[]*api.AdminAuditEntry{},
+		},
+		{
+			name:           "invalid since",
+			method:         http.MethodGet,
+			path:           "/auditlog?since=yesterday",
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: since: The provided since 'yesterday' is invalid.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions().WithAdminAuditLog()
+			defer ti.done()
+
+			a := mock_adminactions.NewMockAzureActions(ti.controller)
+			if tt.mocks != nil {
+				tt.mocks(a)
+			}
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, nil, ti.adminAuditLogDatabase, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, func(*logrus.Entry, env.Interface, *api.OpenShiftCluster, *api.SubscriptionDocument) (adminactions.AzureActions, error) {
+				return a, nil
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f.now = func() time.Time { return now }
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(tt.method, "https://server/admin"+resourceID+tt.path, http.Header{
+				"X-Ms-Client-Principal-Name": []string{"alice"},
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// the request ID is generated per request, so check it is set and
+			// then compare the rest of the entry
+			docs, err := ti.adminAuditLogClient.ListAll(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Slice(docs.AdminAuditEntryDocuments, func(i, j int) bool {
+				return docs.AdminAuditEntryDocuments[i].ID < docs.AdminAuditEntryDocuments[j].ID
+			})
+			for _, doc := range docs.AdminAuditEntryDocuments {
+				if strings.HasPrefix(doc.ID, "08080808-0808-0808-0808-0808080800") {
+					if doc.AdminAuditEntry.RequestID == "" {
+						t.Error("request ID not recorded")
+					}
+					doc.AdminAuditEntry.RequestID = ""
+				}
+			}
+
+			if len(docs.AdminAuditEntryDocuments) != 0 || len(tt.wantDocs) != 0 {
+				for _, diff := range deep.Equal(docs.AdminAuditEntryDocuments, tt.wantDocs) {
+					t.Error(diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+// deadLetterResponse is the admin API representation of a cluster document
+// which the backend has stopped working
+type deadLetterResponse struct {
+	ResourceID string `json:"resourceId"`
+
+	*api.DeadLetter
+}
+
+func (f *frontend) getAdminDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._getAdminDeadLetters(ctx)
+
+	adminReply(log, w, nil, b, err)
+}
+
+// _getAdminDeadLetters lists the dead lettered cluster documents in all
+// subscriptions.  The dequeue history is omitted; get the dead letter of a
+// cluster to inspect it.
+func (f *frontend) _getAdminDeadLetters(ctx context.Context) ([]byte, error) {
+	docs, err := f.dbOpenShiftClusters.ListDeadLetters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]*deadLetterResponse, 0, len(docs.OpenShiftClusterDocuments))
+	for _, doc := range docs.OpenShiftClusterDocuments {
+		deadLetter := *doc.DeadLetter
+		deadLetter.DequeueHistory = nil
+
+		deadLetters = append(deadLetters, &deadLetterResponse{
+			ResourceID: doc.Key,
+			DeadLetter: &deadLetter,
+		})
+	}
+
+	return json.MarshalIndent(deadLetters, "", "    ")
+}
+
+func (f *frontend) getAdminDeadLetter(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._getAdminDeadLetter(ctx, r)
+
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminDeadLetter(ctx context.Context, r *http.Request) ([]byte, error) {
+	resourceID := strings.ToLower(adminResourceID(r))
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, clusterNotFound(r)
+	case err != nil:
+		return nil, err
+	case doc.DeadLetter == nil:
+		return nil, deadLetterNotFound(r)
+	}
+
+	return json.MarshalIndent(&deadLetterResponse{
+		ResourceID: doc.Key,
+		DeadLetter: doc.DeadLetter,
+	}, "", "    ")
+}
+
+func (f *frontend) postAdminDeadLetterRequeue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	header := http.Header{}
+	b, err := f._postAdminDeadLetterRequeue(ctx, r, header)
+
+	adminReply(log, w, header, b, err)
+}
+
+// _postAdminDeadLetterRequeue returns a dead lettered cluster document to the
+// backend queue in the provisioning state it was dead lettered in, under a
+// new asynchronous operation.  The operation which was dead lettered has been
+// failed, so the document must be in a terminal provisioning state.
+func (f *frontend) _postAdminDeadLetterRequeue(ctx context.Context, r *http.Request, header http.Header) ([]byte, error) {
+	resourceID := strings.ToLower(adminResourceID(r))
+
+	var deadLetter *api.DeadLetter
+	var asyncdoc *api.AsyncOperationDocument
+	_, err := f.dbOpenShiftClusters.Patch(ctx, resourceID, func(doc *api.OpenShiftClusterDocument) error {
+		if doc.DeadLetter == nil {
+			return deadLetterNotFound(r)
+		}
+
+		err := validateTerminalProvisioningState(doc.OpenShiftCluster.Properties.ProvisioningState)
+		if err != nil {
+			return err
+		}
+
+		deadLetter = doc.DeadLetter
+
+		id := f.dbAsyncOperations.NewUUID()
+		asyncdoc, err = f.dbAsyncOperations.Create(ctx, &api.AsyncOperationDocument{
+			ID:                  id,
+			OpenShiftClusterKey: doc.Key,
+			AsyncOperation: &api.AsyncOperation{
+				ID:                       strings.TrimSuffix(r.URL.Path, "/deadletter/requeue") + "/operationsstatus/" + id,
+				Name:                     id,
+				InitialProvisioningState: deadLetter.ProvisioningState,
+				ProvisioningState:        deadLetter.ProvisioningState,
+				StartTime:                f.now().UTC(),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		if deadLetter.ProvisioningState != api.ProvisioningStateAdminUpdating {
+			doc.OpenShiftCluster.Properties.FailedProvisioningState = ""
+		}
+		doc.OpenShiftCluster.Properties.LastProvisioningState = doc.OpenShiftCluster.Properties.ProvisioningState
+		doc.OpenShiftCluster.Properties.ProvisioningState = deadLetter.ProvisioningState
+		doc.OpenShiftCluster.Properties.MaintenanceTask = deadLetter.MaintenanceTask
+		doc.AsyncOperationID = id
+
+		doc.DeadLetter = nil
+		doc.Dequeues = 0
+		doc.DequeueHistory = nil
+
+		return nil
+	})
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, clusterNotFound(r)
+	case err != nil:
+		return nil, err
+	}
+
+	header["Azure-AsyncOperation"] = []string{asyncdoc.AsyncOperation.ID}
+
+	return json.MarshalIndent(&deadLetterResponse{
+		ResourceID: resourceID,
+		DeadLetter: deadLetter,
+	}, "", "    ")
+}
+
+func clusterNotFound(r *http.Request) error {
+	return api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "",
+		"The Resource '%s/%s' under resource group '%s' was not found.",
+		chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName"))
+}
+
+func deadLetterNotFound(r *http.Request) error {
+	return api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "",
+		"The Resource '%s/%s' under resource group '%s' is not dead lettered.",
+		chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName"))
+}
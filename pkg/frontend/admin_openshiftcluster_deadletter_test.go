@@ -0,0 +1,311 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminDeadLetters(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	key := strings.ToLower(resourceID)
+	otherResourceID := testdatabase.GetResourcePath(mockSubID, "otherResourceName")
+
+	ctx := context.Background()
+	now := time.Unix(1672574500, 0)
+	asyncOperationID := "02020202-0202-0202-0202-020202020001"
+
+	deadLetter := func() *api.DeadLetter {
+		return &api.DeadLetter{
+			Time:              1672574400,
+			ProvisioningState: api.ProvisioningStateCreating,
+			Dequeues:          6,
+			LastStep:          "[Action github.com/Azure/ARO-RP/pkg/cluster.(*manager).ensureResourceGroup-fm]",
+			LastError:         "lost lease",
+			DequeueHistory: []*api.DequeueRecord{
+				{
+					LeaseOwner: "11111111-1111-1111-1111-111111111111",
+					StartTime:  1672574000,
+					EndTime:    1672574100,
+					Step:       "[Action github.com/Azure/ARO-RP/pkg/cluster.(*manager).ensureResourceGroup-fm]",
+					Error:      "lost lease",
+				},
+				{
+					LeaseOwner: "22222222-2222-2222-2222-222222222222",
+					StartTime:  1672574400,
+				},
+			},
+		}
+	}
+
+	// clusterDocument returns a cluster document as the backend leaves it
+	// when it moves the document to dead letter: the operation has failed but
+	// the dequeue count is kept
+	clusterDocument := func(resourceID string, deadLetter *api.DeadLetter) *api.OpenShiftClusterDocument {
+		doc := &api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState:       api.ProvisioningStateFailed,
+					FailedProvisioningState: api.ProvisioningStateCreating,
+				},
+			},
+			DeadLetter: deadLetter,
+		}
+		if deadLetter != nil {
+			doc.Dequeues = deadLetter.Dequeues
+		}
+		return doc
+	}
+
+	summary := deadLetter()
+	summary.DequeueHistory = nil
+
+	type test struct {
+		name           string
+		method         string
+		path           string
+		fixture        func(*testdatabase.Fixture)
+		checker        func(*testdatabase.Checker)
+		wantStatusCode int
+		wantResponse   interface{}
+		wantError      string
+	}
+
+	for _, tt := range []*test{
+		{
+			name:   "list dead letters",
+			method: http.MethodGet,
+			path:   "/admin/deadletters",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDocument(resourceID, deadLetter()),
+					clusterDocument(otherResourceID, nil),
+				)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &[]*deadLetterResponse{
+				{
+					ResourceID: key,
+					DeadLetter: summary,
+				},
+			},
+		},
+		{
+			name:           "list no dead letters",
+			method:         http.MethodGet,
+			path:           "/admin/deadletters",
+			wantStatusCode: http.StatusOK,
+			wantResponse:   &[]*deadLetterResponse{},
+		},
+		{
+			name:   "get dead letter",
+			method: http.MethodGet,
+			path:   "/admin" + resourceID + "/deadletter",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument(resourceID, deadLetter()))
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &deadLetterResponse{
+				ResourceID: key,
+				DeadLetter: deadLetter(),
+			},
+		},
+		{
+			name:   "get dead letter of cluster which is not dead lettered",
+			method: http.MethodGet,
+			path:   "/admin" + resourceID + "/deadletter",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument(resourceID, nil))
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' is not dead lettered.",
+		},
+		{
+			name:           "get dead letter of cluster which does not exist",
+			method:         http.MethodGet,
+			path:           "/admin" + resourceID + "/deadletter",
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+		{
+			name:   "requeue restores the dead lettered provisioning state",
+			method: http.MethodPost,
+			path:   "/admin" + resourceID + "/deadletter/requeue",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument(resourceID, deadLetter()))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: key,
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID: resourceID,
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState:     api.ProvisioningStateCreating,
+							LastProvisioningState: api.ProvisioningStateFailed,
+						},
+					},
+					AsyncOperationID: asyncOperationID,
+				})
+				c.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+					ID:                  asyncOperationID,
+					OpenShiftClusterKey: key,
+					AsyncOperation: &api.AsyncOperation{
+						ID:                       "/admin" + resourceID + "/operationsstatus/" + asyncOperationID,
+						Name:                     asyncOperationID,
+						InitialProvisioningState: api.ProvisioningStateCreating,
+						ProvisioningState:        api.ProvisioningStateCreating,
+						StartTime:                now.UTC(),
+					},
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &deadLetterResponse{
+				ResourceID: key,
+				DeadLetter: deadLetter(),
+			},
+		},
+		{
+			name:   "requeue dead lettered admin update",
+			method: http.MethodPost,
+			path:   "/admin" + resourceID + "/deadletter/requeue",
+			fixture: func(f *testdatabase.Fixture) {
+				dl := deadLetter()
+				dl.ProvisioningState = api.ProvisioningStateAdminUpdating
+				dl.MaintenanceTask = api.MaintenanceTaskEverything
+
+				doc := clusterDocument(resourceID, dl)
+				doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateSucceeded
+				doc.OpenShiftCluster.Properties.FailedProvisioningState = ""
+				f.AddOpenShiftClusterDocuments(doc)
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: key,
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID: resourceID,
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState:     api.ProvisioningStateAdminUpdating,
+							LastProvisioningState: api.ProvisioningStateSucceeded,
+							MaintenanceTask:       api.MaintenanceTaskEverything,
+						},
+					},
+					AsyncOperationID: asyncOperationID,
+				})
+				c.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+					ID:                  asyncOperationID,
+					OpenShiftClusterKey: key,
+					AsyncOperation: &api.AsyncOperation{
+						ID:                       "/admin" + resourceID + "/operationsstatus/" + asyncOperationID,
+						Name:                     asyncOperationID,
+						InitialProvisioningState: api.ProvisioningStateAdminUpdating,
+						ProvisioningState:        api.ProvisioningStateAdminUpdating,
+						StartTime:                now.UTC(),
+					},
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: func() *deadLetterResponse {
+				dl := deadLetter()
+				dl.ProvisioningState = api.ProvisioningStateAdminUpdating
+				dl.MaintenanceTask = api.MaintenanceTaskEverything
+				return &deadLetterResponse{
+					ResourceID: key,
+					DeadLetter: dl,
+				}
+			}(),
+		},
+		{
+			name:   "requeue cluster whose operation is not terminal",
+			method: http.MethodPost,
+			path:   "/admin" + resourceID + "/deadletter/requeue",
+			fixture: func(f *testdatabase.Fixture) {
+				doc := clusterDocument(resourceID, deadLetter())
+				doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateUpdating
+				doc.OpenShiftCluster.Properties.FailedProvisioningState = ""
+				f.AddOpenShiftClusterDocuments(doc)
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := clusterDocument(resourceID, deadLetter())
+				doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateUpdating
+				doc.OpenShiftCluster.Properties.FailedProvisioningState = ""
+				c.AddOpenShiftClusterDocuments(doc)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: RequestNotAllowed: : Request is not allowed in provisioningState 'Updating'.",
+		},
+		{
+			name:   "requeue cluster which is not dead lettered",
+			method: http.MethodPost,
+			path:   "/admin" + resourceID + "/deadletter/requeue",
+			fixture: func(f *testdatabase.Fixture) {
+				doc := clusterDocument(resourceID, nil)
+				doc.Dequeues = 2
+				f.AddOpenShiftClusterDocuments(doc)
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := clusterDocument(resourceID, nil)
+				doc.Dequeues = 2
+				c.AddOpenShiftClusterDocuments(doc)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' is not dead lettered.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithAsyncOperations().WithSubscriptions()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, func(*logrus.Entry, env.Interface, *api.OpenShiftCluster, *api.SubscriptionDocument) (adminactions.AzureActions, error) {
+				return nil, nil
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f.now = func() time.Time { return now }
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(tt.method, "https://server"+tt.path, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if tt.checker != nil {
+				tt.checker(ti.checker)
+				for _, err := range ti.checker.CheckOpenShiftClusters(ti.openShiftClustersClient) {
+					t.Error(err)
+				}
+				for _, err := range ti.checker.CheckAsyncOperations(ti.asyncOperationsClient) {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
@@ -536,6 +536,9 @@ func TestAdminEtcdCertificateRenew(t *testing.T) {
 				ti.openShiftClustersDatabase,
 				ti.subscriptionsDatabase,
 				nil,
+				nil,
+				nil,
+				nil,
 				api.APIs,
 				&noop.Noop{},
 				&noop.Noop{},
@@ -753,6 +756,9 @@ func TestAdminEtcdCertificateRecovery(t *testing.T) {
 				ti.openShiftClustersDatabase,
 				ti.subscriptionsDatabase,
 				nil,
+				nil,
+				nil,
+				nil,
 				api.APIs,
 				&noop.Noop{},
 				&noop.Noop{},
@@ -167,6 +167,9 @@ func TestAdminEtcdRecovery(t *testing.T) {
 				ti.openShiftClustersDatabase,
 				ti.subscriptionsDatabase,
 				nil,
+				nil,
+				nil,
+				nil,
 				api.APIs,
 				&noop.Noop{},
 				&noop.Noop{},
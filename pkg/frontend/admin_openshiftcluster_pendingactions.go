@@ -0,0 +1,408 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
+)
+
+const (
+	// pendingActionApprovalWindow is how long a held action may be approved
+	// for after it was requested
+	pendingActionApprovalWindow = 4 * time.Hour
+
+	operationPendingActionRequest = "PendingActionRequest"
+	operationPendingActionReject  = "PendingActionReject"
+	operationPendingActionRun     = "PendingActionRun"
+)
+
+// approvalFeatures maps the destructive admin actions which can be held for
+// approval to the feature which enables the hold
+var approvalFeatures = map[string]env.Feature{
+	"deletemanagedresource": env.FeatureRequireApprovalDeleteManagedResource,
+	"etcdrecovery":          env.FeatureRequireApprovalEtcdRecovery,
+	"kubernetesobjects":     env.FeatureRequireApprovalKubernetesObjectsDelete,
+	"redeployvm":            env.FeatureRequireApprovalRedeployVM,
+	"stopvm":                env.FeatureRequireApprovalStopVM,
+}
+
+// pendingActionResponse is the admin API representation of a pending action
+type pendingActionResponse struct {
+	ID         string `json:"id"`
+	ResourceID string `json:"resourceId"`
+
+	*api.PendingAction
+}
+
+// statusRecordingResponseWriter records the status code written by a replayed
+// admin action
+type statusRecordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *statusRecordingResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *statusRecordingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// pendingActionHandler returns the handler which runs the named admin action
+// once it has been approved
+func (f *frontend) pendingActionHandler(action string) http.Handler {
+	var h http.HandlerFunc
+
+	switch action {
+	case "deletemanagedresource":
+		h = f.postAdminOpenShiftDeleteManagedResource
+	case "etcdrecovery":
+		h = f.postAdminOpenShiftClusterEtcdRecovery
+	case "kubernetesobjects":
+		h = f.deleteAdminKubernetesObjects
+	case "redeployvm":
+		h = f.postAdminOpenShiftClusterRedeployVM
+	case "stopvm":
+		h = f.postAdminOpenShiftClusterStopVM
+	default:
+		return nil
+	}
+
+	return f.maintenanceMiddleware.UnplannedMaintenanceSignal(h)
+}
+
+// requireApproval holds the named admin action for approval by a second SRE
+// in the SRE portal if its approval feature is set, and otherwise runs it
+// immediately
+func (f *frontend) requireApproval(action string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !f.env.FeatureIsSet(approvalFeatures[action]) {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+			b, err := f._holdAdminAction(ctx, r, action)
+			if err == nil {
+				w.WriteHeader(http.StatusAccepted)
+			}
+
+			adminReply(log, w, nil, b, err)
+		})
+	}
+}
+
+func (f *frontend) _holdAdminAction(ctx context.Context, r *http.Request, action string) ([]byte, error) {
+	resourceID := strings.TrimPrefix(filepath.Dir(r.URL.Path), "/admin")
+
+	requestedBy, err := pendingActionCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "",
+			"The Resource '%s/%s' under resource group '%s' was not found.",
+			chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName"))
+	case err != nil:
+		return nil, err
+	}
+
+	body, _ := ctx.Value(middleware.ContextKeyBody).([]byte)
+	if len(body) == 0 {
+		body = nil
+	}
+
+	now := f.now().UTC()
+	expiresAt := now.Add(pendingActionApprovalWindow)
+
+	doc, err := f.dbPendingActions.Create(ctx, &api.PendingActionDocument{
+		ID:  f.dbPendingActions.NewUUID(),
+		Key: strings.ToLower(resourceID),
+		PendingAction: &api.PendingAction{
+			Action:      action,
+			Method:      r.Method,
+			Path:        r.URL.Path,
+			RawQuery:    r.URL.RawQuery,
+			Body:        body,
+			State:       api.PendingActionStatePending,
+			RequestedBy: requestedBy,
+			RequestedAt: &now,
+			ExpiresAt:   &expiresAt,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	f.auditPendingAction(r, operationPendingActionRequest, requestedBy, doc, audit.ResultTypeSuccess, "held "+action+" for approval")
+
+	return json.MarshalIndent(pendingActionView(doc), "", "    ")
+}
+
+func (f *frontend) getAdminPendingActions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	b, err := f._getAdminPendingActions(ctx, r)
+
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminPendingActions(ctx context.Context, r *http.Request) ([]byte, error) {
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	docs, err := f.dbPendingActions.ListByKey(ctx, strings.ToLower(resourceID))
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]*pendingActionResponse, 0, len(docs.PendingActionDocuments))
+	for _, doc := range docs.PendingActionDocuments {
+		actions = append(actions, pendingActionView(doc))
+	}
+
+	return json.MarshalIndent(actions, "", "    ")
+}
+
+func (f *frontend) getAdminPendingAction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(filepath.Dir(r.URL.Path))
+
+	b, err := f._getAdminPendingAction(ctx, r)
+
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminPendingAction(ctx context.Context, r *http.Request) ([]byte, error) {
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	doc, err := f.dbPendingActions.Get(ctx, strings.ToLower(resourceID), chi.URLParam(r, "pendingActionId"))
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		return nil, pendingActionNotFound(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(pendingActionView(doc), "", "    ")
+}
+
+// postAdminPendingActionRun runs a held admin action which a second SRE has
+// approved in the SRE portal.  The action is moved to the Running state before
+// it is replayed, so that it runs at most once, and to Succeeded or Failed once
+// it has run.  The response is the response of the action itself.
+func (f *frontend) postAdminPendingActionRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(filepath.Dir(filepath.Dir(r.URL.Path)))
+
+	caller, err := pendingActionCaller(ctx)
+	if err != nil {
+		adminReply(log, w, nil, nil, err)
+		return
+	}
+
+	var h http.Handler
+	doc, err := f.patchPendingAction(ctx, r, api.PendingActionStateApproved, func(doc *api.PendingActionDocument) error {
+		h = f.pendingActionHandler(doc.PendingAction.Action)
+		if h == nil {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The action '%s' cannot be run.", doc.PendingAction.Action)
+		}
+
+		doc.PendingAction.State = api.PendingActionStateRunning
+		return nil
+	})
+	if err != nil {
+		adminReply(log, w, nil, nil, err)
+		return
+	}
+
+	// replay the held request as it was made
+	replay := r.Clone(context.WithValue(ctx, middleware.ContextKeyBody, doc.PendingAction.Body))
+	replay.Method = doc.PendingAction.Method
+	replay.URL.Path = doc.PendingAction.Path
+	replay.URL.RawQuery = doc.PendingAction.RawQuery
+	replay.Body = io.NopCloser(bytes.NewReader(doc.PendingAction.Body))
+
+	rw := &statusRecordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	h.ServeHTTP(rw, replay)
+
+	state, resultType := api.PendingActionStateSucceeded, audit.ResultTypeSuccess
+	if rw.statusCode >= http.StatusBadRequest {
+		state, resultType = api.PendingActionStateFailed, audit.ResultTypeFail
+	}
+
+	// the action has run and its response has been written: if the outcome
+	// cannot be recorded, the action is left Running for an SRE to check
+	doc, err = f.dbPendingActions.Patch(ctx, doc.Key, doc.ID, func(doc *api.PendingActionDocument) error {
+		doc.PendingAction.State = state
+		doc.PendingAction.StatusCode = rw.statusCode
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	f.auditPendingAction(r, operationPendingActionRun, caller, doc, resultType, "ran "+doc.PendingAction.Action+" requested by "+doc.PendingAction.RequestedBy+" and approved by "+doc.PendingAction.Reviewer+": "+string(state))
+}
+
+// postAdminPendingActionReject rejects a held admin action.  Any identity,
+// including the requester, may reject it.
+func (f *frontend) postAdminPendingActionReject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(filepath.Dir(filepath.Dir(r.URL.Path)))
+
+	reviewer, err := pendingActionCaller(ctx)
+	if err != nil {
+		adminReply(log, w, nil, nil, err)
+		return
+	}
+
+	doc, err := f.patchPendingAction(ctx, r, api.PendingActionStatePending, func(doc *api.PendingActionDocument) error {
+		now := f.now().UTC()
+
+		doc.PendingAction.State = api.PendingActionStateRejected
+		doc.PendingAction.Reviewer = reviewer
+		doc.PendingAction.ReviewedAt = &now
+		return nil
+	})
+	if err != nil {
+		adminReply(log, w, nil, nil, err)
+		return
+	}
+
+	f.auditPendingAction(r, operationPendingActionReject, reviewer, doc, audit.ResultTypeSuccess, "rejected "+doc.PendingAction.Action+" requested by "+doc.PendingAction.RequestedBy)
+
+	b, err := json.MarshalIndent(pendingActionView(doc), "", "    ")
+	adminReply(log, w, nil, b, err)
+}
+
+// patchPendingAction applies mutate to a pending action which is in state
+// from and has not expired
+func (f *frontend) patchPendingAction(ctx context.Context, r *http.Request, from api.PendingActionState, mutate func(*api.PendingActionDocument) error) (*api.PendingActionDocument, error) {
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	var patchErr error
+	doc, err := f.dbPendingActions.Patch(ctx, strings.ToLower(resourceID), chi.URLParam(r, "pendingActionId"), func(doc *api.PendingActionDocument) error {
+		switch {
+		case doc.PendingAction.State != from:
+			patchErr = api.NewCloudError(http.StatusConflict, api.CloudErrorCodeRequestNotAllowed, "",
+				"The pending action is in state '%s'.", doc.PendingAction.State)
+		case doc.PendingAction.ExpiresAt != nil && !f.now().Before(*doc.PendingAction.ExpiresAt):
+			patchErr = api.NewCloudError(http.StatusConflict, api.CloudErrorCodeRequestNotAllowed, "",
+				"The pending action expired at %s.", doc.PendingAction.ExpiresAt.Format(time.RFC3339))
+		default:
+			patchErr = mutate(doc)
+		}
+
+		return patchErr
+	})
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, pendingActionNotFound(r)
+	case patchErr != nil:
+		return nil, patchErr
+	case err != nil:
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// pendingActionCaller returns the identity of the caller, which is recorded in
+// the pending action and the audit log.  It is taken from the
+// X-Ms-Client-Principal-Name header, which is set by the admin API caller and
+// is not verified by the RP.  For this reason approval is only possible
+// through the SRE portal, which uses the identity verified by AAD.
+func pendingActionCaller(ctx context.Context) (string, error) {
+	correlationData, _ := ctx.Value(middleware.ContextKeyCorrelationData).(*api.CorrelationData)
+	if correlationData == nil || correlationData.ClientPrincipalName == "" {
+		return "", api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "",
+			"The caller identity is required for actions which need approval.")
+	}
+
+	return correlationData.ClientPrincipalName, nil
+}
+
+func pendingActionNotFound(r *http.Request) error {
+	return api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "",
+		"The pending action '%s' was not found.", chi.URLParam(r, "pendingActionId"))
+}
+
+func pendingActionView(doc *api.PendingActionDocument) *pendingActionResponse {
+	return &pendingActionResponse{
+		ID:            doc.ID,
+		ResourceID:    strings.TrimPrefix(filepath.Dir(doc.PendingAction.Path), "/admin"),
+		PendingAction: doc.PendingAction,
+	}
+}
+
+func (f *frontend) auditPendingAction(r *http.Request, operation, caller string, doc *api.PendingActionDocument, resultType, description string) {
+	var requestID string
+	if correlationData, ok := r.Context().Value(middleware.ContextKeyCorrelationData).(*api.CorrelationData); ok {
+		requestID = correlationData.RequestID
+	}
+
+	f.auditLog.WithFields(logrus.Fields{
+		audit.MetadataAdminOperation:  true,
+		audit.MetadataCreatedTime:     f.now().UTC().Format(time.RFC3339),
+		audit.MetadataLogKind:         audit.IFXAuditLogKind,
+		audit.MetadataSource:          audit.SourceRP,
+		audit.EnvKeyAppID:             audit.SourceRP,
+		audit.EnvKeyCloudRole:         audit.CloudRoleRP,
+		audit.EnvKeyEnvironment:       f.env.Environment().Name,
+		audit.EnvKeyHostname:          f.env.Hostname(),
+		audit.EnvKeyLocation:          f.env.Location(),
+		audit.PayloadKeyCategory:      audit.CategoryResourceManagement,
+		audit.PayloadKeyOperationName: operation,
+		audit.PayloadKeyRequestID:     requestID,
+		audit.PayloadKeyCallerIdentities: []audit.CallerIdentity{
+			{
+				CallerIdentityType:  audit.CallerIdentityTypeObjectID,
+				CallerIdentityValue: caller,
+				CallerIPAddress:     r.RemoteAddr,
+			},
+		},
+		audit.PayloadKeyTargetResources: []audit.TargetResource{
+			{
+				TargetResourceName: doc.Key + "/pendingactions/" + doc.ID,
+				TargetResourceType: "pendingaction",
+			},
+		},
+		audit.PayloadKeyResult: audit.Result{
+			ResultType:        resultType,
+			ResultDescription: description,
+		},
+	}).Info(audit.DefaultLogMessage)
+}
@@ -0,0 +1,396 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
+	mock_adminactions "github.com/Azure/ARO-RP/pkg/util/mocks/adminactions"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestAdminPendingActions(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	mockTenantID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	key := strings.ToLower(resourceID)
+	vmName := "aro-worker-australiasoutheast-7tcq7"
+
+	ctx := context.Background()
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(pendingActionApprovalWindow)
+	expired := now.Add(-time.Minute)
+
+	clusterFixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			Key: key,
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+				Properties: api.OpenShiftClusterProperties{
+					ClusterProfile: api.ClusterProfile{
+						ResourceGroupID: fmt.Sprintf("/subscriptions/%s/resourceGroups/test-cluster", mockSubID),
+					},
+				},
+			},
+		})
+
+		f.AddSubscriptionDocuments(&api.SubscriptionDocument{
+			ID: mockSubID,
+			Subscription: &api.Subscription{
+				State: api.SubscriptionStateRegistered,
+				Properties: &api.SubscriptionProperties{
+					TenantID: mockTenantID,
+				},
+			},
+		})
+	}
+
+	pendingStopVM := func() *api.PendingActionDocument {
+		return &api.PendingActionDocument{
+			ID:  "07070707-0707-0707-0707-070707070001",
+			Key: key,
+			PendingAction: &api.PendingAction{
+				Action:      "stopvm",
+				Method:      http.MethodPost,
+				Path:        "/admin" + key + "/stopvm",
+				RawQuery:    "vmName=" + vmName + "&deallocateVM=false",
+				State:       api.PendingActionStatePending,
+				RequestedBy: "alice",
+				RequestedAt: &now,
+				ExpiresAt:   &expiresAt,
+			},
+		}
+	}
+
+	approvedStopVM := func() *api.PendingActionDocument {
+		doc := pendingStopVM()
+		doc.PendingAction.State = api.PendingActionStateApproved
+		doc.PendingAction.Reviewer = "bob"
+		doc.PendingAction.ReviewedAt = &now
+		return doc
+	}
+
+	type test struct {
+		name           string
+		method         string
+		path           string
+		caller         string
+		fixture        func(*testdatabase.Fixture)
+		checker        func(*testdatabase.Checker)
+		mocks          func(*mock_adminactions.MockAzureActions)
+		wantStatusCode int
+		wantResponse   interface{}
+		wantError      string
+		wantAudit      []string
+	}
+
+	for _, tt := range []*test{
+		{
+			name:   "stopvm is held for approval",
+			method: http.MethodPost,
+			path:   "/stopvm?vmName=" + vmName + "&deallocateVM=false",
+			caller: "alice",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPendingActionDocuments(pendingStopVM())
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantResponse: &pendingActionResponse{
+				ID:            "07070707-0707-0707-0707-070707070001",
+				ResourceID:    key,
+				PendingAction: pendingStopVM().PendingAction,
+			},
+			wantAudit: []string{operationPendingActionRequest},
+		},
+		{
+			name:   "stopvm without a caller identity",
+			method: http.MethodPost,
+			path:   "/stopvm?vmName=" + vmName + "&deallocateVM=false",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantError:      "403: Forbidden: : The caller identity is required for actions which need approval.",
+		},
+		{
+			name:           "stopvm on a cluster which does not exist",
+			method:         http.MethodPost,
+			path:           "/stopvm?vmName=" + vmName + "&deallocateVM=false",
+			caller:         "alice",
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+		{
+			name:   "run an approved action",
+			method: http.MethodPost,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070001/run",
+			caller: "alice",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				f.AddPendingActionDocuments(approvedStopVM())
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := approvedStopVM()
+				doc.PendingAction.State = api.PendingActionStateSucceeded
+				doc.PendingAction.StatusCode = http.StatusOK
+				c.AddPendingActionDocuments(doc)
+			},
+			mocks: func(a *mock_adminactions.MockAzureActions) {
+				a.EXPECT().VMStopAndWait(gomock.Any(), vmName, false).Return(nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantAudit:      []string{operationPendingActionRun},
+		},
+		{
+			name:   "run records a failed action",
+			method: http.MethodPost,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070001/run",
+			caller: "alice",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				f.AddPendingActionDocuments(approvedStopVM())
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := approvedStopVM()
+				doc.PendingAction.State = api.PendingActionStateFailed
+				doc.PendingAction.StatusCode = http.StatusInternalServerError
+				c.AddPendingActionDocuments(doc)
+			},
+			mocks: func(a *mock_adminactions.MockAzureActions) {
+				a.EXPECT().VMStopAndWait(gomock.Any(), vmName, false).Return(fmt.Errorf("it broke"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantError:      "500: InternalServerError: : Internal server error.",
+			wantAudit:      []string{operationPendingActionRun},
+		},
+		{
+			name:   "run an action which has not been approved",
+			method: http.MethodPost,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070001/run",
+			caller: "alice",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				f.AddPendingActionDocuments(pendingStopVM())
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPendingActionDocuments(pendingStopVM())
+			},
+			wantStatusCode: http.StatusConflict,
+			wantError:      "409: RequestNotAllowed: : The pending action is in state 'Pending'.",
+		},
+		{
+			name:   "run an action which is already running",
+			method: http.MethodPost,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070001/run",
+			caller: "alice",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				doc := approvedStopVM()
+				doc.PendingAction.State = api.PendingActionStateRunning
+				f.AddPendingActionDocuments(doc)
+			},
+			wantStatusCode: http.StatusConflict,
+			wantError:      "409: RequestNotAllowed: : The pending action is in state 'Running'.",
+		},
+		{
+			name:   "run after expiry",
+			method: http.MethodPost,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070001/run",
+			caller: "alice",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				doc := approvedStopVM()
+				doc.PendingAction.ExpiresAt = &expired
+				f.AddPendingActionDocuments(doc)
+			},
+			wantStatusCode: http.StatusConflict,
+			wantError:      "409: RequestNotAllowed: : The pending action expired at 2023-01-01T11:59:00Z.",
+		},
+		{
+			name:   "run without a caller identity",
+			method: http.MethodPost,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070001/run",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				f.AddPendingActionDocuments(approvedStopVM())
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPendingActionDocuments(approvedStopVM())
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantError:      "403: Forbidden: : The caller identity is required for actions which need approval.",
+		},
+		{
+			name:   "run, not found",
+			method: http.MethodPost,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070002/run",
+			caller: "alice",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				f.AddPendingActionDocuments(approvedStopVM())
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The pending action '07070707-0707-0707-0707-070707070002' was not found.",
+		},
+		{
+			name:   "reject an approved action",
+			method: http.MethodPost,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070001/reject",
+			caller: "alice",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				f.AddPendingActionDocuments(approvedStopVM())
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPendingActionDocuments(approvedStopVM())
+			},
+			wantStatusCode: http.StatusConflict,
+			wantError:      "409: RequestNotAllowed: : The pending action is in state 'Approved'.",
+		},
+		{
+			name:   "reject by the requester",
+			method: http.MethodPost,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070001/reject",
+			caller: "alice",
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				f.AddPendingActionDocuments(pendingStopVM())
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := pendingStopVM()
+				doc.PendingAction.State = api.PendingActionStateRejected
+				doc.PendingAction.Reviewer = "alice"
+				doc.PendingAction.ReviewedAt = &now
+				c.AddPendingActionDocuments(doc)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &pendingActionResponse{
+				ID:         "07070707-0707-0707-0707-070707070001",
+				ResourceID: key,
+				PendingAction: func() *api.PendingAction {
+					a := pendingStopVM().PendingAction
+					a.State = api.PendingActionStateRejected
+					a.Reviewer = "alice"
+					a.ReviewedAt = &now
+					return a
+				}(),
+			},
+			wantAudit: []string{operationPendingActionReject},
+		},
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/pendingactions",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPendingActionDocuments(pendingStopVM())
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &[]*pendingActionResponse{
+				{
+					ID:            "07070707-0707-0707-0707-070707070001",
+					ResourceID:    key,
+					PendingAction: pendingStopVM().PendingAction,
+				},
+			},
+		},
+		{
+			name:   "get",
+			method: http.MethodGet,
+			path:   "/pendingactions/07070707-0707-0707-0707-070707070001",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPendingActionDocuments(pendingStopVM())
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &pendingActionResponse{
+				ID:            "07070707-0707-0707-0707-070707070001",
+				ResourceID:    key,
+				PendingAction: pendingStopVM().PendingAction,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			features := defaultTestFeatures()
+			features[env.FeatureRequireApprovalStopVM] = true
+
+			ti := newTestInfraWithFeatures(t, features).WithOpenShiftClusters().WithSubscriptions().WithPendingActions()
+			defer ti.done()
+
+			a := mock_adminactions.NewMockAzureActions(ti.controller)
+			if tt.mocks != nil {
+				tt.mocks(a)
+			}
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			auditHook, auditEntry := testlog.NewAudit()
+
+			f, err := NewFrontend(ctx, auditEntry, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, ti.pendingActionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, func(*logrus.Entry, env.Interface, *api.OpenShiftCluster, *api.SubscriptionDocument) (adminactions.AzureActions, error) {
+				return a, nil
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f.now = func() time.Time { return now }
+
+			go f.Run(ctx, nil, nil)
+
+			header := http.Header{}
+			if tt.caller != "" {
+				header.Set("X-Ms-Client-Principal-Name", tt.caller)
+			}
+
+			resp, b, err := ti.request(tt.method, "https://server/admin"+resourceID+tt.path, header, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if tt.checker != nil {
+				tt.checker(ti.checker)
+				for _, err := range ti.checker.CheckPendingActions(ti.pendingActionsClient) {
+					t.Error(err)
+				}
+			}
+
+			var operations []string
+			for _, entry := range auditHook.AllEntries() {
+				payload := fmt.Sprint(entry.Data[audit.MetadataPayload])
+				for _, operation := range []string{operationPendingActionRequest, operationPendingActionReject, operationPendingActionRun} {
+					if strings.Contains(payload, `"OperationName":"`+operation+`"`) {
+						operations = append(operations, operation)
+					}
+				}
+			}
+
+			if strings.Join(operations, ",") != strings.Join(tt.wantAudit, ",") {
+				t.Error(operations)
+			}
+		})
+	}
+}
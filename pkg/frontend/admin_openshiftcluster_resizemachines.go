@@ -0,0 +1,278 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/stringutils"
+)
+
+const (
+	// masterMachinePool is passed as the machineSet to resize the masters,
+	// which are not managed by a MachineSet
+	masterMachinePool = "master"
+
+	machineAPINamespace = "openshift-machine-api"
+	machineGroupKind    = "Machine.machine.openshift.io"
+	machineSetGroupKind = "MachineSet.machine.openshift.io"
+)
+
+// postAdminOpenShiftClusterResizeMachines resizes every machine of a
+// MachineSet, or the masters, one at a time.  The resize is worked by the
+// backend as the ResizeMachines maintenance task; its progress is reported in
+// the returned async operation.  Requesting the same resize again after it
+// failed resumes it, skipping the machines already resized.
+func (f *frontend) postAdminOpenShiftClusterResizeMachines(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	header := http.Header{}
+	b, err := f._postAdminOpenShiftClusterResizeMachines(log, ctx, r, header)
+
+	adminReply(log, w, header, b, err)
+}
+
+func (f *frontend) _postAdminOpenShiftClusterResizeMachines(log *logrus.Entry, ctx context.Context, r *http.Request, header http.Header) ([]byte, error) {
+	resType, resName, resGroupName := chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName")
+	machineSet := r.URL.Query().Get("machineSet")
+	vmSize := r.URL.Query().Get("vmSize")
+
+	err := validateAdminMachineSetName(machineSet)
+	if err != nil {
+		return nil, err
+	}
+
+	isMaster := strings.EqualFold(machineSet, masterMachinePool)
+	if isMaster {
+		err = validateAdminMasterVMSize(vmSize)
+	} else {
+		err = validateAdminWorkerVMSize(vmSize, f.env.FeatureIsSet(env.FeatureRequireD2sV3Workers))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "", "The Resource '%s/%s' under resource group '%s' was not found.", resType, resName, resGroupName)
+	case err != nil:
+		return nil, err
+	}
+
+	// fail early rather than after talking to the cluster; the state is
+	// checked again when the document is updated
+	err = validateResizeProvisioningState(doc.OpenShiftCluster.Properties.ProvisioningState)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := f.kubeActionsFactory(log, f.env, doc.OpenShiftCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	machines, err := resizeTargets(ctx, k, machineSet, isMaster)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(machines) == 0 {
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The machineSet '%s' has no machines to resize.", machineSet)
+	}
+
+	var asyncdoc *api.AsyncOperationDocument
+	doc, err = f.dbOpenShiftClusters.Patch(ctx, doc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		err := validateResizeProvisioningState(doc.OpenShiftCluster.Properties.ProvisioningState)
+		if err != nil {
+			return err
+		}
+
+		resize := &api.MachineResize{
+			MachineSet: machineSet,
+			VMSize:     api.VMSize(vmSize),
+			Machines:   machines,
+		}
+
+		// resume a previous resize to the same size, skipping the machines
+		// it completed which are still to be resized
+		if previous := doc.OpenShiftCluster.Properties.MachineResize; previous != nil &&
+			strings.EqualFold(previous.MachineSet, machineSet) &&
+			strings.EqualFold(string(previous.VMSize), vmSize) {
+			for _, machine := range previous.Completed {
+				if stringutils.Contains(machines, machine) {
+					resize.Completed = append(resize.Completed, machine)
+				}
+			}
+		}
+
+		id := f.dbAsyncOperations.NewUUID()
+		asyncdoc, err = f.dbAsyncOperations.Create(ctx, &api.AsyncOperationDocument{
+			ID:                  id,
+			OpenShiftClusterKey: doc.Key,
+			AsyncOperation: &api.AsyncOperation{
+				ID:                       r.URL.Path + "/operationsstatus/" + id,
+				Name:                     id,
+				InitialProvisioningState: api.ProvisioningStateAdminUpdating,
+				ProvisioningState:        api.ProvisioningStateAdminUpdating,
+				StartTime:                f.now().UTC(),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		doc.OpenShiftCluster.Properties.MaintenanceTask = api.MaintenanceTaskResizeMachines
+		doc.OpenShiftCluster.Properties.MachineResize = resize
+		doc.AsyncOperationID = id
+		adminUpdateProvisioningState(doc)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	header["Azure-AsyncOperation"] = []string{asyncdoc.AsyncOperation.ID}
+
+	asyncdoc.AsyncOperation.Progress = machineResizeProgress(doc.OpenShiftCluster.Properties.MachineResize)
+
+	b, err := json.MarshalIndent(asyncdoc.AsyncOperation, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	return b, statusCodeError(http.StatusAccepted)
+}
+
+// validateResizeProvisioningState only allows a resize to start from a
+// healthy cluster on which no other operation, including another resize, is
+// in flight
+func validateResizeProvisioningState(state api.ProvisioningState) error {
+	if state == api.ProvisioningStateSucceeded {
+		return nil
+	}
+
+	return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeRequestNotAllowed, "", "Request is not allowed in provisioningState '%s'.", state)
+}
+
+// resizeTargets returns the names of the machines to resize, in order
+func resizeTargets(ctx context.Context, k adminactions.KubeActions, machineSet string, isMaster bool) ([]string, error) {
+	if !isMaster {
+		// fail early if the MachineSet does not exist
+		_, err := k.KubeGet(ctx, machineSetGroupKind, machineAPINamespace, machineSet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b, err := k.KubeList(ctx, machineGroupKind, machineAPINamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	machines := &machinev1beta1.MachineList{}
+	err = json.Unmarshal(b, machines)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, machine := range machines.Items {
+		if isMaster && machine.Labels["machine.openshift.io/cluster-api-machine-role"] == "master" ||
+			!isMaster && machine.Labels["machine.openshift.io/cluster-api-machineset"] == machineSet {
+			names = append(names, machine.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// machineResizeProgress reports the progress recorded by the backend in the
+// MachineResize of a cluster
+func machineResizeProgress(resize *api.MachineResize) *api.AsyncOperationProgress {
+	if resize == nil {
+		return nil
+	}
+
+	progress := &api.AsyncOperationProgress{
+		Completed: resize.Completed,
+		Current:   resize.Current,
+		Step:      resize.Step,
+	}
+
+	for _, machine := range resize.Machines {
+		if machine != resize.Current && !stringutils.Contains(resize.Completed, machine) {
+			progress.Pending = append(progress.Pending, machine)
+		}
+	}
+
+	return progress
+}
+
+func (f *frontend) getAdminOpenShiftClusterOperationsStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._getAdminOpenShiftClusterOperationsStatus(ctx, r)
+
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminOpenShiftClusterOperationsStatus(ctx context.Context, r *http.Request) ([]byte, error) {
+	operationID := chi.URLParam(r, "operationId")
+	resourceID := strings.TrimPrefix(filepath.Dir(filepath.Dir(r.URL.Path)), "/admin")
+
+	asyncdoc, err := f.dbAsyncOperations.Get(ctx, operationID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "The entity was not found.")
+	case err != nil:
+		return nil, err
+	}
+
+	if !strings.EqualFold(asyncdoc.OpenShiftClusterKey, resourceID) {
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "The entity was not found.")
+	}
+
+	// the resize is recorded in the cluster while it is in flight, and in the
+	// snapshot taken of the cluster once the operation has ended
+	if asyncdoc.OpenShiftCluster != nil {
+		asyncdoc.AsyncOperation.Progress = machineResizeProgress(asyncdoc.OpenShiftCluster.Properties.MachineResize)
+	} else {
+		doc, err := f.dbOpenShiftClusters.Get(ctx, asyncdoc.OpenShiftClusterKey)
+		switch {
+		case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		case err != nil:
+			return nil, err
+		case doc.AsyncOperationID == operationID:
+			asyncdoc.AsyncOperation.Progress = machineResizeProgress(doc.OpenShiftCluster.Properties.MachineResize)
+		}
+	}
+
+	asyncdoc.AsyncOperation.MissingFields = api.MissingFields{}
+	asyncdoc.AsyncOperation.InitialProvisioningState = ""
+
+	return json.MarshalIndent(asyncdoc.AsyncOperation, "", "    ")
+}
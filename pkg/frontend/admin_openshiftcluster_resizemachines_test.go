@@ -0,0 +1,429 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	mock_adminactions "github.com/Azure/ARO-RP/pkg/util/mocks/adminactions"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminResizeMachines(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	ctx := context.Background()
+
+	machine := func(name, role, machineSet string) machinev1beta1.Machine {
+		return machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: machineAPINamespace,
+				Labels: map[string]string{
+					"machine.openshift.io/cluster-api-machine-role": role,
+					"machine.openshift.io/cluster-api-machineset":   machineSet,
+				},
+			},
+		}
+	}
+
+	machines := &machinev1beta1.MachineList{
+		Items: []machinev1beta1.Machine{
+			machine("aro-master-1", "master", ""),
+			machine("aro-master-0", "master", ""),
+			machine("aro-worker-a-1", "worker", "aro-worker-a"),
+			machine("aro-worker-a-0", "worker", "aro-worker-a"),
+			machine("aro-worker-b-0", "worker", "aro-worker-b"),
+		},
+	}
+
+	b, err := json.Marshal(machines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listMachines := func(k *mock_adminactions.MockKubeActions) {
+		k.EXPECT().KubeList(gomock.Any(), machineGroupKind, machineAPINamespace).Return(b, nil)
+	}
+
+	type test struct {
+		name              string
+		machineSet        string
+		vmSize            string
+		provisioningState api.ProvisioningState
+		machineResize     *api.MachineResize
+		mocks             func(*mock_adminactions.MockKubeActions)
+		wantStatusCode    int
+		wantError         string
+		wantResize        *api.MachineResize
+		wantProgress      *api.AsyncOperationProgress
+	}
+
+	for _, tt := range []*test{
+		{
+			name:              "worker machineset resize is queued",
+			machineSet:        "aro-worker-a",
+			vmSize:            "Standard_D8s_v3",
+			provisioningState: api.ProvisioningStateSucceeded,
+			mocks: func(k *mock_adminactions.MockKubeActions) {
+				k.EXPECT().KubeGet(gomock.Any(), machineSetGroupKind, machineAPINamespace, "aro-worker-a").Return([]byte("{}"), nil)
+				listMachines(k)
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantResize: &api.MachineResize{
+				MachineSet: "aro-worker-a",
+				VMSize:     "Standard_D8s_v3",
+				Machines:   []string{"aro-worker-a-0", "aro-worker-a-1"},
+			},
+			wantProgress: &api.AsyncOperationProgress{
+				Pending: []string{"aro-worker-a-0", "aro-worker-a-1"},
+			},
+		},
+		{
+			name:              "failed master resize is resumed",
+			machineSet:        "master",
+			vmSize:            "Standard_D16s_v3",
+			provisioningState: api.ProvisioningStateSucceeded,
+			machineResize: &api.MachineResize{
+				MachineSet: "master",
+				VMSize:     "Standard_D16s_v3",
+				Machines:   []string{"aro-master-0", "aro-master-1"},
+				Completed:  []string{"aro-master-0"},
+				Current:    "aro-master-1",
+				Step:       "Drain",
+			},
+			mocks:          listMachines,
+			wantStatusCode: http.StatusAccepted,
+			wantResize: &api.MachineResize{
+				MachineSet: "master",
+				VMSize:     "Standard_D16s_v3",
+				Machines:   []string{"aro-master-0", "aro-master-1"},
+				Completed:  []string{"aro-master-0"},
+			},
+			wantProgress: &api.AsyncOperationProgress{
+				Completed: []string{"aro-master-0"},
+				Pending:   []string{"aro-master-1"},
+			},
+		},
+		{
+			name:              "resize to another size starts afresh",
+			machineSet:        "master",
+			vmSize:            "Standard_D32s_v3",
+			provisioningState: api.ProvisioningStateSucceeded,
+			machineResize: &api.MachineResize{
+				MachineSet: "master",
+				VMSize:     "Standard_D16s_v3",
+				Machines:   []string{"aro-master-0", "aro-master-1"},
+				Completed:  []string{"aro-master-0"},
+			},
+			mocks:          listMachines,
+			wantStatusCode: http.StatusAccepted,
+			wantResize: &api.MachineResize{
+				MachineSet: "master",
+				VMSize:     "Standard_D32s_v3",
+				Machines:   []string{"aro-master-0", "aro-master-1"},
+			},
+			wantProgress: &api.AsyncOperationProgress{
+				Pending: []string{"aro-master-0", "aro-master-1"},
+			},
+		},
+		{
+			name:              "resize in flight",
+			machineSet:        "master",
+			vmSize:            "Standard_D16s_v3",
+			provisioningState: api.ProvisioningStateAdminUpdating,
+			mocks:             func(k *mock_adminactions.MockKubeActions) {},
+			wantStatusCode:    http.StatusBadRequest,
+			wantError:         "400: RequestNotAllowed: : Request is not allowed in provisioningState 'AdminUpdating'.",
+		},
+		{
+			name:              "failed cluster",
+			machineSet:        "master",
+			vmSize:            "Standard_D16s_v3",
+			provisioningState: api.ProvisioningStateFailed,
+			mocks:             func(k *mock_adminactions.MockKubeActions) {},
+			wantStatusCode:    http.StatusBadRequest,
+			wantError:         "400: RequestNotAllowed: : Request is not allowed in provisioningState 'Failed'.",
+		},
+		{
+			name:              "machineset has no machines",
+			machineSet:        "aro-worker-c",
+			vmSize:            "Standard_D8s_v3",
+			provisioningState: api.ProvisioningStateSucceeded,
+			mocks: func(k *mock_adminactions.MockKubeActions) {
+				k.EXPECT().KubeGet(gomock.Any(), machineSetGroupKind, machineAPINamespace, "aro-worker-c").Return([]byte("{}"), nil)
+				listMachines(k)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: : The machineSet 'aro-worker-c' has no machines to resize.",
+		},
+		{
+			name:              "invalid worker vmSize",
+			machineSet:        "aro-worker-a",
+			vmSize:            "Standard_D2s_v2",
+			provisioningState: api.ProvisioningStateSucceeded,
+			mocks:             func(k *mock_adminactions.MockKubeActions) {},
+			wantStatusCode:    http.StatusBadRequest,
+			wantError:         "400: InvalidParameter: : The provided vmSize 'Standard_D2s_v2' is unsupported for workers.",
+		},
+		{
+			name:              "invalid machineSet",
+			machineSet:        "aro_worker",
+			vmSize:            "Standard_D8s_v3",
+			provisioningState: api.ProvisioningStateSucceeded,
+			mocks:             func(k *mock_adminactions.MockKubeActions) {},
+			wantStatusCode:    http.StatusBadRequest,
+			wantError:         "400: InvalidParameter: : The provided machineSet 'aro_worker' is invalid.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithAsyncOperations()
+			defer ti.done()
+
+			k := mock_adminactions.NewMockKubeActions(ti.controller)
+			tt.mocks(k)
+
+			ti.fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				Key: strings.ToLower(resourceID),
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: resourceID,
+					Properties: api.OpenShiftClusterProperties{
+						ProvisioningState: tt.provisioningState,
+						MachineResize:     tt.machineResize,
+					},
+				},
+			})
+
+			err := ti.buildFixtures(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil,
+				func(*logrus.Entry, env.Interface, *api.OpenShiftCluster) (adminactions.KubeActions, error) {
+					return k, nil
+				}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodPost,
+				fmt.Sprintf("https://server/admin%s/resizemachines?machineSet=%s&vmSize=%s", resourceID, tt.machineSet, tt.vmSize),
+				nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc, err := ti.openShiftClustersDatabase.Get(ctx, strings.ToLower(resourceID))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantStatusCode != http.StatusAccepted {
+				err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, nil)
+				if err != nil {
+					t.Error(err)
+				}
+
+				if doc.OpenShiftCluster.Properties.ProvisioningState != tt.provisioningState {
+					t.Error(doc.OpenShiftCluster.Properties.ProvisioningState)
+				}
+				if !reflect.DeepEqual(doc.OpenShiftCluster.Properties.MachineResize, tt.machineResize) {
+					t.Errorf("unexpected machine resize %#v", doc.OpenShiftCluster.Properties.MachineResize)
+				}
+				return
+			}
+
+			if resp.StatusCode != http.StatusAccepted {
+				t.Fatalf("unexpected status code %d: %s", resp.StatusCode, string(b))
+			}
+
+			op := &api.AsyncOperation{}
+			err = json.Unmarshal(b, op)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.Header.Get("Azure-AsyncOperation") != op.ID {
+				t.Errorf("unexpected Azure-AsyncOperation header %q", resp.Header.Get("Azure-AsyncOperation"))
+			}
+
+			if doc.AsyncOperationID != op.Name {
+				t.Error(doc.AsyncOperationID)
+			}
+			if doc.OpenShiftCluster.Properties.ProvisioningState != api.ProvisioningStateAdminUpdating {
+				t.Error(doc.OpenShiftCluster.Properties.ProvisioningState)
+			}
+			if doc.OpenShiftCluster.Properties.LastProvisioningState != tt.provisioningState {
+				t.Error(doc.OpenShiftCluster.Properties.LastProvisioningState)
+			}
+			if doc.OpenShiftCluster.Properties.MaintenanceTask != api.MaintenanceTaskResizeMachines {
+				t.Error(doc.OpenShiftCluster.Properties.MaintenanceTask)
+			}
+			if !reflect.DeepEqual(doc.OpenShiftCluster.Properties.MachineResize, tt.wantResize) {
+				t.Errorf("unexpected machine resize %#v", doc.OpenShiftCluster.Properties.MachineResize)
+			}
+
+			// the progress is reported while the backend works the resize
+			resp, b, err = ti.request(http.MethodGet, "https://server"+op.ID, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code %d: %s", resp.StatusCode, string(b))
+			}
+
+			op = &api.AsyncOperation{}
+			err = json.Unmarshal(b, op)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if op.ProvisioningState != api.ProvisioningStateAdminUpdating {
+				t.Error(op.ProvisioningState)
+			}
+			if !reflect.DeepEqual(op.Progress, tt.wantProgress) {
+				t.Errorf("unexpected progress %#v", op.Progress)
+			}
+		})
+	}
+}
+
+func TestAdminOperationsStatusProgress(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	operationID := "11111111-1111-1111-1111-111111111111"
+	ctx := context.Background()
+
+	ti := newTestInfra(t).WithOpenShiftClusters().WithAsyncOperations()
+	defer ti.done()
+
+	// the cluster has since moved on, so the progress comes from the
+	// snapshot taken when the resize failed
+	ti.fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+		Key: strings.ToLower(resourceID),
+		OpenShiftCluster: &api.OpenShiftCluster{
+			ID: resourceID,
+			Properties: api.OpenShiftClusterProperties{
+				ProvisioningState: api.ProvisioningStateSucceeded,
+			},
+		},
+	})
+	ti.fixture.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+		ID:                  operationID,
+		OpenShiftClusterKey: strings.ToLower(resourceID),
+		AsyncOperation: &api.AsyncOperation{
+			ID:                "/admin" + resourceID + "/operationsstatus/" + operationID,
+			ProvisioningState: api.ProvisioningStateFailed,
+		},
+		OpenShiftCluster: &api.OpenShiftCluster{
+			Properties: api.OpenShiftClusterProperties{
+				MachineResize: &api.MachineResize{
+					MachineSet: "master",
+					VMSize:     "Standard_D16s_v3",
+					Machines:   []string{"aro-master-0", "aro-master-1", "aro-master-2"},
+					Completed:  []string{"aro-master-0"},
+					Current:    "aro-master-1",
+					Step:       "Drain",
+				},
+			},
+		},
+	})
+
+	err := ti.buildFixtures(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go f.Run(ctx, nil, nil)
+
+	resp, b, err := ti.request(http.MethodGet, "https://server/admin"+resourceID+"/operationsstatus/"+operationID, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", resp.StatusCode, string(b))
+	}
+
+	op := &api.AsyncOperation{}
+	err = json.Unmarshal(b, op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantProgress := &api.AsyncOperationProgress{
+		Completed: []string{"aro-master-0"},
+		Current:   "aro-master-1",
+		Step:      "Drain",
+		Pending:   []string{"aro-master-2"},
+	}
+	if !reflect.DeepEqual(op.Progress, wantProgress) {
+		t.Errorf("unexpected progress %#v", op.Progress)
+	}
+}
+
+func TestAdminOperationsStatusNotFound(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	ctx := context.Background()
+
+	ti := newTestInfra(t).WithOpenShiftClusters().WithAsyncOperations()
+	defer ti.done()
+
+	ti.fixture.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+		ID:                  mockSubID,
+		OpenShiftClusterKey: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "otherCluster")),
+		AsyncOperation: &api.AsyncOperation{
+			ProvisioningState: api.ProvisioningStateSucceeded,
+		},
+	})
+
+	err := ti.buildFixtures(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go f.Run(ctx, nil, nil)
+
+	for _, operationID := range []string{mockSubID, "11111111-1111-1111-1111-111111111111"} {
+		resp, b, err := ti.request(http.MethodGet,
+			fmt.Sprintf("https://server/admin%s/operationsstatus/%s", resourceID, operationID),
+			nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = validateResponse(resp, b, http.StatusNotFound, "404: NotFound: : The entity was not found.", nil)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+func (f *frontend) postAdminOpenShiftClusterRestore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	err := f._postAdminOpenShiftClusterRestore(ctx, r)
+
+	adminReply(log, w, nil, nil, err)
+}
+
+// _postAdminOpenShiftClusterRestore cancels the deletion of a cluster held for
+// its soft delete grace period, returning it to the provisioning state it was
+// in before the delete.  The asynchronous operation of the delete is marked
+// canceled.
+func (f *frontend) _postAdminOpenShiftClusterRestore(ctx context.Context, r *http.Request) error {
+	resourceID := strings.ToLower(adminResourceID(r))
+
+	var asyncOperationID string
+	_, err := f.dbOpenShiftClusters.Patch(ctx, resourceID, func(doc *api.OpenShiftClusterDocument) error {
+		pendingDeletion := doc.OpenShiftCluster.Properties.PendingDeletion
+		if pendingDeletion == nil {
+			return api.NewCloudError(http.StatusConflict, api.CloudErrorCodeRequestNotAllowed, "", "The cluster is not pending deletion.")
+		}
+
+		// once the grace period has passed, the backend may have started
+		// removing the cluster's infrastructure
+		if doc.LeaseOwner != "" || int(f.now().Unix()) >= pendingDeletion.DeleteAfter {
+			return api.NewCloudError(http.StatusConflict, api.CloudErrorCodeRequestNotAllowed, "", "The cluster cannot be restored because its grace period has passed.")
+		}
+
+		asyncOperationID = doc.AsyncOperationID
+
+		doc.OpenShiftCluster.Properties.ProvisioningState = doc.OpenShiftCluster.Properties.LastProvisioningState
+		doc.OpenShiftCluster.Properties.LastProvisioningState = ""
+		doc.OpenShiftCluster.Properties.PendingDeletion = nil
+		doc.CorrelationData = nil
+		doc.AsyncOperationID = ""
+
+		return nil
+	})
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return clusterNotFound(r)
+	case err != nil:
+		return err
+	}
+
+	if asyncOperationID == "" {
+		return nil
+	}
+
+	_, err = f.dbAsyncOperations.Patch(ctx, asyncOperationID, func(asyncdoc *api.AsyncOperationDocument) error {
+		now := f.now()
+
+		asyncdoc.AsyncOperation.ProvisioningState = api.ProvisioningStateCanceled
+		asyncdoc.AsyncOperation.EndTime = &now
+
+		return nil
+	})
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,157 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminRestore(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	ctx := context.Background()
+	now := time.Unix(1672574400, 0)
+
+	clusterDocument := func(provisioningState api.ProvisioningState, pendingDeletion *api.PendingDeletion) *api.OpenShiftClusterDocument {
+		doc := &api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: provisioningState,
+					SoftDeleteProfile: &api.SoftDeleteProfile{
+						GracePeriodHours: 24,
+					},
+					PendingDeletion: pendingDeletion,
+				},
+			},
+		}
+		if provisioningState == api.ProvisioningStateDeleting {
+			doc.AsyncOperationID = "11111111-1111-1111-1111-111111111111"
+			doc.OpenShiftCluster.Properties.LastProvisioningState = api.ProvisioningStateSucceeded
+		}
+		return doc
+	}
+
+	asyncOperationDocument := func(provisioningState api.ProvisioningState, endTime *time.Time) *api.AsyncOperationDocument {
+		return &api.AsyncOperationDocument{
+			ID:                  "11111111-1111-1111-1111-111111111111",
+			OpenShiftClusterKey: strings.ToLower(resourceID),
+			AsyncOperation: &api.AsyncOperation{
+				ID:                       "11111111-1111-1111-1111-111111111111",
+				InitialProvisioningState: api.ProvisioningStateDeleting,
+				ProvisioningState:        provisioningState,
+				EndTime:                  endTime,
+			},
+		}
+	}
+
+	type test struct {
+		name           string
+		fixture        func(*testdatabase.Fixture)
+		checker        func(*testdatabase.Checker)
+		wantStatusCode int
+		wantError      string
+	}
+
+	for _, tt := range []*test{
+		{
+			name: "cluster pending deletion is restored",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument(api.ProvisioningStateDeleting, &api.PendingDeletion{
+					RequestTime: int(now.Unix()) - 3600,
+					DeleteAfter: int(now.Unix()) + 3600,
+				}))
+				f.AddAsyncOperationDocuments(asyncOperationDocument(api.ProvisioningStateDeleting, nil))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(clusterDocument(api.ProvisioningStateSucceeded, nil))
+				c.AddAsyncOperationDocuments(asyncOperationDocument(api.ProvisioningStateCanceled, &now))
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "cluster whose grace period has passed is not restored",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument(api.ProvisioningStateDeleting, &api.PendingDeletion{
+					RequestTime: int(now.Unix()) - 7200,
+					DeleteAfter: int(now.Unix()) - 3600,
+				}))
+				f.AddAsyncOperationDocuments(asyncOperationDocument(api.ProvisioningStateDeleting, nil))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(clusterDocument(api.ProvisioningStateDeleting, &api.PendingDeletion{
+					RequestTime: int(now.Unix()) - 7200,
+					DeleteAfter: int(now.Unix()) - 3600,
+				}))
+				c.AddAsyncOperationDocuments(asyncOperationDocument(api.ProvisioningStateDeleting, nil))
+			},
+			wantStatusCode: http.StatusConflict,
+			wantError:      "409: RequestNotAllowed: : The cluster cannot be restored because its grace period has passed.",
+		},
+		{
+			name: "cluster which is not pending deletion is not restored",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument(api.ProvisioningStateSucceeded, nil))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(clusterDocument(api.ProvisioningStateSucceeded, nil))
+			},
+			wantStatusCode: http.StatusConflict,
+			wantError:      "409: RequestNotAllowed: : The cluster is not pending deletion.",
+		},
+		{
+			name:           "cluster which does not exist",
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithAsyncOperations().WithSubscriptions()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f.now = func() time.Time { return now }
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodPost, "https://server/admin"+resourceID+"/restore", nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, nil)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if tt.checker != nil {
+				tt.checker(ti.checker)
+			}
+			for _, err := range ti.checker.CheckOpenShiftClusters(ti.openShiftClustersClient) {
+				t.Error(err)
+			}
+			for _, err := range ti.checker.CheckAsyncOperations(ti.asyncOperationsClient) {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -51,6 +51,17 @@ func (f *frontend) putAdminOpenShiftVersion(w http.ResponseWriter, r *http.Reque
 					api.WriteError(w, http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.enabled", "You cannot disable the default installation version.")
 					return
 				}
+				// prevent making the default installation version unavailable
+				// for new installs in this region, which would fail the
+				// creation of every cluster which does not request a version
+				if doc.OpenShiftVersion.Properties.Default {
+					v := &api.OpenShiftVersion{}
+					converter.ToInternal(ext, v)
+					if validateInstallVersionLifecycle(v, f.env.Location(), f.now()) != nil {
+						api.WriteError(w, http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties", "You cannot make the default installation version unavailable for new installs.")
+						return
+					}
+				}
 				versionDoc = doc
 				break
 			}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/api/admin"
@@ -17,6 +18,10 @@ import (
 func TestOpenShiftVersionPut(t *testing.T) {
 	ctx := context.Background()
 
+	generalAvailability := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endOfSupport := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+
 	type test struct {
 		name           string
 		fixture        func(f *testdatabase.Fixture)
@@ -216,6 +221,150 @@ func TestOpenShiftVersionPut(t *testing.T) {
 			wantError:      "400: InvalidParameter: properties.version: Must be provided",
 			wantDocuments:  []*api.OpenShiftVersionDocument{},
 		},
+		{
+			name:    "blocking upgrades to a version",
+			fixture: func(f *testdatabase.Fixture) {},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:             "4.10.1",
+					OpenShiftPullspec:   "f:f/g",
+					InstallerPullspec:   "g:g/h",
+					BlockedUpgradesFrom: []string{"4.9.0"},
+				},
+			},
+			wantStatusCode: http.StatusCreated,
+			wantResponse: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:             "4.10.1",
+					OpenShiftPullspec:   "f:f/g",
+					InstallerPullspec:   "g:g/h",
+					BlockedUpgradesFrom: []string{"4.9.0"},
+				},
+			},
+			wantDocuments: []*api.OpenShiftVersionDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:             "4.10.1",
+							OpenShiftPullspec:   "f:f/g",
+							InstallerPullspec:   "g:g/h",
+							BlockedUpgradesFrom: []string{"4.9.0"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "blocked upgrades must be from versions",
+			fixture: func(f *testdatabase.Fixture) {},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:             "4.10.1",
+					OpenShiftPullspec:   "f:f/g",
+					InstallerPullspec:   "g:g/h",
+					BlockedUpgradesFrom: []string{"4.9"},
+				},
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: properties.blockedUpgradesFrom[0]: The provided version '4.9' is invalid.",
+			wantDocuments:  []*api.OpenShiftVersionDocument{},
+		},
+		{
+			name:    "setting lifecycle metadata",
+			fixture: func(f *testdatabase.Fixture) {},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:                  "4.10.1",
+					OpenShiftPullspec:        "f:f/g",
+					InstallerPullspec:        "g:g/h",
+					GeneralAvailability:      &generalAvailability,
+					EndOfSupport:             &endOfSupport,
+					AllowedRegions:           []string{"eastus", "westeurope"},
+					MinimumMasterVMSize:      admin.VMSize(api.VMSizeStandardD16sV3),
+					MinimumWorkerVMSize:      admin.VMSize(api.VMSizeStandardD8sV3),
+					DeprecatedForNewInstalls: true,
+				},
+			},
+			wantStatusCode: http.StatusCreated,
+			wantResponse: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:                  "4.10.1",
+					OpenShiftPullspec:        "f:f/g",
+					InstallerPullspec:        "g:g/h",
+					GeneralAvailability:      &generalAvailability,
+					EndOfSupport:             &endOfSupport,
+					AllowedRegions:           []string{"eastus", "westeurope"},
+					MinimumMasterVMSize:      admin.VMSize(api.VMSizeStandardD16sV3),
+					MinimumWorkerVMSize:      admin.VMSize(api.VMSizeStandardD8sV3),
+					DeprecatedForNewInstalls: true,
+				},
+			},
+			wantDocuments: []*api.OpenShiftVersionDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:                  "4.10.1",
+							OpenShiftPullspec:        "f:f/g",
+							InstallerPullspec:        "g:g/h",
+							GeneralAvailability:      &generalAvailability,
+							EndOfSupport:             &endOfSupport,
+							AllowedRegions:           []string{"eastus", "westeurope"},
+							MinimumMasterVMSize:      api.VMSizeStandardD16sV3,
+							MinimumWorkerVMSize:      api.VMSizeStandardD8sV3,
+							DeprecatedForNewInstalls: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "end of support must be after general availability",
+			fixture: func(f *testdatabase.Fixture) {},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:             "4.10.1",
+					OpenShiftPullspec:   "f:f/g",
+					InstallerPullspec:   "g:g/h",
+					GeneralAvailability: &endOfSupport,
+					EndOfSupport:        &generalAvailability,
+				},
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: properties.endOfSupport: The provided end of support date must be after the general availability date.",
+			wantDocuments:  []*api.OpenShiftVersionDocument{},
+		},
+		{
+			name:    "allowed regions must be region names",
+			fixture: func(f *testdatabase.Fixture) {},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:           "4.10.1",
+					OpenShiftPullspec: "f:f/g",
+					InstallerPullspec: "g:g/h",
+					AllowedRegions:    []string{"eastus", "West Europe"},
+				},
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: properties.allowedRegions[1]: The provided region 'West Europe' is invalid.",
+			wantDocuments:  []*api.OpenShiftVersionDocument{},
+		},
+		{
+			name:    "minimum master vm size must be supported for masters",
+			fixture: func(f *testdatabase.Fixture) {},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:             "4.10.1",
+					OpenShiftPullspec:   "f:f/g",
+					InstallerPullspec:   "g:g/h",
+					MinimumMasterVMSize: admin.VMSize(api.VMSizeStandardD4sV3),
+				},
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: properties.minimumMasterVmSize: The provided vmSize 'Standard_D4s_v3' is unsupported for master.",
+			wantDocuments:  []*api.OpenShiftVersionDocument{},
+		},
 		{
 			name: "can not disable default install version",
 			fixture: func(f *testdatabase.Fixture) {
@@ -256,6 +405,161 @@ func TestOpenShiftVersionPut(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "can not deprecate default install version for new installs",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftVersionDocuments(&api.OpenShiftVersionDocument{
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:           "4.10.0",
+							Enabled:           true,
+							Default:           true,
+							OpenShiftPullspec: "a:a/b",
+						},
+					},
+				})
+			},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:                  "4.10.0",
+					Enabled:                  true,
+					OpenShiftPullspec:        "a:a/b",
+					InstallerPullspec:        "d:d/e",
+					DeprecatedForNewInstalls: true,
+				},
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: properties: You cannot make the default installation version unavailable for new installs.",
+			wantDocuments: []*api.OpenShiftVersionDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:           "4.10.0",
+							Enabled:           true,
+							Default:           true,
+							OpenShiftPullspec: "a:a/b",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "can not end support of default install version",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftVersionDocuments(&api.OpenShiftVersionDocument{
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:           "4.10.0",
+							Enabled:           true,
+							Default:           true,
+							OpenShiftPullspec: "a:a/b",
+						},
+					},
+				})
+			},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:           "4.10.0",
+					Enabled:           true,
+					OpenShiftPullspec: "a:a/b",
+					InstallerPullspec: "d:d/e",
+					EndOfSupport:      &now,
+				},
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: properties: You cannot make the default installation version unavailable for new installs.",
+			wantDocuments: []*api.OpenShiftVersionDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:           "4.10.0",
+							Enabled:           true,
+							Default:           true,
+							OpenShiftPullspec: "a:a/b",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "can not restrict default install version to other regions",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftVersionDocuments(&api.OpenShiftVersionDocument{
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:           "4.10.0",
+							Enabled:           true,
+							Default:           true,
+							OpenShiftPullspec: "a:a/b",
+						},
+					},
+				})
+			},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:           "4.10.0",
+					Enabled:           true,
+					OpenShiftPullspec: "a:a/b",
+					InstallerPullspec: "d:d/e",
+					AllowedRegions:    []string{"westeurope"},
+				},
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: properties: You cannot make the default installation version unavailable for new installs.",
+			wantDocuments: []*api.OpenShiftVersionDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:           "4.10.0",
+							Enabled:           true,
+							Default:           true,
+							OpenShiftPullspec: "a:a/b",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "default install version may be given lifecycle metadata which keeps it available",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftVersionDocuments(&api.OpenShiftVersionDocument{
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:           "4.10.0",
+							Enabled:           true,
+							Default:           true,
+							OpenShiftPullspec: "a:a/b",
+						},
+					},
+				})
+			},
+			body: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:             "4.10.0",
+					Enabled:             true,
+					OpenShiftPullspec:   "a:a/b",
+					InstallerPullspec:   "d:d/e",
+					GeneralAvailability: &generalAvailability,
+					EndOfSupport:        &endOfSupport,
+					AllowedRegions:      []string{"eastus"},
+				},
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &admin.OpenShiftVersion{
+				Properties: admin.OpenShiftVersionProperties{
+					Version:             "4.10.0",
+					Enabled:             true,
+					OpenShiftPullspec:   "a:a/b",
+					InstallerPullspec:   "d:d/e",
+					GeneralAvailability: &generalAvailability,
+					EndOfSupport:        &endOfSupport,
+					AllowedRegions:      []string{"eastus"},
+				},
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			ti := newTestInfra(t).WithOpenShiftVersions()
@@ -266,11 +570,13 @@ func TestOpenShiftVersionPut(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, nil, nil, nil, nil, ti.openShiftVersionsDatabase, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, nil, nil, nil, nil, ti.openShiftVersionsDatabase, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
 
+			f.now = func() time.Time { return now }
+
 			go f.Run(ctx, nil, nil)
 
 			resp, b, err := ti.request(http.MethodPut, "https://server/admin/versions",
@@ -0,0 +1,198 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+const (
+	// alertTTL is how long ingested alerts are retained
+	alertTTL = 7 * 24 * time.Hour
+
+	// maxAlertIngestionBodySize bounds a single Alertmanager notification.
+	// Alertmanager groups alerts per notification, so this is far above what
+	// the platform-critical receiver sends in practice.
+	maxAlertIngestionBodySize = 256 * 1024
+
+	// alertIngestionRate and alertIngestionBurst bound how often a single
+	// cluster may post notifications.  Alertmanager batches alerts using
+	// group_wait and group_interval, so a well-behaved cluster stays far
+	// below this.
+	alertIngestionRate  = rate.Limit(1)
+	alertIngestionBurst = 20
+)
+
+// alertmanagerWebhook is the payload sent by an Alertmanager webhook receiver.
+// See https://prometheus.io/docs/alerting/latest/configuration/#webhook_config
+type alertmanagerWebhook struct {
+	Receiver string              `json:"receiver,omitempty"`
+	Status   string              `json:"status,omitempty"`
+	Alerts   []alertmanagerAlert `json:"alerts,omitempty"`
+}
+
+type alertmanagerAlert struct {
+	Status      string            `json:"status,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt,omitempty"`
+	EndsAt      time.Time         `json:"endsAt,omitempty"`
+	Fingerprint string            `json:"fingerprint,omitempty"`
+}
+
+// postAlertIngestion receives critical platform alerts forwarded by the ARO
+// operator's Alertmanager receiver.  It is not authenticated by client
+// certificate; instead the caller must present the per-cluster alert ingestion
+// token as a bearer token.  See docs/alert-ingestion.md for the threat model.
+func (f *frontend) postAlertIngestion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	err := f._postAlertIngestion(ctx, r, log)
+
+	reply(log, w, nil, nil, err)
+}
+
+func (f *frontend) _postAlertIngestion(ctx context.Context, r *http.Request, log *logrus.Entry) error {
+	body := r.Context().Value(middleware.ContextKeyBody).([]byte)
+	resType, resName, resGroupName := chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName")
+
+	if len(body) > maxAlertIngestionBodySize {
+		return api.NewCloudError(http.StatusRequestEntityTooLarge, api.CloudErrorCodeInvalidRequestContent, "", "The request content exceeds the maximum size of %d bytes.", maxAlertIngestionBodySize)
+	}
+
+	resourceID := strings.TrimPrefix(r.URL.Path, "/alertingestion")
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "", "The Resource '%s/%s' under resource group '%s' was not found.", resType, resName, resGroupName)
+	case err != nil:
+		return err
+	}
+
+	token := string(doc.OpenShiftCluster.Properties.AlertIngestionToken)
+	bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Forbidden.")
+	}
+
+	// rate limit only once the token has been checked, so that callers
+	// without the token cannot use up a cluster's allowance
+	if !f.alertIngestionLimiter.allow(doc.Key) {
+		return api.NewCloudError(http.StatusTooManyRequests, api.CloudErrorCodeThrottlingLimitExceeded, "", "Too many alert notifications have been received for this cluster. Please retry later.")
+	}
+
+	var payload alertmanagerWebhook
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidRequestContent, "", "The request content was invalid and could not be deserialized: %q.", err)
+	}
+
+	now := f.now()
+	for _, a := range payload.Alerts {
+		alertDoc := &api.ClusterAlertDocument{
+			ID:  alertDocumentID(a),
+			Key: doc.Key,
+			TTL: int(alertTTL / time.Second),
+			ClusterAlert: &api.ClusterAlert{
+				Name:        a.Labels["alertname"],
+				Status:      api.ClusterAlertStatus(a.Status),
+				Severity:    a.Labels["severity"],
+				Namespace:   a.Labels["namespace"],
+				Summary:     a.Annotations["summary"],
+				Description: a.Annotations["description"],
+				Fingerprint: a.Fingerprint,
+				Labels:      a.Labels,
+				Annotations: a.Annotations,
+				StartsAt:    a.StartsAt,
+				EndsAt:      a.EndsAt,
+				ReceivedAt:  now,
+			},
+		}
+
+		_, err = f.dbClusterAlerts.CreateOrUpdate(ctx, alertDoc)
+		if err != nil {
+			return err
+		}
+
+		f.m.EmitGauge("frontend.alertingestion.alerts", 1, map[string]string{
+			"resourceId": doc.OpenShiftCluster.ID,
+			"alertname":  alertDoc.ClusterAlert.Name,
+			"namespace":  alertDoc.ClusterAlert.Namespace,
+			"status":     a.Status,
+		})
+	}
+
+	log.Infof("ingested %d alerts", len(payload.Alerts))
+
+	return nil
+}
+
+// alertDocumentID returns a stable ID for an alert, so that repeated
+// notifications for the same alert instance update a single document
+func alertDocumentID(a alertmanagerAlert) string {
+	h := sha256.Sum256([]byte(a.Fingerprint + "/" + a.StartsAt.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(h[:])
+}
+
+// alertIngestionLimiter rate limits alert ingestion per cluster
+type alertIngestionLimiter struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	limiters map[string]*rate.Limiter
+}
+
+func newAlertIngestionLimiter() *alertIngestionLimiter {
+	return &alertIngestionLimiter{
+		now:      time.Now,
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+// allow reports whether a notification for the cluster with the given key may
+// be ingested now
+func (l *alertIngestionLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	lim := l.limiters[key]
+	if lim == nil {
+		l.prune(now)
+
+		lim = rate.NewLimiter(alertIngestionRate, alertIngestionBurst)
+		l.limiters[key] = lim
+	}
+
+	return lim.AllowN(now, 1)
+}
+
+// prune forgets limiters which have refilled completely, as they are
+// indistinguishable from new ones.  This keeps the map bounded by the number
+// of recently active clusters.
+func (l *alertIngestionLimiter) prune(now time.Time) {
+	for key, lim := range l.limiters {
+		if lim.TokensAt(now) >= alertIngestionBurst {
+			delete(l.limiters, key)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestPostAlertIngestion(t *testing.T) {
+	ctx := context.Background()
+
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	startsAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2023, 1, 1, 0, 5, 0, 0, time.UTC)
+
+	payload := &alertmanagerWebhook{
+		Receiver: "aro-platform-critical",
+		Status:   "firing",
+		Alerts: []alertmanagerAlert{
+			{
+				Status: "firing",
+				Labels: map[string]string{
+					"alertname": "etcdMembersDown",
+					"namespace": "openshift-etcd",
+					"severity":  "critical",
+				},
+				Annotations: map[string]string{
+					"summary": "etcd cluster members are down.",
+				},
+				StartsAt:    startsAt,
+				Fingerprint: "0123456789abcdef",
+			},
+		},
+	}
+
+	bigPayload := &alertmanagerWebhook{
+		Receiver: "aro-platform-critical",
+		Status:   "firing",
+		Alerts: []alertmanagerAlert{
+			{
+				Status: "firing",
+				Annotations: map[string]string{
+					"description": strings.Repeat("x", maxAlertIngestionBodySize),
+				},
+			},
+		},
+	}
+
+	clusterFixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+				Properties: api.OpenShiftClusterProperties{
+					AlertIngestionToken: "token",
+				},
+			},
+		})
+	}
+
+	type test struct {
+		name           string
+		fixture        func(*testdatabase.Fixture)
+		payload        *alertmanagerWebhook
+		throttled      bool
+		token          string
+		wantStatusCode int
+		wantError      string
+		wantAlerts     []*api.ClusterAlertDocument
+	}
+
+	for _, tt := range []*test{
+		{
+			name: "alerts are stored",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(resourceID),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID: resourceID,
+						Properties: api.OpenShiftClusterProperties{
+							AlertIngestionToken: "token",
+						},
+					},
+				})
+			},
+			token:          "token",
+			wantStatusCode: http.StatusOK,
+			wantAlerts: []*api.ClusterAlertDocument{
+				{
+					ID:  alertDocumentID(payload.Alerts[0]),
+					Key: strings.ToLower(resourceID),
+					TTL: 604800,
+					ClusterAlert: &api.ClusterAlert{
+						Name:        "etcdMembersDown",
+						Status:      api.ClusterAlertStatusFiring,
+						Severity:    "critical",
+						Namespace:   "openshift-etcd",
+						Summary:     "etcd cluster members are down.",
+						Fingerprint: "0123456789abcdef",
+						Labels:      payload.Alerts[0].Labels,
+						Annotations: payload.Alerts[0].Annotations,
+						StartsAt:    startsAt,
+						ReceivedAt:  now,
+					},
+				},
+			},
+		},
+		{
+			name: "wrong token",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(resourceID),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID: resourceID,
+						Properties: api.OpenShiftClusterProperties{
+							AlertIngestionToken: "token",
+						},
+					},
+				})
+			},
+			token:          "wrong",
+			wantStatusCode: http.StatusForbidden,
+			wantError:      "403: Forbidden: : Forbidden.",
+		},
+		{
+			name: "no token on cluster",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(resourceID),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID: resourceID,
+					},
+				})
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantError:      "403: Forbidden: : Forbidden.",
+		},
+		{
+			name:           "body too large",
+			fixture:        clusterFixture,
+			payload:        bigPayload,
+			token:          "token",
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+			wantError:      "413: InvalidRequestContent: : The request content exceeds the maximum size of 262144 bytes.",
+		},
+		{
+			name:           "throttled",
+			fixture:        clusterFixture,
+			throttled:      true,
+			token:          "token",
+			wantStatusCode: http.StatusTooManyRequests,
+			wantError:      "429: ThrottlingLimitExceeded: : Too many alert notifications have been received for this cluster. Please retry later.",
+		},
+		{
+			name:           "cluster not found",
+			token:          "token",
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithClusterAlerts()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ti.checker.AddClusterAlertDocuments(tt.wantAlerts...)
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, nil, nil, ti.openShiftClustersDatabase, nil, nil, ti.clusterAlertsDatabase, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.now = func() time.Time { return now }
+			if tt.throttled {
+				f.alertIngestionLimiter.limiters[strings.ToLower(resourceID)] = rate.NewLimiter(alertIngestionRate, 0)
+			}
+
+			body := payload
+			if tt.payload != nil {
+				body = tt.payload
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodPost,
+				"https://server/alertingestion"+resourceID,
+				http.Header{
+					"Authorization": []string{"Bearer " + tt.token},
+					"Content-Type":  []string{"application/json"},
+				}, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, nil)
+			if err != nil {
+				t.Error(err)
+			}
+
+			for _, err := range ti.checker.CheckClusterAlerts(ti.clusterAlertsClient) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestAlertIngestionLimiter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	l := newAlertIngestionLimiter()
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < alertIngestionBurst; i++ {
+		if !l.allow("cluster1") {
+			t.Fatalf("notification %d was throttled", i)
+		}
+	}
+
+	if l.allow("cluster1") {
+		t.Error("expected cluster1 to be throttled")
+	}
+
+	if !l.allow("cluster2") {
+		t.Error("expected cluster2 not to be throttled by cluster1")
+	}
+
+	// once refilled, idle limiters are forgotten when a new cluster is seen
+	now = now.Add(time.Hour)
+
+	if !l.allow("cluster3") {
+		t.Error("expected cluster3 not to be throttled")
+	}
+
+	if len(l.limiters) != 1 {
+		t.Errorf("expected idle limiters to be pruned, got %d limiters", len(l.limiters))
+	}
+}
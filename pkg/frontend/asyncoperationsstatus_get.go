@@ -61,6 +61,7 @@ func (f *frontend) _getAsyncOperationsStatus(ctx context.Context, r *http.Reques
 
 	asyncdoc.AsyncOperation.MissingFields = api.MissingFields{}
 	asyncdoc.AsyncOperation.InitialProvisioningState = ""
+	asyncdoc.AsyncOperation.Progress = nil
 
 	h := &codec.JsonHandle{
 		Indent: 4,
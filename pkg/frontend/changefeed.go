@@ -58,12 +58,19 @@ func (f *frontend) updateFromIterator(ctx context.Context, ticker *time.Ticker,
 	}
 }
 
-// updateOcpVersions adds enabled versions to the frontend cache
+// updateOcpVersions adds enabled versions, and the upgrades blocked to any
+// version, to the frontend cache
 func (f *frontend) updateOcpVersions(docs []*api.OpenShiftVersionDocument) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	for _, doc := range docs {
+		if doc.OpenShiftVersion.Deleting || len(doc.OpenShiftVersion.Properties.BlockedUpgradesFrom) == 0 {
+			delete(f.blockedUpgradesFrom, doc.OpenShiftVersion.Properties.Version)
+		} else {
+			f.blockedUpgradesFrom[doc.OpenShiftVersion.Properties.Version] = doc.OpenShiftVersion.Properties.BlockedUpgradesFrom
+		}
+
 		if doc.OpenShiftVersion.Deleting || !doc.OpenShiftVersion.Properties.Enabled {
 			// https://docs.microsoft.com/en-us/azure/cosmos-db/change-feed-design-patterns#deletes
 			delete(f.enabledOcpVersions, doc.OpenShiftVersion.Properties.Version)
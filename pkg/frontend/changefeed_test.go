@@ -20,6 +20,7 @@ func TestUpdateFromIterator(t *testing.T) {
 		docsInIterator []*api.OpenShiftVersionDocument
 		versions       map[string]*api.OpenShiftVersion
 		wantVersions   map[string]*api.OpenShiftVersion
+		wantBlocked    map[string][]string
 	}{
 		{
 			name: "add to empty",
@@ -173,13 +174,32 @@ func TestUpdateFromIterator(t *testing.T) {
 			},
 			wantVersions: map[string]*api.OpenShiftVersion{},
 		},
+		{
+			name: "add blocked upgrades of disabled version",
+			docsInIterator: []*api.OpenShiftVersionDocument{
+				{
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:             "4.5.7",
+							BlockedUpgradesFrom: []string{"4.5.6"},
+						},
+					},
+				},
+			},
+			versions:     map[string]*api.OpenShiftVersion{},
+			wantVersions: map[string]*api.OpenShiftVersion{},
+			wantBlocked: map[string][]string{
+				"4.5.7": {"4.5.6"},
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			ticker := time.NewTicker(1)
 			ctx, cancel := context.WithCancel(context.TODO())
 
 			frontend := frontend{
-				enabledOcpVersions: tt.versions,
+				enabledOcpVersions:  tt.versions,
+				blockedUpgradesFrom: map[string][]string{},
 			}
 
 			fakeIterator := cosmosdb.NewFakeOpenShiftVersionDocumentIterator(tt.docsInIterator, 0)
@@ -191,6 +211,13 @@ func TestUpdateFromIterator(t *testing.T) {
 			if !reflect.DeepEqual(frontend.enabledOcpVersions, tt.wantVersions) {
 				t.Error(cmp.Diff(frontend.enabledOcpVersions, tt.wantVersions))
 			}
+
+			if tt.wantBlocked == nil {
+				tt.wantBlocked = map[string][]string{}
+			}
+			if !reflect.DeepEqual(frontend.blockedUpgradesFrom, tt.wantBlocked) {
+				t.Error(cmp.Diff(frontend.blockedUpgradesFrom, tt.wantBlocked))
+			}
 		})
 	}
 }
@@ -523,6 +523,9 @@ func TestFixEtcd(t *testing.T) {
 				ti.openShiftClustersDatabase,
 				ti.subscriptionsDatabase,
 				nil,
+				nil,
+				nil,
+				nil,
 				api.APIs,
 				&noop.Noop{},
 				&noop.Noop{},
@@ -27,6 +27,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
 	"github.com/Azure/ARO-RP/pkg/hive"
 	"github.com/Azure/ARO-RP/pkg/metrics"
+	"github.com/Azure/ARO-RP/pkg/mirror"
 	"github.com/Azure/ARO-RP/pkg/util/bucket"
 	"github.com/Azure/ARO-RP/pkg/util/clusterdata"
 	"github.com/Azure/ARO-RP/pkg/util/encryption"
@@ -62,11 +63,21 @@ type frontend struct {
 	dbOpenShiftClusters           database.OpenShiftClusters
 	dbSubscriptions               database.Subscriptions
 	dbOpenShiftVersions           database.OpenShiftVersions
+	dbClusterAlerts               database.ClusterAlerts
+	dbPendingActions              database.PendingActions
+	dbAdminAuditLog               database.AdminAuditLog
 
 	defaultOcpVersion  string // always enabled
 	enabledOcpVersions map[string]*api.OpenShiftVersion
 	apis               map[string]*api.Version
 
+	// blockedUpgradesFrom maps versions to the versions from which upgrading
+	// to them is not supported
+	blockedUpgradesFrom map[string][]string
+	upgradeGraph        func(context.Context, string) (*mirror.Graph, error)
+
+	alertIngestionLimiter *alertIngestionLimiter
+
 	lastChangefeed atomic.Value //time.Time
 	mu             sync.RWMutex
 
@@ -90,6 +101,10 @@ type frontend struct {
 	startTime time.Time
 	ready     atomic.Value
 
+	// drained is set to a func reporting whether the backend has drained once
+	// the RP starts draining
+	drained atomic.Value
+
 	// these helps us to test and mock easier
 	now                          func() time.Time
 	systemDataClusterDocEnricher func(*api.OpenShiftClusterDocument, *api.SystemData)
@@ -117,6 +132,9 @@ func NewFrontend(ctx context.Context,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbSubscriptions database.Subscriptions,
 	dbOpenShiftVersions database.OpenShiftVersions,
+	dbClusterAlerts database.ClusterAlerts,
+	dbPendingActions database.PendingActions,
+	dbAdminAuditLog database.AdminAuditLog,
 	apis map[string]*api.Version,
 	m metrics.Emitter,
 	clusterm metrics.Emitter,
@@ -153,6 +171,9 @@ func NewFrontend(ctx context.Context,
 		dbOpenShiftClusters:           dbOpenShiftClusters,
 		dbSubscriptions:               dbSubscriptions,
 		dbOpenShiftVersions:           dbOpenShiftVersions,
+		dbClusterAlerts:               dbClusterAlerts,
+		dbPendingActions:              dbPendingActions,
+		dbAdminAuditLog:               dbAdminAuditLog,
 		apis:                          apis,
 		m:                             middleware.MetricsMiddleware{Emitter: m},
 		maintenanceMiddleware:         middleware.MaintenanceMiddleware{Emitter: clusterm},
@@ -169,6 +190,11 @@ func NewFrontend(ctx context.Context,
 
 		enabledOcpVersions: map[string]*api.OpenShiftVersion{},
 
+		blockedUpgradesFrom: map[string][]string{},
+		upgradeGraph:        newUpgradeGraphCache(mirror.UpgradeGraph).get,
+
+		alertIngestionLimiter: newAlertIngestionLimiter(),
+
 		bucketAllocator: &bucket.Random{},
 
 		startTime: time.Now(),
@@ -222,6 +248,11 @@ func NewFrontend(ctx context.Context,
 
 func (f *frontend) chiUnauthenticatedRoutes(router chi.Router) {
 	router.Get("/healthz/ready", f.getReady)
+	router.Get("/healthz/drained", f.getDrained)
+
+	// Alert ingestion from the ARO operator is authenticated by a
+	// per-cluster bearer token rather than by client certificate
+	router.Post("/alertingestion/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}", f.postAlertIngestion)
 }
 
 func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
@@ -253,6 +284,8 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 					r.Post("/listcredentials", f.postOpenShiftClusterCredentials)
 
 					r.Post("/listadmincredentials", f.postOpenShiftClusterKubeConfigCredentials)
+
+					r.Get("/upgradepaths", f.getOpenShiftClusterUpgradePaths)
 				})
 
 				r.Get("/detectors", f.listAppLensDetectors)
@@ -289,16 +322,21 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 			r.Put("/", f.putAdminOpenShiftVersion)
 		})
 		r.Get("/supportedvmsizes", f.supportedvmsizes)
+		r.Get("/deadletters", f.getAdminDeadLetters)
 
 		r.Route("/subscriptions/{subscriptionId}", func(r chi.Router) {
 			r.Route("/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}", func(r chi.Router) {
+				r.Use(f.adminAuditLog)
+
+				r.Get("/auditlog", f.getAdminAuditLog)
+
 				// Etcd recovery
-				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/etcdrecovery", f.postAdminOpenShiftClusterEtcdRecovery)
+				r.With(f.requireApproval("etcdrecovery"), f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/etcdrecovery", f.postAdminOpenShiftClusterEtcdRecovery)
 
 				// Kubernetes objects
 				r.Get("/kubernetesobjects", f.getAdminKubernetesObjects)
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/kubernetesobjects", f.postAdminKubernetesObjects)
-				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Delete("/kubernetesobjects", f.deleteAdminKubernetesObjects)
+				r.With(f.requireApproval("kubernetesobjects"), f.maintenanceMiddleware.UnplannedMaintenanceSignal).Delete("/kubernetesobjects", f.deleteAdminKubernetesObjects)
 
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/approvecsr", f.postAdminOpenShiftClusterApproveCSR)
 
@@ -311,9 +349,9 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 
 				r.Get("/clusterdeployment", f.getAdminHiveClusterDeployment)
 
-				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/redeployvm", f.postAdminOpenShiftClusterRedeployVM)
+				r.With(f.requireApproval("redeployvm"), f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/redeployvm", f.postAdminOpenShiftClusterRedeployVM)
 
-				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/stopvm", f.postAdminOpenShiftClusterStopVM)
+				r.With(f.requireApproval("stopvm"), f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/stopvm", f.postAdminOpenShiftClusterStopVM)
 
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/startvm", f.postAdminOpenShiftClusterStartVM)
 
@@ -322,6 +360,10 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 				// We don't emit unplanned maintenance signal for resize since it is only used for planned maintenance
 				r.Post("/resize", f.postAdminOpenShiftClusterVMResize)
 
+				r.Post("/resizemachines", f.postAdminOpenShiftClusterResizeMachines)
+
+				r.Get("/operationsstatus/{operationId}", f.getAdminOpenShiftClusterOperationsStatus)
+
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/reconcilefailednic", f.postAdminReconcileFailedNIC)
 
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/cordonnode", f.postAdminOpenShiftClusterCordonNode)
@@ -329,7 +371,20 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/drainnode", f.postAdminOpenShiftClusterDrainNode)
 
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/etcdcertificaterenew", f.postAdminOpenShiftClusterEtcdCertificateRenew)
-				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/deletemanagedresource", f.postAdminOpenShiftDeleteManagedResource)
+				r.With(f.requireApproval("deletemanagedresource"), f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/deletemanagedresource", f.postAdminOpenShiftDeleteManagedResource)
+
+				// Clusters held for their soft delete grace period
+				r.Post("/restore", f.postAdminOpenShiftClusterRestore)
+
+				// Cluster documents which the backend has stopped working
+				r.Get("/deadletter", f.getAdminDeadLetter)
+				r.Post("/deadletter/requeue", f.postAdminDeadLetterRequeue)
+
+				// Destructive actions held for approval by a second identity
+				r.Get("/pendingactions", f.getAdminPendingActions)
+				r.Get("/pendingactions/{pendingActionId}", f.getAdminPendingAction)
+				r.Post("/pendingactions/{pendingActionId}/run", f.postAdminPendingActionRun)
+				r.Post("/pendingactions/{pendingActionId}/reject", f.postAdminPendingActionReject)
 			})
 		})
 
@@ -409,6 +464,14 @@ func (f *frontend) Run(ctx context.Context, stop <-chan struct{}, done chan<- st
 	}
 }
 
+// Drain marks the frontend not ready, so that the load balancer stops sending
+// it requests, and reports the status of drained on /healthz/drained
+func (f *frontend) Drain(drained func() bool) {
+	f.baseLog.Print("draining, marking not ready")
+	f.drained.Store(drained)
+	f.ready.Store(false)
+}
+
 func adminReply(log *logrus.Entry, w http.ResponseWriter, header http.Header, b []byte, err error) {
 	if apiErr, ok := err.(kerrors.APIStatus); ok {
 		status := apiErr.Status()
@@ -160,7 +160,7 @@ func (l LogMiddleware) Log(h http.Handler) http.Handler {
 				resultType = audit.ResultTypeFail
 			}
 
-			if r.URL.Path == "/healthz/ready" {
+			if r.URL.Path == "/healthz/ready" || r.URL.Path == "/healthz/drained" {
 				return
 			}
 
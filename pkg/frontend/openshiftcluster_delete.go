@@ -7,6 +7,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/sirupsen/logrus"
@@ -48,10 +49,24 @@ func (f *frontend) _deleteOpenShiftCluster(ctx context.Context, r *http.Request,
 		return err
 	}
 
+	if doc.OpenShiftCluster.Properties.DeletionProtection == api.DeletionProtectionEnabled {
+		return api.NewCloudError(http.StatusConflict, api.CloudErrorCodeDeletionProtected, "properties.deletionProtection", "The cluster cannot be deleted because deletion protection is enabled. Disable deletion protection and retry the request.")
+	}
+
 	doc.OpenShiftCluster.Properties.LastProvisioningState = doc.OpenShiftCluster.Properties.ProvisioningState
 	doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateDeleting
 	doc.CorrelationData = correlationData
-	doc.Dequeues = 0
+	resetDequeues(doc)
+
+	// When soft delete is configured, the backend does not dequeue the cluster
+	// until the grace period has passed, during which an SRE may restore it
+	if sdp := doc.OpenShiftCluster.Properties.SoftDeleteProfile; sdp != nil && sdp.GracePeriodHours > 0 {
+		now := f.now()
+		doc.OpenShiftCluster.Properties.PendingDeletion = &api.PendingDeletion{
+			RequestTime: int(now.Unix()),
+			DeleteAfter: int(now.Add(time.Duration(sdp.GracePeriodHours) * time.Hour).Unix()),
+		}
+	}
 
 	subId := chi.URLParam(r, "subscriptionId")
 	resourceProviderNamespace := chi.URLParam(r, "resourceProviderNamespace")
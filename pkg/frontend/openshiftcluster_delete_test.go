@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
@@ -20,6 +21,17 @@ func TestDeleteOpenShiftCluster(t *testing.T) {
 	ctx := context.Background()
 
 	mockSubID := "00000000-0000-0000-0000-000000000000"
+	now := time.Unix(1672574400, 0)
+
+	subscriptionDocument := &api.SubscriptionDocument{
+		ID: mockSubID,
+		Subscription: &api.Subscription{
+			State: api.SubscriptionStateRegistered,
+			Properties: &api.SubscriptionProperties{
+				TenantID: "11111111-1111-1111-1111-111111111111",
+			},
+		},
+	}
 
 	type test struct {
 		name           string
@@ -83,6 +95,145 @@ func TestDeleteOpenShiftCluster(t *testing.T) {
 			wantStatusCode: http.StatusAccepted,
 			wantAsync:      true,
 		},
+		{
+			name:       "dead lettered cluster keeps its dequeue count",
+			resourceID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddSubscriptionDocuments(subscriptionDocument)
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key:      strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+					Dequeues: 6,
+					DeadLetter: &api.DeadLetter{
+						ProvisioningState: api.ProvisioningStateUpdating,
+						Dequeues:          6,
+					},
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:   testdatabase.GetResourcePath(mockSubID, "resourceName"),
+						Name: "resourceName",
+						Type: "Microsoft.RedHatOpenShift/openshiftClusters",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState:       api.ProvisioningStateFailed,
+							FailedProvisioningState: api.ProvisioningStateUpdating,
+						},
+					},
+				})
+			},
+			wantDocuments: func(c *testdatabase.Checker) {
+				c.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+					OpenShiftClusterKey: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+					AsyncOperation: &api.AsyncOperation{
+						InitialProvisioningState: api.ProvisioningStateDeleting,
+						ProvisioningState:        api.ProvisioningStateDeleting,
+					},
+				})
+				c.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key:      strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+					Dequeues: 6,
+					DeadLetter: &api.DeadLetter{
+						ProvisioningState: api.ProvisioningStateUpdating,
+						Dequeues:          6,
+					},
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:   testdatabase.GetResourcePath(mockSubID, "resourceName"),
+						Name: "resourceName",
+						Type: "Microsoft.RedHatOpenShift/openshiftClusters",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState:       api.ProvisioningStateDeleting,
+							LastProvisioningState:   api.ProvisioningStateFailed,
+							FailedProvisioningState: api.ProvisioningStateUpdating,
+						},
+					},
+				})
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantAsync:      true,
+		},
+		{
+			name:       "cluster with deletion protection is not deleted",
+			resourceID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddSubscriptionDocuments(subscriptionDocument)
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:   testdatabase.GetResourcePath(mockSubID, "resourceName"),
+						Name: "resourceName",
+						Type: "Microsoft.RedHatOpenShift/openshiftClusters",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState:  api.ProvisioningStateSucceeded,
+							DeletionProtection: api.DeletionProtectionEnabled,
+						},
+					},
+				})
+			},
+			wantDocuments: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:   testdatabase.GetResourcePath(mockSubID, "resourceName"),
+						Name: "resourceName",
+						Type: "Microsoft.RedHatOpenShift/openshiftClusters",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState:  api.ProvisioningStateSucceeded,
+							DeletionProtection: api.DeletionProtectionEnabled,
+						},
+					},
+				})
+			},
+			wantStatusCode: http.StatusConflict,
+			wantError:      "409: DeletionProtected: properties.deletionProtection: The cluster cannot be deleted because deletion protection is enabled. Disable deletion protection and retry the request.",
+		},
+		{
+			name:       "cluster with soft delete is held for its grace period",
+			resourceID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddSubscriptionDocuments(subscriptionDocument)
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:   testdatabase.GetResourcePath(mockSubID, "resourceName"),
+						Name: "resourceName",
+						Type: "Microsoft.RedHatOpenShift/openshiftClusters",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState: api.ProvisioningStateSucceeded,
+							SoftDeleteProfile: &api.SoftDeleteProfile{
+								GracePeriodHours: 24,
+							},
+						},
+					},
+				})
+			},
+			wantDocuments: func(c *testdatabase.Checker) {
+				c.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+					OpenShiftClusterKey: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+					AsyncOperation: &api.AsyncOperation{
+						InitialProvisioningState: api.ProvisioningStateDeleting,
+						ProvisioningState:        api.ProvisioningStateDeleting,
+					},
+				})
+				c.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:   testdatabase.GetResourcePath(mockSubID, "resourceName"),
+						Name: "resourceName",
+						Type: "Microsoft.RedHatOpenShift/openshiftClusters",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState:     api.ProvisioningStateDeleting,
+							LastProvisioningState: api.ProvisioningStateSucceeded,
+							SoftDeleteProfile: &api.SoftDeleteProfile{
+								GracePeriodHours: 24,
+							},
+							PendingDeletion: &api.PendingDeletion{
+								RequestTime: int(now.Unix()),
+								DeleteAfter: int(now.Add(24 * time.Hour).Unix()),
+							},
+						},
+					},
+				})
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantAsync:      true,
+		},
 		{
 			name:           "cluster not found in db",
 			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
@@ -114,11 +265,13 @@ func TestDeleteOpenShiftCluster(t *testing.T) {
 				ti.subscriptionsClient.SetError(tt.dbError)
 			}
 
-			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
 
+			f.now = func() time.Time { return now }
+
 			go f.Run(ctx, nil, nil)
 
 			resp, b, err := ti.request(http.MethodDelete,
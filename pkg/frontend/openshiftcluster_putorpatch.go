@@ -310,11 +310,20 @@ func setUpdateProvisioningState(doc *api.OpenShiftClusterDocument, apiVersion st
 	}
 }
 
+// resetDequeues resets the dequeue count of a document which is queued for a
+// new operation.  The dequeue count of a dead lettered document is kept, so
+// that it stays dead lettered until an SRE requeues it.
+func resetDequeues(doc *api.OpenShiftClusterDocument) {
+	if doc.DeadLetter == nil {
+		doc.Dequeues = 0
+	}
+}
+
 // Non-admin update (ex: customer cluster update)
 func updateProvisioningState(doc *api.OpenShiftClusterDocument) {
 	doc.OpenShiftCluster.Properties.LastProvisioningState = doc.OpenShiftCluster.Properties.ProvisioningState
 	doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateUpdating
-	doc.Dequeues = 0
+	resetDequeues(doc)
 }
 
 // Admin update (ex: cluster maintenance)
@@ -323,7 +332,7 @@ func adminUpdateProvisioningState(doc *api.OpenShiftClusterDocument) {
 		doc.OpenShiftCluster.Properties.LastProvisioningState = doc.OpenShiftCluster.Properties.ProvisioningState
 		doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateAdminUpdating
 		doc.OpenShiftCluster.Properties.LastAdminUpdateError = ""
-		doc.Dequeues = 0
+		resetDequeues(doc)
 
 		// Set the maintenance to ongoing so we emit the appropriate signal to customerss
 		if doc.OpenShiftCluster.Properties.MaintenanceState == api.MaintenanceStatePending {
@@ -0,0 +1,171 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+	"github.com/Azure/ARO-RP/pkg/mirror"
+	"github.com/Azure/ARO-RP/pkg/util/version"
+)
+
+func (f *frontend) getOpenShiftClusterUpgradePaths(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	resourceType := chi.URLParam(r, "resourceType")
+	resourceProviderNamespace := chi.URLParam(r, "resourceProviderNamespace")
+
+	apiVersion := r.URL.Query().Get(api.APIVersionKey)
+
+	if f.apis[apiVersion].OpenShiftClusterUpgradePathsConverter == nil {
+		api.WriteError(w, http.StatusBadRequest, api.CloudErrorCodeInvalidResourceType, "", "The resource type '%s' could not be found in the namespace '%s' for api version '%s'.", resourceType, resourceProviderNamespace, apiVersion)
+		return
+	}
+
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	b, err := f._getOpenShiftClusterUpgradePaths(ctx, log, r, f.apis[apiVersion].OpenShiftClusterUpgradePathsConverter)
+
+	reply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getOpenShiftClusterUpgradePaths(ctx context.Context, log *logrus.Entry, r *http.Request, converter api.OpenShiftClusterUpgradePathsConverter) ([]byte, error) {
+	resourceType := chi.URLParam(r, "resourceType")
+	resourceName := chi.URLParam(r, "resourceName")
+	resourceGroupName := chi.URLParam(r, "resourceGroupName")
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, r.URL.Path)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "", "The Resource '%s/%s' under resource group '%s' was not found.", resourceType, resourceName, resourceGroupName)
+	case err != nil:
+		return nil, err
+	}
+
+	current, err := version.ParseVersion(doc.OpenShiftCluster.Properties.ClusterProfile.Version)
+	if err != nil {
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeRequestNotAllowed, "", "Upgrade paths cannot be determined for cluster version '%s'.", doc.OpenShiftCluster.Properties.ClusterProfile.Version)
+	}
+
+	// patch upgrades are in the channel of the current minor version
+	graph, err := f.upgradeGraph(ctx, fmt.Sprintf("stable-%d.%d", current.V[0], current.V[1]))
+	if err != nil {
+		return nil, err
+	}
+	graphs := []*mirror.Graph{graph}
+
+	// minor upgrades are in the channel of the next one, which does not exist
+	// until the next minor version is released: without it, there are no
+	// minor upgrades
+	graph, err = f.upgradeGraph(ctx, fmt.Sprintf("stable-%d.%d", current.V[0], current.V[1]+1))
+	if err != nil {
+		log.Warnf("fetching the upgrade graph of the next minor version: %s", err)
+	} else {
+		graphs = append(graphs, graph)
+	}
+
+	f.mu.RLock()
+	paths := upgradePaths(current, graphs, f.blockedUpgradesFrom)
+	f.mu.RUnlock()
+
+	return json.MarshalIndent(converter.ToExternal(paths), "", "    ")
+}
+
+// upgradePaths returns the upgrades from the current version recommended by
+// the given upgrade graphs, noting those which the RP does not support and the
+// risks of those which are conditionally recommended
+func upgradePaths(current *version.Version, graphs []*mirror.Graph, blockedUpgradesFrom map[string][]string) *api.OpenShiftClusterUpgradePaths {
+	from := current.String()
+	paths := map[string]*api.UpgradePath{}
+
+	path := func(to string) *api.UpgradePath {
+		if _, found := paths[to]; !found {
+			paths[to] = &api.UpgradePath{
+				Version:   to,
+				Supported: true,
+			}
+
+			for _, blocked := range blockedUpgradesFrom[to] {
+				if blocked == from {
+					paths[to].Supported = false
+					paths[to].UnsupportedReason = fmt.Sprintf("Upgrading from %s to %s is not supported by Azure Red Hat OpenShift.", from, to)
+				}
+			}
+		}
+		return paths[to]
+	}
+
+	for _, graph := range graphs {
+		if graph == nil {
+			continue
+		}
+
+		for _, edge := range graph.Edges {
+			if edge[0] >= len(graph.Nodes) || edge[1] >= len(graph.Nodes) ||
+				graph.Nodes[edge[0]].Version != from {
+				continue
+			}
+			path(graph.Nodes[edge[1]].Version)
+		}
+
+		for _, conditionalEdge := range graph.ConditionalEdges {
+			for _, edge := range conditionalEdge.Edges {
+				if edge.From != from {
+					continue
+				}
+
+				p := path(edge.To)
+				for _, risk := range conditionalEdge.Risks {
+					if hasUpgradeRisk(p, risk.Name) {
+						continue
+					}
+					p.Risks = append(p.Risks, &api.UpgradeRisk{
+						Name:    risk.Name,
+						Message: risk.Message,
+						URL:     risk.URL,
+					})
+				}
+			}
+		}
+	}
+
+	out := &api.OpenShiftClusterUpgradePaths{
+		CurrentVersion: from,
+		UpgradePaths:   make([]*api.UpgradePath, 0, len(paths)),
+	}
+	for _, p := range paths {
+		out.UpgradePaths = append(out.UpgradePaths, p)
+	}
+
+	sort.Slice(out.UpgradePaths, func(i, j int) bool {
+		vi, erri := version.ParseVersion(out.UpgradePaths[i].Version)
+		vj, errj := version.ParseVersion(out.UpgradePaths[j].Version)
+		if erri != nil || errj != nil {
+			return out.UpgradePaths[i].Version < out.UpgradePaths[j].Version
+		}
+		return vi.Lt(vj)
+	})
+
+	return out
+}
+
+func hasUpgradeRisk(p *api.UpgradePath, name string) bool {
+	for _, risk := range p.Risks {
+		if risk.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	v20240812preview "github.com/Azure/ARO-RP/pkg/api/v20240812preview"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	"github.com/Azure/ARO-RP/pkg/mirror"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestGetOpenShiftClusterUpgradePaths(t *testing.T) {
+	ctx := context.Background()
+
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := fmt.Sprintf("/subscriptions/%s/resourcegroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/resourceName", mockSubID)
+
+	graphs := map[string]*mirror.Graph{
+		"stable-4.13": {
+			Nodes: []mirror.Node{
+				{Version: "4.13.19"},
+				{Version: "4.13.20"},
+				{Version: "4.13.21"},
+				{Version: "4.13.22"},
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+				{1, 3},
+				{2, 3},
+			},
+		},
+		"stable-4.14": {
+			Nodes: []mirror.Node{
+				{Version: "4.13.20"},
+				{Version: "4.14.1"},
+				{Version: "4.14.2"},
+			},
+			Edges: [][2]int{
+				{0, 1},
+			},
+			ConditionalEdges: []mirror.ConditionalEdge{
+				{
+					Edges: []mirror.UpgradeEdge{
+						{From: "4.13.20", To: "4.14.2"},
+						{From: "4.13.19", To: "4.14.2"},
+					},
+					Risks: []mirror.Risk{
+						{
+							Name:    "ExampleRisk",
+							Message: "Clusters with example configuration may fail to upgrade.",
+							URL:     "https://issues.redhat.com/browse/EXAMPLE-1",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	clusterDocument := func(version string) *api.OpenShiftClusterDocument {
+		return &api.OpenShiftClusterDocument{
+			Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID:   testdatabase.GetResourcePath(mockSubID, "resourceName"),
+				Name: "resourceName",
+				Type: "Microsoft.RedHatOpenShift/openshiftClusters",
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: api.ProvisioningStateSucceeded,
+					ClusterProfile: api.ClusterProfile{
+						Version: version,
+					},
+				},
+			},
+		}
+	}
+
+	type test struct {
+		name           string
+		apiVersion     string
+		fixture        func(*testdatabase.Fixture)
+		graphErrs      map[string]error
+		wantStatusCode int
+		wantResponse   *v20240812preview.OpenShiftClusterUpgradePaths
+		wantError      string
+	}
+
+	for _, tt := range []*test{
+		{
+			name: "upgrade paths are listed with blocked edges and risks",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument("4.13.20"))
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &v20240812preview.OpenShiftClusterUpgradePaths{
+				CurrentVersion: "4.13.20",
+				UpgradePaths: []v20240812preview.UpgradePath{
+					{
+						Version:   "4.13.21",
+						Supported: true,
+					},
+					{
+						Version:           "4.13.22",
+						Supported:         false,
+						UnsupportedReason: "Upgrading from 4.13.20 to 4.13.22 is not supported by Azure Red Hat OpenShift.",
+					},
+					{
+						Version:   "4.14.1",
+						Supported: true,
+					},
+					{
+						Version:   "4.14.2",
+						Supported: true,
+						Risks: []v20240812preview.UpgradeRisk{
+							{
+								Name:    "ExampleRisk",
+								Message: "Clusters with example configuration may fail to upgrade.",
+								URL:     "https://issues.redhat.com/browse/EXAMPLE-1",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "cluster on the latest version has no upgrade paths",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument("4.14.2"))
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &v20240812preview.OpenShiftClusterUpgradePaths{
+				CurrentVersion: "4.14.2",
+			},
+		},
+		{
+			name: "cluster without a version",
+			fixture: func(f *testdatabase.Fixture) {
+				doc := clusterDocument("")
+				doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateCreating
+				f.AddOpenShiftClusterDocuments(doc)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: RequestNotAllowed: : Upgrade paths cannot be determined for cluster version ''.",
+		},
+		{
+			name: "next minor upgrade graph cannot be fetched",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument("4.13.20"))
+			},
+			graphErrs: map[string]error{
+				"stable-4.14": errors.New("random error"),
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &v20240812preview.OpenShiftClusterUpgradePaths{
+				CurrentVersion: "4.13.20",
+				UpgradePaths: []v20240812preview.UpgradePath{
+					{
+						Version:   "4.13.21",
+						Supported: true,
+					},
+					{
+						Version:           "4.13.22",
+						Supported:         false,
+						UnsupportedReason: "Upgrading from 4.13.20 to 4.13.22 is not supported by Azure Red Hat OpenShift.",
+					},
+				},
+			},
+		},
+		{
+			name: "upgrade graph cannot be fetched",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDocument("4.13.20"))
+			},
+			graphErrs: map[string]error{
+				"stable-4.13": errors.New("random error"),
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantError:      "500: InternalServerError: : Internal server error.",
+		},
+		{
+			name:           "cluster not found",
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+		{
+			name:           "api does not support upgrade paths",
+			apiVersion:     "2020-04-30",
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidResourceType: : The resource type 'openshiftclusters' could not be found in the namespace 'microsoft.redhatopenshift' for api version '2020-04-30'.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f.upgradeGraph = func(ctx context.Context, channel string) (*mirror.Graph, error) {
+				if err := tt.graphErrs[channel]; err != nil {
+					return nil, err
+				}
+				return graphs[channel], nil
+			}
+
+			f.updateOcpVersions([]*api.OpenShiftVersionDocument{
+				{
+					OpenShiftVersion: &api.OpenShiftVersion{
+						Properties: api.OpenShiftVersionProperties{
+							Version:             "4.13.22",
+							BlockedUpgradesFrom: []string{"4.13.20"},
+						},
+					},
+				},
+			})
+
+			go f.Run(ctx, nil, nil)
+
+			apiVersion := v20240812preview.APIVersion
+			if tt.apiVersion != "" {
+				apiVersion = tt.apiVersion
+			}
+
+			resp, b, err := ti.request(http.MethodGet,
+				fmt.Sprintf("https://server%s/upgradepaths?api-version=%s", resourceID, apiVersion),
+				nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var wantResponse interface{}
+			if tt.wantResponse != nil {
+				wantResponse = tt.wantResponse
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
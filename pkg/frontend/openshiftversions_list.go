@@ -25,7 +25,7 @@ func (f *frontend) listInstallVersions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	versions := f.getEnabledInstallVersions(ctx)
+	versions := f.getInstallableVersions(ctx, chi.URLParam(r, "location"))
 	converter := f.apis[apiVersion].OpenShiftVersionConverter
 
 	b, err := json.MarshalIndent(converter.ToExternalList(versions), "", "    ")
@@ -43,3 +43,18 @@ func (f *frontend) getEnabledInstallVersions(ctx context.Context) []*api.OpenShi
 
 	return versions
 }
+
+// getInstallableVersions returns the enabled versions which are available for
+// new installs in the given location
+func (f *frontend) getInstallableVersions(ctx context.Context, location string) []*api.OpenShiftVersion {
+	now := f.now()
+	versions := make([]*api.OpenShiftVersion, 0)
+
+	for _, v := range f.getEnabledInstallVersions(ctx) {
+		if validateInstallVersionLifecycle(v, location, now) == nil {
+			versions = append(versions, v)
+		}
+	}
+
+	return versions
+}
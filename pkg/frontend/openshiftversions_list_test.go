@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/coreos/go-semver/semver"
 
@@ -23,6 +24,9 @@ func TestListInstallVersions(t *testing.T) {
 	method := http.MethodGet
 	ctx := context.Background()
 
+	past := time.Now().Add(-24 * time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+
 	type test struct {
 		name           string
 		changeFeed     map[string]*api.OpenShiftVersion
@@ -67,6 +71,59 @@ func TestListInstallVersions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "versions not available for new installs are not returned",
+			changeFeed: map[string]*api.OpenShiftVersion{
+				"4.11.0": {
+					Properties: api.OpenShiftVersionProperties{
+						Version:                  "4.11.0",
+						Enabled:                  true,
+						DeprecatedForNewInstalls: true,
+					},
+				},
+				"4.11.1": {
+					Properties: api.OpenShiftVersionProperties{
+						Version:      "4.11.1",
+						Enabled:      true,
+						EndOfSupport: &past,
+					},
+				},
+				"4.11.2": {
+					Properties: api.OpenShiftVersionProperties{
+						Version:             "4.11.2",
+						Enabled:             true,
+						GeneralAvailability: &future,
+					},
+				},
+				"4.11.3": {
+					Properties: api.OpenShiftVersionProperties{
+						Version:        "4.11.3",
+						Enabled:        true,
+						AllowedRegions: []string{"otherlocation"},
+					},
+				},
+				"4.11.4": {
+					Properties: api.OpenShiftVersionProperties{
+						Version:             "4.11.4",
+						Enabled:             true,
+						GeneralAvailability: &past,
+						EndOfSupport:        &future,
+						AllowedRegions:      []string{"otherlocation", "eastus"},
+					},
+				},
+			},
+			apiVersion:     "2022-09-04",
+			wantStatusCode: http.StatusOK,
+			wantResponse: v20220904.OpenShiftVersionList{
+				OpenShiftVersions: []*v20220904.OpenShiftVersion{
+					{
+						Properties: v20220904.OpenShiftVersionProperties{
+							Version: "4.11.4",
+						},
+					},
+				},
+			},
+		},
 		{
 			name:           "api does not exist",
 			apiVersion:     "invalid",
@@ -78,7 +135,7 @@ func TestListInstallVersions(t *testing.T) {
 			ti := newTestInfra(t).WithSubscriptions().WithOpenShiftVersions()
 			defer ti.done()
 
-			frontend, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, nil, nil, nil, nil, ti.openShiftVersionsDatabase, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			frontend, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, nil, nil, nil, nil, ti.openShiftVersionsDatabase, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -30,9 +30,21 @@ func (f *frontend) checkReady() bool {
 }
 
 func (f *frontend) getReady(w http.ResponseWriter, r *http.Request) {
-	if f.checkReady() {
+	if _, ok := f.drained.Load().(func() bool); ok {
+		api.WriteError(w, http.StatusServiceUnavailable, api.CloudErrorCodeInternalServerError, "", "Draining.")
+	} else if f.checkReady() {
 		api.WriteCloudError(w, &api.CloudError{StatusCode: http.StatusOK})
 	} else {
 		api.WriteError(w, http.StatusInternalServerError, api.CloudErrorCodeInternalServerError, "", "Internal server error.")
 	}
 }
+
+// getDrained returns 200 once the RP is draining and its backend has finished
+// its work, after which it is safe to stop the RP
+func (f *frontend) getDrained(w http.ResponseWriter, r *http.Request) {
+	if drained, ok := f.drained.Load().(func() bool); ok && drained() {
+		api.WriteCloudError(w, &api.CloudError{StatusCode: http.StatusOK})
+	} else {
+		api.WriteError(w, http.StatusServiceUnavailable, api.CloudErrorCodeInternalServerError, "", "Not drained.")
+	}
+}
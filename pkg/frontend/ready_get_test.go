@@ -0,0 +1,67 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestDrain(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		drain           bool
+		drained         bool
+		wantReadyCode   int
+		wantDrainedCode int
+	}{
+		{
+			name:            "not draining",
+			wantDrainedCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:            "draining",
+			drain:           true,
+			wantReadyCode:   http.StatusServiceUnavailable,
+			wantDrainedCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:            "drained",
+			drain:           true,
+			drained:         true,
+			wantReadyCode:   http.StatusServiceUnavailable,
+			wantDrainedCode: http.StatusOK,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &frontend{
+				baseLog: logrus.NewEntry(logrus.StandardLogger()),
+			}
+			f.ready.Store(true)
+
+			if tt.drain {
+				f.Drain(func() bool { return tt.drained })
+
+				if f.ready.Load().(bool) {
+					t.Error("still ready")
+				}
+
+				w := httptest.NewRecorder()
+				f.getReady(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+				if w.Code != tt.wantReadyCode {
+					t.Errorf("got ready status code %d", w.Code)
+				}
+			}
+
+			w := httptest.NewRecorder()
+			f.getDrained(w, httptest.NewRequest(http.MethodGet, "/healthz/drained", nil))
+			if w.Code != tt.wantDrainedCode {
+				t.Errorf("got drained status code %d", w.Code)
+			}
+		})
+	}
+}
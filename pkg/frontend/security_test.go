@@ -77,7 +77,7 @@ func TestSecurity(t *testing.T) {
 
 	log := logrus.NewEntry(logrus.StandardLogger())
 	auditHook, auditEntry := testlog.NewAudit()
-	f, err := NewFrontend(ctx, auditEntry, log, _env, nil, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+	f, err := NewFrontend(ctx, auditEntry, log, _env, nil, nil, nil, nil, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -189,6 +189,11 @@ func TestSecurity(t *testing.T) {
 			url:            "https://server/healthz/ready",
 			wantStatusCode: http.StatusOK,
 		},
+		{
+			name:           "drained url, no client certificate",
+			url:            "https://server/healthz/drained",
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
 		{
 			name:           "empty url, invalid certificate",
 			url:            "https://server/",
@@ -80,10 +80,29 @@ type testInfra struct {
 	subscriptionsDatabase     database.Subscriptions
 	openShiftVersionsClient   *cosmosdb.FakeOpenShiftVersionDocumentClient
 	openShiftVersionsDatabase database.OpenShiftVersions
+	clusterAlertsClient       *cosmosdb.FakeClusterAlertDocumentClient
+	clusterAlertsDatabase     database.ClusterAlerts
+	pendingActionsClient      *cosmosdb.FakePendingActionDocumentClient
+	pendingActionsDatabase    database.PendingActions
+	adminAuditLogClient       *cosmosdb.FakeAdminAuditEntryDocumentClient
+	adminAuditLogDatabase     database.AdminAuditLog
 }
 
 func newTestInfra(t *testing.T) *testInfra {
-	return newTestInfraWithFeatures(t, map[env.Feature]bool{env.FeatureRequireD2sV3Workers: false, env.FeatureDisableReadinessDelay: false, env.FeatureEnableOCMEndpoints: false})
+	return newTestInfraWithFeatures(t, defaultTestFeatures())
+}
+
+func defaultTestFeatures() map[env.Feature]bool {
+	return map[env.Feature]bool{
+		env.FeatureRequireD2sV3Workers:                    false,
+		env.FeatureDisableReadinessDelay:                  false,
+		env.FeatureEnableOCMEndpoints:                     false,
+		env.FeatureRequireApprovalDeleteManagedResource:   false,
+		env.FeatureRequireApprovalEtcdRecovery:            false,
+		env.FeatureRequireApprovalKubernetesObjectsDelete: false,
+		env.FeatureRequireApprovalRedeployVM:              false,
+		env.FeatureRequireApprovalStopVM:                  false,
+	}
 }
 
 func newTestInfraWithFeatures(t *testing.T, features map[env.Feature]bool) *testInfra {
@@ -185,6 +204,23 @@ func (ti *testInfra) WithClusterManagerConfigurations() *testInfra {
 	return ti
 }
 
+func (ti *testInfra) WithClusterAlerts() *testInfra {
+	ti.clusterAlertsDatabase, ti.clusterAlertsClient = testdatabase.NewFakeClusterAlerts()
+	return ti
+}
+
+func (ti *testInfra) WithPendingActions() *testInfra {
+	ti.pendingActionsDatabase, ti.pendingActionsClient = testdatabase.NewFakePendingActions()
+	ti.fixture.WithPendingActions(ti.pendingActionsDatabase)
+	return ti
+}
+
+func (ti *testInfra) WithAdminAuditLog() *testInfra {
+	ti.adminAuditLogDatabase, ti.adminAuditLogClient = testdatabase.NewFakeAdminAuditLog()
+	ti.fixture.WithAdminAuditLog(ti.adminAuditLogDatabase)
+	return ti
+}
+
 func (ti *testInfra) done() {
 	ti.controller.Finish()
 	ti.cli.CloseIdleConnections()
@@ -0,0 +1,72 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/Azure/ARO-RP/pkg/mirror"
+)
+
+const upgradeGraphCacheTTL = 10 * time.Minute
+
+type cachedUpgradeGraph struct {
+	graph   *mirror.Graph
+	fetched time.Time
+}
+
+// upgradeGraphCache caches the upgrade graphs fetched from the OpenShift
+// update service, which change rarely, so that each request for a cluster's
+// upgrade paths does not result in a call to the service.  Concurrent
+// requests for a graph which is not cached share a single fetch.
+type upgradeGraphCache struct {
+	mu     sync.Mutex
+	fetch  func(context.Context, string) (*mirror.Graph, error)
+	now    func() time.Time
+	graphs map[string]cachedUpgradeGraph
+	group  singleflight.Group
+}
+
+func newUpgradeGraphCache(fetch func(context.Context, string) (*mirror.Graph, error)) *upgradeGraphCache {
+	return &upgradeGraphCache{
+		fetch:  fetch,
+		now:    time.Now,
+		graphs: map[string]cachedUpgradeGraph{},
+	}
+}
+
+func (c *upgradeGraphCache) get(ctx context.Context, channel string) (*mirror.Graph, error) {
+	c.mu.Lock()
+	cached, found := c.graphs[channel]
+	c.mu.Unlock()
+
+	if found && c.now().Sub(cached.fetched) < upgradeGraphCacheTTL {
+		return cached.graph, nil
+	}
+
+	graph, err, _ := c.group.Do(channel, func() (interface{}, error) {
+		graph, err := c.fetch(ctx, channel)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.graphs[channel] = cachedUpgradeGraph{
+			graph:   graph,
+			fetched: c.now(),
+		}
+		c.mu.Unlock()
+
+		return graph, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return graph.(*mirror.Graph), nil
+}
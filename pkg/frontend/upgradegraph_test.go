@@ -0,0 +1,123 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/mirror"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestUpgradeGraphCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1672574400, 0)
+
+	var fetches int
+	var fetchErr error
+	c := newUpgradeGraphCache(func(ctx context.Context, channel string) (*mirror.Graph, error) {
+		fetches++
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return &mirror.Graph{Nodes: []mirror.Node{{Version: channel}}}, nil
+	})
+	c.now = func() time.Time { return now }
+
+	for _, step := range []struct {
+		name        string
+		advance     time.Duration
+		channel     string
+		fetchErr    error
+		wantFetches int
+		wantErr     string
+	}{
+		{
+			name:        "first request fetches the graph",
+			channel:     "stable-4.14",
+			wantFetches: 1,
+		},
+		{
+			name:        "request within the TTL is served from the cache",
+			advance:     upgradeGraphCacheTTL - time.Second,
+			channel:     "stable-4.14",
+			wantFetches: 1,
+		},
+		{
+			name:        "other channels are fetched separately",
+			channel:     "stable-4.15",
+			wantFetches: 2,
+		},
+		{
+			name:        "request after the TTL fetches the graph again",
+			advance:     time.Second,
+			channel:     "stable-4.14",
+			wantFetches: 3,
+		},
+		{
+			name:        "fetch errors are returned and not cached",
+			advance:     upgradeGraphCacheTTL,
+			channel:     "stable-4.14",
+			fetchErr:    errors.New("random error"),
+			wantFetches: 4,
+			wantErr:     "random error",
+		},
+	} {
+		now = now.Add(step.advance)
+		fetchErr = step.fetchErr
+
+		graph, err := c.get(ctx, step.channel)
+		utilerror.AssertErrorMessage(t, err, step.wantErr)
+
+		if err == nil && graph.Nodes[0].Version != step.channel {
+			t.Errorf("%s: got graph for %s", step.name, graph.Nodes[0].Version)
+		}
+		if fetches != step.wantFetches {
+			t.Errorf("%s: got %d fetches, wanted %d", step.name, fetches, step.wantFetches)
+		}
+	}
+}
+
+func TestUpgradeGraphCacheConcurrentFetch(t *testing.T) {
+	ctx := context.Background()
+
+	var fetches int32
+	release := make(chan struct{})
+	c := newUpgradeGraphCache(func(ctx context.Context, channel string) (*mirror.Graph, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return &mirror.Graph{Nodes: []mirror.Node{{Version: channel}}}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			graph, err := c.get(ctx, "stable-4.14")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if graph.Nodes[0].Version != "stable-4.14" {
+				t.Errorf("got graph for %s", graph.Nodes[0].Version)
+			}
+		}()
+	}
+
+	// give the requests time to queue behind the first fetch
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Errorf("got %d fetches, wanted 1", fetches)
+	}
+}
@@ -5,9 +5,11 @@ package frontend
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/azure"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -200,6 +202,22 @@ func validateAdminMasterVMSize(vmSize string) error {
 	return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided vmSize '%s' is unsupported for master.", vmSize)
 }
 
+func validateAdminWorkerVMSize(vmSize string, requireD2sV3Workers bool) error {
+	if !validate.VMSizeIsValid(api.VMSize(vmSize), requireD2sV3Workers, false) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided vmSize '%s' is unsupported for workers.", vmSize)
+	}
+
+	return nil
+}
+
+func validateAdminMachineSetName(machineSet string) error {
+	if machineSet == "" || !rxKubernetesString.MatchString(machineSet) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided machineSet '%s' is invalid.", machineSet)
+	}
+
+	return nil
+}
+
 // validateInstallVersion validates the install version set in the clusterprofile.version
 // TODO convert this into static validation instead of this receiver function in the validation for frontend.
 func (f *frontend) validateInstallVersion(ctx context.Context, oc *api.OpenShiftCluster) error {
@@ -209,12 +227,73 @@ func (f *frontend) validateInstallVersion(ctx context.Context, oc *api.OpenShift
 	if oc.Properties.ClusterProfile.Version == "" {
 		oc.Properties.ClusterProfile.Version = f.defaultOcpVersion
 	}
-	_, ok := f.enabledOcpVersions[oc.Properties.ClusterProfile.Version]
+	v, ok := f.enabledOcpVersions[oc.Properties.ClusterProfile.Version]
 	f.mu.RUnlock()
 
 	if !ok || !validate.RxInstallVersion.MatchString(oc.Properties.ClusterProfile.Version) {
 		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.clusterProfile.version", "The requested OpenShift version '%s' is invalid.", oc.Properties.ClusterProfile.Version)
 	}
 
+	err := validateInstallVersionLifecycle(v, oc.Location, f.now())
+	if err != nil {
+		return err
+	}
+
+	err = validateInstallVMSize(oc.Properties.MasterProfile.VMSize, v.Properties.MinimumMasterVMSize, v.Properties.Version, "properties.masterProfile.vmSize")
+	if err != nil {
+		return err
+	}
+
+	for i, wp := range oc.Properties.WorkerProfiles {
+		err = validateInstallVMSize(wp.VMSize, v.Properties.MinimumWorkerVMSize, v.Properties.Version, fmt.Sprintf("properties.workerProfiles[%d].vmSize", i))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// validateInstallVersionLifecycle validates that the given version is
+// available for new installs in the given location at the given time
+func validateInstallVersionLifecycle(v *api.OpenShiftVersion, location string, now time.Time) error {
+	switch {
+	case v.Properties.DeprecatedForNewInstalls,
+		v.Properties.GeneralAvailability != nil && now.Before(*v.Properties.GeneralAvailability),
+		v.Properties.EndOfSupport != nil && !now.Before(*v.Properties.EndOfSupport):
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.clusterProfile.version", "The requested OpenShift version '%s' is not available for new installs.", v.Properties.Version)
+	}
+
+	if len(v.Properties.AllowedRegions) == 0 {
+		return nil
+	}
+
+	for _, region := range v.Properties.AllowedRegions {
+		if strings.EqualFold(region, location) {
+			return nil
+		}
+	}
+
+	return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.clusterProfile.version", "The requested OpenShift version '%s' is not available for new installs in location '%s'.", v.Properties.Version, location)
+}
+
+// validateInstallVMSize validates that the given VM size has at least as many
+// cores as the minimum VM size required by the install version
+func validateInstallVMSize(vmSize, minimum api.VMSize, version, path string) error {
+	if minimum == "" {
+		return nil
+	}
+
+	size, ok := validate.VMSizeFromName(vmSize)
+	if !ok {
+		// unsupported sizes are rejected by static validation
+		return nil
+	}
+
+	minimumSize, ok := validate.VMSizeFromName(minimum)
+	if !ok || size.CoreCount >= minimumSize.CoreCount {
+		return nil
+	}
+
+	return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path, "The provided vmSize '%s' is smaller than the minimum vmSize '%s' required by OpenShift version '%s'.", vmSize, minimum, version)
+}
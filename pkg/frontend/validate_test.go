@@ -4,12 +4,15 @@ package frontend
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"github.com/Azure/ARO-RP/pkg/api"
 	utilerror "github.com/Azure/ARO-RP/test/util/error"
 )
 
@@ -265,3 +268,115 @@ func TestValidateAdminMasterVMSize(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateInstallVersion(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	for _, tt := range []struct {
+		test    string
+		version api.OpenShiftVersionProperties
+		modify  func(*api.OpenShiftCluster)
+		wantErr string
+	}{
+		{
+			test: "version without lifecycle metadata is valid",
+		},
+		{
+			test: "version within its lifecycle is valid",
+			version: api.OpenShiftVersionProperties{
+				GeneralAvailability: &before,
+				EndOfSupport:        &after,
+				AllowedRegions:      []string{"EastUS"},
+				MinimumMasterVMSize: api.VMSizeStandardD8sV3,
+				MinimumWorkerVMSize: api.VMSizeStandardD4sV3,
+			},
+		},
+		{
+			test:    "unknown version is invalid",
+			modify:  func(oc *api.OpenShiftCluster) { oc.Properties.ClusterProfile.Version = "4.11.1" },
+			wantErr: "400: InvalidParameter: properties.clusterProfile.version: The requested OpenShift version '4.11.1' is invalid.",
+		},
+		{
+			test: "version deprecated for new installs is invalid",
+			version: api.OpenShiftVersionProperties{
+				DeprecatedForNewInstalls: true,
+			},
+			wantErr: "400: InvalidParameter: properties.clusterProfile.version: The requested OpenShift version '4.11.0' is not available for new installs.",
+		},
+		{
+			test: "version before general availability is invalid",
+			version: api.OpenShiftVersionProperties{
+				GeneralAvailability: &after,
+			},
+			wantErr: "400: InvalidParameter: properties.clusterProfile.version: The requested OpenShift version '4.11.0' is not available for new installs.",
+		},
+		{
+			test: "version at end of support is invalid",
+			version: api.OpenShiftVersionProperties{
+				EndOfSupport: &now,
+			},
+			wantErr: "400: InvalidParameter: properties.clusterProfile.version: The requested OpenShift version '4.11.0' is not available for new installs.",
+		},
+		{
+			test: "version not allowed in the location is invalid",
+			version: api.OpenShiftVersionProperties{
+				AllowedRegions: []string{"westeurope"},
+			},
+			wantErr: "400: InvalidParameter: properties.clusterProfile.version: The requested OpenShift version '4.11.0' is not available for new installs in location 'eastus'.",
+		},
+		{
+			test: "master smaller than the minimum is invalid",
+			version: api.OpenShiftVersionProperties{
+				MinimumMasterVMSize: api.VMSizeStandardD16sV3,
+			},
+			wantErr: "400: InvalidParameter: properties.masterProfile.vmSize: The provided vmSize 'Standard_D8s_v3' is smaller than the minimum vmSize 'Standard_D16s_v3' required by OpenShift version '4.11.0'.",
+		},
+		{
+			test: "worker smaller than the minimum is invalid",
+			version: api.OpenShiftVersionProperties{
+				MinimumWorkerVMSize: api.VMSizeStandardD8sV3,
+			},
+			wantErr: "400: InvalidParameter: properties.workerProfiles[1].vmSize: The provided vmSize 'Standard_D4s_v3' is smaller than the minimum vmSize 'Standard_D8s_v3' required by OpenShift version '4.11.0'.",
+		},
+	} {
+		t.Run(tt.test, func(t *testing.T) {
+			v := &api.OpenShiftVersion{Properties: tt.version}
+			v.Properties.Version = "4.11.0"
+			v.Properties.Enabled = true
+
+			f := &frontend{
+				enabledOcpVersions: map[string]*api.OpenShiftVersion{
+					v.Properties.Version: v,
+				},
+				defaultOcpVersion: v.Properties.Version,
+				now:               func() time.Time { return now },
+			}
+
+			oc := &api.OpenShiftCluster{
+				Location: "eastus",
+				Properties: api.OpenShiftClusterProperties{
+					MasterProfile: api.MasterProfile{
+						VMSize: api.VMSizeStandardD8sV3,
+					},
+					WorkerProfiles: []api.WorkerProfile{
+						{
+							VMSize: api.VMSizeStandardD8sV3,
+						},
+						{
+							VMSize: api.VMSizeStandardD4sV3,
+						},
+					},
+				},
+			}
+			if tt.modify != nil {
+				tt.modify(oc)
+			}
+
+			err := f.validateInstallVersion(ctx, oc)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+		})
+	}
+}
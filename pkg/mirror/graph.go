@@ -4,11 +4,14 @@ package mirror
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"mime"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/Azure/ARO-RP/pkg/util/version"
 )
@@ -19,6 +22,71 @@ type Node struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// Graph is a Cincinnati upgrade graph.  Edges index into Nodes.
+type Graph struct {
+	Nodes            []Node            `json:"nodes,omitempty"`
+	Edges            [][2]int          `json:"edges,omitempty"`
+	ConditionalEdges []ConditionalEdge `json:"conditionalEdges,omitempty"`
+}
+
+// ConditionalEdge is a set of upgrades which are only recommended if the
+// clusters being upgraded are not exposed to the given risks
+type ConditionalEdge struct {
+	Edges []UpgradeEdge `json:"edges,omitempty"`
+	Risks []Risk        `json:"risks,omitempty"`
+}
+
+// UpgradeEdge is an upgrade from one version to another
+type UpgradeEdge struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Risk is a known issue which may affect an upgrade
+type Risk struct {
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// upgradeGraphClient is the client used to call the OpenShift update
+// service, which is called while serving requests and must not hold them
+// indefinitely
+var upgradeGraphClient = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+// UpgradeGraph fetches the upgrade graph of the given channel (e.g.
+// stable-4.14) from the OpenShift update service
+func UpgradeGraph(ctx context.Context, channel string) (*Graph, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.openshift.com/api/upgrades_info/v1/graph?"+url.Values{
+		"channel": []string{channel},
+		"arch":    []string{"amd64"},
+	}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := upgradeGraphClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var g *Graph
+	err = json.NewDecoder(resp.Body).Decode(&g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
 // AddFromGraph adds all nodes whose version is of the form x.y.z (no suffix)
 // and >= min
 func AddFromGraph(min *version.Version) ([]Node, error) {
@@ -46,10 +114,7 @@ func AddFromGraph(min *version.Version) ([]Node, error) {
 		return nil, fmt.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
 	}
 
-	var g *struct {
-		Nodes []Node   `json:"nodes,omitempty"`
-		Edges [][2]int `json:"edges,omitempty"`
-	}
+	var g *Graph
 
 	err = json.NewDecoder(resp.Body).Decode(&g)
 	if err != nil {
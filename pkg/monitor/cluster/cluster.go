@@ -7,6 +7,7 @@ import (
 	"context"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/azure"
 	configv1 "github.com/openshift/api/config/v1"
@@ -40,6 +41,9 @@ type Monitor struct {
 	oc   *api.OpenShiftCluster
 	dims map[string]string
 
+	// endOfSupport maps minor versions to their end of support dates
+	endOfSupport map[string]time.Time
+
 	restconfig *rest.Config
 	cli        kubernetes.Interface
 	configcli  configclient.Interface
@@ -63,7 +67,7 @@ type Monitor struct {
 	wg *sync.WaitGroup
 }
 
-func NewMonitor(log *logrus.Entry, restConfig *rest.Config, oc *api.OpenShiftCluster, m metrics.Emitter, hiveRestConfig *rest.Config, hourlyRun bool, wg *sync.WaitGroup) (*Monitor, error) {
+func NewMonitor(log *logrus.Entry, restConfig *rest.Config, oc *api.OpenShiftCluster, m metrics.Emitter, hiveRestConfig *rest.Config, endOfSupport map[string]time.Time, hourlyRun bool, wg *sync.WaitGroup) (*Monitor, error) {
 	r, err := azure.ParseResourceID(oc.ID)
 	if err != nil {
 		return nil, err
@@ -126,6 +130,8 @@ func NewMonitor(log *logrus.Entry, restConfig *rest.Config, oc *api.OpenShiftClu
 		oc:   oc,
 		dims: dims,
 
+		endOfSupport: endOfSupport,
+
 		restconfig:    restConfig,
 		cli:           cli,
 		configcli:     configcli,
@@ -195,10 +201,12 @@ func (mon *Monitor) Monitor(ctx context.Context) (errs []error) {
 		mon.emitClusterOperatorVersions,
 		mon.emitClusterVersionConditions,
 		mon.emitClusterVersions,
+		mon.emitClusterVersionEndOfSupport,
 		mon.emitDaemonsetStatuses,
 		mon.emitDeploymentStatuses,
 		mon.emitMachineConfigPoolConditions,
 		mon.emitMachineConfigPoolUnmanagedNodeCounts,
+		mon.emitMachineHealthChecks,
 		mon.emitNodeConditions,
 		mon.emitPodConditions,
 		mon.emitDebugPodsCount,
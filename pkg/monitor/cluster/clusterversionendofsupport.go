@@ -0,0 +1,45 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/util/version"
+)
+
+// emitClusterVersionEndOfSupport reports the number of days until the minor
+// version the cluster is running goes out of support, if its end of support
+// date is known
+func (mon *Monitor) emitClusterVersionEndOfSupport(ctx context.Context) error {
+	cv, err := mon.getClusterVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	actualVersion := actualVersion(cv)
+	if actualVersion == "" {
+		return nil
+	}
+
+	v, err := version.ParseVersion(actualVersion)
+	if err != nil {
+		return err
+	}
+
+	endOfSupport, found := mon.endOfSupport[v.MinorVersion()]
+	if !found {
+		return nil
+	}
+
+	daysUntilEndOfSupport := time.Until(endOfSupport) / (24 * time.Hour)
+	mon.emitGauge("cluster.version.endofsupport.days", int64(daysUntilEndOfSupport), map[string]string{
+		"actualVersion":      actualVersion,
+		"actualMinorVersion": v.MinorVersion(),
+		"endOfSupport":       endOfSupport.UTC().Format(time.RFC3339),
+	})
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+)
+
+func TestEmitClusterVersionEndOfSupport(t *testing.T) {
+	ctx := context.Background()
+
+	inTenDays := time.Now().Add(10*24*time.Hour + time.Hour)
+	threeDaysAgo := time.Now().Add(-3*24*time.Hour - time.Hour)
+
+	for _, tt := range []struct {
+		name              string
+		history           []configv1.UpdateHistory
+		endOfSupport      map[string]time.Time
+		wantActualVersion string
+		wantMinorVersion  string
+		wantDays          int64
+	}{
+		{
+			name: "days until end of support are emitted",
+			history: []configv1.UpdateHistory{
+				{
+					State:   configv1.PartialUpdate,
+					Version: "4.12.1",
+				},
+				{
+					State:   configv1.CompletedUpdate,
+					Version: "4.11.5",
+				},
+			},
+			endOfSupport: map[string]time.Time{
+				"4.11": inTenDays,
+				"4.12": threeDaysAgo,
+			},
+			wantActualVersion: "4.11.5",
+			wantMinorVersion:  "4.11",
+			wantDays:          10,
+		},
+		{
+			name: "days since end of support are emitted as negative",
+			history: []configv1.UpdateHistory{
+				{
+					State:   configv1.CompletedUpdate,
+					Version: "4.12.1",
+				},
+			},
+			endOfSupport: map[string]time.Time{
+				"4.11": inTenDays,
+				"4.12": threeDaysAgo,
+			},
+			wantActualVersion: "4.12.1",
+			wantMinorVersion:  "4.12",
+			wantDays:          -3,
+		},
+		{
+			name: "nothing is emitted without an end of support date",
+			history: []configv1.UpdateHistory{
+				{
+					State:   configv1.CompletedUpdate,
+					Version: "4.13.0",
+				},
+			},
+			endOfSupport: map[string]time.Time{
+				"4.11": inTenDays,
+			},
+		},
+		{
+			name: "nothing is emitted before the cluster is installed",
+			history: []configv1.UpdateHistory{
+				{
+					State:   configv1.PartialUpdate,
+					Version: "4.11.5",
+				},
+			},
+			endOfSupport: map[string]time.Time{
+				"4.11": inTenDays,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			configcli := configfake.NewSimpleClientset(&configv1.ClusterVersion{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "version",
+				},
+				Status: configv1.ClusterVersionStatus{
+					History: tt.history,
+				},
+			})
+
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			m := mock_metrics.NewMockEmitter(controller)
+
+			mon := &Monitor{
+				configcli:    configcli,
+				m:            m,
+				endOfSupport: tt.endOfSupport,
+			}
+
+			if tt.wantActualVersion != "" {
+				m.EXPECT().EmitGauge("cluster.version.endofsupport.days", tt.wantDays, map[string]string{
+					"actualVersion":      tt.wantActualVersion,
+					"actualMinorVersion": tt.wantMinorVersion,
+					"endOfSupport":       tt.endOfSupport[tt.wantMinorVersion].UTC().Format(time.RFC3339),
+				})
+			}
+
+			err := mon.emitClusterVersionEndOfSupport(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
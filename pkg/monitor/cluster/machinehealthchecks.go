@@ -0,0 +1,82 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	machineAPINamespace = "openshift-machine-api"
+	mhcPausedAnnotation = "cluster.x-k8s.io/paused"
+)
+
+// mhcRemediationEventReasons are the reasons of the events recorded by the
+// machine-api MachineHealthCheck controller when it remediates, or refuses to
+// remediate, a machine
+var mhcRemediationEventReasons = map[string]bool{
+	"DetectedUnhealthy":     true,
+	"MachineDeleted":        true,
+	"RemediationRestricted": true,
+}
+
+func (mon *Monitor) emitMachineHealthChecks(ctx context.Context) error {
+	mhcs, err := mon.maocli.MachineV1beta1().MachineHealthChecks(machineAPINamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, mhc := range mhcs.Items {
+		var expected, healthy, allowed int64
+		if mhc.Status.ExpectedMachines != nil {
+			expected = int64(*mhc.Status.ExpectedMachines)
+		}
+		if mhc.Status.CurrentHealthy != nil {
+			healthy = int64(*mhc.Status.CurrentHealthy)
+		}
+		allowed = int64(mhc.Status.RemediationsAllowed)
+
+		_, paused := mhc.Annotations[mhcPausedAnnotation]
+
+		mon.emitGauge("machinehealthcheck.unhealthy", expected-healthy, map[string]string{
+			"name":                mhc.Name,
+			"expectedMachines":    strconv.FormatInt(expected, 10),
+			"remediationsAllowed": strconv.FormatInt(allowed, 10),
+			"paused":              strconv.FormatBool(paused),
+		})
+	}
+
+	events, err := mon.cli.CoreV1().Events(machineAPINamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	remediations := map[string]int64{}
+	for _, event := range events.Items {
+		if !mhcRemediationEventReasons[event.Reason] {
+			continue
+		}
+
+		count := int64(event.Count)
+		if count == 0 {
+			count = 1
+		}
+		remediations[event.Reason] += count
+	}
+
+	for reason, count := range remediations {
+		mon.emitGauge("machinehealthcheck.remediations", count, map[string]string{
+			"reason": reason,
+		})
+
+		if mon.hourlyRun {
+			mon.log.Printf("machinehealthcheck.remediations: %s %d", reason, count)
+		}
+	}
+
+	return nil
+}
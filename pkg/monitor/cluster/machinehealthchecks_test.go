@@ -0,0 +1,105 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machinefake "github.com/openshift/client-go/machine/clientset/versioned/fake"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+)
+
+func TestEmitMachineHealthChecks(t *testing.T) {
+	ctx := context.Background()
+
+	maocli := machinefake.NewSimpleClientset(
+		&machinev1beta1.MachineHealthCheck{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "aro-machinehealthcheck",
+				Namespace: "openshift-machine-api",
+				Annotations: map[string]string{
+					"cluster.x-k8s.io/paused": "",
+				},
+			},
+			Status: machinev1beta1.MachineHealthCheckStatus{
+				ExpectedMachines:    to.IntPtr(3),
+				CurrentHealthy:      to.IntPtr(2),
+				RemediationsAllowed: 0,
+			},
+		},
+	)
+
+	cli := fake.NewSimpleClientset(
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "worker-1.1",
+				Namespace: "openshift-machine-api",
+			},
+			Reason: "MachineDeleted",
+			Count:  2,
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "worker-2.1",
+				Namespace: "openshift-machine-api",
+			},
+			Reason: "MachineDeleted",
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "aro-machinehealthcheck.1",
+				Namespace: "openshift-machine-api",
+			},
+			Reason: "RemediationRestricted",
+			Count:  1,
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "worker-3.1",
+				Namespace: "openshift-machine-api",
+			},
+			Reason: "Updated",
+			Count:  5,
+		},
+	)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	m := mock_metrics.NewMockEmitter(controller)
+
+	mon := &Monitor{
+		log:    logrus.NewEntry(logrus.StandardLogger()),
+		cli:    cli,
+		maocli: maocli,
+		m:      m,
+	}
+
+	m.EXPECT().EmitGauge("machinehealthcheck.unhealthy", int64(1), map[string]string{
+		"name":                "aro-machinehealthcheck",
+		"expectedMachines":    "3",
+		"remediationsAllowed": "0",
+		"paused":              "true",
+	})
+	m.EXPECT().EmitGauge("machinehealthcheck.remediations", int64(3), map[string]string{
+		"reason": "MachineDeleted",
+	})
+	m.EXPECT().EmitGauge("machinehealthcheck.remediations", int64(1), map[string]string{
+		"reason": "RemediationRestricted",
+	})
+
+	err := mon.emitMachineHealthChecks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
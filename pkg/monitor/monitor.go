@@ -30,6 +30,7 @@ type monitor struct {
 
 	dbMonitors          database.Monitors
 	dbOpenShiftClusters database.OpenShiftClusters
+	dbOpenShiftVersions database.OpenShiftVersions
 	dbSubscriptions     database.Subscriptions
 
 	m        metrics.Emitter
@@ -37,6 +38,7 @@ type monitor struct {
 	mu       sync.RWMutex
 	docs     map[string]*cacheDoc
 	subs     map[string]*api.SubscriptionDocument
+	versions map[string]*api.OpenShiftVersion
 	env      env.Interface
 
 	isMaster    bool
@@ -56,19 +58,21 @@ type Runnable interface {
 	Run(context.Context) error
 }
 
-func NewMonitor(log *logrus.Entry, dialer proxy.Dialer, dbMonitors database.Monitors, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, m, clusterm metrics.Emitter, liveConfig liveconfig.Manager, e env.Interface) Runnable {
+func NewMonitor(log *logrus.Entry, dialer proxy.Dialer, dbMonitors database.Monitors, dbOpenShiftClusters database.OpenShiftClusters, dbOpenShiftVersions database.OpenShiftVersions, dbSubscriptions database.Subscriptions, m, clusterm metrics.Emitter, liveConfig liveconfig.Manager, e env.Interface) Runnable {
 	return &monitor{
 		baseLog: log,
 		dialer:  dialer,
 
 		dbMonitors:          dbMonitors,
 		dbOpenShiftClusters: dbOpenShiftClusters,
+		dbOpenShiftVersions: dbOpenShiftVersions,
 		dbSubscriptions:     dbSubscriptions,
 
 		m:        m,
 		clusterm: clusterm,
 		docs:     map[string]*cacheDoc{},
 		subs:     map[string]*api.SubscriptionDocument{},
+		versions: map[string]*api.OpenShiftVersion{},
 		env:      e,
 
 		bucketCount: bucket.Buckets,
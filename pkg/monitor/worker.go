@@ -22,6 +22,7 @@ import (
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
 	"github.com/Azure/ARO-RP/pkg/util/recover"
 	"github.com/Azure/ARO-RP/pkg/util/restconfig"
+	"github.com/Azure/ARO-RP/pkg/util/version"
 )
 
 // nsgMonitoringFrequency is used for initializing NSG monitoring ticker
@@ -76,12 +77,15 @@ func (mon *monitor) listBuckets(ctx context.Context) error {
 // changefeed tracks the OpenShiftClusters change feed and keeps mon.docs
 // up-to-date.  We don't monitor clusters in Creating state, hence we don't add
 // them to mon.docs.  We also don't monitor clusters in Deleting state; when
-// this state is reached we delete from mon.docs
+// this state is reached we delete from mon.docs.  It also tracks the
+// Subscriptions and OpenShiftVersions change feeds, keeping mon.subs and
+// mon.versions up-to-date
 func (mon *monitor) changefeed(ctx context.Context, baseLog *logrus.Entry, stop <-chan struct{}) {
 	defer recover.Panic(baseLog)
 
 	clustersIterator := mon.dbOpenShiftClusters.ChangeFeed()
 	subscriptionsIterator := mon.dbSubscriptions.ChangeFeed()
+	versionsIterator := mon.dbOpenShiftVersions.ChangeFeed()
 
 	// Align this time with the deletion mechanism.
 	// Go to docs/monitoring.md for the details.
@@ -155,6 +159,30 @@ func (mon *monitor) changefeed(ctx context.Context, baseLog *logrus.Entry, stop
 			mon.mu.Unlock()
 		}
 
+		for {
+			versions, err := versionsIterator.Next(ctx, -1)
+			if err != nil {
+				successful = false
+				baseLog.Error(err)
+				break
+			}
+			if versions == nil {
+				break
+			}
+
+			mon.mu.Lock()
+
+			for _, doc := range versions.OpenShiftVersionDocuments {
+				if doc.OpenShiftVersion.Deleting {
+					delete(mon.versions, doc.OpenShiftVersion.Properties.Version)
+				} else {
+					mon.versions[doc.OpenShiftVersion.Properties.Version] = doc.OpenShiftVersion
+				}
+			}
+
+			mon.mu.Unlock()
+		}
+
 		if successful {
 			mon.lastChangefeed.Store(time.Now())
 		}
@@ -209,6 +237,7 @@ out:
 		mon.mu.RLock()
 		v := mon.docs[id]
 		sub := mon.subs[r.SubscriptionID]
+		endOfSupport := mon.endOfSupportByMinorVersion()
 		mon.mu.RUnlock()
 
 		if v == nil {
@@ -221,7 +250,7 @@ out:
 		// cached metrics in the remaining minutes
 
 		if sub != nil && sub.Subscription != nil && sub.Subscription.State != api.SubscriptionStateSuspended && sub.Subscription.State != api.SubscriptionStateWarned {
-			mon.workOne(context.Background(), log, v.doc, sub, newh != h, nsgMonitoringTicker)
+			mon.workOne(context.Background(), log, v.doc, sub, endOfSupport, newh != h, nsgMonitoringTicker)
 		}
 
 		select {
@@ -237,7 +266,7 @@ out:
 }
 
 // workOne checks the API server health of a cluster
-func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument, sub *api.SubscriptionDocument, hourlyRun bool, nsgMonTicker *time.Ticker) {
+func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument, sub *api.SubscriptionDocument, endOfSupport map[string]time.Time, hourlyRun bool, nsgMonTicker *time.Ticker) {
 	ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
 	defer cancel()
 
@@ -266,7 +295,7 @@ func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.Ope
 
 	nsgMon := nsg.NewMonitor(log, doc.OpenShiftCluster, mon.env, sub.ID, sub.Subscription.Properties.TenantID, mon.clusterm, dims, &wg, nsgMonTicker.C)
 
-	c, err := cluster.NewMonitor(log, restConfig, doc.OpenShiftCluster, mon.clusterm, hiveRestConfig, hourlyRun, &wg)
+	c, err := cluster.NewMonitor(log, restConfig, doc.OpenShiftCluster, mon.clusterm, hiveRestConfig, endOfSupport, hourlyRun, &wg)
 	if err != nil {
 		log.Error(err)
 		mon.m.EmitGauge("monitor.cluster.failedworker", 1, map[string]string{
@@ -287,6 +316,31 @@ func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.Ope
 	}
 }
 
+// endOfSupportByMinorVersion returns the end of support date of each minor
+// version which has one set on any of its versions, taking the latest if they
+// differ.  Caller must hold mon.mu.RLock.
+func (mon *monitor) endOfSupportByMinorVersion() map[string]time.Time {
+	endOfSupport := map[string]time.Time{}
+
+	for _, v := range mon.versions {
+		if v.Properties.EndOfSupport == nil {
+			continue
+		}
+
+		parsed, err := version.ParseVersion(v.Properties.Version)
+		if err != nil {
+			continue
+		}
+
+		minor := parsed.MinorVersion()
+		if existing, found := endOfSupport[minor]; !found || v.Properties.EndOfSupport.After(existing) {
+			endOfSupport[minor] = *v.Properties.EndOfSupport
+		}
+	}
+
+	return endOfSupport
+}
+
 func execute(ctx context.Context, done chan<- bool, wg *sync.WaitGroup, monitors []monitoring.Monitor) {
 	for _, monitor := range monitors {
 		wg.Add(1)
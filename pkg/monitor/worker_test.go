@@ -0,0 +1,55 @@
+package monitor
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+func TestEndOfSupportByMinorVersion(t *testing.T) {
+	earlier := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	mon := &monitor{
+		versions: map[string]*api.OpenShiftVersion{
+			"4.11.0": {
+				Properties: api.OpenShiftVersionProperties{
+					Version:      "4.11.0",
+					EndOfSupport: &earlier,
+				},
+			},
+			"4.11.5": {
+				Properties: api.OpenShiftVersionProperties{
+					Version:      "4.11.5",
+					EndOfSupport: &later,
+				},
+			},
+			"4.12.0": {
+				Properties: api.OpenShiftVersionProperties{
+					Version:      "4.12.0",
+					EndOfSupport: &earlier,
+				},
+			},
+			"4.13.0": {
+				Properties: api.OpenShiftVersionProperties{
+					Version: "4.13.0",
+				},
+			},
+		},
+	}
+
+	want := map[string]time.Time{
+		"4.11": later,
+		"4.12": earlier,
+	}
+
+	got := mon.endOfSupportByMinorVersion()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, wanted %v", got, want)
+	}
+}
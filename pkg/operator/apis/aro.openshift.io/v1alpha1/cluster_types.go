@@ -8,6 +8,7 @@ import (
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type BannerContent string
@@ -29,6 +30,10 @@ const (
 	DefaultIngressCertificate = "DefaultIngressCertificate"
 	DefaultClusterDNS         = "DefaultClusterDNS"
 	GuardRailsStatus          = "GuardRailsStatus"
+
+	// MachineHealthCheckZoneOutage is true while MachineHealthCheck
+	// remediation is paused because of a zone-wide outage
+	MachineHealthCheckZoneOutage = "MachineHealthCheckZoneOutage"
 )
 
 // AllConditionTypes is a operator conditions currently in use, any condition not in this list is not
@@ -43,6 +48,7 @@ func AllConditionTypes() []string {
 		DefaultIngressCertificate,
 		DefaultClusterDNS,
 		GuardRailsStatus,
+		MachineHealthCheckZoneOutage,
 	}
 }
 
@@ -71,6 +77,58 @@ type InternetCheckerSpec struct {
 	URLs []string `json:"urls,omitempty"`
 }
 
+// AlertRoutingSpec configures forwarding of critical platform alerts from
+// Alertmanager to the RP
+type AlertRoutingSpec struct {
+	// URL is the RP alert ingestion endpoint for this cluster.  If empty,
+	// alerts are not forwarded.
+	URL string `json:"url,omitempty"`
+}
+
+// AutoSizedNodesSpec carries the capacity of the VM sizes in use by the
+// cluster, as reported by the Azure resource SKU API.  It is used to size the
+// system-reserved resources of each MachineConfigPool.
+type AutoSizedNodesSpec struct {
+	VMSizes []VMSizeCapacity `json:"vmSizes,omitempty"`
+}
+
+// VMSizeCapacity is the capacity of a single VM size
+type VMSizeCapacity struct {
+	Name      string `json:"name"`
+	VCPUs     int    `json:"vCPUs"`
+	MemoryMiB int64  `json:"memoryMiB"`
+}
+
+// MachineHealthCheckSpec configures remediation of unhealthy worker machines
+type MachineHealthCheckSpec struct {
+	// Policies override the default remediation policy for individual
+	// machine sets
+	Policies []MachineHealthCheckPolicy `json:"policies,omitempty"`
+}
+
+// MachineHealthCheckPolicy is the remediation policy of a single machine set.
+// Unset fields take the value of the default ARO MachineHealthCheck.
+type MachineHealthCheckPolicy struct {
+	MachineSet string `json:"machineSet"`
+	// UnhealthyConditionTimeout is how long a node may be not ready before
+	// its machine is remediated
+	UnhealthyConditionTimeout *metav1.Duration `json:"unhealthyConditionTimeout,omitempty"`
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:Pattern="^((100|[0-9]{1,2})%|[0-9]+)$"
+	MaxUnhealthy       *intstr.IntOrString `json:"maxUnhealthy,omitempty"`
+	NodeStartupTimeout *metav1.Duration    `json:"nodeStartupTimeout,omitempty"`
+}
+
+// InfraNodesSpec requests dedicated infrastructure machine sets.  The router,
+// the image registry and the monitoring stack are moved onto them.
+type InfraNodesSpec struct {
+	VMSize     string `json:"vmSize"`
+	DiskSizeGB int32  `json:"diskSizeGB,omitempty"`
+	// Replicas is the total number of infra nodes, spread across the zones
+	// of the worker machine sets
+	Replicas int32 `json:"replicas"`
+}
+
 type OperatorFlags map[string]string
 
 func (f OperatorFlags) GetWithDefault(key string, sentinel string) string {
@@ -115,6 +173,13 @@ type ClusterSpec struct {
 	GatewayPrivateEndpointIP string              `json:"gatewayPrivateEndpointIP,omitempty"`
 	Banner                   Banner              `json:"banner,omitempty"`
 	ServiceSubnets           []string            `json:"serviceSubnets,omitempty"`
+	AlertRouting             AlertRoutingSpec    `json:"alertRouting,omitempty"`
+	AutoSizedNodes           AutoSizedNodesSpec  `json:"autoSizedNodes,omitempty"`
+	// MachineHealthCheck is managed in-cluster and is preserved when the RP
+	// updates the cluster spec
+	MachineHealthCheck MachineHealthCheckSpec `json:"machineHealthCheck,omitempty"`
+	// InfraNodes is nil unless dedicated infra nodes have been requested
+	InfraNodes *InfraNodesSpec `json:"infraNodes,omitempty"`
 
 	// OperatorFlags defines feature gates for the ARO Operator
 	OperatorFlags OperatorFlags `json:"operatorflags,omitempty"`
@@ -125,11 +190,24 @@ type Banner struct {
 	Content BannerContent `json:"content,omitempty"`
 }
 
+// AutoSizedNodesPoolStatus reports the system-reserved resources computed for
+// a MachineConfigPool and whether they have been applied
+type AutoSizedNodesPoolStatus struct {
+	MachineConfigPool    string `json:"machineConfigPool"`
+	VMSize               string `json:"vmSize,omitempty"`
+	KubeletConfig        string `json:"kubeletConfig,omitempty"`
+	SystemReservedCPU    string `json:"systemReservedCPU,omitempty"`
+	SystemReservedMemory string `json:"systemReservedMemory,omitempty"`
+	Applied              bool   `json:"applied"`
+	Message              string `json:"message,omitempty"`
+}
+
 // ClusterStatus defines the observed state of Cluster
 type ClusterStatus struct {
 	OperatorVersion   string                         `json:"operatorVersion,omitempty"`
 	Conditions        []operatorv1.OperatorCondition `json:"conditions,omitempty"`
 	RedHatKeysPresent []string                       `json:"redHatKeysPresent,omitempty"`
+	AutoSizedNodes    []AutoSizedNodesPoolStatus     `json:"autoSizedNodes,omitempty"`
 }
 
 // Cluster is the Schema for the clusters API
@@ -7,9 +7,61 @@ package v1alpha1
 
 import (
 	v1 "github.com/openshift/api/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertRoutingSpec) DeepCopyInto(out *AlertRoutingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertRoutingSpec.
+func (in *AlertRoutingSpec) DeepCopy() *AlertRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoSizedNodesPoolStatus) DeepCopyInto(out *AutoSizedNodesPoolStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoSizedNodesPoolStatus.
+func (in *AutoSizedNodesPoolStatus) DeepCopy() *AutoSizedNodesPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoSizedNodesPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoSizedNodesSpec) DeepCopyInto(out *AutoSizedNodesSpec) {
+	*out = *in
+	if in.VMSizes != nil {
+		in, out := &in.VMSizes, &out.VMSizes
+		*out = make([]VMSizeCapacity, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoSizedNodesSpec.
+func (in *AutoSizedNodesSpec) DeepCopy() *AutoSizedNodesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoSizedNodesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Banner) DeepCopyInto(out *Banner) {
 	*out = *in
@@ -100,6 +152,14 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.AlertRouting = in.AlertRouting
+	in.AutoSizedNodes.DeepCopyInto(&out.AutoSizedNodes)
+	in.MachineHealthCheck.DeepCopyInto(&out.MachineHealthCheck)
+	if in.InfraNodes != nil {
+		in, out := &in.InfraNodes, &out.InfraNodes
+		*out = new(InfraNodesSpec)
+		**out = **in
+	}
 	if in.OperatorFlags != nil {
 		in, out := &in.OperatorFlags, &out.OperatorFlags
 		*out = make(OperatorFlags, len(*in))
@@ -134,6 +194,11 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AutoSizedNodes != nil {
+		in, out := &in.AutoSizedNodes, &out.AutoSizedNodes
+		*out = make([]AutoSizedNodesPoolStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -161,6 +226,21 @@ func (in *GenevaLoggingSpec) DeepCopy() *GenevaLoggingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfraNodesSpec) DeepCopyInto(out *InfraNodesSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraNodesSpec.
+func (in *InfraNodesSpec) DeepCopy() *InfraNodesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InfraNodesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InternetCheckerSpec) DeepCopyInto(out *InternetCheckerSpec) {
 	*out = *in
@@ -181,6 +261,58 @@ func (in *InternetCheckerSpec) DeepCopy() *InternetCheckerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineHealthCheckPolicy) DeepCopyInto(out *MachineHealthCheckPolicy) {
+	*out = *in
+	if in.UnhealthyConditionTimeout != nil {
+		in, out := &in.UnhealthyConditionTimeout, &out.UnhealthyConditionTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxUnhealthy != nil {
+		in, out := &in.MaxUnhealthy, &out.MaxUnhealthy
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.NodeStartupTimeout != nil {
+		in, out := &in.NodeStartupTimeout, &out.NodeStartupTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHealthCheckPolicy.
+func (in *MachineHealthCheckPolicy) DeepCopy() *MachineHealthCheckPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineHealthCheckPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineHealthCheckSpec) DeepCopyInto(out *MachineHealthCheckSpec) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]MachineHealthCheckPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHealthCheckSpec.
+func (in *MachineHealthCheckSpec) DeepCopy() *MachineHealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineHealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in OperatorFlags) DeepCopyInto(out *OperatorFlags) {
 	{
@@ -201,3 +333,18 @@ func (in OperatorFlags) DeepCopy() OperatorFlags {
 	in.DeepCopyInto(out)
 	return *out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSizeCapacity) DeepCopyInto(out *VMSizeCapacity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSizeCapacity.
+func (in *VMSizeCapacity) DeepCopy() *VMSizeCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSizeCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -6,6 +6,7 @@ package alertwebhook
 import (
 	"context"
 	"reflect"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/sirupsen/logrus"
@@ -14,8 +15,10 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/Azure/ARO-RP/pkg/operator"
 	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
@@ -23,10 +26,34 @@ import (
 
 const (
 	ControllerName = "Alertwebhook"
+
+	// AlertRoutingTokenKey is the key in the operator secret holding the
+	// bearer token used to authenticate alerts forwarded to the RP
+	AlertRoutingTokenKey = "alertroutingtoken"
+
+	// aroReceiverName is the Alertmanager receiver which forwards critical
+	// platform alerts to the RP
+	aroReceiverName = "aro-platform-critical"
+
+	// defaultWebhookURL is set on the default receivers when alert routing is
+	// not configured
+	defaultWebhookURL = "http://aro-operator-master.openshift-azure-operator.svc.cluster.local:8080/healthz/ready"
 )
 
 var alertManagerName = types.NamespacedName{Name: "alertmanager-main", Namespace: "openshift-monitoring"}
 
+// platformNamespaces are the namespaces whose critical alerts are forwarded to
+// the RP
+var platformNamespaces = []string{
+	"openshift-etcd",
+	"openshift-etcd-operator",
+	"openshift-kube-apiserver",
+	"openshift-kube-apiserver-operator",
+	"openshift-kube-controller-manager",
+	"openshift-kube-controller-manager-operator",
+	"openshift-machine-config-operator",
+}
+
 // Reconciler reconciles the alertmanager webhook
 type Reconciler struct {
 	log *logrus.Entry
@@ -41,7 +68,9 @@ func NewReconciler(log *logrus.Entry, client client.Client) *Reconciler {
 	}
 }
 
-// Reconcile makes sure that the Alertmanager default webhook is set.
+// Reconcile makes sure that Alertmanager forwards critical platform alerts to
+// the RP or, if alert routing is not configured, that the default webhook is
+// set.
 func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	instance := &arov1alpha1.Cluster{}
 	err := r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
@@ -55,51 +84,169 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 	}
 
 	r.log.Debug("running")
-	return reconcile.Result{}, r.setAlertManagerWebhook(ctx, "http://aro-operator-master.openshift-azure-operator.svc.cluster.local:8080/healthz/ready")
+	if instance.Spec.AlertRouting.URL == "" {
+		return reconcile.Result{}, r.setAlertManagerWebhook(ctx, defaultWebhookURL)
+	}
+
+	operatorSecret := &corev1.Secret{}
+	err = r.client.Get(ctx, types.NamespacedName{Namespace: operator.Namespace, Name: operator.SecretName}, operatorSecret)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, r.setAlertManagerRouting(ctx, instance.Spec.AlertRouting.URL, string(operatorSecret.Data[AlertRoutingTokenKey]))
 }
 
 // setAlertManagerWebhook is a hack to disable the
 // AlertmanagerReceiversNotConfigured warning added in 4.3.8.
 func (r *Reconciler) setAlertManagerWebhook(ctx context.Context, addr string) error {
-	s := &corev1.Secret{}
-	err := r.client.Get(ctx, alertManagerName, s)
-	if err != nil {
-		return err
-	}
+	return r.updateAlertManagerConfig(ctx, func(am map[string]interface{}) bool {
+		receivers, ok := am["receivers"].([]interface{})
+		if !ok {
+			return false
+		}
 
-	var am map[string]interface{}
-	err = yaml.Unmarshal(s.Data["alertmanager.yaml"], &am)
-	if err != nil {
-		return err
-	}
+		var changed bool
+		for _, r := range receivers {
+			r, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
 
-	receivers, ok := am["receivers"].([]interface{})
-	if !ok {
-		return nil
-	}
+			if name, ok := r["name"].(string); !ok || (name != "null" && name != "Default") {
+				continue
+			}
+
+			webhookConfigs := []interface{}{
+				map[string]interface{}{"url": addr},
+			}
+
+			if !reflect.DeepEqual(r["webhook_configs"], webhookConfigs) {
+				r["webhook_configs"] = webhookConfigs
+				changed = true
+			}
+		}
+
+		return changed
+	})
+}
 
-	var changed bool
-	for _, r := range receivers {
-		r, ok := r.(map[string]interface{})
+// setAlertManagerRouting ensures that Alertmanager has a receiver which
+// forwards alerts to the RP, and a top-level route which sends critical
+// platform alerts to it.  The route sets continue so that any routing
+// configured by the customer still applies to these alerts.  As a real
+// receiver is now configured, the default webhook hack is removed.
+func (r *Reconciler) setAlertManagerRouting(ctx context.Context, url, token string) error {
+	return r.updateAlertManagerConfig(ctx, func(am map[string]interface{}) bool {
+		var changed bool
+
+		webhookConfig := map[string]interface{}{
+			"url":           url,
+			"send_resolved": true,
+		}
+		if token != "" {
+			webhookConfig["http_config"] = map[string]interface{}{
+				"bearer_token": token,
+			}
+		}
+
+		wantReceiver := map[string]interface{}{
+			"name":            aroReceiverName,
+			"webhook_configs": []interface{}{webhookConfig},
+		}
+
+		receivers, _ := am["receivers"].([]interface{})
+
+		var found bool
+		for i, rc := range receivers {
+			rc, ok := rc.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name, _ := rc["name"].(string)
+			switch name {
+			case aroReceiverName:
+				found = true
+				if !reflect.DeepEqual(rc, wantReceiver) {
+					receivers[i] = wantReceiver
+					changed = true
+				}
+
+			case "null", "Default":
+				// remove our previous hack if present
+				if webhookConfigs, ok := rc["webhook_configs"].([]interface{}); ok && len(webhookConfigs) == 1 {
+					if wc, ok := webhookConfigs[0].(map[string]interface{}); ok &&
+						reflect.DeepEqual(wc, map[string]interface{}{"url": defaultWebhookURL}) {
+						delete(rc, "webhook_configs")
+						changed = true
+					}
+				}
+			}
+		}
+
+		if !found {
+			receivers = append(receivers, wantReceiver)
+			changed = true
+		}
+		am["receivers"] = receivers
+
+		route, ok := am["route"].(map[string]interface{})
 		if !ok {
-			continue
+			route = map[string]interface{}{}
+			am["route"] = route
 		}
 
-		if name, ok := r["name"].(string); !ok || (name != "null" && name != "Default") {
-			continue
+		wantRoute := map[string]interface{}{
+			"receiver": aroReceiverName,
+			"match": map[string]interface{}{
+				"severity": "critical",
+			},
+			"match_re": map[string]interface{}{
+				"namespace": strings.Join(platformNamespaces, "|"),
+			},
+			"group_wait": "10s",
+			"continue":   true,
 		}
 
-		webhookConfigs := []interface{}{
-			map[string]interface{}{"url": addr},
+		routes, _ := route["routes"].([]interface{})
+
+		var newRoutes []interface{}
+		for _, rt := range routes {
+			if rt, ok := rt.(map[string]interface{}); ok && rt["receiver"] == aroReceiverName {
+				continue
+			}
+			newRoutes = append(newRoutes, rt)
 		}
 
-		if !reflect.DeepEqual(r["webhook_configs"], webhookConfigs) {
-			r["webhook_configs"] = webhookConfigs
+		// our route must come first, otherwise an earlier matching route
+		// without continue would prevent it from being evaluated
+		newRoutes = append([]interface{}{wantRoute}, newRoutes...)
+		if !reflect.DeepEqual(routes, newRoutes) {
+			route["routes"] = newRoutes
 			changed = true
 		}
+
+		return changed
+	})
+}
+
+// updateAlertManagerConfig reads the Alertmanager configuration, calls f to
+// mutate it and writes it back if f reports that it made a change.
+func (r *Reconciler) updateAlertManagerConfig(ctx context.Context, f func(map[string]interface{}) bool) error {
+	s := &corev1.Secret{}
+	err := r.client.Get(ctx, alertManagerName, s)
+	if err != nil {
+		return err
 	}
 
-	if !changed {
+	var am map[string]interface{}
+	err = yaml.Unmarshal(s.Data["alertmanager.yaml"], &am)
+	if err != nil {
+		return err
+	}
+
+	if !f(am) {
 		return nil
 	}
 
@@ -117,8 +264,14 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return o.GetName() == alertManagerName.Name && o.GetNamespace() == alertManagerName.Namespace
 	})
 
+	aroClusterPredicate := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetName() == arov1alpha1.SingletonClusterName
+	})
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Secret{}, builder.WithPredicates(isAlertManagerPredicate)).
+		// reconcile when the alert routing configuration changes
+		Watches(&source.Kind{Type: &arov1alpha1.Cluster{}}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(aroClusterPredicate)).
 		Named(ControllerName).
 		Complete(r)
 }
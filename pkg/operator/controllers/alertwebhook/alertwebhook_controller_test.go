@@ -136,6 +136,37 @@ route:
       severity: critical
     receiver: Critical
 `)
+
+	wantRouting = []byte(`
+global:
+  resolve_timeout: 5m
+receivers:
+- name: "null"
+- name: aro-platform-critical
+  webhook_configs:
+  - http_config:
+      bearer_token: token
+    send_resolved: true
+    url: https://rp.example.com/alertingestion/cluster
+route:
+  group_by:
+  - namespace
+  group_interval: 5m
+  group_wait: 30s
+  receiver: "null"
+  repeat_interval: 12h
+  routes:
+  - continue: true
+    group_wait: 10s
+    match:
+      severity: critical
+    match_re:
+      namespace: openshift-etcd|openshift-etcd-operator|openshift-kube-apiserver|openshift-kube-apiserver-operator|openshift-kube-controller-manager|openshift-kube-controller-manager-operator|openshift-machine-config-operator
+    receiver: aro-platform-critical
+  - match:
+      alertname: Watchdog
+    receiver: "null"
+`)
 )
 
 func TestSetAlertManagerWebhook(t *testing.T) {
@@ -145,6 +176,7 @@ func TestSetAlertManagerWebhook(t *testing.T) {
 		name              string
 		alertmanagerYaml  []byte
 		controllerEnabled bool
+		alertRoutingURL   string
 		want              []byte
 	}{
 		{
@@ -159,6 +191,27 @@ func TestSetAlertManagerWebhook(t *testing.T) {
 			controllerEnabled: true,
 			want:              wantNew,
 		},
+		{
+			name:              "old cluster, alert routing configured",
+			alertmanagerYaml:  initialOld,
+			controllerEnabled: true,
+			alertRoutingURL:   "https://rp.example.com/alertingestion/cluster",
+			want:              wantRouting,
+		},
+		{
+			name:              "old cluster, alert routing configured after default webhook was set",
+			alertmanagerYaml:  wantOld,
+			controllerEnabled: true,
+			alertRoutingURL:   "https://rp.example.com/alertingestion/cluster",
+			want:              wantRouting,
+		},
+		{
+			name:              "alert routing already configured",
+			alertmanagerYaml:  wantRouting,
+			controllerEnabled: true,
+			alertRoutingURL:   "https://rp.example.com/alertingestion/cluster",
+			want:              wantRouting,
+		},
 		{
 			name:              "old cluster, disabled",
 			alertmanagerYaml:  initialOld,
@@ -183,6 +236,9 @@ func TestSetAlertManagerWebhook(t *testing.T) {
 					OperatorFlags: arov1alpha1.OperatorFlags{
 						operator.AlertWebhookEnabled: operator.FlagFalse,
 					},
+					AlertRouting: arov1alpha1.AlertRoutingSpec{
+						URL: tt.alertRoutingURL,
+					},
 				},
 			}
 
@@ -200,9 +256,19 @@ func TestSetAlertManagerWebhook(t *testing.T) {
 				},
 			}
 
+			operatorSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      operator.SecretName,
+					Namespace: operator.Namespace,
+				},
+				Data: map[string][]byte{
+					AlertRoutingTokenKey: []byte("token"),
+				},
+			}
+
 			r := &Reconciler{
 				log:    logrus.NewEntry(logrus.StandardLogger()),
-				client: ctrlfake.NewClientBuilder().WithObjects(instance, secret).Build(),
+				client: ctrlfake.NewClientBuilder().WithObjects(instance, secret, operatorSecret).Build(),
 			}
 
 			_, err := r.Reconcile(ctx, ctrl.Request{})
@@ -5,22 +5,33 @@ package autosizednodes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	mcv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	"github.com/sirupsen/logrus"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/Azure/ARO-RP/pkg/operator"
 	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/util/ready"
 )
 
 type Reconciler struct {
@@ -31,7 +42,20 @@ type Reconciler struct {
 
 const (
 	ControllerName = "AutoSizedNodes"
-	configName     = "dynamic-node"
+
+	// legacyConfigName is the KubeletConfig which used to turn on the MCO's
+	// own auto sizing across all built-in pools
+	legacyConfigName = "dynamic-node"
+	configNamePrefix = "dynamic-node-"
+
+	managedLabel      = "aro.openshift.io/autosizednodes"
+	poolLabelPrefix   = "pools.operator.machineconfiguration.openshift.io/"
+	workerPoolName    = "worker"
+	instanceTypeLabel = corev1.LabelInstanceTypeStable
+
+	// rolloutRequeue is how long we wait before checking whether a
+	// MachineConfigPool rollout has completed
+	rolloutRequeue = time.Minute
 )
 
 func NewReconciler(log *logrus.Entry, client client.Client) *Reconciler {
@@ -42,61 +66,312 @@ func NewReconciler(log *logrus.Entry, client client.Client) *Reconciler {
 	}
 }
 
-func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
-	var aro arov1alpha1.Cluster
-	var err error
+// change is a single KubeletConfig create, update or delete.  Every change
+// may cause a MachineConfigPool to roll out, so they are applied one at a time.
+type change struct {
+	pool   string
+	config *mcv1.KubeletConfig
+	delete bool
+}
 
-	err = r.client.Get(ctx, request.NamespacedName, &aro)
+// Reconcile computes system-reserved resources for every MachineConfigPool
+// from the capacity of the VM size of its nodes and manages one KubeletConfig
+// per pool.  Changes are only made when no pool is rolling out, and only one
+// change is made at a time, so that we never trigger reboots in several pools
+// concurrently.
+func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	cluster := &arov1alpha1.Cluster{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, cluster)
 	if err != nil {
 		err = fmt.Errorf("unable to fetch aro cluster: %w", err)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	r.log.Infof("Config changed, autoSize: %t\n", aro.Spec.OperatorFlags.GetSimpleBoolean(operator.AutosizedNodesEnabled))
+	enabled := cluster.Spec.OperatorFlags.GetSimpleBoolean(operator.AutosizedNodesEnabled)
 
-	// key is used to locate the object in the etcd
-	key := types.NamespacedName{
-		Name: configName,
+	mcps := &mcv1.MachineConfigPoolList{}
+	err = r.client.List(ctx, mcps)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not list MachineConfigPools: %w", err)
 	}
+	sort.Slice(mcps.Items, func(i, j int) bool { return mcps.Items[i].Name < mcps.Items[j].Name })
 
-	if !aro.Spec.OperatorFlags.GetSimpleBoolean(operator.AutosizedNodesEnabled) {
-		// defaults to deleting the config
-		config := mcv1.KubeletConfig{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: configName,
-			},
+	configs := &mcv1.KubeletConfigList{}
+	err = r.client.List(ctx, configs, client.HasLabels{managedLabel})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not list KubeletConfigs: %w", err)
+	}
+
+	existing := map[string]*mcv1.KubeletConfig{}
+	for i := range configs.Items {
+		existing[configs.Items[i].Name] = &configs.Items[i]
+	}
+
+	var statuses []arov1alpha1.AutoSizedNodesPoolStatus
+	var changes []change
+
+	// the legacy config enables the MCO's auto sizing, which would override
+	// the values we compute
+	legacy := &mcv1.KubeletConfig{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: legacyConfigName}, legacy)
+	switch {
+	case err == nil:
+		changes = append(changes, change{config: legacy, delete: true})
+	case client.IgnoreNotFound(err) != nil:
+		return ctrl.Result{}, fmt.Errorf("could not fetch KubeletConfig: %w", err)
+	}
+
+	if enabled {
+		nodes := &corev1.NodeList{}
+		err = r.client.List(ctx, nodes)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not list Nodes: %w", err)
 		}
-		err = r.client.Delete(ctx, &config)
+
+		vmSizes, err := poolVMSizes(mcps.Items, nodes.Items)
 		if err != nil {
-			err = fmt.Errorf("could not delete KubeletConfig: %w", err)
+			return ctrl.Result{}, err
+		}
+
+		for _, mcp := range mcps.Items {
+			status, config := desiredConfig(cluster, &mcp, vmSizes[mcp.Name])
+			if config != nil {
+				err = controllerutil.SetControllerReference(cluster, config, r.client.Scheme())
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+
+				current := existing[config.Name]
+				delete(existing, config.Name)
+
+				if current == nil || !reflect.DeepEqual(current.Spec, config.Spec) {
+					if current != nil {
+						config.ResourceVersion = current.ResourceVersion
+					}
+					changes = append(changes, change{pool: mcp.Name, config: config})
+				} else {
+					status.Applied = true
+				}
+			}
+
+			statuses = append(statuses, status)
 		}
-		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	defaultConfig := makeConfig()
+	// anything left over belongs to a pool which no longer exists or can no
+	// longer be sized, or the feature has been disabled
+	names := make([]string, 0, len(existing))
+	for name := range existing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		changes = append(changes, change{pool: strings.TrimPrefix(name, configNamePrefix), config: existing[name], delete: true})
+	}
+
+	if len(changes) == 0 {
+		return ctrl.Result{}, r.updateStatus(ctx, cluster, statuses)
+	}
 
-	var config mcv1.KubeletConfig
-	err = r.client.Get(ctx, key, &config)
-	if kerrors.IsNotFound(err) {
-		// If config doesn't exist, create a new one
-		err := r.client.Create(ctx, &defaultConfig, &client.CreateOptions{})
+	rollingOut := rollingOutPools(mcps.Items)
+	if len(rollingOut) > 0 {
+		r.log.Infof("waiting for MachineConfigPools %s to roll out", strings.Join(rollingOut, ", "))
+		setPending(statuses, fmt.Sprintf("waiting for MachineConfigPools %s to roll out", strings.Join(rollingOut, ", ")))
+		return ctrl.Result{RequeueAfter: rolloutRequeue}, r.updateStatus(ctx, cluster, statuses)
+	}
+
+	c := changes[0]
+	if c.delete {
+		r.log.Infof("deleting KubeletConfig %s", c.config.Name)
+		err = client.IgnoreNotFound(r.client.Delete(ctx, c.config))
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not delete KubeletConfig: %w", err)
+		}
+	} else if c.config.ResourceVersion == "" {
+		r.log.Infof("creating KubeletConfig %s", c.config.Name)
+		err = r.client.Create(ctx, c.config)
 		if err != nil {
-			err = fmt.Errorf("could not create KubeletConfig: %w", err)
+			return ctrl.Result{}, fmt.Errorf("could not create KubeletConfig: %w", err)
+		}
+	} else {
+		r.log.Infof("updating KubeletConfig %s", c.config.Name)
+		err = r.client.Update(ctx, c.config)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not update KubeletConfig: %w", err)
 		}
-		return ctrl.Result{}, err
 	}
-	if err != nil {
-		// If error, return it (controller-runtime will requeue for a retry)
-		return ctrl.Result{}, fmt.Errorf("could not fetch KubeletConfig: %w", err)
+
+	for i := range statuses {
+		if !c.delete && statuses[i].MachineConfigPool == c.pool {
+			statuses[i].Applied = true
+		}
+	}
+	if len(changes) > 1 {
+		setPending(statuses, fmt.Sprintf("waiting for KubeletConfig %s to roll out", c.config.Name))
 	}
 
-	// If already exists, update the spec
-	config.Spec = defaultConfig.Spec
-	err = r.client.Update(ctx, &config)
-	if err != nil {
-		err = fmt.Errorf("could not update KubeletConfig: %w", err)
+	return ctrl.Result{RequeueAfter: rolloutRequeue}, r.updateStatus(ctx, cluster, statuses)
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, cluster *arov1alpha1.Cluster, statuses []arov1alpha1.AutoSizedNodesPoolStatus) error {
+	if reflect.DeepEqual(cluster.Status.AutoSizedNodes, statuses) {
+		return nil
+	}
+
+	cluster.Status.AutoSizedNodes = statuses
+	return r.client.Status().Update(ctx, cluster)
+}
+
+// setPending records message on every pool whose KubeletConfig has not been
+// applied yet
+func setPending(statuses []arov1alpha1.AutoSizedNodesPoolStatus, message string) {
+	for i := range statuses {
+		if !statuses[i].Applied && statuses[i].KubeletConfig != "" {
+			statuses[i].Message = message
+		}
+	}
+}
+
+// rollingOutPools returns the names of the MachineConfigPools which are
+// currently updating
+func rollingOutPools(mcps []mcv1.MachineConfigPool) []string {
+	var names []string
+	for i := range mcps {
+		if !ready.MachineConfigPoolIsReady(&mcps[i]) ||
+			mcv1.IsMachineConfigPoolConditionTrue(mcps[i].Status.Conditions, mcv1.MachineConfigPoolUpdating) {
+			names = append(names, mcps[i].Name)
+		}
+	}
+	return names
+}
+
+// poolVMSizes returns the VM sizes of the nodes in each MachineConfigPool.  As
+// with the MCO, a node which matches a custom pool as well as the worker pool
+// belongs to the custom pool.
+func poolVMSizes(mcps []mcv1.MachineConfigPool, nodes []corev1.Node) (map[string][]string, error) {
+	selectors := map[string]labels.Selector{}
+	for _, mcp := range mcps {
+		if mcp.Spec.NodeSelector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(mcp.Spec.NodeSelector)
+		if err != nil {
+			return nil, err
+		}
+		selectors[mcp.Name] = selector
 	}
-	return ctrl.Result{}, err
+
+	vmSizes := map[string][]string{}
+	for _, node := range nodes {
+		vmSize := node.Labels[instanceTypeLabel]
+		if vmSize == "" {
+			continue
+		}
+
+		var pools []string
+		for name, selector := range selectors {
+			if selector.Matches(labels.Set(node.Labels)) {
+				pools = append(pools, name)
+			}
+		}
+
+		if len(pools) > 1 {
+			for i, name := range pools {
+				if name == workerPoolName {
+					pools = append(pools[:i], pools[i+1:]...)
+					break
+				}
+			}
+		}
+
+		for _, name := range pools {
+			vmSizes[name] = append(vmSizes[name], vmSize)
+		}
+	}
+
+	return vmSizes, nil
+}
+
+// desiredConfig returns the status and desired KubeletConfig for a
+// MachineConfigPool.  The returned config is nil if the pool cannot be sized.
+func desiredConfig(cluster *arov1alpha1.Cluster, mcp *mcv1.MachineConfigPool, vmSizes []string) (arov1alpha1.AutoSizedNodesPoolStatus, *mcv1.KubeletConfig) {
+	status := arov1alpha1.AutoSizedNodesPoolStatus{
+		MachineConfigPool: mcp.Name,
+	}
+
+	poolLabel := poolLabelPrefix + mcp.Name
+	if _, ok := mcp.Labels[poolLabel]; !ok {
+		status.Message = fmt.Sprintf("MachineConfigPool has no %s label", poolLabel)
+		return status, nil
+	}
+
+	if len(vmSizes) == 0 {
+		status.Message = "MachineConfigPool has no nodes with a known VM size"
+		return status, nil
+	}
+
+	// size for the largest VM size in the pool, so that the kubelet and system
+	// daemons are never starved on any of its nodes
+	var capacity *arov1alpha1.VMSizeCapacity
+	for _, vmSize := range vmSizes {
+		c := vmSizeCapacity(cluster, vmSize)
+		if c == nil {
+			status.VMSize = vmSize
+			status.Message = fmt.Sprintf("capacity of VM size %s is unknown", vmSize)
+			return status, nil
+		}
+
+		if capacity == nil || c.MemoryMiB > capacity.MemoryMiB ||
+			(c.MemoryMiB == capacity.MemoryMiB && c.VCPUs > capacity.VCPUs) {
+			capacity = c
+		}
+	}
+
+	cpu := systemReservedCPU(capacity.VCPUs)
+	memory := systemReservedMemory(capacity.MemoryMiB)
+
+	status.VMSize = capacity.Name
+	status.KubeletConfig = configNamePrefix + mcp.Name
+	status.SystemReservedCPU = cpu.String()
+	status.SystemReservedMemory = memory.String()
+
+	// marshalling a map of strings cannot fail
+	b, _ := json.Marshal(map[string]interface{}{
+		"systemReserved": map[string]string{
+			"cpu":    status.SystemReservedCPU,
+			"memory": status.SystemReservedMemory,
+		},
+	})
+
+	return status, &mcv1.KubeletConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: status.KubeletConfig,
+			Labels: map[string]string{
+				managedLabel: "",
+			},
+		},
+		Spec: mcv1.KubeletConfigSpec{
+			AutoSizingReserved: to.BoolPtr(false),
+			MachineConfigPoolSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					poolLabel: "",
+				},
+			},
+			KubeletConfig: &kruntime.RawExtension{
+				Raw: b,
+			},
+		},
+	}
+}
+
+func vmSizeCapacity(cluster *arov1alpha1.Cluster, vmSize string) *arov1alpha1.VMSizeCapacity {
+	for i, c := range cluster.Spec.AutoSizedNodes.VMSizes {
+		if strings.EqualFold(c.Name, vmSize) {
+			return &cluster.Spec.AutoSizedNodes.VMSizes[i]
+		}
+	}
+	return nil
 }
 
 // SetupWithManager prepares the controller with info who to watch
@@ -106,32 +381,22 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return strings.EqualFold(arov1alpha1.SingletonClusterName, name)
 	})
 
+	// only node additions and removals change the VM sizes in a pool
+	nodePredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetLabels()[instanceTypeLabel] != e.ObjectNew.GetLabels()[instanceTypeLabel]
+		},
+	}
+
 	b := ctrl.NewControllerManagedBy(mgr).
 		For(&arov1alpha1.Cluster{}, builder.WithPredicates(clusterPredicate))
 
-		// Controller adds ControllerManagedBy to KubeletConfit created by this controller.
-		// Any changes will trigger reconcile, but only for that config.
+		// Controller adds ControllerManagedBy to KubeletConfigs created by this
+		// controller.  Any changes will trigger reconcile.
 	return b.
 		Named(ControllerName).
 		Owns(&mcv1.KubeletConfig{}).
+		Watches(&source.Kind{Type: &mcv1.MachineConfigPool{}}, &handler.EnqueueRequestForObject{}). // to apply the next change once a rollout completes
+		Watches(&source.Kind{Type: &corev1.Node{}}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(nodePredicate)).
 		Complete(r)
 }
-
-func makeConfig() mcv1.KubeletConfig {
-	return mcv1.KubeletConfig{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: configName,
-		},
-		Spec: mcv1.KubeletConfigSpec{
-			AutoSizingReserved: to.BoolPtr(true),
-			MachineConfigPoolSelector: &metav1.LabelSelector{
-				MatchExpressions: []metav1.LabelSelectorRequirement{
-					{
-						Key:      "machineconfiguration.openshift.io/mco-built-in",
-						Operator: metav1.LabelSelectorOpExists,
-					},
-				},
-			},
-		},
-	}
-}
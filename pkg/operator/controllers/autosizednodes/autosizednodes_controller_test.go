@@ -13,11 +13,11 @@ import (
 	"github.com/google/go-cmp/cmp"
 	mcv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	"github.com/sirupsen/logrus"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	// This "_" import is counterintuitive but is required to initialize the scheme
@@ -32,98 +32,312 @@ func TestAutosizednodesReconciler(t *testing.T) {
 	aro := func(autoSizeEnabled bool) *arov1alpha1.Cluster {
 		return &arov1alpha1.Cluster{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      "aro",
-				Namespace: "openshift-azure-operator",
+				Name: arov1alpha1.SingletonClusterName,
 			},
 			Spec: arov1alpha1.ClusterSpec{
 				OperatorFlags: arov1alpha1.OperatorFlags{
 					operator.AutosizedNodesEnabled: strconv.FormatBool(autoSizeEnabled),
 				},
+				AutoSizedNodes: arov1alpha1.AutoSizedNodesSpec{
+					VMSizes: []arov1alpha1.VMSizeCapacity{
+						{Name: "Standard_D8s_v3", VCPUs: 8, MemoryMiB: 32768},
+						{Name: "Standard_D4s_v3", VCPUs: 4, MemoryMiB: 16384},
+					},
+				},
+			},
+		}
+	}
+
+	mcp := func(name string, updating bool) *mcv1.MachineConfigPool {
+		mcp := &mcv1.MachineConfigPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					poolLabelPrefix + name: "",
+				},
+			},
+			Spec: mcv1.MachineConfigPoolSpec{
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"node-role.kubernetes.io/" + name: "",
+					},
+				},
+			},
+			Status: mcv1.MachineConfigPoolStatus{
+				MachineCount:        3,
+				UpdatedMachineCount: 3,
+				ReadyMachineCount:   3,
+			},
+		}
+		if updating {
+			mcp.Status.UpdatedMachineCount = 1
+		}
+		return mcp
+	}
+
+	node := func(name, vmSize string, roles ...string) *corev1.Node {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					instanceTypeLabel: vmSize,
+				},
+			},
+		}
+		for _, role := range roles {
+			node.Labels["node-role.kubernetes.io/"+role] = ""
+		}
+		return node
+	}
+
+	config := func(pool, cpu, memory string) *mcv1.KubeletConfig {
+		return &mcv1.KubeletConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: configNamePrefix + pool,
+				Labels: map[string]string{
+					managedLabel: "",
+				},
+			},
+			Spec: mcv1.KubeletConfigSpec{
+				AutoSizingReserved: to.BoolPtr(false),
+				MachineConfigPoolSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						poolLabelPrefix + pool: "",
+					},
+				},
+				KubeletConfig: &kruntime.RawExtension{
+					Raw: []byte(`{"systemReserved":{"cpu":"` + cpu + `","memory":"` + memory + `"}}`),
+				},
 			},
 		}
 	}
 
-	emptyConfig := mcv1.KubeletConfig{}
-	config := makeConfig()
+	legacyConfig := &mcv1.KubeletConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: legacyConfigName,
+		},
+		Spec: mcv1.KubeletConfigSpec{
+			AutoSizingReserved: to.BoolPtr(true),
+		},
+	}
+
+	nodes := []kruntime.Object{
+		node("master-0", "Standard_D8s_v3", "master"),
+		node("worker-0", "Standard_D4s_v3", "worker"),
+		node("worker-1", "Standard_D4s_v3", "worker"),
+	}
+
+	masterStatus := arov1alpha1.AutoSizedNodesPoolStatus{
+		MachineConfigPool:    "master",
+		VMSize:               "Standard_D8s_v3",
+		KubeletConfig:        "dynamic-node-master",
+		SystemReservedCPU:    "90m",
+		SystemReservedMemory: "3646Mi",
+	}
+	workerStatus := arov1alpha1.AutoSizedNodesPoolStatus{
+		MachineConfigPool:    "worker",
+		VMSize:               "Standard_D4s_v3",
+		KubeletConfig:        "dynamic-node-worker",
+		SystemReservedCPU:    "80m",
+		SystemReservedMemory: "2663Mi",
+	}
+	applied := func(s arov1alpha1.AutoSizedNodesPoolStatus) arov1alpha1.AutoSizedNodesPoolStatus {
+		s.Applied = true
+		return s
+	}
+	pending := func(s arov1alpha1.AutoSizedNodesPoolStatus, message string) arov1alpha1.AutoSizedNodesPoolStatus {
+		s.Message = message
+		return s
+	}
 
-	tests := []struct {
-		name       string
-		wantErrMsg string
-		client     client.Client
-		wantConfig *mcv1.KubeletConfig
+	for _, tt := range []struct {
+		name        string
+		objects     []kruntime.Object
+		wantConfigs []*mcv1.KubeletConfig
+		wantStatus  []arov1alpha1.AutoSizedNodesPoolStatus
+		wantResult  ctrl.Result
 	}{
 		{
-			name:       "is not needed",
-			client:     fake.NewClientBuilder().WithRuntimeObjects(aro(false)).Build(),
-			wantConfig: &emptyConfig,
-			wantErrMsg: kerrors.NewNotFound(mcv1.Resource("kubeletconfigs"), "dynamic-node").Error(),
+			name:    "is not needed",
+			objects: append([]kruntime.Object{aro(false), mcp("master", false), mcp("worker", false)}, nodes...),
 		},
 		{
-			name:       "is needed and not present already",
-			client:     fake.NewClientBuilder().WithRuntimeObjects(aro(true)).Build(),
-			wantConfig: &config,
+			name:        "is not needed and legacy config is present",
+			objects:     append([]kruntime.Object{aro(false), mcp("master", false), mcp("worker", false), legacyConfig}, nodes...),
+			wantResult:  ctrl.Result{RequeueAfter: rolloutRequeue},
+			wantConfigs: nil,
 		},
 		{
-			name:       "is needed and present already",
-			client:     fake.NewClientBuilder().WithRuntimeObjects(aro(true), &config).Build(),
-			wantConfig: &config,
+			name:        "is not needed and is present",
+			objects:     append([]kruntime.Object{aro(false), mcp("master", false), mcp("worker", false), config("master", "90m", "3646Mi")}, nodes...),
+			wantResult:  ctrl.Result{RequeueAfter: rolloutRequeue},
+			wantConfigs: nil,
 		},
 		{
-			name:       "is not needed and is present",
-			client:     fake.NewClientBuilder().WithRuntimeObjects(aro(false), &config).Build(),
-			wantConfig: &emptyConfig,
-			wantErrMsg: kerrors.NewNotFound(mcv1.Resource("kubeletconfigs"), "dynamic-node").Error(),
+			name:        "is needed and not present already, creates first pool only",
+			objects:     append([]kruntime.Object{aro(true), mcp("master", false), mcp("worker", false)}, nodes...),
+			wantResult:  ctrl.Result{RequeueAfter: rolloutRequeue},
+			wantConfigs: []*mcv1.KubeletConfig{config("master", "90m", "3646Mi")},
+			wantStatus: []arov1alpha1.AutoSizedNodesPoolStatus{
+				applied(masterStatus),
+				pending(workerStatus, "waiting for KubeletConfig dynamic-node-master to roll out"),
+			},
 		},
 		{
-			name: "is needed and config got modified",
-			client: fake.NewClientBuilder().WithRuntimeObjects(
-				aro(true),
-				&mcv1.KubeletConfig{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: configName,
-					},
-					Spec: mcv1.KubeletConfigSpec{
-						AutoSizingReserved: to.BoolPtr(false),
-						MachineConfigPoolSelector: &metav1.LabelSelector{
-							MatchExpressions: []metav1.LabelSelectorRequirement{
-								{
-									Key:      "machineconfiguration.openshift.io/mco-built-in",
-									Operator: metav1.LabelSelectorOpExists,
-								},
-							},
-						},
-					},
-				}).Build(),
-			wantConfig: &config,
+			name:        "is needed, waits for rollout to complete",
+			objects:     append([]kruntime.Object{aro(true), mcp("master", true), mcp("worker", false), config("master", "90m", "3646Mi")}, nodes...),
+			wantResult:  ctrl.Result{RequeueAfter: rolloutRequeue},
+			wantConfigs: []*mcv1.KubeletConfig{config("master", "90m", "3646Mi")},
+			wantStatus: []arov1alpha1.AutoSizedNodesPoolStatus{
+				applied(masterStatus),
+				pending(workerStatus, "waiting for MachineConfigPools master to roll out"),
+			},
 		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
+		{
+			name:        "is needed, creates next pool once rollout completes",
+			objects:     append([]kruntime.Object{aro(true), mcp("master", false), mcp("worker", false), config("master", "90m", "3646Mi")}, nodes...),
+			wantResult:  ctrl.Result{RequeueAfter: rolloutRequeue},
+			wantConfigs: []*mcv1.KubeletConfig{config("master", "90m", "3646Mi"), config("worker", "80m", "2663Mi")},
+			wantStatus: []arov1alpha1.AutoSizedNodesPoolStatus{
+				applied(masterStatus),
+				applied(workerStatus),
+			},
+		},
+		{
+			name:        "is needed and present already",
+			objects:     append([]kruntime.Object{aro(true), mcp("master", false), mcp("worker", false), config("master", "90m", "3646Mi"), config("worker", "80m", "2663Mi")}, nodes...),
+			wantConfigs: []*mcv1.KubeletConfig{config("master", "90m", "3646Mi"), config("worker", "80m", "2663Mi")},
+			wantStatus: []arov1alpha1.AutoSizedNodesPoolStatus{
+				applied(masterStatus),
+				applied(workerStatus),
+			},
+		},
+		{
+			name:        "is needed and config got modified",
+			objects:     append([]kruntime.Object{aro(true), mcp("master", false), mcp("worker", false), config("master", "90m", "3646Mi"), config("worker", "1", "1Gi")}, nodes...),
+			wantResult:  ctrl.Result{RequeueAfter: rolloutRequeue},
+			wantConfigs: []*mcv1.KubeletConfig{config("master", "90m", "3646Mi"), config("worker", "80m", "2663Mi")},
+			wantStatus: []arov1alpha1.AutoSizedNodesPoolStatus{
+				applied(masterStatus),
+				applied(workerStatus),
+			},
+		},
+		{
+			name:        "is needed, removes legacy config first",
+			objects:     append([]kruntime.Object{aro(true), mcp("master", false), mcp("worker", false), legacyConfig}, nodes...),
+			wantResult:  ctrl.Result{RequeueAfter: rolloutRequeue},
+			wantConfigs: nil,
+			wantStatus: []arov1alpha1.AutoSizedNodesPoolStatus{
+				pending(masterStatus, "waiting for KubeletConfig dynamic-node to roll out"),
+				pending(workerStatus, "waiting for KubeletConfig dynamic-node to roll out"),
+			},
+		},
+		{
+			name: "is needed, custom pool takes nodes from the worker pool",
+			objects: []kruntime.Object{
+				aro(true), mcp("infra", false), mcp("master", false), mcp("worker", false),
+				config("master", "90m", "3646Mi"), config("worker", "80m", "2663Mi"),
+				node("master-0", "Standard_D8s_v3", "master"),
+				node("infra-0", "Standard_D8s_v3", "worker", "infra"),
+				node("worker-0", "Standard_D4s_v3", "worker"),
+			},
+			wantResult:  ctrl.Result{RequeueAfter: rolloutRequeue},
+			wantConfigs: []*mcv1.KubeletConfig{config("infra", "90m", "3646Mi"), config("master", "90m", "3646Mi"), config("worker", "80m", "2663Mi")},
+			wantStatus: []arov1alpha1.AutoSizedNodesPoolStatus{
+				{
+					MachineConfigPool:    "infra",
+					VMSize:               "Standard_D8s_v3",
+					KubeletConfig:        "dynamic-node-infra",
+					SystemReservedCPU:    "90m",
+					SystemReservedMemory: "3646Mi",
+					Applied:              true,
+				},
+				applied(masterStatus),
+				applied(workerStatus),
+			},
+		},
+		{
+			name: "is needed, unknown VM size is reported",
+			objects: []kruntime.Object{
+				aro(true), mcp("master", false), mcp("worker", false),
+				config("master", "90m", "3646Mi"),
+				node("master-0", "Standard_D8s_v3", "master"),
+				node("worker-0", "Standard_E4s_v3", "worker"),
+			},
+			wantConfigs: []*mcv1.KubeletConfig{config("master", "90m", "3646Mi")},
+			wantStatus: []arov1alpha1.AutoSizedNodesPoolStatus{
+				applied(masterStatus),
+				{
+					MachineConfigPool: "worker",
+					VMSize:            "Standard_E4s_v3",
+					Message:           "capacity of VM size Standard_E4s_v3 is unknown",
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
-			r := Reconciler{
-				client: test.client,
+			r := &Reconciler{
+				client: fake.NewClientBuilder().WithRuntimeObjects(tt.objects...).Build(),
 				log:    logrus.NewEntry(logrus.StandardLogger()),
 			}
-			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "openshift-azure-operator", Name: "aro"}})
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: arov1alpha1.SingletonClusterName}})
+			utilerror.AssertErrorMessage(t, err, "")
+
+			if result != tt.wantResult {
+				t.Errorf("got result %v, wanted %v", result, tt.wantResult)
+			}
+
+			configs := &mcv1.KubeletConfigList{}
+			err = r.client.List(ctx, configs)
 			if err != nil {
-				t.Error(err)
+				t.Fatal(err)
 			}
 
-			key := types.NamespacedName{
-				Name: configName,
+			if len(configs.Items) != len(tt.wantConfigs) {
+				t.Fatalf("got %d KubeletConfigs, wanted %d", len(configs.Items), len(tt.wantConfigs))
+			}
+			for i, want := range tt.wantConfigs {
+				if configs.Items[i].Name != want.Name {
+					t.Errorf("got KubeletConfig %s, wanted %s", configs.Items[i].Name, want.Name)
+				}
+				if !reflect.DeepEqual(want.Spec, configs.Items[i].Spec) {
+					t.Error(cmp.Diff(want.Spec, configs.Items[i].Spec))
+				}
 			}
-			var c mcv1.KubeletConfig
 
-			err = r.client.Get(ctx, key, &c)
-			utilerror.AssertErrorMessage(t, err, test.wantErrMsg)
+			cluster := &arov1alpha1.Cluster{}
+			err = r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
 
-			if !reflect.DeepEqual(test.wantConfig.Spec, c.Spec) {
-				t.Error(cmp.Diff(test.wantConfig.Spec, c.Spec))
+			if !reflect.DeepEqual(tt.wantStatus, cluster.Status.AutoSizedNodes) {
+				t.Error(cmp.Diff(tt.wantStatus, cluster.Status.AutoSizedNodes))
 			}
+		})
+	}
+}
 
-			if result != (ctrl.Result{}) {
-				t.Error("reconcile returned an unexpected result")
+func TestSystemReserved(t *testing.T) {
+	for _, tt := range []struct {
+		vcpus      int
+		memoryMiB  int64
+		wantCPU    string
+		wantMemory string
+	}{
+		{vcpus: 2, memoryMiB: 8192, wantCPU: "70m", wantMemory: "1844Mi"},
+		{vcpus: 4, memoryMiB: 16384, wantCPU: "80m", wantMemory: "2663Mi"},
+		{vcpus: 8, memoryMiB: 32768, wantCPU: "90m", wantMemory: "3646Mi"},
+		{vcpus: 64, memoryMiB: 262144, wantCPU: "230m", wantMemory: "12166Mi"},
+	} {
+		t.Run(strconv.Itoa(tt.vcpus), func(t *testing.T) {
+			if cpu := systemReservedCPU(tt.vcpus).String(); cpu != tt.wantCPU {
+				t.Errorf("got cpu %s, wanted %s", cpu, tt.wantCPU)
+			}
+			if memory := systemReservedMemory(tt.memoryMiB).String(); memory != tt.wantMemory {
+				t.Errorf("got memory %s, wanted %s", memory, tt.wantMemory)
 			}
 		})
 	}
@@ -3,7 +3,14 @@ package autosizednodes
 // Copyright (c) Microsoft Corporation.
 // Licensed under the Apache License 2.0.
 
-// autosizednodes monitors/creates/removes "dynamic-node" KubeletConfig
-// that tells machine-config-operator to turn on auto sized nodes feature
-// the code that is executed by the mco:
+// autosizednodes computes system-reserved CPU and memory for each
+// MachineConfigPool from the capacity of the VM size of its nodes, as
+// populated by the RP in cluster.spec.autoSizedNodes, and manages one
+// "dynamic-node-<pool>" KubeletConfig per pool.  The values follow the same
+// tiers as the mco's own auto sizing:
 // - https://github.com/openshift/machine-config-operator/blob/fbc4d8e46a7746442f4de3651113d2181d458b12/templates/common/_base/files/kubelet-auto-sizing.yaml
+//
+// KubeletConfig changes cause the machine-config-operator to roll out and
+// reboot the nodes of a pool, so the controller only makes one change at a
+// time, and only when no pool is updating.  What has been applied is reported
+// in cluster.status.autoSizedNodes.
@@ -0,0 +1,68 @@
+package autosizednodes
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// tier reserves a fraction of the capacity up to and including a given size.
+// A size of 0 means "everything above the previous tier".
+type tier struct {
+	size     float64
+	fraction float64
+}
+
+// cpuTiers reserve 6% of the first core, 1% of the next core, 0.5% of the next
+// 2 cores and 0.25% of any cores above 4
+var cpuTiers = []tier{
+	{size: 1, fraction: 0.06},
+	{size: 1, fraction: 0.01},
+	{size: 2, fraction: 0.005},
+	{fraction: 0.0025},
+}
+
+// memoryTiers reserve 25% of the first 4GiB of memory, 20% of the next 4GiB,
+// 10% of the next 8GiB, 6% of the next 112GiB and 2% of any memory above 128GiB
+var memoryTiers = []tier{
+	{size: 4 * 1024, fraction: 0.25},
+	{size: 4 * 1024, fraction: 0.2},
+	{size: 8 * 1024, fraction: 0.1},
+	{size: 112 * 1024, fraction: 0.06},
+	{fraction: 0.02},
+}
+
+func reserve(capacity float64, tiers []tier) float64 {
+	var reserved float64
+	for _, t := range tiers {
+		size := capacity
+		if t.size != 0 {
+			size = math.Min(capacity, t.size)
+		}
+
+		reserved += size * t.fraction
+		capacity -= size
+
+		if capacity <= 0 {
+			break
+		}
+	}
+	return reserved
+}
+
+// systemReservedCPU returns the CPU to reserve for the kubelet and system
+// daemons on a node with the given number of vCPUs
+func systemReservedCPU(vcpus int) *resource.Quantity {
+	millicores := math.Ceil(reserve(float64(vcpus), cpuTiers) * 1000)
+	return resource.NewMilliQuantity(int64(millicores), resource.DecimalSI)
+}
+
+// systemReservedMemory returns the memory to reserve for the kubelet and
+// system daemons on a node with the given amount of memory
+func systemReservedMemory(memoryMiB int64) *resource.Quantity {
+	mib := math.Ceil(reserve(float64(memoryMiB), memoryTiers))
+	return resource.NewQuantity(int64(mib)*1024*1024, resource.BinarySI)
+}
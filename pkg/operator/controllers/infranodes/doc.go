@@ -0,0 +1,17 @@
+package infranodes
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// infranodes creates dedicated infrastructure machine sets when they are
+// requested in cluster.spec.infraNodes, and moves the default
+// IngressController, the image registry and the monitoring stack onto them.
+//
+// One infra machine set is cloned from each ARO worker machine set, so the
+// infra nodes are spread across the same zones and subnets as the workers.
+// Infra nodes are labelled node-role.kubernetes.io/infra and tainted so that
+// only workloads which tolerate the taint are scheduled onto them.
+//
+// Workloads are only moved once enough infra nodes are ready to host them.
+// When infra nodes are no longer requested, the placement is reverted before
+// the infra machine sets are deleted.
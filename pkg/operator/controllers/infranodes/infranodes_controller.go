@@ -0,0 +1,179 @@
+package infranodes
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/Azure/ARO-RP/pkg/operator"
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/operator/controllers/base"
+	"github.com/Azure/ARO-RP/pkg/util/ready"
+)
+
+const (
+	ControllerName = "InfraNodes"
+
+	// minReadyNodes is the number of ready infra nodes needed before
+	// workloads are moved onto them
+	minReadyNodes = 2
+
+	readyRequeue = time.Minute
+)
+
+// Reconciler creates the infra machine sets requested in the cluster spec
+// and places infrastructure workloads onto their nodes
+type Reconciler struct {
+	base.AROController
+}
+
+func NewReconciler(log *logrus.Entry, client client.Client) *Reconciler {
+	return &Reconciler{
+		AROController: base.AROController{
+			Log:    log,
+			Client: client,
+			Name:   ControllerName,
+		},
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	instance, err := r.GetCluster(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !instance.Spec.OperatorFlags.GetSimpleBoolean(operator.InfraNodesEnabled) {
+		r.Log.Debug("controller is disabled")
+		return reconcile.Result{}, nil
+	}
+
+	r.Log.Debug("running")
+
+	if instance.Spec.InfraNodes == nil {
+		err = r.removeInfraNodes(ctx)
+		if err != nil {
+			r.Log.Error(err)
+			r.SetDegraded(ctx, err)
+			return reconcile.Result{}, err
+		}
+
+		r.ClearConditions(ctx)
+		return reconcile.Result{}, nil
+	}
+
+	err = r.ensureMachineSets(ctx, instance)
+	if err != nil {
+		r.Log.Error(err)
+		r.SetDegraded(ctx, err)
+		return reconcile.Result{}, err
+	}
+
+	readyNodes, err := r.readyInfraNodes(ctx)
+	if err != nil {
+		r.Log.Error(err)
+		r.SetDegraded(ctx, err)
+		return reconcile.Result{}, err
+	}
+
+	wantReady := minReadyNodes
+	if int(instance.Spec.InfraNodes.Replicas) < wantReady {
+		wantReady = int(instance.Spec.InfraNodes.Replicas)
+	}
+
+	if readyNodes < wantReady {
+		r.Log.Infof("waiting for infra nodes: %d of %d ready", readyNodes, wantReady)
+		r.ClearConditions(ctx)
+		return reconcile.Result{RequeueAfter: readyRequeue}, nil
+	}
+
+	err = r.ensurePlacement(ctx)
+	if err != nil {
+		r.Log.Error(err)
+		r.SetDegraded(ctx, err)
+		return reconcile.Result{}, err
+	}
+
+	r.ClearConditions(ctx)
+	return reconcile.Result{}, nil
+}
+
+// removeInfraNodes moves the workloads back before it deletes the infra
+// machine sets, so that they are never left without nodes to run on
+func (r *Reconciler) removeInfraNodes(ctx context.Context) error {
+	err := r.removePlacement(ctx)
+	if err != nil {
+		return err
+	}
+
+	machineSets, err := r.managedMachineSets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range machineSets {
+		r.Log.Infof("deleting infra machine set %s", machineSets[i].Name)
+		err = r.Client.Delete(ctx, &machineSets[i])
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) readyInfraNodes(ctx context.Context) (int, error) {
+	nodes := &corev1.NodeList{}
+	err := r.Client.List(ctx, nodes, client.HasLabels{infraNodeLabel})
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for i := range nodes.Items {
+		if ready.NodeIsReady(&nodes.Items[i]) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// SetupWithManager setup our manager
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	aroClusterPredicate := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return strings.EqualFold(arov1alpha1.SingletonClusterName, o.GetName())
+	})
+
+	machineSetPredicate := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		_, managed := o.GetLabels()[managedLabel]
+		return managed
+	})
+
+	infraNodePredicate := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		_, infra := o.GetLabels()[infraNodeLabel]
+		return infra
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arov1alpha1.Cluster{}, builder.WithPredicates(aroClusterPredicate)).
+		Watches(&source.Kind{Type: &machinev1beta1.MachineSet{}}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(machineSetPredicate)).
+		Watches(&source.Kind{Type: &corev1.Node{}}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(infraNodePredicate)).
+		Named(ControllerName).
+		Complete(r)
+}
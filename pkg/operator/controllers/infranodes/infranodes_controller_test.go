@@ -0,0 +1,367 @@
+package infranodes
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/ghodss/yaml"
+	imageregistryv1 "github.com/openshift/api/imageregistry/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Azure/ARO-RP/pkg/operator"
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/util/cmp"
+	_ "github.com/Azure/ARO-RP/pkg/util/scheme"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func workerMachineSet(zone string) *machinev1beta1.MachineSet {
+	name := "aro-fake-worker-eastus" + zone
+	return &machinev1beta1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: machineSetsNamespace,
+			Labels: map[string]string{
+				"machine.openshift.io/cluster-api-cluster": "aro-fake",
+				machineRoleLabel: "worker",
+				machineTypeLabel: "worker",
+			},
+		},
+		Spec: machinev1beta1.MachineSetSpec{
+			Replicas: to.Int32Ptr(1),
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"machine.openshift.io/cluster-api-cluster": "aro-fake",
+					machineSetLabel: name,
+				},
+			},
+			Template: machinev1beta1.MachineTemplateSpec{
+				ObjectMeta: machinev1beta1.ObjectMeta{
+					Labels: map[string]string{
+						"machine.openshift.io/cluster-api-cluster": "aro-fake",
+						machineRoleLabel: "worker",
+						machineTypeLabel: "worker",
+						machineSetLabel:  name,
+					},
+				},
+				Spec: machinev1beta1.MachineSpec{
+					ProviderSpec: machinev1beta1.ProviderSpec{
+						Value: &kruntime.RawExtension{
+							Raw: []byte(`{"vmSize":"Standard_D4s_v3","zone":"` + zone + `","osDisk":{"diskSizeGB":128}}`),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func infraNode(name string, ready bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				infraNodeLabel: "",
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: status,
+				},
+			},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	infraPlacement := &operatorv1.NodePlacement{
+		NodeSelector: &metav1.LabelSelector{
+			MatchLabels: infraNodeSelector(),
+		},
+		Tolerations: infraTolerations(),
+	}
+
+	ingressController := func(placement *operatorv1.NodePlacement) *operatorv1.IngressController {
+		return &operatorv1.IngressController{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ingressControllerName.Name,
+				Namespace: ingressControllerName.Namespace,
+			},
+			Spec: operatorv1.IngressControllerSpec{
+				NodePlacement: placement,
+			},
+		}
+	}
+
+	imageRegistry := func(infra bool) *imageregistryv1.Config {
+		config := &imageregistryv1.Config{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: imageRegistryName.Name,
+			},
+		}
+		if infra {
+			config.Spec.NodeSelector = infraNodeSelector()
+			config.Spec.Tolerations = infraTolerations()
+		}
+		return config
+	}
+
+	monitoringConfig := func(config string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      monitoringName.Name,
+				Namespace: monitoringName.Namespace,
+			},
+			Data: map[string]string{
+				"config.yaml": config,
+			},
+		}
+	}
+
+	infraMonitoringConfig := func() string {
+		config := map[string]interface{}{
+			"enableUserWorkload": true,
+		}
+		for _, name := range monitoringComponents {
+			config[name] = map[string]interface{}{
+				"nodeSelector": infraNodeSelector(),
+				"tolerations":  infraTolerations(),
+			}
+		}
+		b, err := yaml.Marshal(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	infraNodesSpec := &arov1alpha1.InfraNodesSpec{
+		VMSize:   "Standard_D8s_v3",
+		Replicas: 3,
+	}
+
+	for _, tt := range []struct {
+		name            string
+		infraNodes      *arov1alpha1.InfraNodesSpec
+		objects         []client.Object
+		wantMachineSets map[string]int32
+		wantInfra       bool
+		wantRequeue     bool
+		wantErr         string
+	}{
+		{
+			name:       "infra nodes requested, machine sets are created and placement waits for nodes",
+			infraNodes: infraNodesSpec,
+			objects: []client.Object{
+				workerMachineSet("1"), workerMachineSet("2"), workerMachineSet("3"),
+				ingressController(nil), imageRegistry(false), monitoringConfig("enableUserWorkload: true\n"),
+				infraNode("infra-1", true), infraNode("infra-2", false),
+			},
+			wantMachineSets: map[string]int32{
+				"aro-fake-infra-eastus1": 1,
+				"aro-fake-infra-eastus2": 1,
+				"aro-fake-infra-eastus3": 1,
+			},
+			wantRequeue: true,
+		},
+		{
+			name: "infra nodes requested, replicas are spread across zones",
+			infraNodes: &arov1alpha1.InfraNodesSpec{
+				VMSize:   "Standard_D8s_v3",
+				Replicas: 4,
+			},
+			objects: []client.Object{
+				workerMachineSet("1"), workerMachineSet("2"), workerMachineSet("3"),
+				ingressController(nil), imageRegistry(false), monitoringConfig(""),
+			},
+			wantMachineSets: map[string]int32{
+				"aro-fake-infra-eastus1": 2,
+				"aro-fake-infra-eastus2": 1,
+				"aro-fake-infra-eastus3": 1,
+			},
+			wantRequeue: true,
+		},
+		{
+			name:       "infra nodes ready, workloads are moved",
+			infraNodes: infraNodesSpec,
+			objects: []client.Object{
+				workerMachineSet("1"), workerMachineSet("2"), workerMachineSet("3"),
+				ingressController(nil), imageRegistry(false), monitoringConfig("enableUserWorkload: true\n"),
+				infraNode("infra-1", true), infraNode("infra-2", true),
+			},
+			wantMachineSets: map[string]int32{
+				"aro-fake-infra-eastus1": 1,
+				"aro-fake-infra-eastus2": 1,
+				"aro-fake-infra-eastus3": 1,
+			},
+			wantInfra: true,
+		},
+		{
+			name: "infra nodes no longer requested, workloads are moved back and machine sets deleted",
+			objects: func() []client.Object {
+				infraMachineSet, err := infraMachineSet(workerMachineSet("1"), infraNodesSpec, to.Int32Ptr(3))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return []client.Object{
+					workerMachineSet("1"), infraMachineSet,
+					ingressController(infraPlacement), imageRegistry(true), monitoringConfig(infraMonitoringConfig()),
+				}
+			}(),
+			wantMachineSets: map[string]int32{},
+		},
+		{
+			name:       "no worker machine sets to clone",
+			infraNodes: infraNodesSpec,
+			objects: []client.Object{
+				ingressController(nil), imageRegistry(false), monitoringConfig(""),
+			},
+			wantMachineSets: map[string]int32{},
+			wantErr:         "no worker machine sets found to create infra machine sets from",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			instance := &arov1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: arov1alpha1.SingletonClusterName},
+				Spec: arov1alpha1.ClusterSpec{
+					InfraID:    "aro-fake",
+					InfraNodes: tt.infraNodes,
+					OperatorFlags: arov1alpha1.OperatorFlags{
+						operator.InfraNodesEnabled: operator.FlagTrue,
+					},
+				},
+			}
+
+			clientFake := ctrlfake.NewClientBuilder().
+				WithObjects(instance).
+				WithObjects(tt.objects...).
+				Build()
+
+			r := NewReconciler(logrus.NewEntry(logrus.StandardLogger()), clientFake)
+
+			result, err := r.Reconcile(ctx, ctrl.Request{})
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if (result.RequeueAfter != 0) != tt.wantRequeue {
+				t.Errorf("got requeue after %v", result.RequeueAfter)
+			}
+
+			machineSets := &machinev1beta1.MachineSetList{}
+			err = clientFake.List(ctx, machineSets, client.HasLabels{managedLabel})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotMachineSets := map[string]int32{}
+			for _, ms := range machineSets.Items {
+				gotMachineSets[ms.Name] = *ms.Spec.Replicas
+			}
+
+			if !reflect.DeepEqual(gotMachineSets, tt.wantMachineSets) {
+				t.Error(cmp.Diff(gotMachineSets, tt.wantMachineSets))
+			}
+
+			if tt.wantErr != "" {
+				return
+			}
+
+			ic := &operatorv1.IngressController{}
+			err = clientFake.Get(ctx, ingressControllerName, ic)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if (ic.Spec.NodePlacement != nil) != tt.wantInfra {
+				t.Errorf("got ingresscontroller placement %v", ic.Spec.NodePlacement)
+			}
+
+			config := &imageregistryv1.Config{}
+			err = clientFake.Get(ctx, imageRegistryName, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if (config.Spec.NodeSelector != nil) != tt.wantInfra {
+				t.Errorf("got image registry node selector %v", config.Spec.NodeSelector)
+			}
+
+			cm := &corev1.ConfigMap{}
+			err = clientFake.Get(ctx, monitoringName, cm)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if strings.Contains(cm.Data["config.yaml"], infraNodeLabel) != tt.wantInfra {
+				t.Errorf("got monitoring config %q", cm.Data["config.yaml"])
+			}
+		})
+	}
+}
+
+func TestInfraMachineSet(t *testing.T) {
+	ms, err := infraMachineSet(workerMachineSet("2"), &arov1alpha1.InfraNodesSpec{
+		VMSize:     "Standard_D8s_v3",
+		DiskSizeGB: 256,
+		Replicas:   3,
+	}, to.Int32Ptr(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ms.Name != "aro-fake-infra-eastus2" {
+		t.Error(ms.Name)
+	}
+
+	for _, labels := range []map[string]string{ms.Labels, ms.Spec.Template.Labels} {
+		if labels[machineRoleLabel] != infraRole || labels[machineTypeLabel] != infraRole {
+			t.Errorf("got labels %v", labels)
+		}
+	}
+
+	if ms.Spec.Selector.MatchLabels[machineSetLabel] != ms.Name ||
+		ms.Spec.Template.Labels[machineSetLabel] != ms.Name {
+		t.Error("machine set selector does not select its machines")
+	}
+
+	if _, found := ms.Spec.Template.Spec.Labels[infraNodeLabel]; !found {
+		t.Error("infra node label missing")
+	}
+
+	if len(ms.Spec.Template.Spec.Taints) != 1 || ms.Spec.Template.Spec.Taints[0].Key != infraTaintKey {
+		t.Errorf("got taints %v", ms.Spec.Template.Spec.Taints)
+	}
+
+	providerSpec := &machinev1beta1.AzureMachineProviderSpec{}
+	err = json.Unmarshal(ms.Spec.Template.Spec.ProviderSpec.Value.Raw, providerSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if providerSpec.VMSize != "Standard_D8s_v3" || providerSpec.OSDisk.DiskSizeGB != 256 || providerSpec.Zone == nil || *providerSpec.Zone != "2" {
+		t.Errorf("got provider spec %#v", providerSpec)
+	}
+}
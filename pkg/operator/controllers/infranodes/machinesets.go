@@ -0,0 +1,255 @@
+package infranodes
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+)
+
+const (
+	machineSetsNamespace = "openshift-machine-api"
+	machineRoleLabel     = "machine.openshift.io/cluster-api-machine-role"
+	machineTypeLabel     = "machine.openshift.io/cluster-api-machine-type"
+	machineSetLabel      = "machine.openshift.io/cluster-api-machineset"
+	managedLabel         = "aro.openshift.io/infranodes"
+
+	infraRole      = "infra"
+	infraNodeLabel = "node-role.kubernetes.io/infra"
+	infraTaintKey  = "node-role.kubernetes.io/infra"
+)
+
+// ensureMachineSets creates or updates one infra machine set per ARO worker
+// machine set, and deletes infra machine sets whose worker machine set has
+// gone away
+func (r *Reconciler) ensureMachineSets(ctx context.Context, instance *arov1alpha1.Cluster) error {
+	workers := &machinev1beta1.MachineSetList{}
+	err := r.Client.List(ctx, workers, client.InNamespace(machineSetsNamespace), client.MatchingLabels{machineRoleLabel: "worker"})
+	if err != nil {
+		return err
+	}
+
+	// only clone the machine sets created by ARO
+	var templates []machinev1beta1.MachineSet
+	for _, ms := range workers.Items {
+		if strings.Contains(ms.Name, instance.Spec.InfraID) && strings.Contains(ms.Name, "-worker-") {
+			templates = append(templates, ms)
+		}
+	}
+
+	if len(templates) == 0 {
+		return fmt.Errorf("no worker machine sets found to create infra machine sets from")
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+
+	desired := map[string]*machinev1beta1.MachineSet{}
+	for i, template := range templates {
+		ms, err := infraMachineSet(&template, instance.Spec.InfraNodes, replicasFor(i, len(templates), instance.Spec.InfraNodes.Replicas))
+		if err != nil {
+			return err
+		}
+		desired[ms.Name] = ms
+	}
+
+	existing, err := r.managedMachineSets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range existing {
+		if _, found := desired[existing[i].Name]; !found {
+			r.Log.Infof("deleting infra machine set %s", existing[i].Name)
+			err = r.Client.Delete(ctx, &existing[i])
+			if err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		err = r.ensureMachineSet(ctx, desired[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) ensureMachineSet(ctx context.Context, ms *machinev1beta1.MachineSet) error {
+	existing := &machinev1beta1.MachineSet{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: ms.Namespace, Name: ms.Name}, existing)
+	if kerrors.IsNotFound(err) {
+		r.Log.Infof("creating infra machine set %s", ms.Name)
+		return r.Client.Create(ctx, ms)
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Spec.Template.Spec.ProviderSpec.Value == nil {
+		return fmt.Errorf("machine set %s: provider spec missing", existing.Name)
+	}
+
+	existingProviderSpec := &machinev1beta1.AzureMachineProviderSpec{}
+	err = json.Unmarshal(existing.Spec.Template.Spec.ProviderSpec.Value.Raw, existingProviderSpec)
+	if err != nil {
+		return fmt.Errorf("machine set %s: failed to read provider spec: %v", existing.Name, err)
+	}
+
+	providerSpec := &machinev1beta1.AzureMachineProviderSpec{}
+	err = json.Unmarshal(ms.Spec.Template.Spec.ProviderSpec.Value.Raw, providerSpec)
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(existing.Spec.Replicas, ms.Spec.Replicas) &&
+		existingProviderSpec.VMSize == providerSpec.VMSize &&
+		existingProviderSpec.OSDisk.DiskSizeGB == providerSpec.OSDisk.DiskSizeGB {
+		return nil
+	}
+
+	// changes to the provider spec only apply to machines created from now
+	// on; existing machines keep their size
+	existingProviderSpec.VMSize = providerSpec.VMSize
+	existingProviderSpec.OSDisk.DiskSizeGB = providerSpec.OSDisk.DiskSizeGB
+
+	raw, err := json.Marshal(existingProviderSpec)
+	if err != nil {
+		return err
+	}
+
+	existing.Spec.Replicas = ms.Spec.Replicas
+	existing.Spec.Template.Spec.ProviderSpec.Value = &kruntime.RawExtension{Raw: raw}
+
+	r.Log.Infof("updating infra machine set %s", ms.Name)
+	return r.Client.Update(ctx, existing)
+}
+
+func (r *Reconciler) managedMachineSets(ctx context.Context) ([]machinev1beta1.MachineSet, error) {
+	machineSets := &machinev1beta1.MachineSetList{}
+	err := r.Client.List(ctx, machineSets, client.InNamespace(machineSetsNamespace), client.HasLabels{managedLabel})
+	if err != nil {
+		return nil, err
+	}
+
+	return machineSets.Items, nil
+}
+
+// replicasFor spreads the requested replicas across the machine sets, giving
+// the remainder to the first ones
+func replicasFor(i, machineSets int, replicas int32) *int32 {
+	n := replicas / int32(machineSets)
+	if int32(i) < replicas%int32(machineSets) {
+		n++
+	}
+	return &n
+}
+
+// infraMachineSet clones a worker machine set into an infra machine set in
+// the same zone and subnet
+func infraMachineSet(worker *machinev1beta1.MachineSet, spec *arov1alpha1.InfraNodesSpec, replicas *int32) (*machinev1beta1.MachineSet, error) {
+	name := strings.Replace(worker.Name, "-worker-", "-infra-", 1)
+
+	if worker.Spec.Template.Spec.ProviderSpec.Value == nil {
+		return nil, fmt.Errorf("machine set %s: provider spec missing", worker.Name)
+	}
+
+	providerSpec := &machinev1beta1.AzureMachineProviderSpec{}
+	err := json.Unmarshal(worker.Spec.Template.Spec.ProviderSpec.Value.Raw, providerSpec)
+	if err != nil {
+		return nil, fmt.Errorf("machine set %s: failed to read provider spec: %v", worker.Name, err)
+	}
+
+	providerSpec.VMSize = spec.VMSize
+	if spec.DiskSizeGB != 0 {
+		providerSpec.OSDisk.DiskSizeGB = spec.DiskSizeGB
+	}
+
+	raw, err := json.Marshal(providerSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	machineLabels := map[string]string{}
+	for k, v := range worker.Spec.Template.Labels {
+		machineLabels[k] = v
+	}
+	machineLabels[machineRoleLabel] = infraRole
+	machineLabels[machineTypeLabel] = infraRole
+	machineLabels[machineSetLabel] = name
+
+	labels := map[string]string{}
+	for k, v := range worker.Labels {
+		labels[k] = v
+	}
+	labels[machineRoleLabel] = infraRole
+	labels[machineTypeLabel] = infraRole
+	labels[managedLabel] = "true"
+
+	selector := map[string]string{}
+	for k, v := range worker.Spec.Selector.MatchLabels {
+		selector[k] = v
+	}
+	selector[machineSetLabel] = name
+
+	return &machinev1beta1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: worker.Namespace,
+			Labels:    labels,
+		},
+		Spec: machinev1beta1.MachineSetSpec{
+			Replicas: replicas,
+			Selector: metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			Template: machinev1beta1.MachineTemplateSpec{
+				ObjectMeta: machinev1beta1.ObjectMeta{
+					Labels: machineLabels,
+				},
+				Spec: machinev1beta1.MachineSpec{
+					ObjectMeta: machinev1beta1.ObjectMeta{
+						Labels: map[string]string{
+							infraNodeLabel: "",
+						},
+					},
+					Taints: []corev1.Taint{
+						{
+							Key:    infraTaintKey,
+							Value:  "reserved",
+							Effect: corev1.TaintEffectNoSchedule,
+						},
+					},
+					ProviderSpec: machinev1beta1.ProviderSpec{
+						Value: &kruntime.RawExtension{
+							Raw: raw,
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,225 @@
+package infranodes
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ghodss/yaml"
+	imageregistryv1 "github.com/openshift/api/imageregistry/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	ingressControllerName = types.NamespacedName{Namespace: "openshift-ingress-operator", Name: "default"}
+	imageRegistryName     = types.NamespacedName{Name: "cluster"}
+	monitoringName        = types.NamespacedName{Namespace: "openshift-monitoring", Name: "cluster-monitoring-config"}
+
+	// monitoringComponents are the cluster-monitoring-config sections which
+	// accept a nodeSelector and tolerations
+	monitoringComponents = []string{
+		"alertmanagerMain",
+		"k8sPrometheusAdapter",
+		"kubeStateMetrics",
+		"openshiftStateMetrics",
+		"prometheusK8s",
+		"prometheusOperator",
+		"telemeterClient",
+		"thanosQuerier",
+	}
+)
+
+func infraNodeSelector() map[string]string {
+	return map[string]string{
+		infraNodeLabel: "",
+	}
+}
+
+func infraTolerations() []corev1.Toleration {
+	return []corev1.Toleration{
+		{
+			Key:      infraTaintKey,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		},
+	}
+}
+
+func (r *Reconciler) ensurePlacement(ctx context.Context) error {
+	for _, f := range []func(context.Context, bool) error{
+		r.placeIngressController,
+		r.placeImageRegistry,
+		r.placeMonitoring,
+	} {
+		err := f(ctx, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) removePlacement(ctx context.Context) error {
+	for _, f := range []func(context.Context, bool) error{
+		r.placeIngressController,
+		r.placeImageRegistry,
+		r.placeMonitoring,
+	} {
+		err := f(ctx, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// placeIngressController moves the default router onto the infra nodes, or
+// back to the default placement.  A placement which was not set by us is
+// left alone on removal.
+func (r *Reconciler) placeIngressController(ctx context.Context, infra bool) error {
+	ic := &operatorv1.IngressController{}
+	err := r.Client.Get(ctx, ingressControllerName, ic)
+	if err != nil {
+		return err
+	}
+
+	placement := &operatorv1.NodePlacement{
+		NodeSelector: &metav1.LabelSelector{
+			MatchLabels: infraNodeSelector(),
+		},
+		Tolerations: infraTolerations(),
+	}
+
+	switch {
+	case infra && !reflect.DeepEqual(ic.Spec.NodePlacement, placement):
+		ic.Spec.NodePlacement = placement
+	case !infra && reflect.DeepEqual(ic.Spec.NodePlacement, placement):
+		ic.Spec.NodePlacement = nil
+	default:
+		return nil
+	}
+
+	r.Log.Infof("updating placement of ingresscontroller %s", ingressControllerName.Name)
+	return r.Client.Update(ctx, ic)
+}
+
+func (r *Reconciler) placeImageRegistry(ctx context.Context, infra bool) error {
+	config := &imageregistryv1.Config{}
+	err := r.Client.Get(ctx, imageRegistryName, config)
+	if err != nil {
+		return err
+	}
+
+	isInfra := reflect.DeepEqual(config.Spec.NodeSelector, infraNodeSelector()) &&
+		reflect.DeepEqual(config.Spec.Tolerations, infraTolerations())
+
+	switch {
+	case infra && !isInfra:
+		config.Spec.NodeSelector = infraNodeSelector()
+		config.Spec.Tolerations = infraTolerations()
+	case !infra && isInfra:
+		config.Spec.NodeSelector = nil
+		config.Spec.Tolerations = nil
+	default:
+		return nil
+	}
+
+	r.Log.Info("updating placement of the image registry")
+	return r.Client.Update(ctx, config)
+}
+
+// placeMonitoring sets the nodeSelector and tolerations of each monitoring
+// component in cluster-monitoring-config.  The rest of the configuration is
+// preserved, as it is also managed by the monitoring controller and by
+// customers.
+func (r *Reconciler) placeMonitoring(ctx context.Context, infra bool) error {
+	cm := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, monitoringName, cm)
+	if kerrors.IsNotFound(err) {
+		if !infra {
+			return nil
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      monitoringName.Name,
+				Namespace: monitoringName.Namespace,
+			},
+		}
+	} else if err != nil {
+		return err
+	}
+
+	config := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &config)
+	if err != nil {
+		return err
+	}
+
+	// round trip the desired placement so that it compares equal to what
+	// was read back from the configmap
+	var placement map[string]interface{}
+	b, err := yaml.Marshal(map[string]interface{}{
+		"nodeSelector": infraNodeSelector(),
+		"tolerations":  infraTolerations(),
+	})
+	if err != nil {
+		return err
+	}
+	err = yaml.Unmarshal(b, &placement)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, name := range monitoringComponents {
+		component, _ := config[name].(map[string]interface{})
+		if component == nil {
+			if !infra {
+				continue
+			}
+			component = map[string]interface{}{}
+			config[name] = component
+		}
+
+		for key, value := range placement {
+			isInfra := reflect.DeepEqual(component[key], value)
+
+			switch {
+			case infra && !isInfra:
+				component[key] = value
+				changed = true
+			case !infra && isInfra:
+				delete(component, key)
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	b, err = yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data["config.yaml"] = string(b)
+
+	r.Log.Infof("updating placement of the monitoring stack in %s", monitoringName.Name)
+	if cm.ResourceVersion == "" {
+		return r.Client.Create(ctx, cm)
+	}
+	return r.Client.Update(ctx, cm)
+}
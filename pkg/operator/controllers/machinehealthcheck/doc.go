@@ -23,6 +23,16 @@ aro.machinehealthcheck.managed
   occurs 2 or more times within an hour.
 
 The aro-machinehealth check is configured in a way that if 2 worker nodes go not ready it will not take any action.
+
+Remediation can be tuned per machine set through cluster.spec.machineHealthCheck.policies.  Each policy results
+in an aro-machinehealthcheck-<machineset> MHC with its own unhealthy condition timeout, maxUnhealthy and node
+startup timeout; machine sets with a policy are excluded from the default aro-machinehealthcheck.  The RP does
+not overwrite the policies when it updates the cluster spec.
+
+All ARO MHCs are paused while the cluster is upgrading, and while a zone-wide outage is detected (at least 2
+nodes, and at least half of the nodes, in a zone are not ready).  Replacing machines in a zone which is down
+would only create more machines that cannot come up.  The MachineHealthCheckZoneOutage condition reports when
+the circuit is open.
 More information about how the MHC works can be found here:
 https://docs.openshift.com/container-platform/4.12/machine_management/deploying-machine-health-checks.html
 
@@ -13,6 +13,7 @@ import (
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -90,11 +91,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 			return reconcile.Result{RequeueAfter: time.Hour}, err
 		}
 
+		err = r.removeStalePolicies(ctx, nil)
+		if err != nil {
+			r.Log.Error(err)
+			r.SetDegraded(ctx, err)
+
+			return reconcile.Result{RequeueAfter: time.Hour}, err
+		}
+
 		r.ClearConditions(ctx)
 		return reconcile.Result{}, nil
 	}
 
 	var resources []kruntime.Object
+	var mhcs []*machinev1beta1.MachineHealthCheck
 
 	for _, asset := range [][]byte{machinehealthcheckYaml, mhcremediationalertYaml} {
 		resource, _, err := scheme.Codecs.UniversalDeserializer().Decode(asset, nil, nil)
@@ -106,22 +116,47 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 		}
 
 		if mhc, ok := resource.(*machinev1beta1.MachineHealthCheck); ok {
-			isUpgrading, err := r.isClusterUpgrading(ctx)
-			if err != nil {
-				r.Log.Error(err)
-				r.SetDegraded(ctx, err)
-
-				return reconcile.Result{}, err
+			for _, policyMHC := range policyMachineHealthChecks(instance, mhc) {
+				mhcs = append(mhcs, policyMHC)
+				resources = append(resources, policyMHC)
 			}
+			mhcs = append(mhcs, mhc)
+		}
+
+		resources = append(resources, resource)
+	}
+
+	isUpgrading, err := r.isClusterUpgrading(ctx)
+	if err != nil {
+		r.Log.Error(err)
+		r.SetDegraded(ctx, err)
+
+		return reconcile.Result{}, err
+	}
+
+	outageZones, err := r.zoneOutages(ctx)
+	if err != nil {
+		r.Log.Error(err)
+		r.SetDegraded(ctx, err)
+
+		return reconcile.Result{}, err
+	}
+	r.setZoneOutageCondition(ctx, outageZones)
 
-			if isUpgrading {
-				mhc.ObjectMeta.Annotations = map[string]string{
-					MHCPausedAnnotation: "",
-				}
+	if isUpgrading || len(outageZones) > 0 {
+		for _, mhc := range mhcs {
+			mhc.ObjectMeta.Annotations = map[string]string{
+				MHCPausedAnnotation: "",
 			}
 		}
+	}
 
-		resources = append(resources, resource)
+	err = r.removeStalePolicies(ctx, mhcs)
+	if err != nil {
+		r.Log.Error(err)
+		r.SetDegraded(ctx, err)
+
+		return reconcile.Result{}, err
 	}
 
 	// helps with garbage collection of the resources we are dealing with
@@ -146,9 +181,42 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 	}
 
 	r.ClearConditions(ctx)
+
+	// recheck regularly so that remediation resumes once an outage is over
+	if len(outageZones) > 0 {
+		return reconcile.Result{RequeueAfter: zoneOutageRequeue}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
+// removeStalePolicies deletes the MachineHealthChecks of policies which have
+// been removed from the cluster spec
+func (r *Reconciler) removeStalePolicies(ctx context.Context, mhcs []*machinev1beta1.MachineHealthCheck) error {
+	existing := &machinev1beta1.MachineHealthCheckList{}
+	err := r.Client.List(ctx, existing, client.InNamespace(machineAPINamespace), client.HasLabels{policyLabel})
+	if err != nil {
+		return err
+	}
+
+	wanted := map[string]bool{}
+	for _, mhc := range mhcs {
+		wanted[mhc.Name] = true
+	}
+
+	for _, mhc := range existing.Items {
+		if wanted[mhc.Name] {
+			continue
+		}
+
+		err = r.dh.EnsureDeleted(ctx, "MachineHealthCheck", machineAPINamespace, mhc.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *Reconciler) isClusterUpgrading(ctx context.Context) (bool, error) {
 	clusterVersion := &configv1.ClusterVersion{}
 	if err := r.Client.Get(ctx, types.NamespacedName{Name: "version"}, clusterVersion); err != nil {
@@ -183,5 +251,10 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&handler.EnqueueRequestForObject{},
 			builder.WithPredicates(clusterVersionPredicate),
 		).
+		Watches(
+			&source.Kind{Type: &corev1.Node{}},
+			&handler.EnqueueRequestForObject{},
+			builder.WithPredicates(nodeReadinessPredicate),
+		).
 		Complete(r)
 }
@@ -14,13 +14,17 @@ import (
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/Azure/ARO-RP/pkg/operator"
 	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/util/cmp"
 	mock_dynamichelper "github.com/Azure/ARO-RP/pkg/util/mocks/dynamichelper"
 	_ "github.com/Azure/ARO-RP/pkg/util/scheme"
 	utilconditions "github.com/Azure/ARO-RP/test/util/conditions"
@@ -61,6 +65,7 @@ func TestMachineHealthCheckReconciler(t *testing.T) {
 		name             string
 		instance         *arov1alpha1.Cluster
 		clusterversion   *configv1.ClusterVersion
+		objects          []client.Object
 		mocks            func(mdh *mock_dynamichelper.MockInterface)
 		wantConditions   []operatorv1.OperatorCondition
 		wantErr          string
@@ -222,6 +227,102 @@ func TestMachineHealthCheckReconciler(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "Managed Feature Flag is true and a zone is down: pauses MHC and sets condition",
+			instance: &arov1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: arov1alpha1.SingletonClusterName,
+				},
+				Spec: arov1alpha1.ClusterSpec{
+					OperatorFlags: arov1alpha1.OperatorFlags{
+						operator.MachineHealthCheckEnabled: operator.FlagTrue,
+						operator.MachineHealthCheckManaged: operator.FlagTrue,
+					},
+				},
+			},
+			objects: []client.Object{
+				node("worker-1a", "eastus-1", false),
+				node("worker-1b", "eastus-1", false),
+				node("worker-2a", "eastus-2", true),
+				node("worker-3a", "eastus-3", false),
+			},
+			mocks: func(mdh *mock_dynamichelper.MockInterface) {
+				mdh.EXPECT().Ensure(gomock.Any(), mhcIsPaused(true)).Return(nil).Times(1)
+			},
+			wantConditions: []operatorv1.OperatorCondition{
+				{
+					Type:               arov1alpha1.MachineHealthCheckZoneOutage,
+					Status:             operatorv1.ConditionTrue,
+					LastTransitionTime: transitionTime,
+					Message:            "Machine remediation is paused, zone outage detected in eastus-1",
+				},
+			},
+			wantRequeueAfter: zoneOutageRequeue,
+		},
+		{
+			name: "Managed Feature Flag is true and a single node is down: does not pause MHC",
+			instance: &arov1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: arov1alpha1.SingletonClusterName,
+				},
+				Spec: arov1alpha1.ClusterSpec{
+					OperatorFlags: arov1alpha1.OperatorFlags{
+						operator.MachineHealthCheckEnabled: operator.FlagTrue,
+						operator.MachineHealthCheckManaged: operator.FlagTrue,
+					},
+				},
+			},
+			objects: []client.Object{
+				node("worker-1a", "eastus-1", false),
+				node("worker-2a", "eastus-2", true),
+				node("worker-3a", "eastus-3", true),
+			},
+			mocks: func(mdh *mock_dynamichelper.MockInterface) {
+				mdh.EXPECT().Ensure(gomock.Any(), mhcIsPaused(false)).Return(nil).Times(1)
+			},
+			wantConditions: []operatorv1.OperatorCondition{
+				{
+					Type:               arov1alpha1.MachineHealthCheckZoneOutage,
+					Status:             operatorv1.ConditionFalse,
+					LastTransitionTime: transitionTime,
+				},
+			},
+		},
+		{
+			name: "Managed Feature Flag is true with policies: ensures policy MHCs and removes stale ones",
+			instance: &arov1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: arov1alpha1.SingletonClusterName,
+				},
+				Spec: arov1alpha1.ClusterSpec{
+					OperatorFlags: arov1alpha1.OperatorFlags{
+						operator.MachineHealthCheckEnabled: operator.FlagTrue,
+						operator.MachineHealthCheckManaged: operator.FlagTrue,
+					},
+					MachineHealthCheck: arov1alpha1.MachineHealthCheckSpec{
+						Policies: []arov1alpha1.MachineHealthCheckPolicy{
+							{MachineSet: "cluster-worker-eastus1"},
+						},
+					},
+				},
+			},
+			objects: []client.Object{
+				&machinev1beta1.MachineHealthCheck{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "aro-machinehealthcheck-cluster-worker-eastus2",
+						Namespace: "openshift-machine-api",
+						Labels: map[string]string{
+							policyLabel: "",
+						},
+					},
+				},
+			},
+			mocks: func(mdh *mock_dynamichelper.MockInterface) {
+				mdh.EXPECT().EnsureDeleted(gomock.Any(), "MachineHealthCheck", "openshift-machine-api", "aro-machinehealthcheck-cluster-worker-eastus2").Return(nil).Times(1)
+				mdh.EXPECT().Ensure(gomock.Any(), hasMHC("aro-machinehealthcheck-cluster-worker-eastus1")).Return(nil).Times(1)
+			},
+			wantConditions: defaultConditions,
+		},
 		{
 			name: "When ensuring resources fails, an error is returned",
 			instance: &arov1alpha1.Cluster{
@@ -266,6 +367,7 @@ func TestMachineHealthCheckReconciler(t *testing.T) {
 			if tt.instance != nil {
 				clientBuilder = clientBuilder.WithObjects(tt.instance)
 			}
+			clientBuilder = clientBuilder.WithObjects(tt.objects...)
 			if tt.clusterversion == nil {
 				clientBuilder = clientBuilder.WithObjects(clusterversionDefault)
 			} else {
@@ -328,3 +430,131 @@ func (m mhcIsPausedMatcher) String() string {
 func mhcIsPaused(paused bool) gomock.Matcher {
 	return mhcIsPausedMatcher{paused: paused}
 }
+
+type hasMHCMatcher struct {
+	name string
+}
+
+func (m hasMHCMatcher) Matches(x interface{}) bool {
+	if objs, ok := x.([]kruntime.Object); ok {
+		for _, obj := range objs {
+			if mhc, ok := obj.(*machinev1beta1.MachineHealthCheck); ok && mhc.Name == m.name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m hasMHCMatcher) String() string {
+	return "has mhc " + m.name
+}
+
+func hasMHC(name string) gomock.Matcher {
+	return hasMHCMatcher{name: name}
+}
+
+func node(name, zone string, isReady bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if isReady {
+		status = corev1.ConditionTrue
+	}
+
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				corev1.LabelTopologyZone: zone,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: status,
+				},
+			},
+		},
+	}
+}
+
+func TestPolicyMachineHealthChecks(t *testing.T) {
+	defaultMHC := func() *machinev1beta1.MachineHealthCheck {
+		maxUnhealthy := intstr.FromString("1")
+		return &machinev1beta1.MachineHealthCheck{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "aro-machinehealthcheck",
+				Namespace: "openshift-machine-api",
+			},
+			Spec: machinev1beta1.MachineHealthCheckSpec{
+				Selector: metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      machineSetLabel,
+							Operator: metav1.LabelSelectorOpExists,
+						},
+					},
+				},
+				UnhealthyConditions: []machinev1beta1.UnhealthyCondition{
+					{Type: "Ready", Status: "False", Timeout: metav1.Duration{Duration: 15 * time.Minute}},
+					{Type: "Ready", Status: "Unknown", Timeout: metav1.Duration{Duration: 15 * time.Minute}},
+				},
+				MaxUnhealthy:       &maxUnhealthy,
+				NodeStartupTimeout: &metav1.Duration{Duration: 25 * time.Minute},
+			},
+		}
+	}
+
+	maxUnhealthy := intstr.FromString("50%")
+	cluster := &arov1alpha1.Cluster{
+		Spec: arov1alpha1.ClusterSpec{
+			MachineHealthCheck: arov1alpha1.MachineHealthCheckSpec{
+				Policies: []arov1alpha1.MachineHealthCheckPolicy{
+					{
+						MachineSet:                "cluster-worker-eastus1",
+						UnhealthyConditionTimeout: &metav1.Duration{Duration: 30 * time.Minute},
+						MaxUnhealthy:              &maxUnhealthy,
+						NodeStartupTimeout:        &metav1.Duration{Duration: time.Hour},
+					},
+					{
+						MachineSet: "cluster-worker-eastus2",
+					},
+					{
+						MachineSet: "cluster-worker-eastus1",
+					},
+				},
+			},
+		},
+	}
+
+	wantPolicy1 := defaultMHC()
+	wantPolicy1.Name = "aro-machinehealthcheck-cluster-worker-eastus1"
+	wantPolicy1.Labels = map[string]string{policyLabel: ""}
+	wantPolicy1.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{machineSetLabel: "cluster-worker-eastus1"}}
+	wantPolicy1.Spec.UnhealthyConditions[0].Timeout = metav1.Duration{Duration: 30 * time.Minute}
+	wantPolicy1.Spec.UnhealthyConditions[1].Timeout = metav1.Duration{Duration: 30 * time.Minute}
+	wantPolicy1.Spec.MaxUnhealthy = &maxUnhealthy
+	wantPolicy1.Spec.NodeStartupTimeout = &metav1.Duration{Duration: time.Hour}
+
+	wantPolicy2 := defaultMHC()
+	wantPolicy2.Name = "aro-machinehealthcheck-cluster-worker-eastus2"
+	wantPolicy2.Labels = map[string]string{policyLabel: ""}
+	wantPolicy2.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{machineSetLabel: "cluster-worker-eastus2"}}
+
+	wantDefault := defaultMHC()
+	wantDefault.Spec.Selector.MatchExpressions = append(wantDefault.Spec.Selector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      machineSetLabel,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   []string{"cluster-worker-eastus1", "cluster-worker-eastus2"},
+	})
+
+	mhc := defaultMHC()
+	policies := policyMachineHealthChecks(cluster, mhc)
+
+	if diff := cmp.Diff([]*machinev1beta1.MachineHealthCheck{wantPolicy1, wantPolicy2}, policies); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(wantDefault, mhc); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,161 @@
+package machinehealthcheck
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/util/ready"
+)
+
+const (
+	machineAPINamespace = "openshift-machine-api"
+	machineSetLabel     = "machine.openshift.io/cluster-api-machineset"
+
+	// policyLabel marks the MachineHealthChecks created for per-machine set
+	// policies, so that they can be removed when the policy is
+	policyLabel      = "aro.openshift.io/machinehealthcheck-policy"
+	policyNamePrefix = "aro-machinehealthcheck-"
+
+	// a zone is considered to be suffering an outage when at least
+	// zoneOutageMinNodes of its nodes, and at least half of them, are not
+	// ready.  In non-zonal regions all nodes share the same zone label, so a
+	// regional outage trips the circuit in the same way.
+	zoneOutageMinNodes = 2
+	zoneOutageRequeue  = 5 * time.Minute
+)
+
+// policyMachineHealthChecks returns a MachineHealthCheck for each policy in the
+// cluster spec, based on the default ARO MachineHealthCheck, and excludes the
+// machine sets they cover from the default MachineHealthCheck
+func policyMachineHealthChecks(cluster *arov1alpha1.Cluster, defaultMHC *machinev1beta1.MachineHealthCheck) []*machinev1beta1.MachineHealthCheck {
+	var mhcs []*machinev1beta1.MachineHealthCheck
+	var machineSets []string
+
+	seen := map[string]bool{}
+	for _, policy := range cluster.Spec.MachineHealthCheck.Policies {
+		if policy.MachineSet == "" || seen[policy.MachineSet] {
+			continue
+		}
+		seen[policy.MachineSet] = true
+		machineSets = append(machineSets, policy.MachineSet)
+
+		mhc := defaultMHC.DeepCopy()
+		mhc.Name = policyNamePrefix + policy.MachineSet
+		mhc.Labels = map[string]string{
+			policyLabel: "",
+		}
+		mhc.Spec.Selector = metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				machineSetLabel: policy.MachineSet,
+			},
+		}
+
+		if policy.UnhealthyConditionTimeout != nil {
+			for i := range mhc.Spec.UnhealthyConditions {
+				mhc.Spec.UnhealthyConditions[i].Timeout = *policy.UnhealthyConditionTimeout
+			}
+		}
+		if policy.MaxUnhealthy != nil {
+			maxUnhealthy := *policy.MaxUnhealthy
+			mhc.Spec.MaxUnhealthy = &maxUnhealthy
+		}
+		if policy.NodeStartupTimeout != nil {
+			nodeStartupTimeout := *policy.NodeStartupTimeout
+			mhc.Spec.NodeStartupTimeout = &nodeStartupTimeout
+		}
+
+		mhcs = append(mhcs, mhc)
+	}
+
+	if len(machineSets) > 0 {
+		defaultMHC.Spec.Selector.MatchExpressions = append(defaultMHC.Spec.Selector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      machineSetLabel,
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   machineSets,
+		})
+	}
+
+	return mhcs
+}
+
+// zoneOutages returns the zones which appear to be suffering an outage.
+// Remediating machines in such a zone would only replace them with machines
+// which cannot come up either, so remediation is paused until it recovers.
+func (r *Reconciler) zoneOutages(ctx context.Context) ([]string, error) {
+	nodes := &corev1.NodeList{}
+	err := r.Client.List(ctx, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	total := map[string]int{}
+	notReady := map[string]int{}
+	for i := range nodes.Items {
+		zone := nodes.Items[i].Labels[corev1.LabelTopologyZone]
+		if zone == "" {
+			continue
+		}
+
+		total[zone]++
+		if !ready.NodeIsReady(&nodes.Items[i]) {
+			notReady[zone]++
+		}
+	}
+
+	var zones []string
+	for zone, count := range notReady {
+		if count >= zoneOutageMinNodes && 2*count >= total[zone] {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+
+	return zones, nil
+}
+
+func (r *Reconciler) setZoneOutageCondition(ctx context.Context, zones []string) {
+	cnd := &operatorv1.OperatorCondition{
+		Type:   arov1alpha1.MachineHealthCheckZoneOutage,
+		Status: operatorv1.ConditionFalse,
+	}
+
+	if len(zones) > 0 {
+		r.Log.Warnf("pausing machine remediation, zone outage detected in %s", strings.Join(zones, ", "))
+		cnd.Status = operatorv1.ConditionTrue
+		cnd.Message = fmt.Sprintf("Machine remediation is paused, zone outage detected in %s", strings.Join(zones, ", "))
+	}
+
+	r.SetConditions(ctx, cnd)
+}
+
+// nodeReadinessPredicate passes node events which may change the outcome of
+// zoneOutages, ignoring the frequent heartbeat-only status updates
+var nodeReadinessPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return false
+		}
+
+		return ready.NodeIsReady(oldNode) != ready.NodeIsReady(newNode) ||
+			oldNode.Labels[corev1.LabelTopologyZone] != newNode.Labels[corev1.LabelTopologyZone]
+	},
+}
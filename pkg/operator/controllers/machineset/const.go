@@ -6,4 +6,12 @@ package machineset
 const (
 	machineSetsNamespace = "openshift-machine-api"
 	minSupportedReplicas = 2
+
+	machineRoleLabel = "machine.openshift.io/cluster-api-machine-role"
+	workerRole       = "worker"
+	infraRole        = "infra"
+
+	// infraNodesLabel marks the infra machine sets managed by the infranodes
+	// controller
+	infraNodesLabel = "aro.openshift.io/infranodes"
 )
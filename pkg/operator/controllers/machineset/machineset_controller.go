@@ -30,7 +30,7 @@ type Reconciler struct {
 	base.AROController
 }
 
-// MachineSet reconciler watches MachineSet objects for changes, evaluates total worker replica count, and reverts changes if needed.
+// MachineSet reconciler watches MachineSet objects for changes, evaluates total worker and infra replica counts, and reverts changes if needed.
 func NewReconciler(log *logrus.Entry, client client.Client) *Reconciler {
 	return &Reconciler{
 		AROController: base.AROController{
@@ -63,8 +63,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 		return reconcile.Result{}, err
 	}
 
+	// infra machine sets requested through the cluster spec are held to the
+	// requested count, worker machine sets to minSupportedReplicas
+	role := modifiedMachineset.Labels[machineRoleLabel]
+	minReplicas := minSupportedReplicas
+	if role == infraRole {
+		if instance.Spec.InfraNodes == nil || modifiedMachineset.Labels[infraNodesLabel] == "" {
+			r.ClearConditions(ctx)
+			return reconcile.Result{}, nil
+		}
+		minReplicas = int(instance.Spec.InfraNodes.Replicas)
+	}
+
 	machinesets := &machinev1beta1.MachineSetList{}
-	selector, _ := labels.Parse("machine.openshift.io/cluster-api-machine-role=worker")
+	selector, _ := labels.Parse(machineRoleLabel + "=" + role)
 	err = r.Client.List(ctx, machinesets, &client.ListOptions{
 		Namespace:     machineSetsNamespace,
 		LabelSelector: selector,
@@ -76,7 +88,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 		return reconcile.Result{}, err
 	}
 
-	// Count amount of total current worker replicas
+	// Count amount of total current replicas of the role
 	replicaCount := 0
 	for _, machineset := range machinesets.Items {
 		// If there are any custom machinesets in the list, bail and don't requeue
@@ -90,10 +102,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 		}
 	}
 
-	if replicaCount < minSupportedReplicas {
-		r.Log.Infof("Found less than %v worker replicas. The MachineSet controller will attempt scaling.", minSupportedReplicas)
+	if replicaCount < minReplicas {
+		r.Log.Infof("Found less than %v %s replicas. The MachineSet controller will attempt scaling.", minReplicas, role)
 		// Add replicas to the object, and call Update
-		modifiedMachineset.Spec.Replicas = to.Int32Ptr(int32(minSupportedReplicas-replicaCount) + *modifiedMachineset.Spec.Replicas)
+		modifiedMachineset.Spec.Replicas = to.Int32Ptr(int32(minReplicas-replicaCount) + *modifiedMachineset.Spec.Replicas)
 		err := r.Client.Update(ctx, modifiedMachineset)
 		if err != nil {
 			r.Log.Error(err)
@@ -109,8 +121,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	machineSetPredicate := predicate.NewPredicateFuncs(func(o client.Object) bool {
-		role := o.GetLabels()["machine.openshift.io/cluster-api-machine-role"]
-		return strings.EqualFold("worker", role)
+		role := o.GetLabels()[machineRoleLabel]
+		return strings.EqualFold(workerRole, role) || strings.EqualFold(infraRole, role)
 	})
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -74,10 +74,31 @@ func TestReconciler(t *testing.T) {
 		return []client.Object{workerMachineSet0, workerMachineSet1, workerMachineSet2}
 	}
 
+	fakeInfraMachineSets := func(replicas ...int32) []client.Object {
+		var machineSets []client.Object
+		for i, r := range replicas {
+			machineSets = append(machineSets, &machinev1beta1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "aro-fake-infra-" + strconv.Itoa(i),
+					Namespace: machineSetsNamespace,
+					Labels: map[string]string{
+						"machine.openshift.io/cluster-api-machine-role": "infra",
+						"aro.openshift.io/infranodes":                   "true",
+					},
+				},
+				Spec: machinev1beta1.MachineSetSpec{
+					Replicas: to.Int32Ptr(r),
+				},
+			})
+		}
+		return machineSets
+	}
+
 	tests := []struct {
 		name            string
 		objectName      string
 		machinesets     []client.Object
+		infraNodes      *arov1alpha1.InfraNodesSpec
 		wantReplicas    int32
 		featureFlag     bool
 		assertReplicas  bool
@@ -176,6 +197,38 @@ func TestReconciler(t *testing.T) {
 			startConditions: defaultConditions,
 			wantConditions:  defaultConditions,
 		},
+		{
+			name:            "one infra replica of three requested, infra machineset-0 modified",
+			objectName:      "aro-fake-infra-0",
+			machinesets:     append(fakeMachineSets(1, 1, 1), fakeInfraMachineSets(1, 0, 0)...),
+			infraNodes:      &arov1alpha1.InfraNodesSpec{VMSize: "Standard_D8s_v3", Replicas: 3},
+			wantReplicas:    3,
+			featureFlag:     true,
+			assertReplicas:  true,
+			startConditions: defaultConditions,
+			wantConditions:  defaultConditions,
+		},
+		{
+			name:            "three infra replicas of three requested, infra machineset-0 modified",
+			objectName:      "aro-fake-infra-0",
+			machinesets:     append(fakeMachineSets(1, 1, 1), fakeInfraMachineSets(1, 1, 1)...),
+			infraNodes:      &arov1alpha1.InfraNodesSpec{VMSize: "Standard_D8s_v3", Replicas: 3},
+			wantReplicas:    1,
+			featureFlag:     true,
+			assertReplicas:  true,
+			startConditions: defaultConditions,
+			wantConditions:  defaultConditions,
+		},
+		{
+			name:            "infra nodes not requested, infra machineset-0 modified",
+			objectName:      "aro-fake-infra-0",
+			machinesets:     append(fakeMachineSets(1, 1, 1), fakeInfraMachineSets(0, 0, 0)...),
+			wantReplicas:    0,
+			featureFlag:     true,
+			assertReplicas:  true,
+			startConditions: defaultConditions,
+			wantConditions:  defaultConditions,
+		},
 		{
 			name:            "machineset-0 not found",
 			objectName:      "aro-fake-machineset-0",
@@ -201,7 +254,8 @@ func TestReconciler(t *testing.T) {
 			instance := &arov1alpha1.Cluster{
 				ObjectMeta: metav1.ObjectMeta{Name: arov1alpha1.SingletonClusterName},
 				Spec: arov1alpha1.ClusterSpec{
-					InfraID: "aro-fake",
+					InfraID:    "aro-fake",
+					InfraNodes: tt.infraNodes,
 					OperatorFlags: arov1alpha1.OperatorFlags{
 						operator.MachineSetEnabled: strconv.FormatBool(tt.featureFlag),
 					},
@@ -9,6 +9,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -37,7 +38,10 @@ import (
 	pkgoperator "github.com/Azure/ARO-RP/pkg/operator"
 	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
 	aroclient "github.com/Azure/ARO-RP/pkg/operator/clientset/versioned"
+	"github.com/Azure/ARO-RP/pkg/operator/controllers/alertwebhook"
 	"github.com/Azure/ARO-RP/pkg/operator/controllers/genevalogging"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient/mgmt/compute"
+	"github.com/Azure/ARO-RP/pkg/util/computeskus"
 	"github.com/Azure/ARO-RP/pkg/util/dynamichelper"
 	utilkubernetes "github.com/Azure/ARO-RP/pkg/util/kubernetes"
 	utilpem "github.com/Azure/ARO-RP/pkg/util/pem"
@@ -68,9 +72,11 @@ type operator struct {
 	extensionscli extensionsclient.Interface
 	kubernetescli kubernetes.Interface
 	dh            dynamichelper.Interface
+
+	resourceSkus compute.ResourceSkusClient
 }
 
-func New(log *logrus.Entry, env env.Interface, oc *api.OpenShiftCluster, arocli aroclient.Interface, client client.Client, extensionscli extensionsclient.Interface, kubernetescli kubernetes.Interface) (Operator, error) {
+func New(log *logrus.Entry, env env.Interface, oc *api.OpenShiftCluster, arocli aroclient.Interface, client client.Client, extensionscli extensionsclient.Interface, kubernetescli kubernetes.Interface, resourceSkus compute.ResourceSkusClient) (Operator, error) {
 	restConfig, err := restconfig.RestConfig(env, oc)
 	if err != nil {
 		return nil, err
@@ -90,6 +96,8 @@ func New(log *logrus.Entry, env env.Interface, oc *api.OpenShiftCluster, arocli
 		extensionscli: extensionscli,
 		kubernetescli: kubernetescli,
 		dh:            dh,
+
+		resourceSkus: resourceSkus,
 	}, nil
 }
 
@@ -222,6 +230,11 @@ func (o *operator) resources(ctx context.Context) ([]kruntime.Object, error) {
 		return nil, err
 	}
 
+	vmSizes, err := o.vmSizeCapacities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	serviceSubnets := []string{
 		"/subscriptions/" + o.env.SubscriptionID() + "/resourceGroups/" + o.env.ResourceGroup() + "/providers/Microsoft.Network/virtualNetworks/rp-pe-vnet-001/subnets/rp-pe-subnet",
 		"/subscriptions/" + o.env.SubscriptionID() + "/resourceGroups/" + o.env.ResourceGroup() + "/providers/Microsoft.Network/virtualNetworks/rp-vnet/subnets/rp-subnet",
@@ -266,6 +279,9 @@ func (o *operator) resources(ctx context.Context) ([]kruntime.Object, error) {
 			APIIntIP:                 o.oc.Properties.APIServerProfile.IntIP,
 			IngressIP:                ingressIP,
 			GatewayPrivateEndpointIP: o.oc.Properties.NetworkProfile.GatewayPrivateEndpointIP,
+			AutoSizedNodes: arov1alpha1.AutoSizedNodesSpec{
+				VMSizes: vmSizes,
+			},
 			// Update the OperatorFlags from the version in the RP
 			OperatorFlags: arov1alpha1.OperatorFlags(o.oc.Properties.OperatorFlags),
 		},
@@ -278,8 +294,23 @@ func (o *operator) resources(ctx context.Context) ([]kruntime.Object, error) {
 		cluster.Spec.GatewayDomains = make([]string, 0)
 	}
 
+	if o.oc.Properties.InfraProfile != nil {
+		cluster.Spec.InfraNodes = &arov1alpha1.InfraNodesSpec{
+			VMSize:     string(o.oc.Properties.InfraProfile.VMSize),
+			DiskSizeGB: int32(o.oc.Properties.InfraProfile.DiskSizeGB),
+			Replicas:   int32(o.oc.Properties.InfraProfile.Count),
+		}
+	}
+
+	// forward critical platform alerts to the RP, if it is configured to
+	// ingest them
+	if alertIngestionURL := o.env.LiveConfig().AlertIngestionURL(ctx); alertIngestionURL != "" {
+		cluster.Spec.AlertRouting.URL = strings.TrimSuffix(alertIngestionURL, "/") + "/alertingestion" + strings.ToLower(o.oc.ID)
+	}
+
 	// create a secret here for genevalogging, later we will copy it to
-	// the genevalogging namespace.
+	// the genevalogging namespace.  It also holds the token used to
+	// authenticate forwarded alerts.
 	return append(results,
 		&corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -287,15 +318,66 @@ func (o *operator) resources(ctx context.Context) ([]kruntime.Object, error) {
 				Namespace: pkgoperator.Namespace,
 			},
 			Data: map[string][]byte{
-				genevalogging.GenevaCertName: gcsCertBytes,
-				genevalogging.GenevaKeyName:  gcsKeyBytes,
-				corev1.DockerConfigJsonKey:   []byte(ps),
+				genevalogging.GenevaCertName:      gcsCertBytes,
+				genevalogging.GenevaKeyName:       gcsKeyBytes,
+				corev1.DockerConfigJsonKey:        []byte(ps),
+				alertwebhook.AlertRoutingTokenKey: []byte(o.oc.Properties.AlertIngestionToken),
 			},
 		},
 		cluster,
 	), nil
 }
 
+// vmSizeCapacities returns the capacity of the VM sizes in use by the cluster,
+// which the operator uses to size system-reserved resources
+func (o *operator) vmSizeCapacities(ctx context.Context) ([]arov1alpha1.VMSizeCapacity, error) {
+	vmSizes := []string{string(o.oc.Properties.MasterProfile.VMSize)}
+	workerProfiles, _ := api.GetEnrichedWorkerProfiles(o.oc.Properties)
+	for _, wp := range workerProfiles {
+		vmSizes = append(vmSizes, string(wp.VMSize))
+	}
+	sort.Strings(vmSizes)
+
+	filter := fmt.Sprintf("location eq %s", o.oc.Location)
+	skus, err := o.resourceSkus.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	filteredSkus := computeskus.FilterVMSizes(skus, o.oc.Location)
+
+	var capacities []arov1alpha1.VMSizeCapacity
+	for i, vmSize := range vmSizes {
+		if i > 0 && vmSizes[i-1] == vmSize {
+			continue
+		}
+
+		sku := filteredSkus[vmSize]
+		if sku == nil {
+			o.log.Warnf("VM size %s is not available in region %s", vmSize, o.oc.Location)
+			continue
+		}
+
+		vcpus, err := computeskus.VCPUs(sku)
+		if err != nil {
+			return nil, err
+		}
+
+		memoryMiB, err := computeskus.MemoryMiB(sku)
+		if err != nil {
+			return nil, err
+		}
+
+		capacities = append(capacities, arov1alpha1.VMSizeCapacity{
+			Name:      vmSize,
+			VCPUs:     vcpus,
+			MemoryMiB: memoryMiB,
+		})
+	}
+
+	return capacities, nil
+}
+
 func (o *operator) CreateOrUpdate(ctx context.Context) error {
 	resources, err := o.resources(ctx)
 	if err != nil {
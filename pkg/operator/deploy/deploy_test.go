@@ -5,11 +5,15 @@ package deploy
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	configv1 "github.com/openshift/api/config/v1"
+	"github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,7 +21,9 @@ import (
 	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/Azure/ARO-RP/pkg/api"
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
 	"github.com/Azure/ARO-RP/pkg/util/cmp"
+	mock_compute "github.com/Azure/ARO-RP/pkg/util/mocks/azureclient/mgmt/compute"
 	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
 	utilerror "github.com/Azure/ARO-RP/test/util/error"
 )
@@ -461,3 +467,96 @@ func TestCheckPodImageVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestVMSizeCapacities(t *testing.T) {
+	ctx := context.Background()
+
+	sku := func(name, vcpus, memoryGB string) mgmtcompute.ResourceSku {
+		return mgmtcompute.ResourceSku{
+			Name:         to.StringPtr(name),
+			ResourceType: to.StringPtr("virtualMachines"),
+			Locations:    &[]string{"eastus"},
+			LocationInfo: &[]mgmtcompute.ResourceSkuLocationInfo{
+				{Location: to.StringPtr("eastus")},
+			},
+			Capabilities: &[]mgmtcompute.ResourceSkuCapabilities{
+				{Name: to.StringPtr("vCPUs"), Value: to.StringPtr(vcpus)},
+				{Name: to.StringPtr("MemoryGB"), Value: to.StringPtr(memoryGB)},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		skus    []mgmtcompute.ResourceSku
+		skusErr error
+		want    []arov1alpha1.VMSizeCapacity
+		wantErr string
+	}{
+		{
+			name: "master and worker VM sizes are returned once each",
+			skus: []mgmtcompute.ResourceSku{
+				sku("Standard_D8s_v3", "8", "32"),
+				sku("Standard_D4s_v3", "4", "16"),
+				sku("Standard_E4s_v3", "4", "32"),
+			},
+			want: []arov1alpha1.VMSizeCapacity{
+				{Name: "Standard_D4s_v3", VCPUs: 4, MemoryMiB: 16384},
+				{Name: "Standard_D8s_v3", VCPUs: 8, MemoryMiB: 32768},
+			},
+		},
+		{
+			name: "unavailable VM sizes are skipped",
+			skus: []mgmtcompute.ResourceSku{
+				sku("Standard_D8s_v3", "8", "32"),
+			},
+			want: []arov1alpha1.VMSizeCapacity{
+				{Name: "Standard_D8s_v3", VCPUs: 8, MemoryMiB: 32768},
+			},
+		},
+		{
+			name: "malformed capacity is an error",
+			skus: []mgmtcompute.ResourceSku{
+				sku("Standard_D8s_v3", "eight", "32"),
+			},
+			wantErr: `strconv.Atoi: parsing "eight": invalid syntax`,
+		},
+		{
+			name:    "sku lookup fails",
+			skusErr: errors.New("random error"),
+			wantErr: "random error",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			resourceSkus := mock_compute.NewMockResourceSkusClient(controller)
+			resourceSkus.EXPECT().List(gomock.Any(), "location eq eastus").Return(tt.skus, tt.skusErr)
+
+			o := &operator{
+				log: logrus.NewEntry(logrus.StandardLogger()),
+				oc: &api.OpenShiftCluster{
+					Location: "eastus",
+					Properties: api.OpenShiftClusterProperties{
+						MasterProfile: api.MasterProfile{
+							VMSize: api.VMSizeStandardD8sV3,
+						},
+						WorkerProfiles: []api.WorkerProfile{
+							{VMSize: api.VMSizeStandardD4sV3},
+							{VMSize: api.VMSizeStandardD4sV3},
+						},
+					},
+				},
+				resourceSkus: resourceSkus,
+			}
+
+			capacities, err := o.vmSizeCapacities(ctx)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if !reflect.DeepEqual(capacities, tt.want) {
+				t.Error(cmp.Diff(capacities, tt.want))
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ const (
 	RestartDnsmasqEnabled              = "aro.restartdnsmasq.enabled"
 	GenevaLoggingEnabled               = "aro.genevalogging.enabled"
 	ImageConfigEnabled                 = "aro.imageconfig.enabled"
+	InfraNodesEnabled                  = "aro.infranodes.enabled"
 	IngressEnabled                     = "aro.ingress.enabled"
 	MachineEnabled                     = "aro.machine.enabled"
 	MachineSetEnabled                  = "aro.machineset.enabled"
@@ -51,6 +52,7 @@ func DefaultOperatorFlags() map[string]string {
 		RestartDnsmasqEnabled:              FlagFalse,
 		GenevaLoggingEnabled:               FlagTrue,
 		ImageConfigEnabled:                 FlagTrue,
+		InfraNodesEnabled:                  FlagTrue,
 		IngressEnabled:                     FlagTrue,
 		MachineEnabled:                     FlagTrue,
 		MachineSetEnabled:                  FlagTrue,
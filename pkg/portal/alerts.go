@@ -0,0 +1,54 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+)
+
+// alerts returns the critical platform alerts which the cluster has
+// forwarded to the RP, most recent first
+func (p *portal) alerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.internalServerError(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	docs, err := p.dbClusterAlerts.ListByKey(ctx, resourceID)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	alerts := make([]*api.ClusterAlert, 0, len(docs.ClusterAlertDocuments))
+	for _, doc := range docs.ClusterAlertDocuments {
+		if doc.ClusterAlert == nil {
+			continue
+		}
+		alerts = append(alerts, doc.ClusterAlert)
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].StartsAt.After(alerts[j].StartsAt) })
+
+	b, err := json.MarshalIndent(alerts, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
@@ -10,4 +10,8 @@ import (
 //go:embed v1/*
 //go:embed v2/*
 //go:embed prometheus-ui/*
+//go:embed sshrecordings/*
+//go:embed elevations/*
+//go:embed grants/*
+//go:embed pendingactions/*
 var EmbeddedFiles embed.FS
@@ -0,0 +1,54 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+)
+
+// auditLog returns the admin API calls which have been made against the
+// cluster, most recent first
+func (p *portal) auditLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.internalServerError(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	docs, err := p.dbAdminAuditLog.ListByKey(ctx, resourceID)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	entries := make([]*api.AdminAuditEntry, 0, len(docs.AdminAuditEntryDocuments))
+	for _, doc := range docs.AdminAuditEntryDocuments {
+		if doc.AdminAuditEntry == nil {
+			continue
+		}
+		entries = append(entries, doc.AdminAuditEntry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].StartedAt.After(entries[j].StartedAt) })
+
+	b, err := json.MarshalIndent(entries, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
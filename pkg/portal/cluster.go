@@ -4,19 +4,34 @@ package portal
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/gorilla/mux"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/Azure/ARO-RP/pkg/portal/cluster"
 	"github.com/Azure/ARO-RP/pkg/portal/prometheus"
 )
 
+const (
+	podLogsDefaultTailLines = 1000
+	podLogsMaxTailLines     = 100000
+	podLogsFollowTimeout    = time.Hour
+)
+
+var rxKind = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
 type AdminOpenShiftCluster struct {
 	Key                     string `json:"key"`
 	Name                    string `json:"name"`
@@ -195,6 +210,22 @@ func (p *portal) machineSets(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(b)
 }
 
+// statisticsCatalog lists the statistics which can be requested from
+// statistics, with their units and parameters
+func (p *portal) statisticsCatalog(w http.ResponseWriter, r *http.Request) {
+	b, err := json.MarshalIndent(cluster.GetStatisticsCatalog(), "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(b)
+	if err != nil {
+		p.log.Error(err)
+	}
+}
+
 func (p *portal) statistics(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	duration := r.URL.Query().Get("duration")
@@ -215,7 +246,13 @@ func (p *portal) statistics(w http.ResponseWriter, r *http.Request) {
 	resourceGroup := apiVars["resourceGroup"]
 	clusterName := apiVars["clusterName"]
 	resourceID := p.getResourceID(subscriptionID, resourceGroup, clusterName)
-	promQuery, err := cluster.GetPromQuery(statisticsType)
+	parameters := map[string]string{}
+	for k := range r.URL.Query() {
+		if k != "duration" && k != "endtime" {
+			parameters[k] = r.URL.Query().Get(k)
+		}
+	}
+	promQuery, err := cluster.GetPromQuery(statisticsType, parameters)
 	if err != nil {
 		p.badRequest(w, err)
 		return
@@ -251,3 +288,165 @@ func (p *portal) statistics(w http.ResponseWriter, r *http.Request) {
 		p.log.Error(err)
 	}
 }
+
+// events lists the events of a cluster, most recent first.  The namespace,
+// kind and name query parameters filter by the namespace and involved object.
+func (p *portal) events(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := cluster.EventFilter{
+		Namespace:          r.URL.Query().Get("namespace"),
+		InvolvedObjectKind: r.URL.Query().Get("kind"),
+		InvolvedObjectName: r.URL.Query().Get("name"),
+	}
+
+	if filter.Namespace != "" && len(validation.IsDNS1123Label(filter.Namespace)) > 0 ||
+		filter.InvolvedObjectKind != "" && !rxKind.MatchString(filter.InvolvedObjectKind) ||
+		filter.InvolvedObjectName != "" && len(validation.IsDNS1123Subdomain(filter.InvolvedObjectName)) > 0 {
+		p.badRequest(w, fmt.Errorf("invalid filter %#v", filter))
+		return
+	}
+
+	fetcher, err := p.makeFetcher(ctx, r)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	events, err := fetcher.Events(ctx, filter)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	p.writeJSON(w, events)
+}
+
+// podLogs returns the logs of a pod as plain text.  The container, tail,
+// previous and follow query parameters are passed through to the API server;
+// when following, the logs are streamed for at most podLogsFollowTimeout.
+// Pod logs may contain customer data, so unlike the other cluster diagnostics
+// they are only available to elevated users.
+func (p *portal) podLogs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !p.requireElevated(w, r) {
+		return
+	}
+
+	apiVars := mux.Vars(r)
+	namespace := apiVars["namespace"]
+	name := apiVars["name"]
+	query := r.URL.Query()
+
+	options := cluster.PodLogOptions{
+		Container: query.Get("container"),
+		TailLines: podLogsDefaultTailLines,
+	}
+
+	if len(validation.IsDNS1123Label(namespace)) > 0 ||
+		len(validation.IsDNS1123Subdomain(name)) > 0 ||
+		options.Container != "" && len(validation.IsDNS1123Label(options.Container)) > 0 {
+		p.badRequest(w, fmt.Errorf("invalid pod %s/%s", namespace, name))
+		return
+	}
+
+	var err error
+	if v := query.Get("tail"); v != "" {
+		options.TailLines, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || options.TailLines < 1 || options.TailLines > podLogsMaxTailLines {
+			http.Error(w, fmt.Sprintf("tail must be between 1 and %d", podLogsMaxTailLines), http.StatusBadRequest)
+			return
+		}
+	}
+
+	for _, b := range []struct {
+		name  string
+		value *bool
+	}{
+		{"follow", &options.Follow},
+		{"previous", &options.Previous},
+	} {
+		if v := query.Get(b.name); v != "" {
+			*b.value, err = strconv.ParseBool(v)
+			if err != nil {
+				p.badRequest(w, err)
+				return
+			}
+		}
+	}
+
+	if options.Follow {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, podLogsFollowTimeout)
+		defer cancel()
+	}
+
+	fetcher, err := p.makeFetcher(ctx, r)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	logs, err := fetcher.PodLogs(ctx, namespace, name, options)
+	if kerrors.IsNotFound(err) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	if kerrors.IsBadRequest(err) {
+		// e.g. a container name is required, or there is no previous container
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+	defer logs.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	var dst io.Writer = w
+	if flusher, ok := w.(http.Flusher); ok && options.Follow {
+		dst = &flushWriter{w: w, f: flusher}
+	}
+
+	_, err = io.Copy(dst, logs)
+	if err != nil && ctx.Err() == nil {
+		p.log.Warn(err)
+	}
+}
+
+// flushWriter flushes after every write so that followed logs reach the
+// browser as they are produced
+type flushWriter struct {
+	w io.Writer
+	f http.Flusher
+}
+
+func (fw *flushWriter) Write(b []byte) (int, error) {
+	n, err := fw.w.Write(b)
+	fw.f.Flush()
+	return n, err
+}
+
+// clusterVersionHistory returns the desired version, channel and update
+// history of a cluster
+func (p *portal) clusterVersionHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	fetcher, err := p.makeFetcher(ctx, r)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	history, err := fetcher.ClusterVersionHistory(ctx)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	p.writeJSON(w, history)
+}
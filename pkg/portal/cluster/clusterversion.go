@@ -0,0 +1,67 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ClusterVersionUpdate struct {
+	State          string `json:"state"`
+	Version        string `json:"version"`
+	Image          string `json:"image"`
+	Verified       bool   `json:"verified"`
+	StartedTime    string `json:"startedTime"`
+	CompletionTime string `json:"completionTime,omitempty"`
+}
+
+type ClusterVersionHistoryInformation struct {
+	DesiredVersion string                 `json:"desiredVersion"`
+	Channel        string                 `json:"channel"`
+	History        []ClusterVersionUpdate `json:"history"`
+}
+
+func clusterVersionHistoryInformationFromClusterVersion(cv *configv1.ClusterVersion) *ClusterVersionHistoryInformation {
+	final := &ClusterVersionHistoryInformation{
+		DesiredVersion: cv.Status.Desired.Version,
+		Channel:        cv.Spec.Channel,
+		History:        make([]ClusterVersionUpdate, 0, len(cv.Status.History)),
+	}
+
+	// the cluster version operator keeps the history most recent first
+	for _, h := range cv.Status.History {
+		update := ClusterVersionUpdate{
+			State:       string(h.State),
+			Version:     h.Version,
+			Image:       h.Image,
+			Verified:    h.Verified,
+			StartedTime: h.StartedTime.UTC().Format(time.RFC3339),
+		}
+
+		if h.CompletionTime != nil {
+			update.CompletionTime = h.CompletionTime.UTC().Format(time.RFC3339)
+		}
+
+		final.History = append(final.History, update)
+	}
+
+	return final
+}
+
+func (f *realFetcher) ClusterVersionHistory(ctx context.Context) (*ClusterVersionHistoryInformation, error) {
+	cv, err := f.configCli.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return clusterVersionHistoryInformationFromClusterVersion(cv), nil
+}
+
+func (c *client) ClusterVersionHistory(ctx context.Context) (*ClusterVersionHistoryInformation, error) {
+	return c.fetcher.ClusterVersionHistory(ctx)
+}
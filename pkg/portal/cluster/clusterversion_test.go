@@ -0,0 +1,95 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestClusterVersionHistory(t *testing.T) {
+	ctx := context.Background()
+
+	started := metav1.NewTime(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC))
+	installed := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	completed := metav1.NewTime(time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC))
+
+	configCli := configfake.NewSimpleClientset(&configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "version",
+		},
+		Spec: configv1.ClusterVersionSpec{
+			Channel: "stable-4.13",
+		},
+		Status: configv1.ClusterVersionStatus{
+			Desired: configv1.Release{
+				Version: "4.13.1",
+			},
+			History: []configv1.UpdateHistory{
+				{
+					State:       configv1.PartialUpdate,
+					Version:     "4.13.1",
+					Image:       "quay.io/openshift-release-dev/ocp-release@sha256:b",
+					Verified:    true,
+					StartedTime: started,
+				},
+				{
+					State:          configv1.CompletedUpdate,
+					Version:        "4.12.25",
+					Image:          "quay.io/openshift-release-dev/ocp-release@sha256:a",
+					StartedTime:    installed,
+					CompletionTime: &completed,
+				},
+			},
+		},
+	})
+
+	_, log := testlog.New()
+
+	c := &client{
+		fetcher: &realFetcher{
+			configCli: configCli,
+			log:       log,
+		},
+		log: log,
+	}
+
+	info, err := c.ClusterVersionHistory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &ClusterVersionHistoryInformation{
+		DesiredVersion: "4.13.1",
+		Channel:        "stable-4.13",
+		History: []ClusterVersionUpdate{
+			{
+				State:       "Partial",
+				Version:     "4.13.1",
+				Image:       "quay.io/openshift-release-dev/ocp-release@sha256:b",
+				Verified:    true,
+				StartedTime: "2023-01-02T00:00:00Z",
+			},
+			{
+				State:          "Completed",
+				Version:        "4.12.25",
+				Image:          "quay.io/openshift-release-dev/ocp-release@sha256:a",
+				StartedTime:    "2023-01-01T00:00:00Z",
+				CompletionTime: "2023-01-01T01:00:00Z",
+			},
+		},
+	}
+
+	for _, l := range deep.Equal(expected, info) {
+		t.Error(l)
+	}
+}
@@ -0,0 +1,128 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// eventsLimit caps the number of events returned, most recent first
+const eventsLimit = 500
+
+// EventFilter restricts the events listed.  Empty fields match everything.
+type EventFilter struct {
+	Namespace          string
+	InvolvedObjectKind string
+	InvolvedObjectName string
+}
+
+type EventObject struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+type EventInformation struct {
+	Namespace      string      `json:"namespace"`
+	Type           string      `json:"type"`
+	Reason         string      `json:"reason"`
+	Message        string      `json:"message"`
+	Source         string      `json:"source"`
+	Count          int32       `json:"count"`
+	InvolvedObject EventObject `json:"involvedObject"`
+	FirstTimestamp string      `json:"firstTimestamp"`
+	LastTimestamp  string      `json:"lastTimestamp"`
+}
+
+type EventListInformation struct {
+	Events []EventInformation `json:"events"`
+}
+
+// eventTime returns the most recent time at which event occurred
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func eventListInformationFromEventList(events *corev1.EventList) *EventListInformation {
+	items := make([]*corev1.Event, 0, len(events.Items))
+	for i := range events.Items {
+		items = append(items, &events.Items[i])
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return eventTime(items[i]).After(eventTime(items[j])) })
+
+	if len(items) > eventsLimit {
+		items = items[:eventsLimit]
+	}
+
+	final := &EventListInformation{
+		Events: make([]EventInformation, 0, len(items)),
+	}
+
+	for _, event := range items {
+		source := event.Source.Component
+		if source == "" {
+			source = event.ReportingController
+		}
+
+		firstTimestamp := event.FirstTimestamp.Time
+		if firstTimestamp.IsZero() {
+			firstTimestamp = eventTime(event)
+		}
+
+		final.Events = append(final.Events, EventInformation{
+			Namespace: event.Namespace,
+			Type:      event.Type,
+			Reason:    event.Reason,
+			Message:   event.Message,
+			Source:    source,
+			Count:     event.Count,
+			InvolvedObject: EventObject{
+				Kind:      event.InvolvedObject.Kind,
+				Namespace: event.InvolvedObject.Namespace,
+				Name:      event.InvolvedObject.Name,
+			},
+			FirstTimestamp: firstTimestamp.UTC().Format(time.RFC3339),
+			LastTimestamp:  eventTime(event).UTC().Format(time.RFC3339),
+		})
+	}
+
+	return final
+}
+
+func (f *realFetcher) Events(ctx context.Context, filter EventFilter) (*EventListInformation, error) {
+	selector := fields.Set{}
+	if filter.InvolvedObjectKind != "" {
+		selector["involvedObject.kind"] = filter.InvolvedObjectKind
+	}
+	if filter.InvolvedObjectName != "" {
+		selector["involvedObject.name"] = filter.InvolvedObjectName
+	}
+
+	r, err := f.kubernetesCli.CoreV1().Events(filter.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: selector.AsSelector().String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return eventListInformationFromEventList(r), nil
+}
+
+func (c *client) Events(ctx context.Context, filter EventFilter) (*EventListInformation, error) {
+	return c.fetcher.Events(ctx, filter)
+}
@@ -0,0 +1,121 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestEvents(t *testing.T) {
+	ctx := context.Background()
+
+	earlier := metav1.NewTime(time.Date(2023, 1, 1, 11, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	kubernetesCli := fake.NewSimpleClientset(
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "etcd.1",
+				Namespace: "openshift-etcd",
+			},
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      "Pod",
+				Namespace: "openshift-etcd",
+				Name:      "etcd-master-0",
+			},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "Unhealthy",
+			Message:        "Readiness probe failed",
+			Source:         corev1.EventSource{Component: "kubelet"},
+			Count:          3,
+			FirstTimestamp: earlier,
+			LastTimestamp:  earlier,
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "etcd.2",
+				Namespace: "openshift-etcd",
+			},
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      "Pod",
+				Namespace: "openshift-etcd",
+				Name:      "etcd-master-1",
+			},
+			Type:                corev1.EventTypeNormal,
+			Reason:              "Started",
+			Message:             "Started container etcd",
+			ReportingController: "kubelet",
+			EventTime:           metav1.NewMicroTime(later.Time),
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other",
+				Namespace: "default",
+			},
+			LastTimestamp: later,
+		},
+	)
+
+	_, log := testlog.New()
+
+	c := &client{
+		fetcher: &realFetcher{
+			kubernetesCli: kubernetesCli,
+			log:           log,
+		},
+		log: log,
+	}
+
+	info, err := c.Events(ctx, EventFilter{Namespace: "openshift-etcd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &EventListInformation{
+		Events: []EventInformation{
+			{
+				Namespace: "openshift-etcd",
+				Type:      "Normal",
+				Reason:    "Started",
+				Message:   "Started container etcd",
+				Source:    "kubelet",
+				InvolvedObject: EventObject{
+					Kind:      "Pod",
+					Namespace: "openshift-etcd",
+					Name:      "etcd-master-1",
+				},
+				FirstTimestamp: "2023-01-01T12:00:00Z",
+				LastTimestamp:  "2023-01-01T12:00:00Z",
+			},
+			{
+				Namespace: "openshift-etcd",
+				Type:      "Warning",
+				Reason:    "Unhealthy",
+				Message:   "Readiness probe failed",
+				Source:    "kubelet",
+				Count:     3,
+				InvolvedObject: EventObject{
+					Kind:      "Pod",
+					Namespace: "openshift-etcd",
+					Name:      "etcd-master-0",
+				},
+				FirstTimestamp: "2023-01-01T11:00:00Z",
+				LastTimestamp:  "2023-01-01T11:00:00Z",
+			},
+		},
+	}
+
+	for _, l := range deep.Equal(expected, info) {
+		t.Error(l)
+	}
+}
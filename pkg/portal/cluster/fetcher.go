@@ -5,6 +5,7 @@ package cluster
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"time"
 
@@ -26,6 +27,9 @@ type FetchClient interface {
 	Machines(context.Context) (*MachineListInformation, error)
 	MachineSets(context.Context) (*MachineSetListInformation, error)
 	Statistics(context.Context, *http.Client, string, time.Duration, time.Time, string) ([]Metrics, error)
+	Events(context.Context, EventFilter) (*EventListInformation, error)
+	PodLogs(ctx context.Context, namespace, name string, options PodLogOptions) (io.ReadCloser, error)
+	ClusterVersionHistory(context.Context) (*ClusterVersionHistoryInformation, error)
 }
 
 // client is an implementation of FetchClient. It currently contains a "fetcher"
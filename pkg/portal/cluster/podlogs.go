@@ -0,0 +1,48 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podLogsLimitBytes caps the size of the logs returned, including when they
+// are followed
+const podLogsLimitBytes = 10 * 1024 * 1024
+
+// PodLogOptions selects the logs returned by PodLogs
+type PodLogOptions struct {
+	Container string
+	TailLines int64
+	Follow    bool
+	Previous  bool
+}
+
+func (f *realFetcher) PodLogs(ctx context.Context, namespace, name string, options PodLogOptions) (io.ReadCloser, error) {
+	return f.kubernetesCli.CoreV1().Pods(namespace).GetLogs(name, podLogOptions(options)).Stream(ctx)
+}
+
+func podLogOptions(options PodLogOptions) *corev1.PodLogOptions {
+	limitBytes := int64(podLogsLimitBytes)
+
+	logOptions := &corev1.PodLogOptions{
+		Container:  options.Container,
+		Follow:     options.Follow,
+		Previous:   options.Previous,
+		LimitBytes: &limitBytes,
+	}
+
+	if options.TailLines > 0 {
+		logOptions.TailLines = &options.TailLines
+	}
+
+	return logOptions
+}
+
+func (c *client) PodLogs(ctx context.Context, namespace, name string, options PodLogOptions) (io.ReadCloser, error) {
+	return c.fetcher.PodLogs(ctx, namespace, name, options)
+}
@@ -0,0 +1,104 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestPodLogOptions(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		options PodLogOptions
+		want    *corev1.PodLogOptions
+	}{
+		{
+			name: "no options",
+			want: &corev1.PodLogOptions{
+				LimitBytes: ptr.To(int64(podLogsLimitBytes)),
+			},
+		},
+		{
+			name: "container and tail",
+			options: PodLogOptions{
+				Container: "etcd",
+				TailLines: 100,
+			},
+			want: &corev1.PodLogOptions{
+				Container:  "etcd",
+				TailLines:  ptr.To(int64(100)),
+				LimitBytes: ptr.To(int64(podLogsLimitBytes)),
+			},
+		},
+		{
+			name: "follow",
+			options: PodLogOptions{
+				TailLines: 1000,
+				Follow:    true,
+			},
+			want: &corev1.PodLogOptions{
+				Follow:     true,
+				TailLines:  ptr.To(int64(1000)),
+				LimitBytes: ptr.To(int64(podLogsLimitBytes)),
+			},
+		},
+		{
+			name: "previous",
+			options: PodLogOptions{
+				Container: "etcd",
+				Previous:  true,
+			},
+			want: &corev1.PodLogOptions{
+				Container:  "etcd",
+				Previous:   true,
+				LimitBytes: ptr.To(int64(podLogsLimitBytes)),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, l := range deep.Equal(podLogOptions(tt.options), tt.want) {
+				t.Error(l)
+			}
+		})
+	}
+}
+
+func TestPodLogs(t *testing.T) {
+	ctx := context.Background()
+
+	_, log := testlog.New()
+
+	c := &client{
+		fetcher: &realFetcher{
+			kubernetesCli: fake.NewSimpleClientset(),
+			log:           log,
+		},
+		log: log,
+	}
+
+	logs, err := c.PodLogs(ctx, "openshift-etcd", "etcd-master-0", PodLogOptions{Follow: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logs.Close()
+
+	b, err := io.ReadAll(logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the fake clientset returns fixed logs for any pod
+	if string(b) != "fake logs" {
+		t.Error(string(b))
+	}
+}
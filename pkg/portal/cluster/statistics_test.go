@@ -0,0 +1,154 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestGetPromQuery(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		statisticsType string
+		parameters     map[string]string
+		wantQuery      string
+		wantErr        string
+	}{
+		{
+			name:           "no parameters",
+			statisticsType: "kubeapimemory",
+			wantQuery:      `process_resident_memory_bytes{job="apiserver"}`,
+		},
+		{
+			name:           "optional parameter omitted",
+			statisticsType: "nodepressure",
+			wantQuery:      `sum(kube_node_status_condition{condition=~"MemoryPressure|DiskPressure|PIDPressure",status="true"}) by (node, condition)`,
+		},
+		{
+			name:           "optional parameter set",
+			statisticsType: "nodepressure",
+			parameters:     map[string]string{"node": "cluster-worker-0"},
+			wantQuery:      `sum(kube_node_status_condition{condition=~"MemoryPressure|DiskPressure|PIDPressure",status="true",node="cluster-worker-0"}) by (node, condition)`,
+		},
+		{
+			name:           "required parameter set",
+			statisticsType: "namespacerestarts",
+			parameters:     map[string]string{"namespace": "openshift-etcd"},
+			wantQuery:      `sum(increase(kube_pod_container_status_restarts_total{namespace="openshift-etcd"}[1h])) by (pod)`,
+		},
+		{
+			name:           "required parameter missing",
+			statisticsType: "namespacerestarts",
+			wantErr:        "missing parameter 'namespace' for statistic type 'namespacerestarts'",
+		},
+		{
+			name:           "parameter does not match pattern",
+			statisticsType: "namespacerestarts",
+			parameters:     map[string]string{"namespace": `x"}) or vector(1`},
+			wantErr:        `invalid value 'x"}) or vector(1' for parameter 'namespace'`,
+		},
+		{
+			name:           "unknown parameter",
+			statisticsType: "kubeapimemory",
+			parameters:     map[string]string{"node": "cluster-worker-0"},
+			wantErr:        "invalid parameter 'node' for statistic type 'kubeapimemory'",
+		},
+		{
+			name:           "unknown statistic",
+			statisticsType: "invalid",
+			wantErr:        "invalid statistic type 'invalid'",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := GetPromQuery(tt.statisticsType, tt.parameters)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if query != tt.wantQuery {
+				t.Error(query)
+			}
+		})
+	}
+}
+
+func TestLoadStatisticsCatalog(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		catalog string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			catalog: `version: 1
+statistics:
+- name: a
+  unit: count
+  query: up`,
+		},
+		{
+			name:    "unsupported version",
+			catalog: `version: 2`,
+			wantErr: "unsupported statistics catalog version 2",
+		},
+		{
+			name: "duplicate",
+			catalog: `version: 1
+statistics:
+- name: a
+  unit: count
+  query: up
+- name: a
+  unit: count
+  query: up`,
+			wantErr: `duplicate statistic "a"`,
+		},
+		{
+			name: "missing unit",
+			catalog: `version: 1
+statistics:
+- name: a
+  query: up`,
+			wantErr: `statistic "a" must have a name, unit and query`,
+		},
+		{
+			name: "parameter without pattern",
+			catalog: `version: 1
+statistics:
+- name: a
+  unit: count
+  query: up
+  parameters:
+  - name: node`,
+			wantErr: `parameter "node" of statistic "a" must have a pattern`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadStatisticsCatalog([]byte(tt.catalog))
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestGetStatisticsCatalog(t *testing.T) {
+	c := GetStatisticsCatalog()
+
+	if c.Version != statisticsCatalogVersion {
+		t.Error(c.Version)
+	}
+
+	groups := map[string]bool{}
+	for _, s := range c.Statistics {
+		if s.Query != "" {
+			t.Errorf("%s: query should not be exposed", s.Name)
+		}
+		groups[s.Group] = true
+	}
+
+	for _, group := range []string{"kube-apiserver", "kube-controller-manager", "dns", "ingress", "etcd", "network", "nodes", "machine-config", "image-registry"} {
+		if !groups[group] {
+			t.Errorf("missing group %s", group)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+)
+
+const statisticsCatalogVersion = 1
+
+//go:embed statistics.yaml
+var statisticsCatalogYAML []byte
+
+var catalog = mustLoadStatisticsCatalog(statisticsCatalogYAML)
+
+// StatisticsCatalog describes the statistics which the portal can query, so
+// that the frontend can render them without knowing about each of them
+type StatisticsCatalog struct {
+	Version    int          `json:"version"`
+	Statistics []*Statistic `json:"statistics"`
+}
+
+// Statistic is a single PromQL-backed statistic
+type Statistic struct {
+	Name       string                `json:"name"`
+	Group      string                `json:"group"`
+	Title      string                `json:"title"`
+	Unit       string                `json:"unit"`
+	Query      string                `json:"query,omitempty"`
+	Parameters []*StatisticParameter `json:"parameters,omitempty"`
+
+	template *template.Template
+}
+
+// StatisticParameter is a value which can be passed into the query of a
+// statistic, for example a namespace or node name
+type StatisticParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Pattern     string `json:"pattern,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+
+	rx *regexp.Regexp
+}
+
+func mustLoadStatisticsCatalog(b []byte) *StatisticsCatalog {
+	c, err := loadStatisticsCatalog(b)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func loadStatisticsCatalog(b []byte) (*StatisticsCatalog, error) {
+	var c *StatisticsCatalog
+	err := yaml.Unmarshal(b, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Version != statisticsCatalogVersion {
+		return nil, fmt.Errorf("unsupported statistics catalog version %d", c.Version)
+	}
+
+	names := map[string]struct{}{}
+	for _, s := range c.Statistics {
+		if s.Name == "" || s.Unit == "" || s.Query == "" {
+			return nil, fmt.Errorf("statistic %q must have a name, unit and query", s.Name)
+		}
+
+		if _, found := names[s.Name]; found {
+			return nil, fmt.Errorf("duplicate statistic %q", s.Name)
+		}
+		names[s.Name] = struct{}{}
+
+		s.template, err = template.New(s.Name).Option("missingkey=zero").Parse(s.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, param := range s.Parameters {
+			if param.Pattern == "" {
+				return nil, fmt.Errorf("parameter %q of statistic %q must have a pattern", param.Name, s.Name)
+			}
+
+			// parameter values are substituted into PromQL verbatim, so they
+			// must always match the whole pattern
+			param.rx, err = regexp.Compile("^(?:" + param.Pattern + ")$")
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func (c *StatisticsCatalog) get(name string) *Statistic {
+	for _, s := range c.Statistics {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// GetStatisticsCatalog returns the catalog of statistics, without the queries,
+// sorted by group
+func GetStatisticsCatalog() *StatisticsCatalog {
+	c := &StatisticsCatalog{
+		Version:    catalog.Version,
+		Statistics: make([]*Statistic, 0, len(catalog.Statistics)),
+	}
+
+	for _, s := range catalog.Statistics {
+		c.Statistics = append(c.Statistics, &Statistic{
+			Name:       s.Name,
+			Group:      s.Group,
+			Title:      s.Title,
+			Unit:       s.Unit,
+			Parameters: s.Parameters,
+		})
+	}
+
+	sort.SliceStable(c.Statistics, func(i, j int) bool { return c.Statistics[i].Group < c.Statistics[j].Group })
+
+	return c
+}
+
+func (s *Statistic) promQuery(parameters map[string]string) (string, error) {
+	values := map[string]string{}
+
+	for name := range parameters {
+		if s.parameter(name) == nil {
+			return "", fmt.Errorf("invalid parameter '%s' for statistic type '%s'", name, s.Name)
+		}
+	}
+
+	for _, param := range s.Parameters {
+		value := parameters[param.Name]
+		if value == "" {
+			if param.Required {
+				return "", fmt.Errorf("missing parameter '%s' for statistic type '%s'", param.Name, s.Name)
+			}
+			continue
+		}
+
+		if !param.rx.MatchString(value) {
+			return "", fmt.Errorf("invalid value '%s' for parameter '%s'", value, param.Name)
+		}
+
+		values[param.Name] = value
+	}
+
+	buf := &bytes.Buffer{}
+	err := s.template.Execute(buf, values)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (s *Statistic) parameter(name string) *StatisticParameter {
+	for _, param := range s.Parameters {
+		if param.Name == name {
+			return param
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ package portal
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -12,8 +13,11 @@ import (
 
 	"github.com/go-test/deep"
 	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/portal/cluster"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	testdatabase "github.com/Azure/ARO-RP/test/database"
 )
 
@@ -235,3 +239,289 @@ func TestClusterDetail(t *testing.T) {
 		t.Error(l)
 	}
 }
+
+func TestClusterAlerts(t *testing.T) {
+	dbClusterAlerts, clusterAlertsClient := testdatabase.NewFakeClusterAlerts()
+
+	key := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/succeeded"
+	older := time.Date(2011, 1, 2, 1, 3, 0, 0, time.UTC)
+	newer := time.Date(2011, 1, 2, 2, 3, 0, 0, time.UTC)
+
+	for _, doc := range []*api.ClusterAlertDocument{
+		{
+			ID:  "a",
+			Key: key,
+			ClusterAlert: &api.ClusterAlert{
+				Name:     "etcdMembersDown",
+				Status:   api.ClusterAlertStatusResolved,
+				StartsAt: older,
+			},
+		},
+		{
+			ID:  "b",
+			Key: key,
+			ClusterAlert: &api.ClusterAlert{
+				Name:     "KubeAPIErrorBudgetBurn",
+				Status:   api.ClusterAlertStatusFiring,
+				StartsAt: newer,
+			},
+		},
+		{
+			ID:  "c",
+			Key: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/other",
+			ClusterAlert: &api.ClusterAlert{
+				Name:     "etcdNoLeader",
+				StartsAt: newer,
+			},
+		},
+	} {
+		_, err := clusterAlertsClient.Create(context.Background(), doc.Key, doc, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &portal{
+		dbClusterAlerts: dbClusterAlerts,
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/api/00000000-0000-0000-0000-000000000000/resourcegroupname/succeeded/alerts", nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	aadAuthenticatedRouter := mux.NewRouter()
+	p.aadAuthenticatedRoutes(aadAuthenticatedRouter, nil, nil, nil)
+	w := httptest.NewRecorder()
+	aadAuthenticatedRouter.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Error(w.Header().Get("Content-Type"))
+	}
+
+	var r []*api.ClusterAlert
+	err = json.NewDecoder(w.Body).Decode(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*api.ClusterAlert{
+		{
+			Name:     "KubeAPIErrorBudgetBurn",
+			Status:   api.ClusterAlertStatusFiring,
+			StartsAt: newer,
+		},
+		{
+			Name:     "etcdMembersDown",
+			Status:   api.ClusterAlertStatusResolved,
+			StartsAt: older,
+		},
+	}
+
+	for _, l := range deep.Equal(expected, r) {
+		t.Error(l)
+	}
+}
+
+func TestClusterAuditLog(t *testing.T) {
+	dbAdminAuditLog, adminAuditLogClient := testdatabase.NewFakeAdminAuditLog()
+
+	key := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/succeeded"
+	older := time.Date(2011, 1, 2, 1, 3, 0, 0, time.UTC)
+	newer := time.Date(2011, 1, 2, 2, 3, 0, 0, time.UTC)
+
+	for _, doc := range []*api.AdminAuditEntryDocument{
+		{
+			ID:  "a",
+			Key: key,
+			AdminAuditEntry: &api.AdminAuditEntry{
+				CallerIdentity: "alice",
+				Method:         http.MethodPost,
+				Path:           "/admin" + key + "/redeployvm",
+				StatusCode:     http.StatusOK,
+				StartedAt:      older,
+			},
+		},
+		{
+			ID:  "b",
+			Key: key,
+			AdminAuditEntry: &api.AdminAuditEntry{
+				CallerIdentity: "bob",
+				Method:         http.MethodPost,
+				Path:           "/admin" + key + "/stopvm",
+				StatusCode:     http.StatusInternalServerError,
+				StartedAt:      newer,
+			},
+		},
+		{
+			ID:  "c",
+			Key: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/other",
+			AdminAuditEntry: &api.AdminAuditEntry{
+				CallerIdentity: "carol",
+				StartedAt:      newer,
+			},
+		},
+	} {
+		_, err := adminAuditLogClient.Create(context.Background(), doc.Key, doc, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &portal{
+		dbAdminAuditLog: dbAdminAuditLog,
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/api/00000000-0000-0000-0000-000000000000/resourcegroupname/succeeded/auditlog", nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	aadAuthenticatedRouter := mux.NewRouter()
+	p.aadAuthenticatedRoutes(aadAuthenticatedRouter, nil, nil, nil)
+	w := httptest.NewRecorder()
+	aadAuthenticatedRouter.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Error(w.Header().Get("Content-Type"))
+	}
+
+	var r []*api.AdminAuditEntry
+	err = json.NewDecoder(w.Body).Decode(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*api.AdminAuditEntry{
+		{
+			CallerIdentity: "bob",
+			Method:         http.MethodPost,
+			Path:           "/admin" + key + "/stopvm",
+			StatusCode:     http.StatusInternalServerError,
+			StartedAt:      newer,
+		},
+		{
+			CallerIdentity: "alice",
+			Method:         http.MethodPost,
+			Path:           "/admin" + key + "/redeployvm",
+			StatusCode:     http.StatusOK,
+			StartedAt:      older,
+		},
+	}
+
+	for _, l := range deep.Equal(expected, r) {
+		t.Error(l)
+	}
+}
+
+func TestStatisticsCatalog(t *testing.T) {
+	p := &portal{}
+
+	req, err := http.NewRequest(http.MethodGet, "/api/00000000-0000-0000-0000-000000000000/resourcegroupname/succeeded/statistics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aadAuthenticatedRouter := mux.NewRouter()
+	p.aadAuthenticatedRoutes(aadAuthenticatedRouter, nil, nil, nil)
+	w := httptest.NewRecorder()
+	aadAuthenticatedRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Error(w.Code)
+	}
+
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Error(w.Header().Get("Content-Type"))
+	}
+
+	var c *cluster.StatisticsCatalog
+	err = json.NewDecoder(w.Body).Decode(&c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range deep.Equal(c, cluster.GetStatisticsCatalog()) {
+		t.Error(l)
+	}
+}
+
+func TestClusterDiagnosticsValidation(t *testing.T) {
+	apiPath := "/api/00000000-0000-0000-0000-000000000000/resourcegroupname/succeeded"
+
+	for _, tt := range []struct {
+		name           string
+		path           string
+		notElevated    bool
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:     "events, invalid namespace",
+			path:     apiPath + "/events?namespace=Bad_Namespace",
+			wantBody: "Bad Request\n",
+		},
+		{
+			name:     "events, invalid kind",
+			path:     apiPath + "/events?kind=pod,name=x",
+			wantBody: "Bad Request\n",
+		},
+		{
+			name:     "pod logs, invalid container",
+			path:     apiPath + "/pods/openshift-etcd/etcd-master-0/logs?container=Bad_Container",
+			wantBody: "Bad Request\n",
+		},
+		{
+			name:     "pod logs, invalid tail",
+			path:     apiPath + "/pods/openshift-etcd/etcd-master-0/logs?tail=0",
+			wantBody: "tail must be between 1 and 100000\n",
+		},
+		{
+			name:     "pod logs, invalid follow",
+			path:     apiPath + "/pods/openshift-etcd/etcd-master-0/logs?follow=sometimes",
+			wantBody: "Bad Request\n",
+		},
+		{
+			name:           "pod logs, not elevated",
+			path:           apiPath + "/pods/openshift-etcd/etcd-master-0/logs",
+			notElevated:    true,
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Forbidden\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &portal{
+				log:              logrus.NewEntry(logrus.StandardLogger()),
+				elevatedGroupIDs: []string{"00000000-0000-0000-0000-000000000001"},
+			}
+
+			groups := []string{"00000000-0000-0000-0000-000000000001"}
+			if tt.notElevated {
+				groups = []string{"00000000-0000-0000-0000-000000000002"}
+			}
+
+			ctx := context.WithValue(context.Background(), middleware.ContextKeyGroups, groups)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantStatusCode == 0 {
+				tt.wantStatusCode = http.StatusBadRequest
+			}
+
+			aadAuthenticatedRouter := mux.NewRouter()
+			p.aadAuthenticatedRoutes(aadAuthenticatedRouter, nil, nil, nil)
+			w := httptest.NewRecorder()
+			aadAuthenticatedRouter.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Error(w.Code)
+			}
+
+			if w.Body.String() != tt.wantBody {
+				t.Error(w.Body.String())
+			}
+		})
+	}
+}
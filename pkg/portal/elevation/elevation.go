@@ -0,0 +1,68 @@
+package elevation
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
+)
+
+// Just-in-time elevation lets an SRE who is not in an elevated group request
+// elevated access to a single cluster.  The request is stored as a
+// PortalDocument with Portal.Elevation set; once a second, elevated, SRE
+// approves it, kubeconfigs and SSH passwords issued to the requester for that
+// cluster are elevated until the requested duration has passed.  Every step is
+// written to the audit log.
+
+// Audit operation names
+const (
+	OperationRequest = "ElevationRequest"
+	OperationApprove = "ElevationApprove"
+	OperationDeny    = "ElevationDeny"
+	OperationUse     = "ElevationUse"
+)
+
+// Active returns the approved, unexpired elevation of username on the cluster
+// which expires last, or nil if there is none
+func Active(ctx context.Context, dbPortal database.Portal, username, resourceID string, now time.Time) (*api.PortalDocument, error) {
+	docs, err := dbPortal.ListByResourceID(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var active *api.PortalDocument
+	for _, doc := range docs.PortalDocuments {
+		if doc.Portal.Elevation == nil ||
+			doc.Portal.Username != username ||
+			doc.Portal.Elevation.State != api.ElevationStateApproved ||
+			doc.Portal.Elevation.ExpiresAt == nil ||
+			!doc.Portal.Elevation.ExpiresAt.After(now) {
+			continue
+		}
+
+		if active == nil || doc.Portal.Elevation.ExpiresAt.After(*active.Portal.Elevation.ExpiresAt) {
+			active = doc
+		}
+	}
+
+	return active, nil
+}
+
+// Audit writes an elevation event about the cluster resourceID to the audit
+// log on behalf of the user making the request r
+func Audit(auditLog *logrus.Entry, env env.Core, r *http.Request, operation, resourceID, elevationID, description string) {
+	middleware.Audit(auditLog, env, r, operation, elevationID, audit.TargetResource{
+		TargetResourceName: resourceID,
+		TargetResourceType: "elevation",
+	}, description)
+}
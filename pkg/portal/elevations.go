@@ -0,0 +1,272 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/portal/elevation"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const (
+	elevationMinDuration = 15 * time.Minute
+	elevationMaxDuration = 8 * time.Hour
+
+	// elevationPendingTimeout is how long a request can wait for review
+	elevationPendingTimeout = time.Hour
+
+	// elevationRetention is how long requests are kept and shown in the
+	// portal; the audit log is the permanent record
+	elevationRetention = 7 * 24 * time.Hour
+
+	elevationMaxReasonLength = 1024
+
+	elevationStateExpired = "Expired"
+)
+
+// Elevation is the portal view of a just-in-time elevation request
+type Elevation struct {
+	ID          string     `json:"id"`
+	Username    string     `json:"username"`
+	Reason      string     `json:"reason"`
+	Duration    string     `json:"duration"`
+	State       string     `json:"state"`
+	RequestedAt *time.Time `json:"requestedAt,omitempty"`
+	Reviewer    string     `json:"reviewer,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+type elevationRequest struct {
+	Reason   string `json:"reason"`
+	Duration string `json:"duration"`
+}
+
+func (p *portal) elevationView(doc *api.PortalDocument) *Elevation {
+	e := doc.Portal.Elevation
+
+	state := string(e.State)
+	switch {
+	case e.State == api.ElevationStatePending && e.RequestedAt != nil && !p.now().Before(e.RequestedAt.Add(elevationPendingTimeout)),
+		e.State == api.ElevationStateApproved && e.ExpiresAt != nil && !p.now().Before(*e.ExpiresAt):
+		state = elevationStateExpired
+	}
+
+	return &Elevation{
+		ID:          doc.ID,
+		Username:    doc.Portal.Username,
+		Reason:      e.Reason,
+		Duration:    (time.Duration(e.Duration) * time.Second).String(),
+		State:       state,
+		RequestedAt: e.RequestedAt,
+		Reviewer:    e.Reviewer,
+		ReviewedAt:  e.ReviewedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
+
+// elevations lists the just-in-time elevation requests for a cluster, most
+// recent first
+func (p *portal) elevations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.badRequest(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	docs, err := p.dbPortal.ListByResourceID(ctx, resourceID)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	elevations := []*Elevation{}
+	for _, doc := range docs.PortalDocuments {
+		// never list kubeconfig or SSH documents: their IDs are credentials
+		if doc.Portal.Elevation != nil {
+			elevations = append(elevations, p.elevationView(doc))
+		}
+	}
+
+	sort.SliceStable(elevations, func(i, j int) bool {
+		return elevations[i].RequestedAt != nil && elevations[j].RequestedAt != nil &&
+			elevations[i].RequestedAt.After(*elevations[j].RequestedAt)
+	})
+
+	p.writeJSON(w, elevations)
+}
+
+// requestElevation records a request by the user for elevated access to a
+// cluster, for review by a second SRE
+func (p *portal) requestElevation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.badRequest(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	mediatype, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediatype != "application/json" {
+		http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req *elevationRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || req == nil {
+		p.badRequest(w, err)
+		return
+	}
+
+	if req.Reason == "" || len(req.Reason) > elevationMaxReasonLength {
+		http.Error(w, fmt.Sprintf("A reason of at most %d characters is required.", elevationMaxReasonLength), http.StatusBadRequest)
+		return
+	}
+
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil || duration < elevationMinDuration || duration > elevationMaxDuration {
+		http.Error(w, fmt.Sprintf("The duration must be between %s and %s.", elevationMinDuration, elevationMaxDuration), http.StatusBadRequest)
+		return
+	}
+
+	now := p.now().UTC()
+	doc := &api.PortalDocument{
+		ID:  p.dbPortal.NewUUID(),
+		TTL: int(elevationRetention / time.Second),
+		Portal: &api.Portal{
+			Username: ctx.Value(middleware.ContextKeyUsername).(string),
+			ID:       resourceID,
+			Elevation: &api.Elevation{
+				Reason:      req.Reason,
+				Duration:    int(duration / time.Second),
+				State:       api.ElevationStatePending,
+				RequestedAt: &now,
+			},
+		},
+	}
+
+	doc, err = p.dbPortal.Create(ctx, doc)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	elevation.Audit(p.audit, p.env, r, elevation.OperationRequest, resourceID, doc.ID,
+		fmt.Sprintf("requested elevation for %s: %s", duration, req.Reason))
+
+	w.WriteHeader(http.StatusCreated)
+	p.writeJSON(w, p.elevationView(doc))
+}
+
+// approveElevation approves a pending elevation request
+func (p *portal) approveElevation(w http.ResponseWriter, r *http.Request) {
+	p.reviewElevation(w, r, api.ElevationStateApproved)
+}
+
+// denyElevation denies a pending elevation request
+func (p *portal) denyElevation(w http.ResponseWriter, r *http.Request) {
+	p.reviewElevation(w, r, api.ElevationStateDenied)
+}
+
+// reviewElevation moves a pending elevation request to state.  Only an
+// elevated user who is not the requester may review a request.
+func (p *portal) reviewElevation(w http.ResponseWriter, r *http.Request, state api.ElevationState) {
+	ctx := r.Context()
+
+	if !p.requireElevated(w, r) {
+		return
+	}
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.badRequest(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	id, err := uuid.FromString(apiVars["elevation"])
+	if err != nil {
+		p.badRequest(w, err)
+		return
+	}
+
+	username := ctx.Value(middleware.ContextKeyUsername).(string)
+
+	var reviewErr string
+	var reviewStatus int
+
+	doc, err := p.dbPortal.Patch(ctx, id.String(), func(doc *api.PortalDocument) error {
+		reviewErr, reviewStatus = "", 0
+
+		switch {
+		case doc.Portal.Elevation == nil || doc.Portal.ID != resourceID:
+			reviewErr, reviewStatus = http.StatusText(http.StatusNotFound), http.StatusNotFound
+		case doc.Portal.Username == username:
+			reviewErr, reviewStatus = "Elevation requests must be reviewed by a second SRE.", http.StatusForbidden
+		case p.elevationView(doc).State != string(api.ElevationStatePending):
+			reviewErr, reviewStatus = "The elevation request is no longer pending.", http.StatusConflict
+		}
+		if reviewErr != "" {
+			return errors.New(reviewErr)
+		}
+
+		now := p.now().UTC()
+		doc.Portal.Elevation.State = state
+		doc.Portal.Elevation.Reviewer = username
+		doc.Portal.Elevation.ReviewedAt = &now
+
+		if state == api.ElevationStateApproved {
+			expiresAt := now.Add(time.Duration(doc.Portal.Elevation.Duration) * time.Second)
+			doc.Portal.Elevation.ExpiresAt = &expiresAt
+		}
+
+		return nil
+	})
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		reviewErr, reviewStatus = http.StatusText(http.StatusNotFound), http.StatusNotFound
+	}
+	if reviewErr != "" {
+		http.Error(w, reviewErr, reviewStatus)
+		return
+	}
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	operation := elevation.OperationDeny
+	if state == api.ElevationStateApproved {
+		operation = elevation.OperationApprove
+	}
+
+	elevation.Audit(p.audit, p.env, r, operation, resourceID, doc.ID,
+		fmt.Sprintf("%s elevation of %s for %s: %s", state, doc.Portal.Username, time.Duration(doc.Portal.Elevation.Duration)*time.Second, doc.Portal.Elevation.Reason))
+
+	p.writeJSON(w, p.elevationView(doc))
+}
+
+// elevationsPage serves the page which lists, requests and reviews the
+// just-in-time elevations of a cluster
+func (p *portal) elevationsPage(w http.ResponseWriter, r *http.Request) {
+	p.serve("elevations/index.html")(w, r)
+}
@@ -0,0 +1,402 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestElevations(t *testing.T) {
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/resourcename"
+	apiPath := "/api/00000000-0000-0000-0000-000000000000/resourcegroup/resourcename/elevations"
+	id := "03030303-0303-0303-0303-030303030001"
+	otherID := "03030303-0303-0303-0303-030303030002"
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	requestedAt := now.Add(-10 * time.Minute)
+	longAgo := now.Add(-2 * time.Hour)
+	reviewedAt := now
+	expiresAt := now.Add(2 * time.Hour)
+
+	pendingDoc := func() *api.PortalDocument {
+		return &api.PortalDocument{
+			ID:  id,
+			TTL: 604800,
+			Portal: &api.Portal{
+				Username: "requester",
+				ID:       resourceID,
+				Elevation: &api.Elevation{
+					Reason:      "ICM 1234",
+					Duration:    7200,
+					State:       api.ElevationStatePending,
+					RequestedAt: &requestedAt,
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		username       string
+		elevated       bool
+		fixture        func(*testdatabase.Fixture)
+		checker        func(*testdatabase.Checker)
+		wantStatusCode int
+		wantBody       string
+		wantAudit      string
+	}{
+		{
+			name:     "list",
+			method:   http.MethodGet,
+			path:     apiPath,
+			username: "requester",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(pendingDoc(),
+					&api.PortalDocument{
+						ID: otherID,
+						Portal: &api.Portal{
+							Username:   "requester",
+							ID:         resourceID,
+							Kubeconfig: &api.Kubeconfig{},
+						},
+					},
+					&api.PortalDocument{
+						ID: "03030303-0303-0303-0303-030303030003",
+						Portal: &api.Portal{
+							Username: "requester",
+							ID:       resourceID,
+							Elevation: &api.Elevation{
+								Reason:      "old",
+								Duration:    3600,
+								State:       api.ElevationStatePending,
+								RequestedAt: &longAgo,
+							},
+						},
+					})
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `[
+    {
+        "id": "03030303-0303-0303-0303-030303030001",
+        "username": "requester",
+        "reason": "ICM 1234",
+        "duration": "2h0m0s",
+        "state": "Pending",
+        "requestedAt": "2023-01-01T11:50:00Z"
+    },
+    {
+        "id": "03030303-0303-0303-0303-030303030003",
+        "username": "requester",
+        "reason": "old",
+        "duration": "1h0m0s",
+        "state": "Expired",
+        "requestedAt": "2023-01-01T10:00:00Z"
+    }
+]`,
+		},
+		{
+			name:     "request",
+			method:   http.MethodPost,
+			path:     apiPath,
+			body:     `{"reason":"ICM 1234","duration":"2h"}`,
+			username: "requester",
+			checker: func(c *testdatabase.Checker) {
+				doc := pendingDoc()
+				doc.Portal.Elevation.RequestedAt = &now
+				c.AddPortalDocuments(doc)
+			},
+			wantStatusCode: http.StatusCreated,
+			wantBody: `{
+    "id": "03030303-0303-0303-0303-030303030001",
+    "username": "requester",
+    "reason": "ICM 1234",
+    "duration": "2h0m0s",
+    "state": "Pending",
+    "requestedAt": "2023-01-01T12:00:00Z"
+}`,
+			wantAudit: "ElevationRequest",
+		},
+		{
+			name:           "request, no reason",
+			method:         http.MethodPost,
+			path:           apiPath,
+			body:           `{"duration":"2h"}`,
+			username:       "requester",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "A reason of at most 1024 characters is required.\n",
+		},
+		{
+			name:           "request, duration too long",
+			method:         http.MethodPost,
+			path:           apiPath,
+			body:           `{"reason":"ICM 1234","duration":"24h"}`,
+			username:       "requester",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "The duration must be between 15m0s and 8h0m0s.\n",
+		},
+		{
+			name:           "request, junk",
+			method:         http.MethodPost,
+			path:           apiPath,
+			body:           `{{`,
+			username:       "requester",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "Bad Request\n",
+		},
+		{
+			name:     "approve",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/approve",
+			username: "approver",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(pendingDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := pendingDoc()
+				doc.Portal.Elevation.State = api.ElevationStateApproved
+				doc.Portal.Elevation.Reviewer = "approver"
+				doc.Portal.Elevation.ReviewedAt = &reviewedAt
+				doc.Portal.Elevation.ExpiresAt = &expiresAt
+				c.AddPortalDocuments(doc)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+    "id": "03030303-0303-0303-0303-030303030001",
+    "username": "requester",
+    "reason": "ICM 1234",
+    "duration": "2h0m0s",
+    "state": "Approved",
+    "requestedAt": "2023-01-01T11:50:00Z",
+    "reviewer": "approver",
+    "reviewedAt": "2023-01-01T12:00:00Z",
+    "expiresAt": "2023-01-01T14:00:00Z"
+}`,
+			wantAudit: "ElevationApprove",
+		},
+		{
+			name:     "deny",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/deny",
+			username: "approver",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(pendingDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := pendingDoc()
+				doc.Portal.Elevation.State = api.ElevationStateDenied
+				doc.Portal.Elevation.Reviewer = "approver"
+				doc.Portal.Elevation.ReviewedAt = &reviewedAt
+				c.AddPortalDocuments(doc)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+    "id": "03030303-0303-0303-0303-030303030001",
+    "username": "requester",
+    "reason": "ICM 1234",
+    "duration": "2h0m0s",
+    "state": "Denied",
+    "requestedAt": "2023-01-01T11:50:00Z",
+    "reviewer": "approver",
+    "reviewedAt": "2023-01-01T12:00:00Z"
+}`,
+			wantAudit: "ElevationDeny",
+		},
+		{
+			name:     "approve, not elevated",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/approve",
+			username: "approver",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(pendingDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPortalDocuments(pendingDoc())
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Forbidden\n",
+		},
+		{
+			name:     "approve, own request",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/approve",
+			username: "requester",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(pendingDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPortalDocuments(pendingDoc())
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Elevation requests must be reviewed by a second SRE.\n",
+		},
+		{
+			name:     "approve, expired",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/approve",
+			username: "approver",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				doc := pendingDoc()
+				doc.Portal.Elevation.RequestedAt = &longAgo
+				f.AddPortalDocuments(doc)
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := pendingDoc()
+				doc.Portal.Elevation.RequestedAt = &longAgo
+				c.AddPortalDocuments(doc)
+			},
+			wantStatusCode: http.StatusConflict,
+			wantBody:       "The elevation request is no longer pending.\n",
+		},
+		{
+			name:     "approve, not an elevation",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + otherID + "/approve",
+			username: "approver",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(&api.PortalDocument{
+					ID: otherID,
+					Portal: &api.Portal{
+						Username:   "requester",
+						ID:         resourceID,
+						Kubeconfig: &api.Kubeconfig{},
+					},
+				})
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPortalDocuments(&api.PortalDocument{
+					ID: otherID,
+					Portal: &api.Portal{
+						Username:   "requester",
+						ID:         resourceID,
+						Kubeconfig: &api.Kubeconfig{},
+					},
+				})
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "Not Found\n",
+		},
+		{
+			name:           "approve, not found",
+			method:         http.MethodPost,
+			path:           apiPath + "/" + id + "/approve",
+			username:       "approver",
+			elevated:       true,
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "Not Found\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			_env := mock_env.NewMockCore(controller)
+			_env.EXPECT().Environment().AnyTimes().Return(&azureclient.PublicCloud)
+			_env.EXPECT().Hostname().AnyTimes().Return("testhost")
+			_env.EXPECT().Location().AnyTimes().Return("eastus")
+
+			dbPortal, portalClient := testdatabase.NewFakePortal()
+
+			fixture := testdatabase.NewFixture().WithPortal(dbPortal)
+			if tt.fixture != nil {
+				tt.fixture(fixture)
+			}
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checker := testdatabase.NewChecker()
+			if tt.checker != nil {
+				tt.checker(checker)
+			}
+
+			auditHook, auditLog := testlog.NewAudit()
+
+			p := &portal{
+				env:              _env,
+				audit:            auditLog,
+				log:              logrus.NewEntry(logrus.StandardLogger()),
+				elevatedGroupIDs: elevatedGroupIDs,
+				dbPortal:         dbPortal,
+				now:              func() time.Time { return now },
+			}
+
+			groups := nonElevatedGroupIDs
+			if tt.elevated {
+				groups = elevatedGroupIDs
+			}
+
+			ctx := context.WithValue(context.Background(), middleware.ContextKeyUsername, tt.username)
+			ctx = context.WithValue(ctx, middleware.ContextKeyGroups, groups)
+
+			req, err := http.NewRequestWithContext(ctx, tt.method, tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			router := mux.NewRouter()
+			p.aadAuthenticatedRoutes(router, nil, nil, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Error(w.Code)
+			}
+
+			b, err := io.ReadAll(w.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != tt.wantBody {
+				t.Error(string(b))
+			}
+
+			if tt.checker != nil {
+				for _, err := range checker.CheckPortals(portalClient) {
+					t.Error(err)
+				}
+			}
+
+			var operations []string
+			for _, entry := range auditHook.AllEntries() {
+				operations = append(operations, fmt.Sprint(entry.Data[audit.MetadataPayload]))
+			}
+
+			if tt.wantAudit == "" && len(operations) > 0 ||
+				tt.wantAudit != "" && (len(operations) != 1 || !strings.Contains(operations[0], `"OperationName":"`+tt.wantAudit+`"`)) {
+				t.Error(operations)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+const (
+	// fleetCacheTTL is how long the cluster list behind the fleet endpoints
+	// is reused before Cosmos DB is read again
+	fleetCacheTTL = time.Minute
+
+	fleetDefaultCreatingMinutes = 60
+
+	fleetUnknown = "Unknown"
+)
+
+// FleetSummary counts the clusters in the region by various properties
+type FleetSummary struct {
+	ListedAt           time.Time      `json:"listedAt"`
+	Total              int            `json:"total"`
+	Versions           map[string]int `json:"versions"`
+	ProvisioningStates map[string]int `json:"provisioningStates"`
+	MaintenanceStates  map[string]int `json:"maintenanceStates"`
+	OperatorVersions   map[string]int `json:"operatorVersions"`
+}
+
+// FleetCluster is the fleet overview of a single cluster
+type FleetCluster struct {
+	ResourceID              string     `json:"resourceId"`
+	Version                 string     `json:"version"`
+	ProvisioningState       string     `json:"provisioningState"`
+	FailedProvisioningState string     `json:"failedProvisioningState,omitempty"`
+	MaintenanceState        string     `json:"maintenanceState"`
+	OperatorVersion         string     `json:"operatorVersion"`
+	LastAdminUpdateError    string     `json:"lastAdminUpdateError,omitempty"`
+	CreatedAt               *time.Time `json:"createdAt,omitempty"`
+}
+
+// fleetCache holds the fleet overview of every cluster, listed at most once
+// per fleetCacheTTL however many SREs are looking at the dashboard
+type fleetCache struct {
+	mu       sync.Mutex
+	clusters []*FleetCluster
+	listedAt time.Time
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return fleetUnknown
+	}
+	return s
+}
+
+func fleetClusterView(oc *api.OpenShiftCluster) *FleetCluster {
+	c := &FleetCluster{
+		ResourceID:              oc.ID,
+		Version:                 orUnknown(oc.Properties.ClusterProfile.Version),
+		ProvisioningState:       oc.Properties.ProvisioningState.String(),
+		FailedProvisioningState: oc.Properties.FailedProvisioningState.String(),
+		MaintenanceState:        orUnknown(string(oc.Properties.MaintenanceState)),
+		OperatorVersion:         orUnknown(oc.Properties.OperatorVersion),
+		LastAdminUpdateError:    oc.Properties.LastAdminUpdateError,
+	}
+
+	if !oc.Properties.CreatedAt.IsZero() {
+		createdAt := oc.Properties.CreatedAt.UTC()
+		c.CreatedAt = &createdAt
+	}
+
+	return c
+}
+
+// fleetClusters returns the fleet overview of every cluster, from the cache if
+// it is fresh enough
+func (p *portal) fleetClusters(ctx context.Context) ([]*FleetCluster, time.Time, error) {
+	p.fleet.mu.Lock()
+	defer p.fleet.mu.Unlock()
+
+	if p.fleet.clusters != nil && p.now().Sub(p.fleet.listedAt) < fleetCacheTTL {
+		return p.fleet.clusters, p.fleet.listedAt, nil
+	}
+
+	docs, err := p.dbOpenShiftClusters.ListAll(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	clusters := make([]*FleetCluster, 0, len(docs.OpenShiftClusterDocuments))
+	for _, doc := range docs.OpenShiftClusterDocuments {
+		if doc.OpenShiftCluster == nil {
+			continue
+		}
+
+		clusters = append(clusters, fleetClusterView(doc.OpenShiftCluster))
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool { return strings.Compare(clusters[i].ResourceID, clusters[j].ResourceID) < 0 })
+
+	p.fleet.clusters = clusters
+	p.fleet.listedAt = p.now().UTC()
+
+	return p.fleet.clusters, p.fleet.listedAt, nil
+}
+
+// fleetSummary counts the clusters in the region by version, provisioning
+// state, maintenance state and operator version
+func (p *portal) fleetSummary(w http.ResponseWriter, r *http.Request) {
+	clusters, listedAt, err := p.fleetClusters(r.Context())
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	summary := &FleetSummary{
+		ListedAt:           listedAt,
+		Total:              len(clusters),
+		Versions:           map[string]int{},
+		ProvisioningStates: map[string]int{},
+		MaintenanceStates:  map[string]int{},
+		OperatorVersions:   map[string]int{},
+	}
+
+	for _, c := range clusters {
+		summary.Versions[c.Version]++
+		summary.ProvisioningStates[c.ProvisioningState]++
+		summary.MaintenanceStates[c.MaintenanceState]++
+		summary.OperatorVersions[c.OperatorVersion]++
+	}
+
+	p.writeJSON(w, summary)
+}
+
+// fleetAdminUpdateErrors lists the clusters whose last admin update failed
+func (p *portal) fleetAdminUpdateErrors(w http.ResponseWriter, r *http.Request) {
+	clusters, _, err := p.fleetClusters(r.Context())
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	result := []*FleetCluster{}
+	for _, c := range clusters {
+		if c.LastAdminUpdateError != "" {
+			result = append(result, c)
+		}
+	}
+
+	p.writeJSON(w, result)
+}
+
+// fleetCreating lists the clusters which have been Creating for longer than
+// the minutes query parameter (by default an hour), oldest first
+func (p *portal) fleetCreating(w http.ResponseWriter, r *http.Request) {
+	minutes := fleetDefaultCreatingMinutes
+	if v := r.URL.Query().Get("minutes"); v != "" {
+		var err error
+		minutes, err = strconv.Atoi(v)
+		if err != nil || minutes < 0 {
+			http.Error(w, fmt.Sprintf("invalid minutes %q", v), http.StatusBadRequest)
+			return
+		}
+	}
+
+	clusters, _, err := p.fleetClusters(r.Context())
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	threshold := p.now().Add(-time.Duration(minutes) * time.Minute)
+
+	result := []*FleetCluster{}
+	for _, c := range clusters {
+		if c.ProvisioningState == api.ProvisioningStateCreating.String() &&
+			c.CreatedAt != nil && c.CreatedAt.Before(threshold) {
+			result = append(result, c)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].CreatedAt.Before(*result[j].CreatedAt) })
+
+	p.writeJSON(w, result)
+}
@@ -0,0 +1,276 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestFleet(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	longAgo := now.Add(-2 * time.Hour)
+	recently := now.Add(-10 * time.Minute)
+
+	resourceID := func(name string) string {
+		return "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/" + name
+	}
+
+	doc := func(name string, properties api.OpenShiftClusterProperties) *api.OpenShiftClusterDocument {
+		return &api.OpenShiftClusterDocument{
+			ID:  name,
+			Key: resourceID(name),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID:         resourceID(name),
+				Properties: properties,
+			},
+		}
+	}
+
+	docs := []*api.OpenShiftClusterDocument{
+		doc("succeeded", api.OpenShiftClusterProperties{
+			ProvisioningState: api.ProvisioningStateSucceeded,
+			ClusterProfile:    api.ClusterProfile{Version: "4.12.25"},
+			MaintenanceState:  api.MaintenanceStateNone,
+			OperatorVersion:   "abc",
+			CreatedAt:         longAgo,
+		}),
+		doc("failedupdate", api.OpenShiftClusterProperties{
+			ProvisioningState:    api.ProvisioningStateSucceeded,
+			ClusterProfile:       api.ClusterProfile{Version: "4.12.25"},
+			MaintenanceState:     api.MaintenanceStateUnplanned,
+			OperatorVersion:      "abc",
+			LastAdminUpdateError: "it broke",
+			CreatedAt:            longAgo,
+		}),
+		doc("stuck", api.OpenShiftClusterProperties{
+			ProvisioningState: api.ProvisioningStateCreating,
+			ClusterProfile:    api.ClusterProfile{Version: "4.13.1"},
+			CreatedAt:         longAgo,
+		}),
+		doc("creating", api.OpenShiftClusterProperties{
+			ProvisioningState: api.ProvisioningStateCreating,
+			ClusterProfile:    api.ClusterProfile{Version: "4.13.1"},
+			CreatedAt:         recently,
+		}),
+	}
+
+	for _, tt := range []struct {
+		name           string
+		path           string
+		wantStatusCode int
+		wantResponse   interface{}
+	}{
+		{
+			name:           "summary",
+			path:           "/api/fleet/summary",
+			wantStatusCode: http.StatusOK,
+			wantResponse: &FleetSummary{
+				ListedAt: now,
+				Total:    4,
+				Versions: map[string]int{
+					"4.12.25": 2,
+					"4.13.1":  2,
+				},
+				ProvisioningStates: map[string]int{
+					"Succeeded": 2,
+					"Creating":  2,
+				},
+				MaintenanceStates: map[string]int{
+					"None":      1,
+					"Unplanned": 1,
+					"Unknown":   2,
+				},
+				OperatorVersions: map[string]int{
+					"abc":     2,
+					"Unknown": 2,
+				},
+			},
+		},
+		{
+			name:           "admin update errors",
+			path:           "/api/fleet/adminupdateerrors",
+			wantStatusCode: http.StatusOK,
+			wantResponse: []*FleetCluster{
+				{
+					ResourceID:           resourceID("failedupdate"),
+					Version:              "4.12.25",
+					ProvisioningState:    "Succeeded",
+					MaintenanceState:     "Unplanned",
+					OperatorVersion:      "abc",
+					LastAdminUpdateError: "it broke",
+					CreatedAt:            &longAgo,
+				},
+			},
+		},
+		{
+			name:           "creating, default",
+			path:           "/api/fleet/creating",
+			wantStatusCode: http.StatusOK,
+			wantResponse: []*FleetCluster{
+				{
+					ResourceID:        resourceID("stuck"),
+					Version:           "4.13.1",
+					ProvisioningState: "Creating",
+					MaintenanceState:  "Unknown",
+					OperatorVersion:   "Unknown",
+					CreatedAt:         &longAgo,
+				},
+			},
+		},
+		{
+			name:           "creating, 5 minutes",
+			path:           "/api/fleet/creating?minutes=5",
+			wantStatusCode: http.StatusOK,
+			wantResponse: []*FleetCluster{
+				{
+					ResourceID:        resourceID("stuck"),
+					Version:           "4.13.1",
+					ProvisioningState: "Creating",
+					MaintenanceState:  "Unknown",
+					OperatorVersion:   "Unknown",
+					CreatedAt:         &longAgo,
+				},
+				{
+					ResourceID:        resourceID("creating"),
+					Version:           "4.13.1",
+					ProvisioningState: "Creating",
+					MaintenanceState:  "Unknown",
+					OperatorVersion:   "Unknown",
+					CreatedAt:         &recently,
+				},
+			},
+		},
+		{
+			name:           "creating, invalid minutes",
+			path:           "/api/fleet/creating?minutes=x",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+
+			fixture := testdatabase.NewFixture().WithOpenShiftClusters(dbOpenShiftClusters)
+			fixture.AddOpenShiftClusterDocuments(docs...)
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := &portal{
+				log:                 logrus.NewEntry(logrus.StandardLogger()),
+				dbOpenShiftClusters: dbOpenShiftClusters,
+				now:                 func() time.Time { return now },
+			}
+
+			req, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			router := mux.NewRouter()
+			p.aadAuthenticatedRoutes(router, nil, nil, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatal(w.Code)
+			}
+
+			if tt.wantResponse == nil {
+				return
+			}
+
+			b, err := io.ReadAll(w.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantBody, err := json.MarshalIndent(tt.wantResponse, "", "    ")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, l := range deep.Equal(string(b), string(wantBody)) {
+				t.Error(l)
+			}
+		})
+	}
+}
+
+func TestFleetCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	dbOpenShiftClusters, openShiftClustersClient := testdatabase.NewFakeOpenShiftClusters()
+
+	doc := func(name string) *api.OpenShiftClusterDocument {
+		return &api.OpenShiftClusterDocument{
+			ID:  name,
+			Key: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/" + name,
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/" + name,
+			},
+		}
+	}
+
+	_, err := openShiftClustersClient.Create(ctx, doc("a").Key, doc("a"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &portal{
+		dbOpenShiftClusters: dbOpenShiftClusters,
+		now:                 func() time.Time { return now },
+	}
+
+	clusters, _, err := p.fleetClusters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 {
+		t.Fatal(len(clusters))
+	}
+
+	_, err = openShiftClustersClient.Create(ctx, doc("b").Key, doc("b"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// within the TTL, the cached list is returned
+	now = now.Add(fleetCacheTTL / 2)
+	clusters, _, err = p.fleetClusters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 {
+		t.Error(len(clusters))
+	}
+
+	// after the TTL, the list is refreshed
+	now = now.Add(fleetCacheTTL)
+	clusters, listedAt, err := p.fleetClusters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 2 {
+		t.Error(len(clusters))
+	}
+	if !listedAt.Equal(now) {
+		t.Error(listedAt)
+	}
+}
@@ -0,0 +1,244 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/portal/ssh"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
+)
+
+const (
+	grantTypeKubeconfig = "kubeconfig"
+	grantTypeSSH        = "ssh"
+
+	operationGrantRevoke = "GrantRevoke"
+)
+
+// Grant is the portal view of an issued kubeconfig or SSH password.  The
+// document ID is the credential itself, so a grant is identified by its hash.
+type Grant struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	Username    string     `json:"username"`
+	ResourceID  string     `json:"resourceId"`
+	Elevated    bool       `json:"elevated,omitempty"`
+	Scope       string     `json:"scope,omitempty"`
+	Hostname    string     `json:"hostname,omitempty"`
+	ElevationID string     `json:"elevationId,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+	RevokedBy   string     `json:"revokedBy,omitempty"`
+}
+
+// grantID returns the public identifier of the grant stored in the portal
+// document with the given ID
+func grantID(docID string) string {
+	h := sha256.Sum256([]byte(docID))
+	return hex.EncodeToString(h[:])
+}
+
+// grantView returns the portal view of doc, or nil if doc does not grant
+// access
+func grantView(doc *api.PortalDocument) *Grant {
+	g := &Grant{
+		ID:          grantID(doc.ID),
+		Username:    doc.Portal.Username,
+		ResourceID:  doc.Portal.ID,
+		ElevationID: doc.Portal.ElevationID,
+		RevokedAt:   doc.Portal.RevokedAt,
+		RevokedBy:   doc.Portal.RevokedBy,
+	}
+
+	switch {
+	case doc.Portal.Kubeconfig != nil:
+		g.Type = grantTypeKubeconfig
+		g.Elevated = doc.Portal.Kubeconfig.Elevated
+		if doc.Portal.Kubeconfig.Scope != nil {
+			g.Scope = string(doc.Portal.Kubeconfig.Scope.Type)
+		}
+	case doc.Portal.SSH != nil:
+		g.Type = grantTypeSSH
+		g.Elevated = true
+		g.Hostname = ssh.Hostname(doc.Portal.SSH)
+	default:
+		return nil
+	}
+
+	// Cosmos DB expires documents TTL seconds after their last modification
+	if doc.Timestamp != 0 && doc.TTL > 0 {
+		expiresAt := time.Unix(int64(doc.Timestamp+doc.TTL), 0).UTC()
+		g.ExpiresAt = &expiresAt
+	}
+
+	return g
+}
+
+// grantViews returns the portal views of the grants in docs, latest expiry
+// first.  If username is not empty, only the grants of that user are returned.
+func grantViews(docs *api.PortalDocuments, username string) []*Grant {
+	grants := []*Grant{}
+	for _, doc := range docs.PortalDocuments {
+		if username != "" && doc.Portal.Username != username {
+			continue
+		}
+
+		if g := grantView(doc); g != nil {
+			grants = append(grants, g)
+		}
+	}
+
+	sort.SliceStable(grants, func(i, j int) bool {
+		return grants[i].ExpiresAt != nil && grants[j].ExpiresAt != nil &&
+			grants[i].ExpiresAt.After(*grants[j].ExpiresAt)
+	})
+
+	return grants
+}
+
+// clusterGrants lists the kubeconfigs and SSH passwords issued for a cluster.
+// Users who are not elevated only see their own grants.
+func (p *portal) clusterGrants(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.badRequest(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	docs, err := p.dbPortal.ListByResourceID(ctx, resourceID)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	var username string
+	if !p.isElevated(r) {
+		username = ctx.Value(middleware.ContextKeyUsername).(string)
+	}
+
+	p.writeJSON(w, grantViews(docs, username))
+}
+
+// userGrants lists the kubeconfigs and SSH passwords issued to a user across
+// all clusters: by default the user making the request.  Only elevated users
+// may list the grants of another user.
+func (p *portal) userGrants(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		username = ctx.Value(middleware.ContextKeyUsername).(string)
+	}
+
+	if username != ctx.Value(middleware.ContextKeyUsername).(string) && !p.requireElevated(w, r) {
+		return
+	}
+
+	docs, err := p.dbPortal.ListByUsername(ctx, username)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	p.writeJSON(w, grantViews(docs, username))
+}
+
+// revokeGrant revokes a kubeconfig or SSH password before it expires.  Users
+// may revoke their own grants; elevated users may revoke anyone's.  The proxies
+// check for revocation on every request and SSH channel.
+func (p *portal) revokeGrant(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.badRequest(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	docs, err := p.dbPortal.ListByResourceID(ctx, resourceID)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	var docID string
+	for _, doc := range docs.PortalDocuments {
+		if grantView(doc) != nil && grantID(doc.ID) == apiVars["grant"] {
+			docID = doc.ID
+			break
+		}
+	}
+	if docID == "" {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	username := ctx.Value(middleware.ContextKeyUsername).(string)
+	elevated := p.isElevated(r)
+
+	var revokeErr string
+	var revokeStatus int
+
+	doc, err := p.dbPortal.Patch(ctx, docID, func(doc *api.PortalDocument) error {
+		revokeErr, revokeStatus = "", 0
+
+		switch {
+		case doc.Portal.Username != username && !elevated:
+			revokeErr, revokeStatus = http.StatusText(http.StatusForbidden), http.StatusForbidden
+		case doc.Portal.RevokedAt != nil:
+			revokeErr, revokeStatus = "The grant has already been revoked.", http.StatusConflict
+		}
+		if revokeErr != "" {
+			return errors.New(revokeErr)
+		}
+
+		now := p.now().UTC()
+		doc.Portal.RevokedAt = &now
+		doc.Portal.RevokedBy = username
+
+		return nil
+	})
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		revokeErr, revokeStatus = http.StatusText(http.StatusNotFound), http.StatusNotFound
+	}
+	if revokeErr != "" {
+		http.Error(w, revokeErr, revokeStatus)
+		return
+	}
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	g := grantView(doc)
+
+	middleware.Audit(p.audit, p.env, r, operationGrantRevoke, g.ID, audit.TargetResource{
+		TargetResourceName: resourceID,
+		TargetResourceType: "grant",
+	}, fmt.Sprintf("revoked %s grant of %s", g.Type, doc.Portal.Username))
+
+	p.writeJSON(w, g)
+}
+
+// grantsPage serves the page which lists and revokes the grants of a cluster
+func (p *portal) grantsPage(w http.ResponseWriter, r *http.Request) {
+	p.serve("grants/index.html")(w, r)
+}
@@ -0,0 +1,345 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestGrants(t *testing.T) {
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/resourcename"
+	apiPath := "/api/00000000-0000-0000-0000-000000000000/resourcegroup/resourcename/grants"
+
+	// grant IDs are the SHA-256 of the document IDs
+	kubeconfigGrantID := "0c061affd1aa174d5436d963349492c6a1e7c30ec900b2b6b4781a53668abfd9"
+	sshGrantID := "232e5e02f58ff8a20a8f3dc10b78ca01718545ae0bf5901cd3778968df8a4927"
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	kubeconfigDoc := func() *api.PortalDocument {
+		return &api.PortalDocument{
+			ID:        "03030303-0303-0303-0303-030303030001",
+			TTL:       21600,
+			Timestamp: int(now.Unix()),
+			Portal: &api.Portal{
+				Username: "user",
+				ID:       resourceID,
+				Kubeconfig: &api.Kubeconfig{
+					Elevated: true,
+				},
+			},
+		}
+	}
+
+	sshDoc := func() *api.PortalDocument {
+		return &api.PortalDocument{
+			ID:        "03030303-0303-0303-0303-030303030002",
+			TTL:       3600,
+			Timestamp: int(now.Unix()),
+			Portal: &api.Portal{
+				Username: "other",
+				ID:       resourceID,
+				SSH: &api.SSH{
+					Master:        1,
+					Authenticated: true,
+				},
+			},
+		}
+	}
+
+	elevationDoc := func() *api.PortalDocument {
+		return &api.PortalDocument{
+			ID: "03030303-0303-0303-0303-030303030003",
+			Portal: &api.Portal{
+				Username: "user",
+				ID:       resourceID,
+				Elevation: &api.Elevation{
+					State: api.ElevationStatePending,
+				},
+			},
+		}
+	}
+
+	fixture := func(f *testdatabase.Fixture) {
+		f.AddPortalDocuments(kubeconfigDoc(), sshDoc(), elevationDoc())
+	}
+
+	kubeconfigGrant := `{
+        "id": "` + kubeconfigGrantID + `",
+        "type": "kubeconfig",
+        "username": "user",
+        "resourceId": "` + resourceID + `",
+        "elevated": true,
+        "expiresAt": "2023-01-01T18:00:00Z"
+    }`
+
+	sshGrant := `{
+        "id": "` + sshGrantID + `",
+        "type": "ssh",
+        "username": "other",
+        "resourceId": "` + resourceID + `",
+        "elevated": true,
+        "hostname": "master-1",
+        "expiresAt": "2023-01-01T13:00:00Z"
+    }`
+
+	for _, tt := range []struct {
+		name           string
+		method         string
+		path           string
+		username       string
+		elevated       bool
+		fixture        func(*testdatabase.Fixture)
+		checker        func(*testdatabase.Checker)
+		wantStatusCode int
+		wantBody       string
+		wantAudit      string
+	}{
+		{
+			name:           "list cluster, elevated",
+			method:         http.MethodGet,
+			path:           apiPath,
+			username:       "user",
+			elevated:       true,
+			fixture:        fixture,
+			wantStatusCode: http.StatusOK,
+			wantBody:       "[\n    " + kubeconfigGrant + ",\n    " + sshGrant + "\n]",
+		},
+		{
+			name:           "list cluster, not elevated",
+			method:         http.MethodGet,
+			path:           apiPath,
+			username:       "user",
+			fixture:        fixture,
+			wantStatusCode: http.StatusOK,
+			wantBody:       "[\n    " + kubeconfigGrant + "\n]",
+		},
+		{
+			name:           "list own",
+			method:         http.MethodGet,
+			path:           "/api/grants",
+			username:       "other",
+			fixture:        fixture,
+			wantStatusCode: http.StatusOK,
+			wantBody:       "[\n    " + sshGrant + "\n]",
+		},
+		{
+			name:           "list other user, elevated",
+			method:         http.MethodGet,
+			path:           "/api/grants?username=other",
+			username:       "user",
+			elevated:       true,
+			fixture:        fixture,
+			wantStatusCode: http.StatusOK,
+			wantBody:       "[\n    " + sshGrant + "\n]",
+		},
+		{
+			name:           "list other user, not elevated",
+			method:         http.MethodGet,
+			path:           "/api/grants?username=other",
+			username:       "user",
+			fixture:        fixture,
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Forbidden\n",
+		},
+		{
+			name:     "revoke own",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + kubeconfigGrantID + "/revoke",
+			username: "user",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(kubeconfigDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := kubeconfigDoc()
+				doc.Portal.RevokedAt = &now
+				doc.Portal.RevokedBy = "user"
+				c.AddPortalDocuments(doc)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+    "id": "` + kubeconfigGrantID + `",
+    "type": "kubeconfig",
+    "username": "user",
+    "resourceId": "` + resourceID + `",
+    "elevated": true,
+    "expiresAt": "2023-01-01T18:00:00Z",
+    "revokedAt": "2023-01-01T12:00:00Z",
+    "revokedBy": "user"
+}`,
+			wantAudit: "GrantRevoke",
+		},
+		{
+			name:     "revoke other user, elevated",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + sshGrantID + "/revoke",
+			username: "user",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(sshDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := sshDoc()
+				doc.Portal.RevokedAt = &now
+				doc.Portal.RevokedBy = "user"
+				c.AddPortalDocuments(doc)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+    "id": "` + sshGrantID + `",
+    "type": "ssh",
+    "username": "other",
+    "resourceId": "` + resourceID + `",
+    "elevated": true,
+    "hostname": "master-1",
+    "expiresAt": "2023-01-01T13:00:00Z",
+    "revokedAt": "2023-01-01T12:00:00Z",
+    "revokedBy": "user"
+}`,
+			wantAudit: "GrantRevoke",
+		},
+		{
+			name:     "revoke other user, not elevated",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + sshGrantID + "/revoke",
+			username: "user",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(sshDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPortalDocuments(sshDoc())
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Forbidden\n",
+		},
+		{
+			name:     "revoke, already revoked",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + kubeconfigGrantID + "/revoke",
+			username: "user",
+			fixture: func(f *testdatabase.Fixture) {
+				doc := kubeconfigDoc()
+				doc.Portal.RevokedAt = &now
+				doc.Portal.RevokedBy = "admin"
+				f.AddPortalDocuments(doc)
+			},
+			wantStatusCode: http.StatusConflict,
+			wantBody:       "The grant has already been revoked.\n",
+		},
+		{
+			name:           "revoke, not a grant",
+			method:         http.MethodPost,
+			path:           apiPath + "/f8f6158e0e28dece5e2c89df38faee46382e236056028801cbac490e6c3308d6/revoke",
+			username:       "user",
+			elevated:       true,
+			fixture:        fixture,
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "Not Found\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			_env := mock_env.NewMockCore(controller)
+			_env.EXPECT().Environment().AnyTimes().Return(&azureclient.PublicCloud)
+			_env.EXPECT().Hostname().AnyTimes().Return("testhost")
+			_env.EXPECT().Location().AnyTimes().Return("eastus")
+
+			dbPortal, portalClient := testdatabase.NewFakePortal()
+
+			fixture := testdatabase.NewFixture().WithPortal(dbPortal)
+			if tt.fixture != nil {
+				tt.fixture(fixture)
+			}
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checker := testdatabase.NewChecker()
+			if tt.checker != nil {
+				tt.checker(checker)
+			}
+
+			auditHook, auditLog := testlog.NewAudit()
+
+			p := &portal{
+				env:              _env,
+				audit:            auditLog,
+				log:              logrus.NewEntry(logrus.StandardLogger()),
+				elevatedGroupIDs: elevatedGroupIDs,
+				dbPortal:         dbPortal,
+				now:              func() time.Time { return now },
+			}
+
+			groups := nonElevatedGroupIDs
+			if tt.elevated {
+				groups = elevatedGroupIDs
+			}
+
+			ctx := context.WithValue(context.Background(), middleware.ContextKeyUsername, tt.username)
+			ctx = context.WithValue(ctx, middleware.ContextKeyGroups, groups)
+
+			req, err := http.NewRequestWithContext(ctx, tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			router := mux.NewRouter()
+			p.aadAuthenticatedRoutes(router, nil, nil, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Error(w.Code)
+			}
+
+			b, err := io.ReadAll(w.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != tt.wantBody {
+				t.Error(string(b))
+			}
+
+			if tt.checker != nil {
+				for _, err := range checker.CheckPortals(portalClient) {
+					t.Error(err)
+				}
+			}
+
+			var operations []string
+			for _, entry := range auditHook.AllEntries() {
+				operations = append(operations, fmt.Sprint(entry.Data[audit.MetadataPayload]))
+			}
+
+			if tt.wantAudit == "" && len(operations) > 0 ||
+				tt.wantAudit != "" && (len(operations) != 1 || !strings.Contains(operations[0], `"OperationName":"`+tt.wantAudit+`"`)) {
+				t.Error(operations)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/api/validate"
 	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/portal/elevation"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/portal/util/clientcache"
 	"github.com/Azure/ARO-RP/pkg/proxy"
@@ -47,6 +48,8 @@ type Kubeconfig struct {
 	Env         env.Core
 
 	ReverseProxy *httputil.ReverseProxy
+
+	now func() time.Time
 }
 
 func New(baseLog *logrus.Entry,
@@ -73,6 +76,8 @@ func New(baseLog *logrus.Entry,
 		dialer:      dialer,
 		clientCache: clientcache.New(time.Hour),
 		Env:         env,
+
+		now: time.Now,
 	}
 
 	k.ReverseProxy = &httputil.ReverseProxy{
@@ -85,7 +90,13 @@ func New(baseLog *logrus.Entry,
 }
 
 // New creates a New PortalDocument allowing kubeconfig access to a cluster for
-// 6 hours and returns a kubeconfig with the temporary credentials
+// 6 hours and returns a kubeconfig with the temporary credentials.  If the user
+// is not in an elevated group but has an approved just-in-time elevation for
+// the cluster, the kubeconfig is elevated and lasts until the elevation ends.
+//
+// If the scope query parameter is set ("readonly" or "logs"), the kubeconfig
+// is never elevated and the proxy only forwards the requests the scope allows,
+// optionally limited to the namespaces given in the namespace parameter.
 func (k *Kubeconfig) New(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -95,36 +106,81 @@ func (k *Kubeconfig) New(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	elevated := len(middleware.GroupsIntersect(k.elevatedGroupIDs, ctx.Value(middleware.ContextKeyGroups).([]string))) > 0
+	scope, err := parseScope(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	username := ctx.Value(middleware.ContextKeyUsername).(string)
+	elevated := scope == nil && len(middleware.GroupsIntersect(k.elevatedGroupIDs, ctx.Value(middleware.ContextKeyGroups).([]string))) > 0
+	timeout := kubeconfigNewTimeout
+
+	var elevationDoc *api.PortalDocument
+	if !elevated && scope == nil {
+		var err error
+		elevationDoc, err = elevation.Active(ctx, k.DbPortal, username, resourceID, k.now())
+		if err != nil {
+			k.internalServerError(w, err)
+			return
+		}
+
+		if elevationDoc != nil {
+			elevated = true
+			timeout = elevationDoc.Portal.Elevation.ExpiresAt.Sub(k.now())
+			if timeout < time.Second {
+				timeout = time.Second
+			}
+		}
+	}
 
 	token := k.DbPortal.NewUUID()
 	portalDoc := &api.PortalDocument{
 		ID:  token,
-		TTL: int(kubeconfigNewTimeout / time.Second),
+		TTL: int(timeout / time.Second),
 		Portal: &api.Portal{
-			Username: ctx.Value(middleware.ContextKeyUsername).(string),
+			Username: username,
 			ID:       resourceID,
 			Kubeconfig: &api.Kubeconfig{
 				Elevated: elevated,
+				Scope:    scope,
 			},
 		},
 	}
+	if elevationDoc != nil {
+		portalDoc.Portal.ElevationID = elevationDoc.ID
+	}
 
-	_, err := k.DbPortal.Create(ctx, portalDoc)
+	_, err = k.DbPortal.Create(ctx, portalDoc)
 	if err != nil {
 		k.internalServerError(w, err)
 		return
 	}
 
-	b, err := k.makeKubeconfig("https://"+r.Host+resourceID+"/kubeconfig/proxy", token)
+	if elevationDoc != nil {
+		elevation.Audit(k.Audit, k.Env, r, elevation.OperationUse, resourceID, elevationDoc.ID,
+			fmt.Sprintf("issued elevated kubeconfig for %s", timeout.Truncate(time.Second)))
+	}
+
+	namespace := "default"
+	if scope != nil && len(scope.Namespaces) > 0 {
+		namespace = scope.Namespaces[0]
+	}
+
+	b, err := k.makeKubeconfig("https://"+r.Host+resourceID+"/kubeconfig/proxy", token, namespace)
 	if err != nil {
 		k.internalServerError(w, err)
 		return
 	}
 
 	filename := strings.Split(r.URL.Path, "/")[8]
-	if elevated {
+	switch {
+	case elevated:
 		filename += "-elevated"
+	case scope != nil && scope.Type == api.KubeconfigScopeTypeReadOnly:
+		filename += "-readonly"
+	case scope != nil && scope.Type == api.KubeconfigScopeTypeLogsOnly:
+		filename += "-logs"
 	}
 
 	w.Header().Add("Content-Type", "application/json")
@@ -137,7 +193,7 @@ func (k *Kubeconfig) internalServerError(w http.ResponseWriter, err error) {
 	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 }
 
-func (k *Kubeconfig) makeKubeconfig(server, token string) ([]byte, error) {
+func (k *Kubeconfig) makeKubeconfig(server, token, namespace string) ([]byte, error) {
 	return json.MarshalIndent(&clientcmdv1.Config{
 		APIVersion: "v1",
 		Kind:       "Config",
@@ -166,7 +222,7 @@ func (k *Kubeconfig) makeKubeconfig(server, token string) ([]byte, error) {
 				Name: "context",
 				Context: clientcmdv1.Context{
 					Cluster:   "cluster",
-					Namespace: "default",
+					Namespace: namespace,
 					AuthInfo:  "user",
 				},
 			},
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -33,6 +34,26 @@ func TestNew(t *testing.T) {
 
 	servingCert := &x509.Certificate{}
 
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(2 * time.Hour)
+	expiredAt := now.Add(-time.Minute)
+	elevationDoc := func(expiresAt *time.Time) *api.PortalDocument {
+		return &api.PortalDocument{
+			ID: "03030303-0303-0303-0303-030303030000",
+			Portal: &api.Portal{
+				Username: username,
+				ID:       resourceID,
+				Elevation: &api.Elevation{
+					Reason:    "ICM 1234",
+					Duration:  7200,
+					State:     api.ElevationStateApproved,
+					Reviewer:  "approver",
+					ExpiresAt: expiresAt,
+				},
+			},
+		}
+	}
+
 	for _, tt := range []struct {
 		name           string
 		r              func(*http.Request)
@@ -41,6 +62,7 @@ func TestNew(t *testing.T) {
 		wantStatusCode int
 		wantHeaders    http.Header
 		wantBody       string
+		wantAudit      bool
 	}{
 		{
 			name: "success - not elevated",
@@ -85,6 +107,87 @@ func TestNew(t *testing.T) {
 			},
 			wantBody: "{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"default\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}",
 		},
+		{
+			name: "success - just-in-time elevation",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				fixture.AddPortalDocuments(elevationDoc(&expiresAt))
+				checker.AddPortalDocuments(elevationDoc(&expiresAt), &api.PortalDocument{
+					ID:  password,
+					TTL: 7200,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							Elevated: true,
+						},
+						ElevationID: "03030303-0303-0303-0303-030303030000",
+					},
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeaders: http.Header{
+				"Content-Disposition": []string{`attachment; filename="cluster-elevated.kubeconfig"`},
+			},
+			wantBody:  "{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"default\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}",
+			wantAudit: true,
+		},
+		{
+			name: "success - just-in-time elevation expired",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				fixture.AddPortalDocuments(elevationDoc(&expiredAt))
+				checker.AddPortalDocuments(elevationDoc(&expiredAt), &api.PortalDocument{
+					ID:  password,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username:   username,
+						ID:         resourceID,
+						Kubeconfig: &api.Kubeconfig{},
+					},
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeaders: http.Header{
+				"Content-Disposition": []string{`attachment; filename="cluster.kubeconfig"`},
+			},
+			wantBody: "{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"default\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}",
+		},
+		{
+			name: "success - scoped",
+			r: func(r *http.Request) {
+				r.URL.RawQuery = "scope=readonly&namespace=openshift-etcd,openshift-apiserver"
+			},
+			elevated: true,
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalDocument := &api.PortalDocument{
+					ID:  password,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							Scope: &api.KubeconfigScope{
+								Type:       api.KubeconfigScopeTypeReadOnly,
+								Namespaces: []string{"openshift-etcd", "openshift-apiserver"},
+							},
+						},
+					},
+				}
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeaders: http.Header{
+				"Content-Disposition": []string{`attachment; filename="cluster-readonly.kubeconfig"`},
+			},
+			wantBody: "{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"openshift-etcd\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}",
+		},
+		{
+			name: "bad scope",
+			r: func(r *http.Request) {
+				r.URL.RawQuery = "scope=admin"
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "invalid scope \"admin\"\n",
+		},
 		{
 			name: "bad path",
 			r: func(r *http.Request) {
@@ -145,10 +248,11 @@ func TestNew(t *testing.T) {
 
 			aadAuthenticatedRouter := &mux.Router{}
 
-			_, audit := testlog.NewAudit()
+			auditHook, audit := testlog.NewAudit()
 			_, baseLog := testlog.New()
 			_, baseAccessLog := testlog.New()
 			k := New(baseLog, audit, _env, baseAccessLog, servingCert, elevatedGroupIDs, nil, dbPortal, nil)
+			k.now = func() time.Time { return now }
 
 			if tt.r != nil {
 				tt.r(r)
@@ -194,6 +298,10 @@ func TestNew(t *testing.T) {
 			if string(b) != tt.wantBody {
 				t.Errorf("%q", string(b))
 			}
+
+			if tt.wantAudit != (len(auditHook.AllEntries()) == 1) {
+				t.Error(auditHook.AllEntries())
+			}
 		})
 	}
 }
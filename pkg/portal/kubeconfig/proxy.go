@@ -46,7 +46,8 @@ func (k *Kubeconfig) director(r *http.Request) {
 	ctx := r.Context()
 
 	portalDoc, _ := ctx.Value(middleware.ContextKeyPortalDoc).(*api.PortalDocument)
-	if portalDoc == nil || portalDoc.Portal.Kubeconfig == nil {
+	if portalDoc == nil || portalDoc.Portal.Kubeconfig == nil ||
+		portalDoc.Portal.RevokedAt != nil {
 		k.error(r, http.StatusForbidden, nil)
 		return
 	}
@@ -58,6 +59,14 @@ func (k *Kubeconfig) director(r *http.Request) {
 		return
 	}
 
+	path := "/" + strings.Join(strings.Split(r.URL.Path, "/")[11:], "/")
+
+	err := authorize(portalDoc.Portal.Kubeconfig.Scope, r.Method, path)
+	if err != nil {
+		k.error(r, http.StatusForbidden, err)
+		return
+	}
+
 	key := struct {
 		resourceID string
 		elevated   bool
@@ -81,7 +90,7 @@ func (k *Kubeconfig) director(r *http.Request) {
 	r.RequestURI = ""
 	r.URL.Scheme = "https"
 	r.URL.Host = "kubernetes:6443"
-	r.URL.Path = "/" + strings.Join(strings.Split(r.URL.Path, "/")[11:], "/")
+	r.URL.Path = path
 	r.Header.Del("Authorization")
 	r.Host = r.URL.Host
 
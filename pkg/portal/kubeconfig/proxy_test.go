@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -214,6 +215,74 @@ func TestProxy(t *testing.T) {
 			wantStatusCode: http.StatusOK,
 			wantBody:       "GET /test HTTP/1.1\r\nHost: kubernetes:6443\r\nAccept-Encoding: gzip\r\nUser-Agent: testua\r\nX-Authenticated-Name: system:aro-sre\r\n\r\n",
 		},
+		{
+			name: "success - scoped",
+			r: func(r *http.Request) {
+				r.URL.Path = resourceID + "/kubeconfig/proxy/version"
+			},
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, openShiftClustersClient *cosmosdb.FakeOpenShiftClusterDocumentClient, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalDocument := &api.PortalDocument{
+					ID:  token,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							Scope: &api.KubeconfigScope{
+								Type: api.KubeconfigScopeTypeReadOnly,
+							},
+						},
+					},
+				}
+				fixture.AddPortalDocuments(portalDocument)
+				checker.AddPortalDocuments(portalDocument)
+				openShiftClusterDocument := &api.OpenShiftClusterDocument{
+					ID:  resourceID,
+					Key: resourceID,
+					OpenShiftCluster: &api.OpenShiftCluster{
+						Properties: api.OpenShiftClusterProperties{
+							NetworkProfile: api.NetworkProfile{
+								APIServerPrivateEndpointIP: apiServerPrivateEndpointIP,
+							},
+							AROServiceKubeconfig: api.SecureBytes(serviceKubeconfig),
+							AROSREKubeconfig:     api.SecureBytes(sreKubeconfig),
+						},
+					},
+				}
+				fixture.AddOpenShiftClusterDocuments(openShiftClusterDocument)
+				checker.AddOpenShiftClusterDocuments(openShiftClusterDocument)
+			},
+			mocks: func(dialer *mock_proxy.MockDialer) {
+				dialer.EXPECT().DialContext(gomock.Any(), "tcp", apiServerPrivateEndpointIP+":6443").Return(l.DialContext(ctx, "", ""))
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "GET /version HTTP/1.1\r\nHost: kubernetes:6443\r\nAccept-Encoding: gzip\r\nUser-Agent: testua\r\nX-Authenticated-Name: system:aro-sre\r\n\r\n",
+		},
+		{
+			name: "scoped, not allowed",
+			r: func(r *http.Request) {
+				r.URL.Path = resourceID + "/kubeconfig/proxy/api/v1/namespaces/default/secrets"
+			},
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, openShiftClustersClient *cosmosdb.FakeOpenShiftClusterDocumentClient, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalDocument := &api.PortalDocument{
+					ID:  token,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							Scope: &api.KubeconfigScope{
+								Type: api.KubeconfigScopeTypeReadOnly,
+							},
+						},
+					},
+				}
+				fixture.AddPortalDocuments(portalDocument)
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Forbidden\n",
+		},
 		{
 			name: "no auth",
 			r: func(r *http.Request) {
@@ -252,6 +321,29 @@ func TestProxy(t *testing.T) {
 			wantStatusCode: http.StatusForbidden,
 			wantBody:       "Forbidden\n",
 		},
+		{
+			name: "revoked",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, openShiftClustersClient *cosmosdb.FakeOpenShiftClusterDocumentClient, portalClient *cosmosdb.FakePortalDocumentClient) {
+				revokedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+				portalDocument := &api.PortalDocument{
+					ID:  token,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							Elevated: true,
+						},
+						RevokedAt: &revokedAt,
+						RevokedBy: "admin",
+					},
+				}
+				fixture.AddPortalDocuments(portalDocument)
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Forbidden\n",
+		},
 		{
 			name: "bad path",
 			r: func(r *http.Request) {
@@ -0,0 +1,212 @@
+package kubeconfig
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+const (
+	scopeMaxNamespaces = 20
+)
+
+// scopes maps the values of the scope query parameter of New to scope types
+var scopes = map[string]api.KubeconfigScopeType{
+	"readonly": api.KubeconfigScopeTypeReadOnly,
+	"logs":     api.KubeconfigScopeTypeLogsOnly,
+}
+
+// readOnlyDeniedSubresources can be reached with GET requests but are not
+// read-only
+var readOnlyDeniedSubresources = map[string]bool{
+	"attach":      true,
+	"exec":        true,
+	"portforward": true,
+	"proxy":       true,
+}
+
+// readableNonResourcePaths are the top-level non-resource paths which scoped
+// kubeconfigs may read besides API discovery.  Others, e.g. /logs or
+// /debug/pprof, expose node or API server internals and are denied.
+var readableNonResourcePaths = map[string]bool{
+	"openapi": true,
+	"version": true,
+}
+
+// parseScope returns the scope requested by the query parameters of a
+// kubeconfig/new request, or nil if none was requested
+func parseScope(query url.Values) (*api.KubeconfigScope, error) {
+	if query.Get("scope") == "" {
+		if len(query["namespace"]) > 0 {
+			return nil, fmt.Errorf("namespace requires scope")
+		}
+		return nil, nil
+	}
+
+	t, ok := scopes[query.Get("scope")]
+	if !ok {
+		return nil, fmt.Errorf("invalid scope %q", query.Get("scope"))
+	}
+
+	scope := &api.KubeconfigScope{
+		Type: t,
+	}
+
+	seen := map[string]bool{}
+	for _, v := range query["namespace"] {
+		for _, namespace := range strings.Split(v, ",") {
+			namespace = strings.TrimSpace(namespace)
+			if namespace == "" || seen[namespace] {
+				continue
+			}
+
+			if len(validation.IsDNS1123Label(namespace)) > 0 {
+				return nil, fmt.Errorf("invalid namespace %q", namespace)
+			}
+
+			seen[namespace] = true
+			scope.Namespaces = append(scope.Namespaces, namespace)
+		}
+	}
+
+	if len(scope.Namespaces) > scopeMaxNamespaces {
+		return nil, fmt.Errorf("at most %d namespaces may be given", scopeMaxNamespaces)
+	}
+
+	return scope, nil
+}
+
+// apiRequest describes a request to the Kubernetes API server
+type apiRequest struct {
+	// resource is empty for non-resource requests, e.g. discovery, /version
+	// or /healthz
+	resource    string
+	subresource string
+	namespace   string
+}
+
+// parseAPIRequest parses the path of a request to the Kubernetes API server,
+// e.g. /api/v1/namespaces/default/pods/name/log,
+// /apis/apps/v1/namespaces/default/deployments or
+// /api/v1/watch/namespaces/default/secrets
+func parseAPIRequest(p string) (*apiRequest, error) {
+	if path.Clean(p) != p {
+		return nil, fmt.Errorf("invalid path %q", p)
+	}
+
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+
+	switch {
+	case parts[0] == "api" && len(parts) > 2:
+		parts = parts[2:]
+	case parts[0] == "apis" && len(parts) > 3:
+		parts = parts[3:]
+	default:
+		return &apiRequest{}, nil
+	}
+
+	r := &apiRequest{}
+
+	// the API server still serves the deprecated watch paths, which address
+	// the same resources as the paths without the watch segment
+	if parts[0] == "watch" && len(parts) > 1 {
+		parts = parts[1:]
+	}
+
+	if parts[0] == "namespaces" && len(parts) > 1 {
+		r.namespace = parts[1]
+
+		// /api/v1/namespaces/{name} is the namespace object itself
+		if len(parts) == 2 {
+			r.resource = "namespaces"
+			return r, nil
+		}
+
+		parts = parts[2:]
+	}
+
+	r.resource = parts[0]
+	if len(parts) > 2 {
+		r.subresource = parts[2]
+	}
+
+	return r, nil
+}
+
+// authorize returns nil if scope allows a request with the given method to the
+// given Kubernetes API server path
+func authorize(scope *api.KubeconfigScope, method, p string) error {
+	if scope == nil {
+		return nil
+	}
+
+	if method != http.MethodGet && method != http.MethodHead {
+		return fmt.Errorf("method %s is not allowed by scope %s", method, scope.Type)
+	}
+
+	r, err := parseAPIRequest(p)
+	if err != nil {
+		return err
+	}
+
+	if r.resource == "" {
+		if !isReadableNonResourcePath(p) {
+			return fmt.Errorf("%s is not allowed by scope %s", p, scope.Type)
+		}
+		return nil
+	}
+
+	switch scope.Type {
+	case api.KubeconfigScopeTypeReadOnly:
+		// secrets can also be read through subresources, e.g.
+		// imagestreams/{name}/secrets
+		if r.resource == "secrets" || r.subresource == "secrets" || readOnlyDeniedSubresources[r.subresource] {
+			return fmt.Errorf("%s is not allowed by scope %s", p, scope.Type)
+		}
+
+	case api.KubeconfigScopeTypeLogsOnly:
+		if r.resource != "pods" || (r.subresource != "" && r.subresource != "log") {
+			return fmt.Errorf("%s is not allowed by scope %s", p, scope.Type)
+		}
+
+	default:
+		return fmt.Errorf("unknown scope %s", scope.Type)
+	}
+
+	if len(scope.Namespaces) > 0 {
+		for _, namespace := range scope.Namespaces {
+			if r.namespace == namespace {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%s is not in an allowed namespace", p)
+	}
+
+	return nil
+}
+
+// isReadableNonResourcePath returns true if p is an API discovery path, e.g.
+// /api, /apis/apps or /apis/apps/v1, or is under one of
+// readableNonResourcePaths
+func isReadableNonResourcePath(p string) bool {
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+
+	switch parts[0] {
+	case "api":
+		return len(parts) <= 2
+	case "apis":
+		return len(parts) <= 3
+	default:
+		return readableNonResourcePaths[parts[0]]
+	}
+}
@@ -0,0 +1,267 @@
+package kubeconfig
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestParseScope(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		query     string
+		wantScope *api.KubeconfigScope
+		wantErr   string
+	}{
+		{
+			name: "no scope",
+		},
+		{
+			name:  "read-only",
+			query: "scope=readonly",
+			wantScope: &api.KubeconfigScope{
+				Type: api.KubeconfigScopeTypeReadOnly,
+			},
+		},
+		{
+			name:  "logs, namespaces",
+			query: "scope=logs&namespace=a,b&namespace=c&namespace=a",
+			wantScope: &api.KubeconfigScope{
+				Type:       api.KubeconfigScopeTypeLogsOnly,
+				Namespaces: []string{"a", "b", "c"},
+			},
+		},
+		{
+			name:    "invalid scope",
+			query:   "scope=admin",
+			wantErr: `invalid scope "admin"`,
+		},
+		{
+			name:    "invalid namespace",
+			query:   "scope=readonly&namespace=Bad_Namespace",
+			wantErr: `invalid namespace "Bad_Namespace"`,
+		},
+		{
+			name:    "namespace without scope",
+			query:   "namespace=a",
+			wantErr: "namespace requires scope",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			scope, err := parseScope(query)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if !reflect.DeepEqual(scope, tt.wantScope) {
+				t.Error(scope)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	readOnly := &api.KubeconfigScope{
+		Type: api.KubeconfigScopeTypeReadOnly,
+	}
+	logsOnly := &api.KubeconfigScope{
+		Type: api.KubeconfigScopeTypeLogsOnly,
+	}
+	readOnlyNamespaced := &api.KubeconfigScope{
+		Type:       api.KubeconfigScopeTypeReadOnly,
+		Namespaces: []string{"openshift-etcd"},
+	}
+
+	for _, tt := range []struct {
+		name    string
+		scope   *api.KubeconfigScope
+		method  string
+		path    string
+		wantErr string
+	}{
+		{
+			name:   "unscoped",
+			method: http.MethodDelete,
+			path:   "/api/v1/namespaces/default/pods/name",
+		},
+		{
+			name:   "read-only, discovery",
+			scope:  readOnly,
+			method: http.MethodGet,
+			path:   "/apis/apps/v1",
+		},
+		{
+			name:   "read-only, get",
+			scope:  readOnly,
+			method: http.MethodGet,
+			path:   "/apis/apps/v1/namespaces/default/deployments/name",
+		},
+		{
+			name:   "read-only, list nodes",
+			scope:  readOnly,
+			method: http.MethodGet,
+			path:   "/api/v1/nodes",
+		},
+		{
+			name:    "read-only, delete",
+			scope:   readOnly,
+			method:  http.MethodDelete,
+			path:    "/api/v1/namespaces/default/pods/name",
+			wantErr: "method DELETE is not allowed by scope ReadOnly",
+		},
+		{
+			name:    "read-only, secrets",
+			scope:   readOnly,
+			method:  http.MethodGet,
+			path:    "/api/v1/namespaces/default/secrets",
+			wantErr: "/api/v1/namespaces/default/secrets is not allowed by scope ReadOnly",
+		},
+		{
+			name:    "read-only, watch secrets",
+			scope:   readOnly,
+			method:  http.MethodGet,
+			path:    "/api/v1/watch/secrets",
+			wantErr: "/api/v1/watch/secrets is not allowed by scope ReadOnly",
+		},
+		{
+			name:    "read-only, watch namespaced secrets",
+			scope:   readOnly,
+			method:  http.MethodGet,
+			path:    "/api/v1/watch/namespaces/kube-system/secrets",
+			wantErr: "/api/v1/watch/namespaces/kube-system/secrets is not allowed by scope ReadOnly",
+		},
+		{
+			name:   "read-only, watch deployments",
+			scope:  readOnly,
+			method: http.MethodGet,
+			path:   "/apis/apps/v1/watch/deployments",
+		},
+		{
+			name:    "read-only namespaced, watch deployments in all namespaces",
+			scope:   readOnlyNamespaced,
+			method:  http.MethodGet,
+			path:    "/apis/apps/v1/watch/deployments",
+			wantErr: "/apis/apps/v1/watch/deployments is not in an allowed namespace",
+		},
+		{
+			name:   "read-only namespaced, watch deployments in allowed namespace",
+			scope:  readOnlyNamespaced,
+			method: http.MethodGet,
+			path:   "/apis/apps/v1/watch/namespaces/openshift-etcd/deployments",
+		},
+		{
+			name:    "read-only, secrets subresource",
+			scope:   readOnly,
+			method:  http.MethodGet,
+			path:    "/apis/image.openshift.io/v1/namespaces/default/imagestreams/name/secrets",
+			wantErr: "/apis/image.openshift.io/v1/namespaces/default/imagestreams/name/secrets is not allowed by scope ReadOnly",
+		},
+		{
+			name:   "read-only, api discovery",
+			scope:  readOnly,
+			method: http.MethodGet,
+			path:   "/api",
+		},
+		{
+			name:   "read-only, version",
+			scope:  readOnly,
+			method: http.MethodGet,
+			path:   "/version",
+		},
+		{
+			name:   "read-only, openapi",
+			scope:  readOnly,
+			method: http.MethodGet,
+			path:   "/openapi/v3/apis/apps/v1",
+		},
+		{
+			name:    "read-only, node logs",
+			scope:   readOnly,
+			method:  http.MethodGet,
+			path:    "/logs/kube-apiserver/audit.log",
+			wantErr: "/logs/kube-apiserver/audit.log is not allowed by scope ReadOnly",
+		},
+		{
+			name:    "read-only, pprof",
+			scope:   readOnly,
+			method:  http.MethodGet,
+			path:    "/debug/pprof/heap",
+			wantErr: "/debug/pprof/heap is not allowed by scope ReadOnly",
+		},
+		{
+			name:    "logs-only, metrics",
+			scope:   logsOnly,
+			method:  http.MethodGet,
+			path:    "/metrics",
+			wantErr: "/metrics is not allowed by scope LogsOnly",
+		},
+		{
+			name:    "read-only, exec",
+			scope:   readOnly,
+			method:  http.MethodGet,
+			path:    "/api/v1/namespaces/default/pods/name/exec",
+			wantErr: "/api/v1/namespaces/default/pods/name/exec is not allowed by scope ReadOnly",
+		},
+		{
+			name:    "unclean path",
+			scope:   readOnly,
+			method:  http.MethodGet,
+			path:    "/api/v1/namespaces/default/../kube-system/secrets",
+			wantErr: `invalid path "/api/v1/namespaces/default/../kube-system/secrets"`,
+		},
+		{
+			name:   "logs-only, log",
+			scope:  logsOnly,
+			method: http.MethodGet,
+			path:   "/api/v1/namespaces/default/pods/name/log",
+		},
+		{
+			name:    "logs-only, configmaps",
+			scope:   logsOnly,
+			method:  http.MethodGet,
+			path:    "/api/v1/namespaces/default/configmaps",
+			wantErr: "/api/v1/namespaces/default/configmaps is not allowed by scope LogsOnly",
+		},
+		{
+			name:   "namespaced, allowed namespace",
+			scope:  readOnlyNamespaced,
+			method: http.MethodGet,
+			path:   "/api/v1/namespaces/openshift-etcd/pods",
+		},
+		{
+			name:   "namespaced, namespace object",
+			scope:  readOnlyNamespaced,
+			method: http.MethodGet,
+			path:   "/api/v1/namespaces/openshift-etcd",
+		},
+		{
+			name:    "namespaced, other namespace",
+			scope:   readOnlyNamespaced,
+			method:  http.MethodGet,
+			path:    "/api/v1/namespaces/default/pods",
+			wantErr: "/api/v1/namespaces/default/pods is not in an allowed namespace",
+		},
+		{
+			name:    "namespaced, cluster-scoped",
+			scope:   readOnlyNamespaced,
+			method:  http.MethodGet,
+			path:    "/api/v1/pods",
+			wantErr: "/api/v1/pods is not in an allowed namespace",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorize(tt.scope, tt.method, tt.path)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+		})
+	}
+}
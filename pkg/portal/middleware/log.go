@@ -30,6 +30,12 @@ func (w *logResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return hijacker.Hijack()
 }
 
+func (w *logResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 func (w *logResponseWriter) Write(b []byte) (int, error) {
 	n, err := w.ResponseWriter.Write(b)
 	w.bytes += n
@@ -130,6 +136,40 @@ func Log(env env.Core, auditLog, baseLog *logrus.Entry) func(http.Handler) http.
 	}
 }
 
+// Audit writes an event other than the HTTP request itself, for example the
+// grant or revocation of access, to the audit log on behalf of the user making
+// the request r
+func Audit(auditLog *logrus.Entry, env env.Core, r *http.Request, operation, requestID string, target audit.TargetResource, description string) {
+	username, _ := r.Context().Value(ContextKeyUsername).(string)
+
+	auditLog.WithFields(logrus.Fields{
+		audit.MetadataAdminOperation:  true,
+		audit.MetadataCreatedTime:     time.Now().UTC().Format(time.RFC3339),
+		audit.MetadataLogKind:         audit.IFXAuditLogKind,
+		audit.MetadataSource:          audit.SourceAdminPortal,
+		audit.EnvKeyAppID:             audit.SourceAdminPortal,
+		audit.EnvKeyCloudRole:         audit.CloudRoleRP,
+		audit.EnvKeyEnvironment:       env.Environment().Name,
+		audit.EnvKeyHostname:          env.Hostname(),
+		audit.EnvKeyLocation:          env.Location(),
+		audit.PayloadKeyCategory:      audit.CategoryAuthorization,
+		audit.PayloadKeyOperationName: operation,
+		audit.PayloadKeyRequestID:     requestID,
+		audit.PayloadKeyCallerIdentities: []audit.CallerIdentity{
+			{
+				CallerIdentityType:  audit.CallerIdentityTypeUsername,
+				CallerIdentityValue: username,
+				CallerIPAddress:     r.RemoteAddr,
+			},
+		},
+		audit.PayloadKeyTargetResources: []audit.TargetResource{target},
+		audit.PayloadKeyResult: audit.Result{
+			ResultType:        audit.ResultTypeSuccess,
+			ResultDescription: description,
+		},
+	}).Info(audit.DefaultLogMessage)
+}
+
 func auditTargetResourceType(r *http.Request) string {
 	if matches := utillog.RXTolerantSubResourceID.FindStringSubmatch(r.URL.Path); matches != nil {
 		return matches[len(matches)-1]
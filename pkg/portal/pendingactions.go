@@ -0,0 +1,203 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const (
+	// pendingActionRunWindow is how long an approved admin action may be run
+	// for after it was approved
+	pendingActionRunWindow = time.Hour
+
+	operationPendingActionApprove = "PendingActionApprove"
+	operationPendingActionReject  = "PendingActionReject"
+
+	pendingActionStateExpired = "Expired"
+)
+
+// PendingAction is the portal view of a destructive admin action held for
+// approval
+type PendingAction struct {
+	ID          string     `json:"id"`
+	Action      string     `json:"action"`
+	Method      string     `json:"method"`
+	Path        string     `json:"path"`
+	RawQuery    string     `json:"rawQuery,omitempty"`
+	Body        string     `json:"body,omitempty"`
+	State       string     `json:"state"`
+	RequestedBy string     `json:"requestedBy"`
+	RequestedAt *time.Time `json:"requestedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	Reviewer    string     `json:"reviewer,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewedAt,omitempty"`
+	StatusCode  int        `json:"statusCode,omitempty"`
+}
+
+func (p *portal) pendingActionView(doc *api.PendingActionDocument) *PendingAction {
+	a := doc.PendingAction
+
+	state := string(a.State)
+	if (a.State == api.PendingActionStatePending || a.State == api.PendingActionStateApproved) &&
+		a.ExpiresAt != nil && !p.now().Before(*a.ExpiresAt) {
+		state = pendingActionStateExpired
+	}
+
+	return &PendingAction{
+		ID:          doc.ID,
+		Action:      a.Action,
+		Method:      a.Method,
+		Path:        a.Path,
+		RawQuery:    a.RawQuery,
+		Body:        string(a.Body),
+		State:       state,
+		RequestedBy: a.RequestedBy,
+		RequestedAt: a.RequestedAt,
+		ExpiresAt:   a.ExpiresAt,
+		Reviewer:    a.Reviewer,
+		ReviewedAt:  a.ReviewedAt,
+		StatusCode:  a.StatusCode,
+	}
+}
+
+// pendingActions lists the admin actions held for approval on a cluster, most
+// recent first
+func (p *portal) pendingActions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.badRequest(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	docs, err := p.dbPendingActions.ListByKey(ctx, resourceID)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	actions := []*PendingAction{}
+	for _, doc := range docs.PendingActionDocuments {
+		actions = append(actions, p.pendingActionView(doc))
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].RequestedAt != nil && actions[j].RequestedAt != nil &&
+			actions[i].RequestedAt.After(*actions[j].RequestedAt)
+	})
+
+	p.writeJSON(w, actions)
+}
+
+// approvePendingAction approves an admin action held for approval, after which
+// it can be run through the admin API
+func (p *portal) approvePendingAction(w http.ResponseWriter, r *http.Request) {
+	p.reviewPendingAction(w, r, api.PendingActionStateApproved)
+}
+
+// rejectPendingAction rejects an admin action held for approval
+func (p *portal) rejectPendingAction(w http.ResponseWriter, r *http.Request) {
+	p.reviewPendingAction(w, r, api.PendingActionStateRejected)
+}
+
+// reviewPendingAction moves a pending admin action to state.  Only an elevated
+// user may review an action, and the user, whose identity is verified by AAD,
+// may not approve an action requested under their own name.
+func (p *portal) reviewPendingAction(w http.ResponseWriter, r *http.Request, state api.PendingActionState) {
+	ctx := r.Context()
+
+	if !p.requireElevated(w, r) {
+		return
+	}
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.badRequest(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	id, err := uuid.FromString(apiVars["pendingAction"])
+	if err != nil {
+		p.badRequest(w, err)
+		return
+	}
+
+	username := ctx.Value(middleware.ContextKeyUsername).(string)
+
+	var reviewErr string
+	var reviewStatus int
+
+	doc, err := p.dbPendingActions.Patch(ctx, resourceID, id.String(), func(doc *api.PendingActionDocument) error {
+		reviewErr, reviewStatus = "", 0
+
+		switch {
+		case state == api.PendingActionStateApproved && strings.EqualFold(doc.PendingAction.RequestedBy, username):
+			reviewErr, reviewStatus = "Pending actions must be approved by a second SRE.", http.StatusForbidden
+		case p.pendingActionView(doc).State != string(api.PendingActionStatePending):
+			reviewErr, reviewStatus = "The action is no longer pending.", http.StatusConflict
+		}
+		if reviewErr != "" {
+			return errors.New(reviewErr)
+		}
+
+		now := p.now().UTC()
+		doc.PendingAction.State = state
+		doc.PendingAction.Reviewer = username
+		doc.PendingAction.ReviewedAt = &now
+
+		if state == api.PendingActionStateApproved {
+			expiresAt := now.Add(pendingActionRunWindow)
+			doc.PendingAction.ExpiresAt = &expiresAt
+		}
+
+		return nil
+	})
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		reviewErr, reviewStatus = http.StatusText(http.StatusNotFound), http.StatusNotFound
+	}
+	if reviewErr != "" {
+		http.Error(w, reviewErr, reviewStatus)
+		return
+	}
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	operation := operationPendingActionReject
+	if state == api.PendingActionStateApproved {
+		operation = operationPendingActionApprove
+	}
+
+	middleware.Audit(p.audit, p.env, r, operation, doc.ID, audit.TargetResource{
+		TargetResourceName: resourceID + "/pendingactions/" + doc.ID,
+		TargetResourceType: "pendingaction",
+	}, fmt.Sprintf("%s %s requested by %s", state, doc.PendingAction.Action, doc.PendingAction.RequestedBy))
+
+	p.writeJSON(w, p.pendingActionView(doc))
+}
+
+// pendingActionsPage serves the page which lists and reviews the admin actions
+// held for approval on a cluster
+func (p *portal) pendingActionsPage(w http.ResponseWriter, r *http.Request) {
+	p.serve("pendingactions/index.html")(w, r)
+}
@@ -0,0 +1,316 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestPendingActions(t *testing.T) {
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/resourcename"
+	apiPath := "/api/00000000-0000-0000-0000-000000000000/resourcegroup/resourcename/pendingactions"
+	id := "07070707-0707-0707-0707-070707070001"
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	requestedAt := now.Add(-10 * time.Minute)
+	expiresAt := now.Add(4 * time.Hour)
+	expired := now.Add(-time.Minute)
+	runExpiresAt := now.Add(time.Hour)
+
+	pendingDoc := func() *api.PendingActionDocument {
+		return &api.PendingActionDocument{
+			ID:  id,
+			Key: resourceID,
+			PendingAction: &api.PendingAction{
+				Action:      "stopvm",
+				Method:      http.MethodPost,
+				Path:        "/admin" + resourceID + "/stopvm",
+				RawQuery:    "vmName=master-0",
+				State:       api.PendingActionStatePending,
+				RequestedBy: "requester@example.com",
+				RequestedAt: &requestedAt,
+				ExpiresAt:   &expiresAt,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		username       string
+		elevated       bool
+		fixture        func(*testdatabase.Fixture)
+		checker        func(*testdatabase.Checker)
+		wantStatusCode int
+		wantBody       string
+		wantAudit      string
+	}{
+		{
+			name:     "list",
+			method:   http.MethodGet,
+			path:     apiPath,
+			username: "approver@example.com",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPendingActionDocuments(pendingDoc())
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `[
+    {
+        "id": "07070707-0707-0707-0707-070707070001",
+        "action": "stopvm",
+        "method": "POST",
+        "path": "/admin/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/stopvm",
+        "rawQuery": "vmName=master-0",
+        "state": "Pending",
+        "requestedBy": "requester@example.com",
+        "requestedAt": "2023-01-01T11:50:00Z",
+        "expiresAt": "2023-01-01T16:00:00Z"
+    }
+]`,
+		},
+		{
+			name:     "approve",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/approve",
+			username: "approver@example.com",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPendingActionDocuments(pendingDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := pendingDoc()
+				doc.PendingAction.State = api.PendingActionStateApproved
+				doc.PendingAction.Reviewer = "approver@example.com"
+				doc.PendingAction.ReviewedAt = &now
+				doc.PendingAction.ExpiresAt = &runExpiresAt
+				c.AddPendingActionDocuments(doc)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+    "id": "07070707-0707-0707-0707-070707070001",
+    "action": "stopvm",
+    "method": "POST",
+    "path": "/admin/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/stopvm",
+    "rawQuery": "vmName=master-0",
+    "state": "Approved",
+    "requestedBy": "requester@example.com",
+    "requestedAt": "2023-01-01T11:50:00Z",
+    "expiresAt": "2023-01-01T13:00:00Z",
+    "reviewer": "approver@example.com",
+    "reviewedAt": "2023-01-01T12:00:00Z"
+}`,
+			wantAudit: operationPendingActionApprove,
+		},
+		{
+			name:     "reject",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/reject",
+			username: "requester@example.com",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPendingActionDocuments(pendingDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := pendingDoc()
+				doc.PendingAction.State = api.PendingActionStateRejected
+				doc.PendingAction.Reviewer = "requester@example.com"
+				doc.PendingAction.ReviewedAt = &now
+				c.AddPendingActionDocuments(doc)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+    "id": "07070707-0707-0707-0707-070707070001",
+    "action": "stopvm",
+    "method": "POST",
+    "path": "/admin/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/stopvm",
+    "rawQuery": "vmName=master-0",
+    "state": "Rejected",
+    "requestedBy": "requester@example.com",
+    "requestedAt": "2023-01-01T11:50:00Z",
+    "expiresAt": "2023-01-01T16:00:00Z",
+    "reviewer": "requester@example.com",
+    "reviewedAt": "2023-01-01T12:00:00Z"
+}`,
+			wantAudit: operationPendingActionReject,
+		},
+		{
+			name:     "approve, not elevated",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/approve",
+			username: "approver@example.com",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPendingActionDocuments(pendingDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPendingActionDocuments(pendingDoc())
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Forbidden\n",
+		},
+		{
+			name:     "approve, own request",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/approve",
+			username: "Requester@example.com",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPendingActionDocuments(pendingDoc())
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddPendingActionDocuments(pendingDoc())
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Pending actions must be approved by a second SRE.\n",
+		},
+		{
+			name:     "approve, expired",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/approve",
+			username: "approver@example.com",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				doc := pendingDoc()
+				doc.PendingAction.ExpiresAt = &expired
+				f.AddPendingActionDocuments(doc)
+			},
+			checker: func(c *testdatabase.Checker) {
+				doc := pendingDoc()
+				doc.PendingAction.ExpiresAt = &expired
+				c.AddPendingActionDocuments(doc)
+			},
+			wantStatusCode: http.StatusConflict,
+			wantBody:       "The action is no longer pending.\n",
+		},
+		{
+			name:     "approve, already approved",
+			method:   http.MethodPost,
+			path:     apiPath + "/" + id + "/approve",
+			username: "approver@example.com",
+			elevated: true,
+			fixture: func(f *testdatabase.Fixture) {
+				doc := pendingDoc()
+				doc.PendingAction.State = api.PendingActionStateApproved
+				f.AddPendingActionDocuments(doc)
+			},
+			wantStatusCode: http.StatusConflict,
+			wantBody:       "The action is no longer pending.\n",
+		},
+		{
+			name:           "approve, not found",
+			method:         http.MethodPost,
+			path:           apiPath + "/" + id + "/approve",
+			username:       "approver@example.com",
+			elevated:       true,
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "Not Found\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			_env := mock_env.NewMockCore(controller)
+			_env.EXPECT().Environment().AnyTimes().Return(&azureclient.PublicCloud)
+			_env.EXPECT().Hostname().AnyTimes().Return("testhost")
+			_env.EXPECT().Location().AnyTimes().Return("eastus")
+
+			dbPendingActions, pendingActionsClient := testdatabase.NewFakePendingActions()
+
+			fixture := testdatabase.NewFixture().WithPendingActions(dbPendingActions)
+			if tt.fixture != nil {
+				tt.fixture(fixture)
+			}
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checker := testdatabase.NewChecker()
+			if tt.checker != nil {
+				tt.checker(checker)
+			}
+
+			auditHook, auditLog := testlog.NewAudit()
+
+			p := &portal{
+				env:              _env,
+				audit:            auditLog,
+				log:              logrus.NewEntry(logrus.StandardLogger()),
+				elevatedGroupIDs: elevatedGroupIDs,
+				dbPendingActions: dbPendingActions,
+				now:              func() time.Time { return now },
+			}
+
+			groups := nonElevatedGroupIDs
+			if tt.elevated {
+				groups = elevatedGroupIDs
+			}
+
+			ctx := context.WithValue(context.Background(), middleware.ContextKeyUsername, tt.username)
+			ctx = context.WithValue(ctx, middleware.ContextKeyGroups, groups)
+
+			req, err := http.NewRequestWithContext(ctx, tt.method, tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			router := mux.NewRouter()
+			p.aadAuthenticatedRoutes(router, nil, nil, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Error(w.Code)
+			}
+
+			b, err := io.ReadAll(w.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != tt.wantBody {
+				t.Error(string(b))
+			}
+
+			if tt.checker != nil {
+				for _, err := range checker.CheckPendingActions(pendingActionsClient) {
+					t.Error(err)
+				}
+			}
+
+			var operations []string
+			for _, entry := range auditHook.AllEntries() {
+				operations = append(operations, fmt.Sprint(entry.Data[audit.MetadataPayload]))
+			}
+
+			if tt.wantAudit == "" && len(operations) > 0 ||
+				tt.wantAudit != "" && (len(operations) != 1 || !strings.Contains(operations[0], `"OperationName":"`+tt.wantAudit+`"`)) {
+				t.Error(operations)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io/fs"
@@ -35,6 +36,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/portal/prometheus"
 	"github.com/Azure/ARO-RP/pkg/portal/ssh"
 	"github.com/Azure/ARO-RP/pkg/proxy"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
 	"github.com/Azure/ARO-RP/pkg/util/heartbeat"
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
 )
@@ -66,9 +68,15 @@ type portal struct {
 
 	dbPortal            database.Portal
 	dbOpenShiftClusters database.OpenShiftClusters
+	dbClusterAlerts     database.ClusterAlerts
+	dbAdminAuditLog     database.AdminAuditLog
+	dbPendingActions    database.PendingActions
 
 	dialer proxy.Dialer
 
+	aead              encryption.AEAD
+	sshRecordingStore ssh.RecordingStore
+
 	templateV1         *template.Template
 	templateV2         *template.Template
 	templatePrometheus *template.Template
@@ -76,6 +84,10 @@ type portal struct {
 	aad middleware.AAD
 
 	m metrics.Emitter
+
+	fleet fleetCache
+
+	now func() time.Time
 }
 
 func NewPortal(env env.Core,
@@ -97,7 +109,12 @@ func NewPortal(env env.Core,
 	elevatedGroupIDs []string,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbPortal database.Portal,
+	dbClusterAlerts database.ClusterAlerts,
+	dbAdminAuditLog database.AdminAuditLog,
+	dbPendingActions database.PendingActions,
 	dialer proxy.Dialer,
+	aead encryption.AEAD,
+	sshRecordingStore ssh.RecordingStore,
 	m metrics.Emitter,
 ) Runnable {
 	return &portal{
@@ -123,10 +140,18 @@ func NewPortal(env env.Core,
 
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		dbPortal:            dbPortal,
+		dbClusterAlerts:     dbClusterAlerts,
+		dbAdminAuditLog:     dbAdminAuditLog,
+		dbPendingActions:    dbPendingActions,
 
 		dialer: dialer,
 
+		aead:              aead,
+		sshRecordingStore: sshRecordingStore,
+
 		m: m,
+
+		now: time.Now,
 	}
 }
 
@@ -188,7 +213,7 @@ func (p *portal) setupRouter(kconfig *kubeconfig.Kubeconfig, prom *prometheus.Pr
 }
 
 func (p *portal) setupServices() (*kubeconfig.Kubeconfig, *prometheus.Prometheus, *ssh.SSH, error) {
-	ssh, err := ssh.New(p.env, p.log, p.baseAccessLog, p.sshl, p.sshKey, p.elevatedGroupIDs, p.dbOpenShiftClusters, p.dbPortal, p.dialer)
+	ssh, err := ssh.New(p.env, p.log, p.audit, p.baseAccessLog, p.sshl, p.sshKey, p.elevatedGroupIDs, p.dbOpenShiftClusters, p.dbPortal, p.dialer, p.aead, p.sshRecordingStore)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -289,6 +314,10 @@ func (p *portal) aadAuthenticatedRoutes(r *mux.Router, prom *prometheus.Promethe
 	r.Methods(http.MethodGet).Path("/api/clusters").HandlerFunc(p.clusters)
 	r.Methods(http.MethodGet).Path("/api/info").HandlerFunc(p.info)
 	r.Methods(http.MethodGet).Path("/api/regions").HandlerFunc(p.regions)
+	r.Methods(http.MethodGet).Path("/api/grants").HandlerFunc(p.userGrants)
+	r.Methods(http.MethodGet).Path("/api/fleet/summary").HandlerFunc(p.fleetSummary)
+	r.Methods(http.MethodGet).Path("/api/fleet/adminupdateerrors").HandlerFunc(p.fleetAdminUpdateErrors)
+	r.Methods(http.MethodGet).Path("/api/fleet/creating").HandlerFunc(p.fleetCreating)
 
 	// Cluster-specific routes
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/clusteroperators").HandlerFunc(p.clusterOperators)
@@ -296,7 +325,24 @@ func (p *portal) aadAuthenticatedRoutes(r *mux.Router, prom *prometheus.Promethe
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/nodes").HandlerFunc(p.nodes)
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/machines").HandlerFunc(p.machines)
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/machine-sets").HandlerFunc(p.machineSets)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/statistics").HandlerFunc(p.statisticsCatalog)
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/statistics/{statisticsType}").HandlerFunc(p.statistics)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/alerts").HandlerFunc(p.alerts)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/auditlog").HandlerFunc(p.auditLog)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/events").HandlerFunc(p.events)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/pods/{namespace}/{name}/logs").HandlerFunc(p.podLogs)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/clusterversion/history").HandlerFunc(p.clusterVersionHistory)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/sshrecordings").HandlerFunc(p.sshRecordings)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/sshrecordings/{recording}").HandlerFunc(p.sshRecording)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/elevations").HandlerFunc(p.elevations)
+	r.Methods(http.MethodPost).Path("/api/{subscription}/{resourceGroup}/{clusterName}/elevations").HandlerFunc(p.requestElevation)
+	r.Methods(http.MethodPost).Path("/api/{subscription}/{resourceGroup}/{clusterName}/elevations/{elevation}/approve").HandlerFunc(p.approveElevation)
+	r.Methods(http.MethodPost).Path("/api/{subscription}/{resourceGroup}/{clusterName}/elevations/{elevation}/deny").HandlerFunc(p.denyElevation)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/pendingactions").HandlerFunc(p.pendingActions)
+	r.Methods(http.MethodPost).Path("/api/{subscription}/{resourceGroup}/{clusterName}/pendingactions/{pendingAction}/approve").HandlerFunc(p.approvePendingAction)
+	r.Methods(http.MethodPost).Path("/api/{subscription}/{resourceGroup}/{clusterName}/pendingactions/{pendingAction}/reject").HandlerFunc(p.rejectPendingAction)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/grants").HandlerFunc(p.clusterGrants)
+	r.Methods(http.MethodPost).Path("/api/{subscription}/{resourceGroup}/{clusterName}/grants/{grant}/revoke").HandlerFunc(p.revokeGrant)
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}").HandlerFunc(p.clusterInfo)
 
 	// prometheus
@@ -323,6 +369,10 @@ func (p *portal) aadAuthenticatedRoutes(r *mux.Router, prom *prometheus.Promethe
 
 	// ssh
 	r.Methods(http.MethodPost).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/ssh/new").HandlerFunc(sshStruct.New)
+	r.Methods(http.MethodGet).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/sshrecordings").HandlerFunc(p.sshRecordingsPage)
+	r.Methods(http.MethodGet).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/elevations").HandlerFunc(p.elevationsPage)
+	r.Methods(http.MethodGet).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/grants").HandlerFunc(p.grantsPage)
+	r.Methods(http.MethodGet).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/pendingactions").HandlerFunc(p.pendingActionsPage)
 
 	for _, name := range names {
 		regexp, _ := regexp.Compile(`v[1,2]/build/.*\..*`)
@@ -448,3 +498,14 @@ func (p *portal) badRequest(w http.ResponseWriter, err error) {
 	p.log.Debug(err)
 	http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 }
+
+func (p *portal) writeJSON(w http.ResponseWriter, v interface{}) {
+	b, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
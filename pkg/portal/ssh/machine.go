@@ -0,0 +1,96 @@
+package ssh
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	cryptossh "golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+const machineAPINamespace = "openshift-machine-api"
+
+// jumpConn is a connection to a node tunnelled through a master.  Closing it
+// also closes the connection to the master.
+type jumpConn struct {
+	net.Conn
+	jump *cryptossh.Client
+}
+
+func (c *jumpConn) Close() error {
+	err := c.Conn.Close()
+	_ = c.jump.Close()
+	return err
+}
+
+// dialMachine connects to the SSH port of the given machine.  Only the masters
+// are reachable from the portal, so the connection is tunnelled through the
+// first master which accepts it, in the same way as `ssh -J`.
+func (s *SSH) dialMachine(ctx context.Context, oc *api.OpenShiftCluster, name string, config *cryptossh.ClientConfig) (net.Conn, error) {
+	ip, err := s.machineIP(ctx, oc, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var jump *cryptossh.Client
+	for i := 0; i < 3; i++ {
+		address := fmt.Sprintf("%s:%d", oc.Properties.NetworkProfile.APIServerPrivateEndpointIP, 2200+i)
+
+		var c net.Conn
+		c, err = s.dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			continue
+		}
+
+		var conn cryptossh.Conn
+		var chans <-chan cryptossh.NewChannel
+		var reqs <-chan *cryptossh.Request
+		conn, chans, reqs, err = cryptossh.NewClientConn(c, "", config)
+		if err != nil {
+			c.Close()
+			continue
+		}
+
+		jump = cryptossh.NewClient(conn, chans, reqs)
+		break
+	}
+	if jump == nil {
+		return nil, err
+	}
+
+	c, err := jump.Dial("tcp", net.JoinHostPort(ip, "22"))
+	if err != nil {
+		jump.Close()
+		return nil, err
+	}
+
+	return &jumpConn{Conn: c, jump: jump}, nil
+}
+
+// machineIP returns the private IP of the given machine
+func (s *SSH) machineIP(ctx context.Context, oc *api.OpenShiftCluster, name string) (string, error) {
+	cli, err := s.newMachineClient(oc)
+	if err != nil {
+		return "", err
+	}
+
+	machine, err := cli.MachineV1beta1().Machines(machineAPINamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	for _, address := range machine.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			return address.Address, nil
+		}
+	}
+
+	return "", fmt.Errorf("machine %s has no internal IP", name)
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"strings"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
 	"github.com/Azure/ARO-RP/pkg/util/recover"
 	"github.com/Azure/ARO-RP/pkg/util/uuid"
@@ -56,6 +58,10 @@ const (
 
 const (
 	sshTimeout = time.Hour // never allow a connection to live longer than an hour.
+
+	// revocationCheckInterval is how often an established connection checks
+	// whether its password has been revoked
+	revocationCheckInterval = time.Minute
 )
 
 func (s *SSH) Run() error {
@@ -101,12 +107,17 @@ func (s *SSH) newConn(ctx context.Context, clientConn net.Conn) error {
 		portalDoc, err = s.dbPortal.Patch(ctx, password.String(), func(portalDoc *api.PortalDocument) error {
 			if portalDoc.Portal.SSH == nil ||
 				connmetadata.User() != strings.SplitN(portalDoc.Portal.Username, "@", 2)[0] ||
-				portalDoc.Portal.SSH.Authenticated {
+				portalDoc.Portal.SSH.Authenticated ||
+				portalDoc.Portal.RevokedAt != nil {
 				return fmt.Errorf("invalid username")
 			}
 
 			portalDoc.Portal.SSH.Authenticated = true
 
+			// keep the document for as long as the connection may live, so
+			// that it can be listed and revoked
+			portalDoc.TTL = int(sshTimeout / time.Second)
+
 			return nil
 		})
 		if err != nil {
@@ -129,30 +140,28 @@ func (s *SSH) newConn(ctx context.Context, clientConn net.Conn) error {
 		return err
 	}
 
+	hostname := Hostname(portalDoc.Portal.SSH)
+
 	// Log the incoming connection attempt.
 	accessLog := utillog.EnrichWithPath(s.baseAccessLog, portalDoc.Portal.ID)
 	accessLog = accessLog.WithFields(logrus.Fields{
-		"hostname":    fmt.Sprintf("master-%d", portalDoc.Portal.SSH.Master),
+		"hostname":    hostname,
 		"remote_addr": clientConn.RemoteAddr().String(),
 		"username":    portalDoc.Portal.Username,
 	})
 
 	accessLog.Print("authentication succeeded")
 
-	openShiftDoc, err := s.dbOpenShiftClusters.Get(ctx, strings.ToLower(portalDoc.Portal.ID))
+	timeout, err := s.sessionTimeout(ctx, portalDoc)
 	if err != nil {
 		return err
 	}
 
-	address := fmt.Sprintf("%s:%d", openShiftDoc.OpenShiftCluster.Properties.NetworkProfile.APIServerPrivateEndpointIP, 2200+portalDoc.Portal.SSH.Master)
-
-	c2, err := s.dialer.DialContext(ctx, "tcp", address)
+	openShiftDoc, err := s.dbOpenShiftClusters.Get(ctx, strings.ToLower(portalDoc.Portal.ID))
 	if err != nil {
 		return err
 	}
 
-	defer c2.Close()
-
 	key, err := x509.ParsePKCS1PrivateKey(openShiftDoc.OpenShiftCluster.Properties.SSHKey)
 	if err != nil {
 		return err
@@ -163,14 +172,29 @@ func (s *SSH) newConn(ctx context.Context, clientConn net.Conn) error {
 		return err
 	}
 
-	// Connect the second connection leg (portal->cluster).
-	downstreamConn, downstreamNewChannels, downstreamRequests, err := cryptossh.NewClientConn(c2, "", &cryptossh.ClientConfig{
+	clientConfig := &cryptossh.ClientConfig{
 		User: "core",
 		Auth: []cryptossh.AuthMethod{
 			cryptossh.PublicKeys(signer),
 		},
 		HostKeyCallback: cryptossh.InsecureIgnoreHostKey(),
-	})
+	}
+
+	var c2 net.Conn
+	if portalDoc.Portal.SSH.Machine != "" {
+		c2, err = s.dialMachine(ctx, openShiftDoc.OpenShiftCluster, portalDoc.Portal.SSH.Machine, clientConfig)
+	} else {
+		address := fmt.Sprintf("%s:%d", openShiftDoc.OpenShiftCluster.Properties.NetworkProfile.APIServerPrivateEndpointIP, 2200+portalDoc.Portal.SSH.Master)
+		c2, err = s.dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+
+	defer c2.Close()
+
+	// Connect the second connection leg (portal->cluster).
+	downstreamConn, downstreamNewChannels, downstreamRequests, err := cryptossh.NewClientConn(c2, "", clientConfig)
 	if err != nil {
 		return err
 	}
@@ -189,16 +213,73 @@ func (s *SSH) newConn(ctx context.Context, clientConn net.Conn) error {
 		return err
 	}
 
+	recorder := s.newSessionRecorder(accessLog, portalDoc, hostname)
+
 	// Proxy channels and requests between the two connections.
-	return s.proxyConn(ctx, accessLog, keyring, upstreamConn, downstreamConn, upstreamNewChannels, downstreamNewChannels, upstreamRequests, downstreamRequests)
+	return s.proxyConn(ctx, accessLog, keyring, recorder, portalDoc.ID, timeout, upstreamConn, downstreamConn, upstreamNewChannels, downstreamNewChannels, upstreamRequests, downstreamRequests)
+}
+
+// Hostname returns the name of the node being accessed
+func Hostname(ssh *api.SSH) string {
+	if ssh.Machine != "" {
+		return ssh.Machine
+	}
+
+	return fmt.Sprintf("master-%d", ssh.Master)
+}
+
+// sessionTimeout returns how long the connection may live: sshTimeout, or
+// until the end of the just-in-time elevation which granted access, if sooner
+func (s *SSH) sessionTimeout(ctx context.Context, portalDoc *api.PortalDocument) (time.Duration, error) {
+	if portalDoc.Portal.ElevationID == "" {
+		return sshTimeout, nil
+	}
+
+	elevationDoc, err := s.dbPortal.Get(ctx, portalDoc.Portal.ElevationID)
+	if err != nil {
+		return 0, err
+	}
+
+	if elevationDoc.Portal.Elevation == nil || elevationDoc.Portal.Elevation.ExpiresAt == nil {
+		return 0, fmt.Errorf("elevation %s is not approved", elevationDoc.ID)
+	}
+
+	timeout := elevationDoc.Portal.Elevation.ExpiresAt.Sub(s.now())
+	if timeout > sshTimeout {
+		timeout = sshTimeout
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("elevation %s has expired", elevationDoc.ID)
+	}
+
+	return timeout, nil
+}
+
+// revoked returns true if the portal document which authenticated a connection
+// has been revoked or deleted
+func (s *SSH) revoked(ctx context.Context, portalID string) (bool, error) {
+	portalDoc, err := s.dbPortal.Get(ctx, portalID)
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return portalDoc.Portal.RevokedAt != nil, nil
 }
 
 // proxyConn handles incoming new channel and administrative requests.  It calls
-// newChannel to handle new channels, each on a new goroutine.
-func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring agent.Agent, upstreamConn, downstreamConn cryptossh.Conn, upstreamNewChannels, downstreamNewChannels <-chan cryptossh.NewChannel, upstreamRequests, downstreamRequests <-chan *cryptossh.Request) error {
-	timer := time.NewTimer(sshTimeout)
+// newChannel to handle new channels, each on a new goroutine.  The connection
+// is closed once it has been revoked: this is checked when the SRE opens a new
+// channel and every revocationCheckInterval.
+func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring agent.Agent, recorder *sessionRecorder, portalID string, timeout time.Duration, upstreamConn, downstreamConn cryptossh.Conn, upstreamNewChannels, downstreamNewChannels <-chan cryptossh.NewChannel, upstreamRequests, downstreamRequests <-chan *cryptossh.Request) error {
+	timer := time.NewTimer(timeout)
 	defer timer.Stop()
 
+	ticker := time.NewTicker(revocationCheckInterval)
+	defer ticker.Stop()
+
 	var sessionOpened bool
 
 	for {
@@ -206,11 +287,32 @@ func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring ag
 		case <-timer.C:
 			return nil
 
+		case <-ticker.C:
+			revoked, err := s.revoked(ctx, portalID)
+			if err != nil {
+				accessLog.Warnf("failed to check revocation: %v", err)
+			} else if revoked {
+				accessLog.Print("revoked")
+				return nil
+			}
+
 		case nc := <-upstreamNewChannels:
 			if nc == nil {
 				return nil
 			}
 
+			revoked, err := s.revoked(ctx, portalID)
+			if err != nil {
+				accessLog.Warnf("failed to check revocation: %v", err)
+				_ = nc.Reject(cryptossh.ConnectionFailed, "failed to check revocation")
+				continue
+			}
+			if revoked {
+				accessLog.Print("revoked")
+				_ = nc.Reject(cryptossh.Prohibited, "access revoked")
+				return nil
+			}
+
 			// on the first SRE->cluster session, inject an advertisement of
 			// agent availability.
 			var firstSession bool
@@ -220,7 +322,7 @@ func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring ag
 			}
 
 			go func() {
-				_ = s.newChannel(ctx, accessLog, nc, upstreamConn, downstreamConn, firstSession)
+				_ = s.newChannel(ctx, accessLog, nc, upstreamConn, downstreamConn, firstSession, recorder)
 			}()
 
 		case nc := <-downstreamNewChannels:
@@ -235,7 +337,7 @@ func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring ag
 				}()
 			} else {
 				go func() {
-					_ = s.newChannel(ctx, accessLog, nc, downstreamConn, upstreamConn, false)
+					_ = s.newChannel(ctx, accessLog, nc, downstreamConn, upstreamConn, false, nil)
 				}()
 			}
 
@@ -276,8 +378,9 @@ func (s *SSH) handleAgent(accessLog *logrus.Entry, nc cryptossh.NewChannel, keyr
 
 // newChannel handles an incoming request to create a new channel.  If the
 // channel creation is successful, it calls proxyChannel to proxy the channel
-// between SRE and cluster.
-func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc cryptossh.NewChannel, upstreamConn, downstreamConn cryptossh.Conn, firstSession bool) error {
+// between SRE and cluster.  Session channels opened by the SRE are recorded if
+// a recorder is passed.
+func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc cryptossh.NewChannel, upstreamConn, downstreamConn cryptossh.Conn, firstSession bool, recorder *sessionRecorder) error {
 	defer recover.Panic(s.log)
 
 	ch2, rs2, err := downstreamConn.OpenChannel(nc.ChannelType(), nc.ExtraData())
@@ -307,7 +410,19 @@ func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc crypto
 		go s.keepAliveConn(ctx, ch1)
 	}
 
-	return s.proxyChannel(ch1, ch2, rs1, rs2)
+	var rec *recording
+	if recorder != nil && nc.ChannelType() == "session" {
+		var name string
+		rec, name = recorder.new()
+		defer func() {
+			err := recorder.save(ctx, rec, name)
+			if err != nil {
+				channelLog.WithField("recording", name).Errorf("failed to save recording: %v", err)
+			}
+		}()
+	}
+
+	return s.proxyChannel(ch1, ch2, rs1, rs2, rec)
 }
 
 func (s *SSH) proxyGlobalRequest(r *cryptossh.Request, c cryptossh.Conn) error {
@@ -328,7 +443,17 @@ func (s *SSH) proxyRequest(r *cryptossh.Request, ch cryptossh.Channel) error {
 	return r.Reply(ok, nil)
 }
 
-func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptossh.Request) error {
+// proxyChannel proxies a channel between ch1 and ch2.  If rec is not nil,
+// what ch1 sends is recorded as input and what ch2 sends as output.  Data is
+// recorded before it is forwarded, and the channel is closed once the
+// recording is full.
+func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptossh.Request, rec *recording) error {
+	var w1, w2 io.Writer = ch1, ch2
+	if rec != nil {
+		w1 = io.MultiWriter(rec.output(), ch1)
+		w2 = io.MultiWriter(rec.input(), ch2)
+	}
+
 	g := errgroup.Group{}
 
 	g.Go(func() error {
@@ -336,7 +461,11 @@ func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptoss
 		defer func() {
 			_ = ch1.CloseWrite()
 		}()
-		_, err := io.Copy(ch1, ch2)
+		_, err := io.Copy(w1, ch2)
+		if err == errRecordingFull {
+			_ = ch1.Close()
+			return ch2.Close()
+		}
 		if err != nil {
 			return err
 		}
@@ -348,7 +477,11 @@ func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptoss
 		defer func() {
 			_ = ch2.CloseWrite()
 		}()
-		_, err := io.Copy(ch2, ch1)
+		_, err := io.Copy(w2, ch1)
+		if err == errRecordingFull {
+			_ = ch2.Close()
+			return ch1.Close()
+		}
 		if err != nil {
 			return err
 		}
@@ -359,6 +492,9 @@ func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptoss
 		defer recover.Panic(s.log)
 
 		for r := range rs1 {
+			if rec != nil {
+				rec.request(r)
+			}
 			err := s.proxyRequest(r, ch2)
 			if err != nil {
 				break
@@ -9,17 +9,24 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 	"github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machineclient "github.com/openshift/client-go/machine/clientset/versioned"
+	machinefake "github.com/openshift/client-go/machine/clientset/versioned/fake"
 	"github.com/sirupsen/logrus"
 	cryptossh "golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
@@ -27,6 +34,7 @@ import (
 	utiltls "github.com/Azure/ARO-RP/pkg/util/tls"
 	testdatabase "github.com/Azure/ARO-RP/test/database"
 	"github.com/Azure/ARO-RP/test/util/bufferedpipe"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
 	"github.com/Azure/ARO-RP/test/util/listener"
 	testlog "github.com/Azure/ARO-RP/test/util/log"
 )
@@ -64,7 +72,9 @@ func fakeClient(c net.Conn, serverKey *rsa.PublicKey, user string, password stri
 }
 
 // fakeServer returns a test listener for an SSH server which validates the
-// client key, reads ping request(s) and writes pong replies
+// client key, reads ping request(s) and writes pong replies.  It also accepts
+// direct-tcpip channels, which it connects back to itself, so that it can act
+// as its own jump host.
 func fakeServer(clientKey *rsa.PublicKey) (*listener.Listener, error) {
 	l := listener.NewListener()
 
@@ -105,11 +115,37 @@ func fakeServer(clientKey *rsa.PublicKey) (*listener.Listener, error) {
 			}
 
 			go func() {
-				conn, _, requests, err := cryptossh.NewServerConn(c, config)
+				conn, channels, requests, err := cryptossh.NewServerConn(c, config)
 				if err != nil {
 					return
 				}
 
+				go func() {
+					for nc := range channels {
+						if nc.ChannelType() != "direct-tcpip" {
+							_ = nc.Reject(cryptossh.UnknownChannelType, "")
+							continue
+						}
+
+						ch, rs, err := nc.Accept()
+						if err != nil {
+							continue
+						}
+						go cryptossh.DiscardRequests(rs)
+
+						c1, c2 := bufferedpipe.New()
+						go l.Enqueue(c1)
+						go func() {
+							_, _ = io.Copy(ch, c2)
+							_ = ch.Close()
+						}()
+						go func() {
+							_, _ = io.Copy(c2, ch)
+							_ = c2.Close()
+						}()
+					}
+				}()
+
 				go func() {
 					for request := range requests {
 						if request.Type == "ping" && request.WantReply {
@@ -188,12 +224,40 @@ func TestProxy(t *testing.T) {
 		}
 	}
 
+	machinePortalDocument := func(id string) *api.PortalDocument {
+		portalDocument := goodPortalDocument(id)
+		portalDocument.Portal.SSH = &api.SSH{
+			Machine: "cluster-worker-0",
+		}
+		return portalDocument
+	}
+
+	workerMachine := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-worker-0",
+			Namespace: "openshift-machine-api",
+		},
+		Status: machinev1beta1.MachineStatus{
+			Addresses: []corev1.NodeAddress{
+				{
+					Type:    corev1.NodeHostName,
+					Address: "cluster-worker-0",
+				},
+				{
+					Type:    corev1.NodeInternalIP,
+					Address: "10.0.2.4",
+				},
+			},
+		},
+	}
+
 	type test struct {
 		name           string
 		username       string
 		password       string
 		fixtureChecker func(*test, *testdatabase.Fixture, *testdatabase.Checker, *cosmosdb.FakeOpenShiftClusterDocumentClient, *cosmosdb.FakePortalDocumentClient)
 		mocks          func(*mock_proxy.MockDialer)
+		machines       []*machinev1beta1.Machine
 		wantErrPrefix  string
 		wantLogs       []map[string]types.GomegaMatcher
 	}
@@ -210,6 +274,7 @@ func TestProxy(t *testing.T) {
 				fixture.AddOpenShiftClusterDocuments(openShiftClusterDocument)
 				portalDocument = goodPortalDocument(tt.password)
 				portalDocument.Portal.SSH.Authenticated = true
+				portalDocument.TTL = 3600
 				checker.AddPortalDocuments(portalDocument)
 				checker.AddOpenShiftClusterDocuments(openShiftClusterDocument)
 			},
@@ -246,6 +311,83 @@ func TestProxy(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "good, machine",
+			username: username,
+			password: password,
+			fixtureChecker: func(tt *test, fixture *testdatabase.Fixture, checker *testdatabase.Checker, openShiftClustersClient *cosmosdb.FakeOpenShiftClusterDocumentClient, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalDocument := machinePortalDocument(tt.password)
+				fixture.AddPortalDocuments(portalDocument)
+				openShiftClusterDocument := goodOpenShiftClusterDocument()
+				fixture.AddOpenShiftClusterDocuments(openShiftClusterDocument)
+				portalDocument = machinePortalDocument(tt.password)
+				portalDocument.Portal.SSH.Authenticated = true
+				portalDocument.TTL = 3600
+				checker.AddPortalDocuments(portalDocument)
+				checker.AddOpenShiftClusterDocuments(openShiftClusterDocument)
+			},
+			mocks: func(dialer *mock_proxy.MockDialer) {
+				gomock.InOrder(
+					dialer.EXPECT().DialContext(gomock.Any(), "tcp", apiServerPrivateEndpointIP+":2200").Return(nil, fmt.Errorf("sad")),
+					dialer.EXPECT().DialContext(gomock.Any(), "tcp", apiServerPrivateEndpointIP+":2201").Return(l.DialContext(ctx, "", "")),
+				)
+			},
+			machines: []*machinev1beta1.Machine{workerMachine},
+			wantLogs: []map[string]types.GomegaMatcher{
+				{
+					"level":       gomega.Equal(logrus.InfoLevel),
+					"msg":         gomega.Equal("authentication succeeded"),
+					"remote_addr": gomega.Not(gomega.BeEmpty()),
+					"username":    gomega.Equal(username),
+				},
+				{
+					"level":           gomega.Equal(logrus.InfoLevel),
+					"msg":             gomega.Equal("connected"),
+					"hostname":        gomega.Equal("cluster-worker-0"),
+					"resource_group":  gomega.Equal(resourceGroup),
+					"resource_id":     gomega.Equal(resourceID),
+					"resource_name":   gomega.Equal(resourceName),
+					"subscription_id": gomega.Equal(subscriptionID),
+					"username":        gomega.Equal(username),
+				},
+				{
+					"level":           gomega.Equal(logrus.InfoLevel),
+					"msg":             gomega.Equal("disconnected"),
+					"duration":        gomega.BeNumerically(">", 0),
+					"hostname":        gomega.Equal("cluster-worker-0"),
+					"resource_group":  gomega.Equal(resourceGroup),
+					"resource_id":     gomega.Equal(resourceID),
+					"resource_name":   gomega.Equal(resourceName),
+					"subscription_id": gomega.Equal(subscriptionID),
+					"username":        gomega.Equal(username),
+				},
+			},
+		},
+		{
+			name:     "machine not found",
+			username: username,
+			password: password,
+			fixtureChecker: func(tt *test, fixture *testdatabase.Fixture, checker *testdatabase.Checker, openShiftClustersClient *cosmosdb.FakeOpenShiftClusterDocumentClient, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalDocument := machinePortalDocument(tt.password)
+				fixture.AddPortalDocuments(portalDocument)
+				openShiftClusterDocument := goodOpenShiftClusterDocument()
+				fixture.AddOpenShiftClusterDocuments(openShiftClusterDocument)
+				portalDocument = machinePortalDocument(tt.password)
+				portalDocument.Portal.SSH.Authenticated = true
+				portalDocument.TTL = 3600
+				checker.AddPortalDocuments(portalDocument)
+				checker.AddOpenShiftClusterDocuments(openShiftClusterDocument)
+			},
+			wantErrPrefix: "EOF",
+			wantLogs: []map[string]types.GomegaMatcher{
+				{
+					"level":       gomega.Equal(logrus.InfoLevel),
+					"msg":         gomega.Equal("authentication succeeded"),
+					"remote_addr": gomega.Not(gomega.BeEmpty()),
+					"username":    gomega.Equal(username),
+				},
+			},
+		},
 		{
 			name:     "bad username",
 			username: "bad",
@@ -313,6 +455,28 @@ func TestProxy(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "revoked password",
+			username: username,
+			password: password,
+			fixtureChecker: func(tt *test, fixture *testdatabase.Fixture, checker *testdatabase.Checker, openShiftClustersClient *cosmosdb.FakeOpenShiftClusterDocumentClient, portalClient *cosmosdb.FakePortalDocumentClient) {
+				revokedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+				portalDocument := goodPortalDocument(tt.password)
+				portalDocument.Portal.RevokedAt = &revokedAt
+				portalDocument.Portal.RevokedBy = "admin"
+				fixture.AddPortalDocuments(portalDocument)
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantErrPrefix: "ssh: handshake failed",
+			wantLogs: []map[string]types.GomegaMatcher{
+				{
+					"level":       gomega.Equal(logrus.WarnLevel),
+					"msg":         gomega.Equal("authentication failed"),
+					"remote_addr": gomega.Not(gomega.BeEmpty()),
+					"username":    gomega.Equal(username),
+				},
+			},
+		},
 		{
 			name:     "sad openshiftClusters database",
 			username: username,
@@ -322,6 +486,7 @@ func TestProxy(t *testing.T) {
 				fixture.AddPortalDocuments(portalDocument)
 				portalDocument = goodPortalDocument(tt.password)
 				portalDocument.Portal.SSH.Authenticated = true
+				portalDocument.TTL = 3600
 				checker.AddPortalDocuments(portalDocument)
 
 				openShiftClustersClient.SetError(fmt.Errorf("sad"))
@@ -364,6 +529,7 @@ func TestProxy(t *testing.T) {
 				fixture.AddOpenShiftClusterDocuments(openShiftClusterDocument)
 				portalDocument = goodPortalDocument(tt.password)
 				portalDocument.Portal.SSH.Authenticated = true
+				portalDocument.TTL = 3600
 				checker.AddPortalDocuments(portalDocument)
 				checker.AddOpenShiftClusterDocuments(openShiftClusterDocument)
 			},
@@ -413,11 +579,22 @@ func TestProxy(t *testing.T) {
 
 			hook, log := testlog.New()
 
-			s, err := New(nil, nil, log, nil, hostKey, nil, dbOpenShiftClusters, dbPortal, dialer)
+			s, err := New(nil, nil, nil, log, nil, hostKey, nil, dbOpenShiftClusters, dbPortal, dialer, nil, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
 
+			s.newMachineClient = func(*api.OpenShiftCluster) (machineclient.Interface, error) {
+				machineCli := machinefake.NewSimpleClientset()
+				for _, machine := range tt.machines {
+					err := machineCli.Tracker().Add(machine)
+					if err != nil {
+						return nil, err
+					}
+				}
+				return machineCli, nil
+			}
+
 			r := mux.NewRouter()
 			r.Methods(http.MethodPost).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/ssh/new").HandlerFunc(s.New)
 
@@ -454,3 +631,145 @@ func TestProxy(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionTimeout(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	elevationDoc := func(id string, expiresAt time.Time) *api.PortalDocument {
+		return &api.PortalDocument{
+			ID: id,
+			Portal: &api.Portal{
+				Username: "username",
+				Elevation: &api.Elevation{
+					State:     api.ElevationStateApproved,
+					ExpiresAt: &expiresAt,
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name        string
+		elevationID string
+		wantTimeout time.Duration
+		wantErr     string
+	}{
+		{
+			name:        "standing elevation",
+			wantTimeout: sshTimeout,
+		},
+		{
+			name:        "elevation ends first",
+			elevationID: "03030303-0303-0303-0303-030303030001",
+			wantTimeout: 10 * time.Minute,
+		},
+		{
+			name:        "elevation ends later",
+			elevationID: "03030303-0303-0303-0303-030303030002",
+			wantTimeout: sshTimeout,
+		},
+		{
+			name:        "elevation expired",
+			elevationID: "03030303-0303-0303-0303-030303030003",
+			wantErr:     "elevation 03030303-0303-0303-0303-030303030003 has expired",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbPortal, _ := testdatabase.NewFakePortal()
+
+			fixture := testdatabase.NewFixture().WithPortal(dbPortal)
+			fixture.AddPortalDocuments(
+				elevationDoc("03030303-0303-0303-0303-030303030001", now.Add(10*time.Minute)),
+				elevationDoc("03030303-0303-0303-0303-030303030002", now.Add(4*time.Hour)),
+				elevationDoc("03030303-0303-0303-0303-030303030003", now.Add(-time.Minute)),
+			)
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &SSH{
+				dbPortal: dbPortal,
+				now:      func() time.Time { return now },
+			}
+
+			timeout, err := s.sessionTimeout(ctx, &api.PortalDocument{
+				Portal: &api.Portal{
+					ElevationID: tt.elevationID,
+				},
+			})
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if timeout != tt.wantTimeout {
+				t.Error(timeout)
+			}
+		})
+	}
+}
+
+func TestRevoked(t *testing.T) {
+	ctx := context.Background()
+	revokedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		name        string
+		portalID    string
+		wantRevoked bool
+	}{
+		{
+			name:     "active",
+			portalID: "03030303-0303-0303-0303-030303030001",
+		},
+		{
+			name:        "revoked",
+			portalID:    "03030303-0303-0303-0303-030303030002",
+			wantRevoked: true,
+		},
+		{
+			name:        "deleted",
+			portalID:    "03030303-0303-0303-0303-030303030003",
+			wantRevoked: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbPortal, _ := testdatabase.NewFakePortal()
+
+			fixture := testdatabase.NewFixture().WithPortal(dbPortal)
+			fixture.AddPortalDocuments(
+				&api.PortalDocument{
+					ID: "03030303-0303-0303-0303-030303030001",
+					Portal: &api.Portal{
+						SSH: &api.SSH{},
+					},
+				},
+				&api.PortalDocument{
+					ID: "03030303-0303-0303-0303-030303030002",
+					Portal: &api.Portal{
+						SSH:       &api.SSH{},
+						RevokedAt: &revokedAt,
+					},
+				},
+			)
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &SSH{
+				dbPortal: dbPortal,
+			}
+
+			revoked, err := s.revoked(ctx, tt.portalID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if revoked != tt.wantRevoked {
+				t.Error(revoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package ssh
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+// This file records SSH session channels in the asciicast v2 format
+// (https://docs.asciinema.org/manual/asciicast/v2/), so that recordings can be
+// played back with standard tooling.  The whole recording is held in memory
+// and is encrypted and stored when the channel closes; sessions are limited to
+// sshTimeout, and a channel is closed if its recording reaches
+// recordingMaxSize, so that no session traffic goes unrecorded.
+
+const (
+	recordingDefaultWidth  = 80
+	recordingDefaultHeight = 24
+
+	// recordingMaxSize bounds the memory held by a single channel recording
+	recordingMaxSize = 16 << 20
+
+	recordingEventOutput = "o"
+	recordingEventInput  = "i"
+	recordingEventResize = "r"
+)
+
+// errRecordingFull is returned by recording writers once the recording has
+// reached its maximum size
+var errRecordingFull = errors.New("recording size limit reached")
+
+type recordingHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Duration  float64           `json:"duration,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// recording is the asciicast recording of a single session channel
+type recording struct {
+	mu sync.Mutex
+
+	now   func() time.Time
+	start time.Time
+	end   time.Time
+
+	term   string
+	width  int
+	height int
+	hasPty bool
+
+	// pending holds the trailing bytes of an incomplete UTF-8 sequence per
+	// event type, until the rest of the sequence arrives
+	pending map[string][]byte
+	events  bytes.Buffer
+	maxSize int
+	full    bool
+}
+
+func newRecording(now func() time.Time) *recording {
+	return &recording{
+		now:     now,
+		start:   now(),
+		width:   recordingDefaultWidth,
+		height:  recordingDefaultHeight,
+		pending: map[string][]byte{},
+		maxSize: recordingMaxSize,
+	}
+}
+
+type recordingWriter struct {
+	r    *recording
+	code string
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	err := w.r.write(w.code, b)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// output returns a writer which records what the SRE was shown
+func (r *recording) output() *recordingWriter {
+	return &recordingWriter{r: r, code: recordingEventOutput}
+}
+
+// input returns a writer which records what the SRE typed
+func (r *recording) input() *recordingWriter {
+	return &recordingWriter{r: r, code: recordingEventInput}
+}
+
+func (r *recording) write(code string, b []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.full {
+		return errRecordingFull
+	}
+
+	b = append(r.pending[code], b...)
+	b, r.pending[code] = splitUTF8(b)
+	if len(b) > 0 {
+		return r.event(code, string(b))
+	}
+
+	return nil
+}
+
+// request records the terminal size from pty-req and window-change channel
+// requests
+func (r *recording) request(req *cryptossh.Request) {
+	switch req.Type {
+	case "pty-req":
+		var payload struct {
+			Term     string
+			Columns  uint32
+			Rows     uint32
+			WidthPx  uint32
+			HeightPx uint32
+			Modes    string
+		}
+		if cryptossh.Unmarshal(req.Payload, &payload) == nil {
+			r.setPty(payload.Term, int(payload.Columns), int(payload.Rows))
+		}
+
+	case "window-change":
+		var payload struct {
+			Columns  uint32
+			Rows     uint32
+			WidthPx  uint32
+			HeightPx uint32
+		}
+		if cryptossh.Unmarshal(req.Payload, &payload) == nil {
+			r.resize(int(payload.Columns), int(payload.Rows))
+		}
+	}
+}
+
+func (r *recording) setPty(term string, width, height int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hasPty {
+		r.resizeLocked(width, height)
+		return
+	}
+
+	r.hasPty = true
+	r.term = term
+	if width > 0 && height > 0 {
+		r.width, r.height = width, height
+	}
+}
+
+func (r *recording) resize(width, height int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resizeLocked(width, height)
+}
+
+func (r *recording) resizeLocked(width, height int) {
+	if width > 0 && height > 0 && !r.full {
+		_ = r.event(recordingEventResize, fmt.Sprintf("%dx%d", width, height))
+	}
+}
+
+// event must be called with r.mu held.  If the event would take the recording
+// past maxSize, it is dropped, a final output event noting the truncation is
+// recorded in its place and errRecordingFull is returned.
+func (r *recording) event(code, data string) error {
+	b, err := r.marshalEvent(code, data)
+	if err != nil {
+		return err
+	}
+
+	if r.events.Len()+len(b) > r.maxSize {
+		r.full = true
+
+		b, err = r.marshalEvent(recordingEventOutput, "\r\n[recording size limit reached, closing channel]\r\n")
+		if err == nil {
+			r.events.Write(b)
+		}
+
+		return errRecordingFull
+	}
+
+	r.events.Write(b)
+	return nil
+}
+
+func (r *recording) marshalEvent(code, data string) ([]byte, error) {
+	b, err := json.Marshal([]interface{}{
+		r.now().Sub(r.start).Seconds(),
+		code,
+		data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// close stops the recording
+func (r *recording) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, code := range []string{recordingEventInput, recordingEventOutput} {
+		if len(r.pending[code]) > 0 && !r.full {
+			_ = r.event(code, string(r.pending[code]))
+		}
+		delete(r.pending, code)
+	}
+
+	r.end = r.now()
+}
+
+// marshal returns the asciicast file
+func (r *recording) marshal(title string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	header := recordingHeader{
+		Version:   2,
+		Width:     r.width,
+		Height:    r.height,
+		Timestamp: r.start.Unix(),
+		Title:     title,
+	}
+	if !r.end.IsZero() {
+		header.Duration = r.end.Sub(r.start).Seconds()
+	}
+	if r.term != "" {
+		header.Env = map[string]string{
+			"TERM": r.term,
+		}
+	}
+
+	b, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	b = append(b, '\n')
+	return append(b, r.events.Bytes()...), nil
+}
+
+// splitUTF8 splits off an incomplete UTF-8 sequence at the end of b
+func splitUTF8(b []byte) ([]byte, []byte) {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return b[:i], append([]byte(nil), b[i:]...)
+			}
+			break
+		}
+	}
+
+	return b, nil
+}
+
+// sessionRecorder records the session channels of one SRE connection
+type sessionRecorder struct {
+	log *logrus.Entry
+
+	aead       encryption.AEAD
+	recordings RecordingStore
+	now        func() time.Time
+
+	resourceID string
+	portalID   string
+	username   string
+	hostname   string
+
+	mu       sync.Mutex
+	channels int
+}
+
+// newSessionRecorder returns nil if no recording store is configured
+func (s *SSH) newSessionRecorder(log *logrus.Entry, portalDoc *api.PortalDocument, hostname string) *sessionRecorder {
+	if s.recordings == nil {
+		return nil
+	}
+
+	return &sessionRecorder{
+		log: log,
+
+		aead:       s.aead,
+		recordings: s.recordings,
+		now:        s.now,
+
+		resourceID: portalDoc.Portal.ID,
+		portalID:   portalDoc.ID,
+		username:   portalDoc.Portal.Username,
+		hostname:   hostname,
+	}
+}
+
+// new starts the recording of a session channel
+func (sr *sessionRecorder) new() (*recording, string) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	name := fmt.Sprintf("%s-%d.cast", sr.portalID, sr.channels)
+	sr.channels++
+
+	return newRecording(sr.now), name
+}
+
+// save encrypts and stores a finished recording
+func (sr *sessionRecorder) save(ctx context.Context, r *recording, name string) error {
+	r.close()
+
+	b, err := r.marshal(fmt.Sprintf("%s@%s", sr.username, sr.hostname))
+	if err != nil {
+		return err
+	}
+
+	b, err = sr.aead.Seal(b)
+	if err != nil {
+		return err
+	}
+
+	err = sr.recordings.Put(ctx, sr.resourceID, &Recording{
+		Name:      name,
+		Username:  sr.username,
+		Hostname:  sr.hostname,
+		StartTime: r.start,
+		Duration:  r.end.Sub(r.start).Seconds(),
+	}, b)
+	if err != nil {
+		return err
+	}
+
+	sr.log.WithField("recording", name).Print("recording saved")
+	return nil
+}
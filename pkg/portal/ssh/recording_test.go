@@ -0,0 +1,186 @@
+package ssh
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+type fakeRecordingStore struct {
+	resourceID string
+	recording  *Recording
+	b          []byte
+}
+
+func (s *fakeRecordingStore) Put(ctx context.Context, resourceID string, recording *Recording, b []byte) error {
+	s.resourceID, s.recording, s.b = resourceID, recording, b
+	return nil
+}
+
+func (s *fakeRecordingStore) List(ctx context.Context, resourceID string) ([]*Recording, error) {
+	return nil, nil
+}
+
+func (s *fakeRecordingStore) Get(ctx context.Context, resourceID, name string) ([]byte, error) {
+	return s.b, nil
+}
+
+func TestRecording(t *testing.T) {
+	ctx := context.Background()
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster"
+
+	start := time.Unix(1700000000, 0)
+	now := start
+	clock := func() time.Time {
+		now = now.Add(500 * time.Millisecond)
+		return now
+	}
+
+	aead, err := encryption.NewXChaCha20Poly1305(ctx, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &fakeRecordingStore{}
+
+	s := &SSH{
+		aead:       aead,
+		recordings: store,
+		now:        clock,
+	}
+
+	recorder := s.newSessionRecorder(logrus.NewEntry(logrus.StandardLogger()), &api.PortalDocument{
+		ID: "03030303-0303-0303-0303-030303030001",
+		Portal: &api.Portal{
+			Username: "username@example.com",
+			ID:       resourceID,
+		},
+	}, "cluster-worker-0")
+
+	rec, name := recorder.new()
+	if name != "03030303-0303-0303-0303-030303030001-0.cast" {
+		t.Error(name)
+	}
+
+	rec.request(&cryptossh.Request{
+		Type: "pty-req",
+		Payload: cryptossh.Marshal(struct {
+			Term     string
+			Columns  uint32
+			Rows     uint32
+			WidthPx  uint32
+			HeightPx uint32
+			Modes    string
+		}{"xterm", 120, 40, 0, 0, ""}),
+	})
+
+	_, _ = rec.output().Write([]byte("$ "))
+	_, _ = rec.input().Write([]byte("ls\r"))
+
+	// "é" split across two writes
+	_, _ = rec.output().Write([]byte{'c', 'a', 'f', 0xc3})
+	_, _ = rec.output().Write([]byte{0xa9, '\r', '\n'})
+
+	rec.request(&cryptossh.Request{
+		Type: "window-change",
+		Payload: cryptossh.Marshal(struct {
+			Columns  uint32
+			Rows     uint32
+			WidthPx  uint32
+			HeightPx uint32
+		}{100, 30, 0, 0}),
+	})
+
+	err = recorder.save(ctx, rec, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if store.resourceID != resourceID {
+		t.Error(store.resourceID)
+	}
+
+	wantRecording := Recording{
+		Name:      name,
+		Username:  "username@example.com",
+		Hostname:  "cluster-worker-0",
+		StartTime: start.Add(500 * time.Millisecond),
+		Duration:  3,
+	}
+	if *store.recording != wantRecording {
+		t.Errorf("%#v", store.recording)
+	}
+
+	b, err := aead.Open(store.b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantCast := `{"version":2,"width":120,"height":40,"timestamp":1700000000,"duration":3,"title":"username@example.com@cluster-worker-0","env":{"TERM":"xterm"}}
+[0.5,"o","$ "]
+[1,"i","ls\r"]
+[1.5,"o","caf"]
+[2,"o","é\r\n"]
+[2.5,"r","100x30"]
+`
+	if string(b) != wantCast {
+		t.Error(string(b))
+	}
+}
+
+func TestNewSessionRecorderDisabled(t *testing.T) {
+	s := &SSH{}
+
+	if s.newSessionRecorder(nil, nil, "") != nil {
+		t.Error("expected no recorder")
+	}
+}
+
+func TestRecordingFull(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	rec := newRecording(func() time.Time { return start })
+	rec.maxSize = 50
+
+	for i := 0; i < 2; i++ {
+		n, err := rec.output().Write([]byte("0123456789"))
+		if err != nil || n != 10 {
+			t.Fatal(n, err)
+		}
+	}
+
+	n, err := rec.input().Write([]byte("0123456789"))
+	if err != errRecordingFull || n != 0 {
+		t.Fatal(n, err)
+	}
+
+	n, err = rec.output().Write([]byte("x"))
+	if err != errRecordingFull || n != 0 {
+		t.Fatal(n, err)
+	}
+
+	rec.resize(100, 30)
+	rec.close()
+
+	b, err := rec.marshal("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantCast := `{"version":2,"width":80,"height":24,"timestamp":1700000000}
+[0,"o","0123456789"]
+[0,"o","0123456789"]
+[0,"o","\r\n[recording size limit reached, closing channel]\r\n"]
+`
+	if string(b) != wantCast {
+		t.Error(string(b))
+	}
+}
@@ -0,0 +1,142 @@
+package ssh
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	azstorage "github.com/Azure/azure-sdk-for-go/storage"
+
+	"github.com/Azure/ARO-RP/pkg/util/storage"
+)
+
+const recordingsContainer = "sshrecordings"
+
+// Recording describes a stored session recording
+type Recording struct {
+	// Name is <portal document ID>-<channel>, unique within a cluster
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	Hostname  string    `json:"hostname"`
+	StartTime time.Time `json:"startTime"`
+	Duration  float64   `json:"duration"`
+}
+
+// RecordingStore stores encrypted session recordings, keyed by cluster
+// resource ID
+type RecordingStore interface {
+	Put(ctx context.Context, resourceID string, recording *Recording, b []byte) error
+	List(ctx context.Context, resourceID string) ([]*Recording, error)
+	Get(ctx context.Context, resourceID, name string) ([]byte, error)
+}
+
+type blobRecordingStore struct {
+	storage       storage.Manager
+	resourceGroup string
+	account       string
+}
+
+// NewRecordingStore returns a RecordingStore which stores recordings in the
+// given storage account
+func NewRecordingStore(storage storage.Manager, resourceGroup, account string) RecordingStore {
+	return &blobRecordingStore{
+		storage:       storage,
+		resourceGroup: resourceGroup,
+		account:       account,
+	}
+}
+
+func recordingPrefix(resourceID string) string {
+	return strings.TrimPrefix(strings.ToLower(resourceID), "/") + "/"
+}
+
+// container returns a new client every time, as the SAS token it is built
+// from expires
+func (s *blobRecordingStore) container(ctx context.Context) (*azstorage.Container, error) {
+	blobService, err := s.storage.BlobService(ctx, s.resourceGroup, s.account, mgmtstorage.Permissions("rwl"), mgmtstorage.SignedResourceTypes("co"))
+	if err != nil {
+		return nil, err
+	}
+
+	return blobService.GetContainerReference(recordingsContainer), nil
+}
+
+func (s *blobRecordingStore) Put(ctx context.Context, resourceID string, recording *Recording, b []byte) error {
+	c, err := s.container(ctx)
+	if err != nil {
+		return err
+	}
+
+	blob := c.GetBlobReference(recordingPrefix(resourceID) + recording.Name)
+	blob.Metadata = azstorage.BlobMetadata{
+		"username":  recording.Username,
+		"hostname":  recording.Hostname,
+		"starttime": recording.StartTime.UTC().Format(time.RFC3339),
+		"duration":  strconv.FormatFloat(recording.Duration, 'f', 3, 64),
+	}
+
+	return blob.CreateBlockBlobFromReader(bytes.NewReader(b), nil)
+}
+
+func (s *blobRecordingStore) List(ctx context.Context, resourceID string) ([]*Recording, error) {
+	c, err := s.container(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := recordingPrefix(resourceID)
+
+	var recordings []*Recording
+	params := azstorage.ListBlobsParameters{
+		Prefix: prefix,
+		Include: &azstorage.IncludeBlobDataset{
+			Metadata: true,
+		},
+	}
+	for {
+		resp, err := c.ListBlobs(params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blob := range resp.Blobs {
+			startTime, _ := time.Parse(time.RFC3339, blob.Metadata["starttime"])
+			duration, _ := strconv.ParseFloat(blob.Metadata["duration"], 64)
+
+			recordings = append(recordings, &Recording{
+				Name:      strings.TrimPrefix(blob.Name, prefix),
+				Username:  blob.Metadata["username"],
+				Hostname:  blob.Metadata["hostname"],
+				StartTime: startTime,
+				Duration:  duration,
+			})
+		}
+
+		if resp.NextMarker == "" {
+			return recordings, nil
+		}
+		params.Marker = resp.NextMarker
+	}
+}
+
+func (s *blobRecordingStore) Get(ctx context.Context, resourceID, name string) ([]byte, error) {
+	c, err := s.container(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := c.GetBlobReference(recordingPrefix(resourceID) + name).Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
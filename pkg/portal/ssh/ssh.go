@@ -15,6 +15,7 @@ import (
 	"text/template"
 	"time"
 
+	machineclient "github.com/openshift/client-go/machine/clientset/versioned"
 	"github.com/sirupsen/logrus"
 	cryptossh "golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/knownhosts"
@@ -23,8 +24,11 @@ import (
 	"github.com/Azure/ARO-RP/pkg/api/validate"
 	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/portal/elevation"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/proxy"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	"github.com/Azure/ARO-RP/pkg/util/restconfig"
 )
 
 const (
@@ -34,6 +38,7 @@ const (
 type SSH struct {
 	env           env.Core
 	log           *logrus.Entry
+	audit         *logrus.Entry
 	baseAccessLog *logrus.Entry
 	l             net.Listener
 
@@ -44,6 +49,12 @@ type SSH struct {
 
 	dialer proxy.Dialer
 
+	newMachineClient func(*api.OpenShiftCluster) (machineclient.Interface, error)
+
+	aead       encryption.AEAD
+	recordings RecordingStore
+	now        func() time.Time
+
 	baseServerConfig *cryptossh.ServerConfig
 
 	hostPubKey cryptossh.PublicKey
@@ -51,6 +62,7 @@ type SSH struct {
 
 func New(env env.Core,
 	log *logrus.Entry,
+	audit *logrus.Entry,
 	baseAccessLog *logrus.Entry,
 	l net.Listener,
 	hostKey *rsa.PrivateKey,
@@ -58,6 +70,8 @@ func New(env env.Core,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbPortal database.Portal,
 	dialer proxy.Dialer,
+	aead encryption.AEAD,
+	recordings RecordingStore,
 ) (*SSH, error) {
 	hostPubKey, err := cryptossh.NewPublicKey(&hostKey.PublicKey)
 	if err != nil {
@@ -67,6 +81,7 @@ func New(env env.Core,
 	s := &SSH{
 		env:           env,
 		log:           log,
+		audit:         audit,
 		baseAccessLog: baseAccessLog,
 		l:             l,
 
@@ -77,6 +92,10 @@ func New(env env.Core,
 
 		dialer: dialer,
 
+		aead:       aead,
+		recordings: recordings,
+		now:        time.Now,
+
 		baseServerConfig: &cryptossh.ServerConfig{},
 
 		hostPubKey: hostPubKey,
@@ -89,11 +108,21 @@ func New(env env.Core,
 
 	s.baseServerConfig.AddHostKey(signer)
 
+	s.newMachineClient = func(oc *api.OpenShiftCluster) (machineclient.Interface, error) {
+		restConfig, err := restconfig.RestConfig(s.dialer, oc)
+		if err != nil {
+			return nil, err
+		}
+
+		return machineclient.NewForConfig(restConfig)
+	}
+
 	return s, nil
 }
 
 type request struct {
-	Master int `json:"master,omitempty"`
+	Master  int    `json:"master,omitempty"`
+	Machine string `json:"machine,omitempty"`
 }
 
 type response struct {
@@ -127,15 +156,26 @@ func (s *SSH) New(w http.ResponseWriter, r *http.Request) {
 
 	var req *request
 	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil || req.Master < 0 || req.Master > 2 {
+	if err != nil || req.Master < 0 || req.Master > 2 ||
+		req.Machine != "" && !validate.RxDomainNameRFC1123.MatchString(req.Machine) {
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
 	elevated := len(middleware.GroupsIntersect(s.elevatedGroupIDs, ctx.Value(middleware.ContextKeyGroups).([]string))) > 0
+
+	var elevationDoc *api.PortalDocument
 	if !elevated {
-		s.sendResponse(w, "", "", "", "Elevated access is required.", s.env.IsLocalDevelopmentMode())
-		return
+		elevationDoc, err = elevation.Active(ctx, s.dbPortal, ctx.Value(middleware.ContextKeyUsername).(string), resourceID, s.now())
+		if err != nil {
+			s.internalServerError(w, err)
+			return
+		}
+
+		if elevationDoc == nil {
+			s.sendResponse(w, "", "", "", "Elevated access is required.", s.env.IsLocalDevelopmentMode())
+			return
+		}
 	}
 
 	username := r.Context().Value(middleware.ContextKeyUsername).(string)
@@ -149,10 +189,14 @@ func (s *SSH) New(w http.ResponseWriter, r *http.Request) {
 			Username: ctx.Value(middleware.ContextKeyUsername).(string),
 			ID:       resourceID,
 			SSH: &api.SSH{
-				Master: req.Master,
+				Master:  req.Master,
+				Machine: req.Machine,
 			},
 		},
 	}
+	if elevationDoc != nil {
+		portalDoc.Portal.ElevationID = elevationDoc.ID
+	}
 
 	_, err = s.dbPortal.Create(ctx, portalDoc)
 	if err != nil {
@@ -160,6 +204,11 @@ func (s *SSH) New(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if elevationDoc != nil {
+		elevation.Audit(s.audit, s.env, r, elevation.OperationUse, resourceID, elevationDoc.ID,
+			fmt.Sprintf("issued SSH password for %s", Hostname(portalDoc.Portal.SSH)))
+	}
+
 	host := r.Host
 	if strings.ContainsRune(r.Host, ':') {
 		host, _, err = net.SplitHostPort(r.Host)
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -22,9 +23,11 @@ import (
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/portal/util/responsewriter"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
 	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
 	utiltls "github.com/Azure/ARO-RP/pkg/util/tls"
 	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
 )
 
 func TestNew(t *testing.T) {
@@ -44,12 +47,32 @@ func TestNew(t *testing.T) {
 	}
 	khline := knownhosts.Line([]string{"localhost"}, hostPubKey)
 
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(2 * time.Hour)
+	elevationDoc := &api.PortalDocument{
+		ID: "03030303-0303-0303-0303-030303030000",
+		Portal: &api.Portal{
+			Username: username,
+			ID:       resourceID,
+			Elevation: &api.Elevation{
+				Reason:    "ICM 1234",
+				Duration:  7200,
+				State:     api.ElevationStateApproved,
+				Reviewer:  "approver",
+				ExpiresAt: &expiresAt,
+			},
+		},
+	}
+
 	for _, tt := range []struct {
 		name           string
+		request        string
 		r              func(*http.Request)
+		fixture        func(*testdatabase.Fixture)
 		checker        func(*testdatabase.Checker, *cosmosdb.FakePortalDocumentClient)
 		wantStatusCode int
 		wantBody       string
+		wantAudit      bool
 	}{
 		{
 			name: "success",
@@ -73,6 +96,41 @@ func TestNew(t *testing.T) {
 }
 `,
 		},
+		{
+			name:    "success, machine",
+			request: `{"machine":"cluster-abcde-worker-eastus1-fghij"}`,
+			checker: func(checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				checker.AddPortalDocuments(&api.PortalDocument{
+					ID:  password,
+					TTL: 60,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						SSH: &api.SSH{
+							Machine: "cluster-abcde-worker-eastus1-fghij",
+						},
+					},
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+    "command": "echo '` + khline + `' > localhost_known_host ; ssh -o UserKnownHostsFile=localhost_known_host username@localhost",
+    "password": "03030303-0303-0303-0303-030303030001"
+}
+`,
+		},
+		{
+			name:           "bad machine",
+			request:        `{"machine":"../cluster"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "Bad Request\n",
+		},
+		{
+			name:           "bad master",
+			request:        `{"master":3}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "Bad Request\n",
+		},
 		{
 			name: "bad path",
 			r: func(r *http.Request) {
@@ -113,6 +171,36 @@ func TestNew(t *testing.T) {
 			wantStatusCode: http.StatusOK,
 			wantBody:       "{\n    \"error\": \"Elevated access is required.\"\n}\n",
 		},
+		{
+			name: "success, just-in-time elevation",
+			r: func(r *http.Request) {
+				*r = *r.WithContext(context.WithValue(r.Context(), middleware.ContextKeyGroups, []string{}))
+			},
+			fixture: func(fixture *testdatabase.Fixture) {
+				fixture.AddPortalDocuments(elevationDoc)
+			},
+			checker: func(checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				checker.AddPortalDocuments(elevationDoc, &api.PortalDocument{
+					ID:  password,
+					TTL: 60,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						SSH: &api.SSH{
+							Master: master,
+						},
+						ElevationID: elevationDoc.ID,
+					},
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+    "command": "echo '` + khline + `' > localhost_known_host ; ssh -o UserKnownHostsFile=localhost_known_host username@localhost",
+    "password": "03030303-0303-0303-0303-030303030001"
+}
+`,
+			wantAudit: true,
+		},
 		{
 			name: "sad database",
 			checker: func(checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
@@ -127,16 +215,30 @@ func TestNew(t *testing.T) {
 
 			dbPortal, portalClient := testdatabase.NewFakePortal()
 
+			fixture := testdatabase.NewFixture().WithPortal(dbPortal)
+			if tt.fixture != nil {
+				tt.fixture(fixture)
+			}
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
 			checker := testdatabase.NewChecker()
 
 			if tt.checker != nil {
 				tt.checker(checker, portalClient)
 			}
 
+			if tt.request == "" {
+				tt.request = fmt.Sprintf(`{"master":%d}`, master)
+			}
+
 			ctx = context.WithValue(ctx, middleware.ContextKeyUsername, username)
 			ctx = context.WithValue(ctx, middleware.ContextKeyGroups, elevatedGroupIDs)
 			r, err := http.NewRequestWithContext(ctx, http.MethodPost,
-				"https://localhost:8444"+resourceID+"/ssh/new", strings.NewReader(fmt.Sprintf(`{"master":%d}`, master)))
+				"https://localhost:8444"+resourceID+"/ssh/new", strings.NewReader(tt.request))
 			if err != nil {
 				panic(err)
 			}
@@ -148,11 +250,17 @@ func TestNew(t *testing.T) {
 
 			env := mock_env.NewMockCore(ctrl)
 			env.EXPECT().IsLocalDevelopmentMode().AnyTimes().Return(false)
+			env.EXPECT().Environment().AnyTimes().Return(&azureclient.PublicCloud)
+			env.EXPECT().Hostname().AnyTimes().Return("testhost")
+			env.EXPECT().Location().AnyTimes().Return("eastus")
+
+			auditHook, audit := testlog.NewAudit()
 
-			s, err := New(env, logrus.NewEntry(logrus.StandardLogger()), nil, nil, hostKey, elevatedGroupIDs, nil, dbPortal, nil)
+			s, err := New(env, logrus.NewEntry(logrus.StandardLogger()), audit, nil, nil, hostKey, elevatedGroupIDs, nil, dbPortal, nil, nil, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
+			s.now = func() time.Time { return now }
 
 			router := mux.NewRouter()
 			router.Methods(http.MethodPost).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/ssh/new").HandlerFunc(s.New)
@@ -193,6 +301,10 @@ func TestNew(t *testing.T) {
 			if string(b) != tt.wantBody {
 				t.Errorf("wanted %s but got %s", tt.wantBody, string(b))
 			}
+
+			if tt.wantAudit != (len(auditHook.AllEntries()) == 1) {
+				t.Error(auditHook.AllEntries())
+			}
 		})
 	}
 }
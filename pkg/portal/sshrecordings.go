@@ -0,0 +1,132 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/portal/ssh"
+)
+
+var rxRecordingName = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}-[0-9]+\.cast$`)
+
+// isElevated returns true if the user is in an elevated group
+func (p *portal) isElevated(r *http.Request) bool {
+	return len(middleware.GroupsIntersect(p.elevatedGroupIDs, r.Context().Value(middleware.ContextKeyGroups).([]string))) > 0
+}
+
+// requireElevated returns false and writes a 403 response if the user is not
+// in an elevated group
+func (p *portal) requireElevated(w http.ResponseWriter, r *http.Request) bool {
+	elevated := p.isElevated(r)
+	if !elevated {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	}
+
+	return elevated
+}
+
+// sshRecordings lists the recorded SSH sessions of a cluster, most recent
+// first
+func (p *portal) sshRecordings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !p.requireElevated(w, r) {
+		return
+	}
+
+	if p.sshRecordingStore == nil {
+		http.Error(w, "SSH session recording is not configured", http.StatusNotFound)
+		return
+	}
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.badRequest(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	recordings, err := p.sshRecordingStore.List(ctx, resourceID)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	if recordings == nil {
+		recordings = []*ssh.Recording{}
+	}
+
+	sort.SliceStable(recordings, func(i, j int) bool { return recordings[i].StartTime.After(recordings[j].StartTime) })
+
+	b, err := json.MarshalIndent(recordings, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// sshRecording returns a decrypted SSH session recording in asciicast format
+func (p *portal) sshRecording(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !p.requireElevated(w, r) {
+		return
+	}
+
+	if p.sshRecordingStore == nil {
+		http.Error(w, "SSH session recording is not configured", http.StatusNotFound)
+		return
+	}
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		p.badRequest(w, fmt.Errorf("invalid resource ID"))
+		return
+	}
+
+	name := apiVars["recording"]
+	if !rxRecordingName.MatchString(name) {
+		p.badRequest(w, fmt.Errorf("invalid recording name %q", name))
+		return
+	}
+
+	b, err := p.sshRecordingStore.Get(ctx, resourceID, name)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	b, err = p.aead.Open(b)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-asciicast")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	_, _ = w.Write(b)
+}
+
+// sshRecordingsPage serves the page which lists and plays back the recorded
+// SSH sessions of a cluster
+func (p *portal) sshRecordingsPage(w http.ResponseWriter, r *http.Request) {
+	if !p.requireElevated(w, r) {
+		return
+	}
+
+	p.serve("sshrecordings/index.html")(w, r)
+}
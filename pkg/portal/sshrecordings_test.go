@@ -0,0 +1,225 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Azure/ARO-RP/pkg/portal/ssh"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+type fakeRecordingStore struct {
+	recordings map[string][]*ssh.Recording
+	blobs      map[string][]byte
+}
+
+func (s *fakeRecordingStore) Put(ctx context.Context, resourceID string, recording *ssh.Recording, b []byte) error {
+	s.recordings[resourceID] = append(s.recordings[resourceID], recording)
+	s.blobs[resourceID+"/"+recording.Name] = b
+	return nil
+}
+
+func (s *fakeRecordingStore) List(ctx context.Context, resourceID string) ([]*ssh.Recording, error) {
+	return s.recordings[resourceID], nil
+}
+
+func (s *fakeRecordingStore) Get(ctx context.Context, resourceID, name string) ([]byte, error) {
+	b, found := s.blobs[resourceID+"/"+name]
+	if !found {
+		return nil, fmt.Errorf("not found")
+	}
+	return b, nil
+}
+
+func TestSSHRecordings(t *testing.T) {
+	ctx := context.Background()
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/resourcename"
+	apiPath := "/api/00000000-0000-0000-0000-000000000000/resourcegroup/resourcename/sshrecordings"
+	name := "03030303-0303-0303-0303-030303030001-0.cast"
+	cast := `{"version":2,"width":80,"height":24,"timestamp":1700000000}` + "\n" + `[0.5,"o","$ "]` + "\n"
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	_env := mock_env.NewMockCore(controller)
+	_env.EXPECT().IsLocalDevelopmentMode().AnyTimes().Return(false)
+	_env.EXPECT().Location().AnyTimes().Return("eastus")
+	_env.EXPECT().TenantID().AnyTimes().Return("00000000-0000-0000-0000-000000000001")
+	_env.EXPECT().Environment().AnyTimes().Return(&azureclient.PublicCloud)
+	_env.EXPECT().Hostname().AnyTimes().Return("testhost")
+
+	dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+	dbPortal, _ := testdatabase.NewFakePortal()
+
+	aead, err := encryption.NewXChaCha20Poly1305(ctx, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := aead.Seal([]byte(cast))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &fakeRecordingStore{
+		recordings: map[string][]*ssh.Recording{},
+		blobs:      map[string][]byte{},
+	}
+
+	older := &ssh.Recording{
+		Name:      "03030303-0303-0303-0303-030303030000-0.cast",
+		Username:  "username",
+		Hostname:  "master-0",
+		StartTime: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Duration:  1,
+	}
+	newer := &ssh.Recording{
+		Name:      name,
+		Username:  "username",
+		Hostname:  "cluster-worker-0",
+		StartTime: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Duration:  0.5,
+	}
+	for _, recording := range []*ssh.Recording{older, newer} {
+		err = store.Put(ctx, resourceID, recording, sealed)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := NewTestPortal(_env, dbOpenShiftClusters, dbPortal)
+	defer p.Cleanup()
+
+	p.p.aead = aead
+	p.p.sshRecordingStore = store
+
+	err = p.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name            string
+		path            string
+		elevated        bool
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "list",
+			path:            apiPath,
+			elevated:        true,
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/json",
+			wantBody: `[
+    {
+        "name": "03030303-0303-0303-0303-030303030001-0.cast",
+        "username": "username",
+        "hostname": "cluster-worker-0",
+        "startTime": "2023-01-02T00:00:00Z",
+        "duration": 0.5
+    },
+    {
+        "name": "03030303-0303-0303-0303-030303030000-0.cast",
+        "username": "username",
+        "hostname": "master-0",
+        "startTime": "2023-01-01T00:00:00Z",
+        "duration": 1
+    }
+]`,
+		},
+		{
+			name:            "list, not elevated",
+			path:            apiPath,
+			wantStatusCode:  http.StatusForbidden,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Forbidden\n",
+		},
+		{
+			name:            "get",
+			path:            apiPath + "/" + name,
+			elevated:        true,
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/x-asciicast",
+			wantBody:        cast,
+		},
+		{
+			name:            "get, not elevated",
+			path:            apiPath + "/" + name,
+			wantStatusCode:  http.StatusForbidden,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Forbidden\n",
+		},
+		{
+			name:            "get, invalid name",
+			path:            apiPath + "/other.cast",
+			elevated:        true,
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Bad Request\n",
+		},
+		{
+			name:            "page, not elevated",
+			path:            "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/sshrecordings",
+			wantStatusCode:  http.StatusForbidden,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Forbidden\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := p.Request(http.MethodGet, tt.path, true, tt.elevated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Error(resp.StatusCode)
+			}
+
+			if resp.Header.Get("Content-Type") != tt.wantContentType {
+				t.Error(resp.Header.Get("Content-Type"))
+			}
+
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != tt.wantBody {
+				t.Error(string(b))
+			}
+		})
+	}
+
+	t.Run("page", func(t *testing.T) {
+		resp, err := p.Request(http.MethodGet, "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/sshrecordings", true, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(b), "SSH session recordings") {
+			t.Error(resp.StatusCode, string(b))
+		}
+	})
+}
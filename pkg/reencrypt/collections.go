@@ -0,0 +1,152 @@
+package reencrypt
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+// pageSize is the number of documents read from a collection at a time.  The
+// progress marker is updated after each page.
+const pageSize = 100
+
+// collection adapts the document client of a collection with encrypted fields
+// so that the collections can be processed in the same way
+type collection interface {
+	name() string
+	list(continuation string) pager
+	newDocument() interface{}
+	id(interface{}) string
+	replace(context.Context, interface{}) error
+}
+
+// pager returns the documents of a collection a page at a time.  next returns
+// nil once there are no further pages.
+type pager interface {
+	next(context.Context) ([]interface{}, error)
+	continuation() string
+}
+
+type openShiftClusters struct {
+	c cosmosdb.OpenShiftClusterDocumentClient
+}
+
+func (openShiftClusters) name() string { return "OpenShiftClusters" }
+
+func (c openShiftClusters) list(continuation string) pager {
+	return openShiftClusterPager{c.c.List(&cosmosdb.Options{Continuation: continuation})}
+}
+
+func (openShiftClusters) newDocument() interface{} { return &api.OpenShiftClusterDocument{} }
+
+func (openShiftClusters) id(doc interface{}) string { return doc.(*api.OpenShiftClusterDocument).ID }
+
+func (c openShiftClusters) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.OpenShiftClusterDocument)
+	_, err := c.c.Replace(ctx, d.PartitionKey, d, &cosmosdb.Options{})
+	return err
+}
+
+type openShiftClusterPager struct {
+	i cosmosdb.OpenShiftClusterDocumentIterator
+}
+
+func (p openShiftClusterPager) next(ctx context.Context) ([]interface{}, error) {
+	docs, err := p.i.Next(ctx, pageSize)
+	if err != nil || docs == nil {
+		return nil, err
+	}
+
+	page := make([]interface{}, 0, len(docs.OpenShiftClusterDocuments))
+	for _, doc := range docs.OpenShiftClusterDocuments {
+		page = append(page, doc)
+	}
+
+	return page, nil
+}
+
+func (p openShiftClusterPager) continuation() string { return p.i.Continuation() }
+
+type portals struct {
+	c cosmosdb.PortalDocumentClient
+}
+
+func (portals) name() string { return "Portal" }
+
+func (c portals) list(continuation string) pager {
+	return portalPager{c.c.List(&cosmosdb.Options{Continuation: continuation})}
+}
+
+func (portals) newDocument() interface{} { return &api.PortalDocument{} }
+
+func (portals) id(doc interface{}) string { return doc.(*api.PortalDocument).ID }
+
+func (c portals) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.PortalDocument)
+	_, err := c.c.Replace(ctx, d.ID, d, &cosmosdb.Options{})
+	return err
+}
+
+type portalPager struct {
+	i cosmosdb.PortalDocumentIterator
+}
+
+func (p portalPager) next(ctx context.Context) ([]interface{}, error) {
+	docs, err := p.i.Next(ctx, pageSize)
+	if err != nil || docs == nil {
+		return nil, err
+	}
+
+	page := make([]interface{}, 0, len(docs.PortalDocuments))
+	for _, doc := range docs.PortalDocuments {
+		page = append(page, doc)
+	}
+
+	return page, nil
+}
+
+func (p portalPager) continuation() string { return p.i.Continuation() }
+
+type gateways struct {
+	c cosmosdb.GatewayDocumentClient
+}
+
+func (gateways) name() string { return "Gateway" }
+
+func (c gateways) list(continuation string) pager {
+	return gatewayPager{c.c.List(&cosmosdb.Options{Continuation: continuation})}
+}
+
+func (gateways) newDocument() interface{} { return &api.GatewayDocument{} }
+
+func (gateways) id(doc interface{}) string { return doc.(*api.GatewayDocument).ID }
+
+func (c gateways) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.GatewayDocument)
+	_, err := c.c.Replace(ctx, d.ID, d, &cosmosdb.Options{})
+	return err
+}
+
+type gatewayPager struct {
+	i cosmosdb.GatewayDocumentIterator
+}
+
+func (p gatewayPager) next(ctx context.Context) ([]interface{}, error) {
+	docs, err := p.i.Next(ctx, pageSize)
+	if err != nil || docs == nil {
+		return nil, err
+	}
+
+	page := make([]interface{}, 0, len(docs.GatewayDocuments))
+	for _, doc := range docs.GatewayDocuments {
+		page = append(page, doc)
+	}
+
+	return page, nil
+}
+
+func (p gatewayPager) continuation() string { return p.i.Continuation() }
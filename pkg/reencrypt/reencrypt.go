@@ -0,0 +1,322 @@
+package reencrypt
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ugorji/go/codec"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/metrics"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+// Reencrypter re-seals the encrypted fields of the OpenShiftClusters, Portal
+// and Gateway documents with the primary encryption key, so that the keys it
+// replaced can be retired
+type Reencrypter interface {
+	// Run re-seals every document which has a field that was not sealed with
+	// the primary key.  Progress is recorded per collection, and a run which
+	// is interrupted resumes where it left off.
+	Run(context.Context) error
+
+	// Check returns the number of documents which still have a field that
+	// was not sealed with the primary key
+	Check(context.Context) (int, error)
+}
+
+type reencrypter struct {
+	log *logrus.Entry
+	m   metrics.Emitter
+
+	dbKeyRotation database.KeyRotation
+	collections   []collection
+
+	// plain is the handle of the document clients, which leaves encrypted
+	// fields sealed.  tracking opens and seals them and counts the values
+	// it opens which were not sealed with the primary key.
+	plain    *codec.JsonHandle
+	tracking *codec.JsonHandle
+	tracker  *tracker
+
+	now func() time.Time
+}
+
+// NewReencrypter returns a new Reencrypter.  dbc must have been created without
+// an AEAD, so that documents are read and written with their encrypted fields
+// sealed.  aead must be the AEAD used by the other components, so that it can
+// open all the keys which are in use.
+func NewReencrypter(log *logrus.Entry, m metrics.Emitter, dbc cosmosdb.DatabaseClient, dbName string, dbKeyRotation database.KeyRotation, aead encryption.AEAD) (Reencrypter, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	return newReencrypter(log, m, dbKeyRotation, aead, []collection{
+		openShiftClusters{c: cosmosdb.NewOpenShiftClusterDocumentClient(collc, openShiftClusters{}.name())},
+		portals{c: cosmosdb.NewPortalDocumentClient(collc, portals{}.name())},
+		gateways{c: cosmosdb.NewGatewayDocumentClient(collc, gateways{}.name())},
+	})
+}
+
+func newReencrypter(log *logrus.Entry, m metrics.Emitter, dbKeyRotation database.KeyRotation, aead encryption.AEAD, collections []collection) (*reencrypter, error) {
+	rotating, ok := aead.(encryption.Rotating)
+	if !ok {
+		return nil, fmt.Errorf("AEAD cannot distinguish its primary key")
+	}
+
+	plain, err := database.NewJSONHandle(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tracker{Rotating: rotating}
+
+	tracking, err := database.NewJSONHandle(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reencrypter{
+		log: log,
+		m:   m,
+
+		dbKeyRotation: dbKeyRotation,
+		collections:   collections,
+
+		plain:    plain,
+		tracking: tracking,
+		tracker:  t,
+
+		now: time.Now,
+	}, nil
+}
+
+func (r *reencrypter) Run(ctx context.Context) error {
+	markers := make([]*api.KeyRotationDocument, len(r.collections))
+
+	// a run starts afresh once every collection has been completed, for
+	// example following a further rotation of the key.  Otherwise the
+	// collections which have already been completed are skipped.
+	restart := true
+	for i, c := range r.collections {
+		doc, err := r.dbKeyRotation.Get(ctx, strings.ToLower(c.name()))
+		if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+			return err
+		}
+		if err == nil {
+			markers[i] = doc
+		}
+
+		if markers[i] == nil || markers[i].KeyRotation.CompletedAt == nil {
+			restart = false
+		}
+	}
+
+	for i, c := range r.collections {
+		if !restart && markers[i] != nil && markers[i].KeyRotation.CompletedAt != nil {
+			r.log.Infof("%s: already completed", c.name())
+			continue
+		}
+
+		err := r.reencryptCollection(ctx, c, markers[i])
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.name(), err)
+		}
+	}
+
+	return nil
+}
+
+func (r *reencrypter) reencryptCollection(ctx context.Context, c collection, marker *api.KeyRotationDocument) (err error) {
+	switch {
+	case marker == nil:
+		marker, err = r.dbKeyRotation.Create(ctx, &api.KeyRotationDocument{
+			ID: strings.ToLower(c.name()),
+			KeyRotation: &api.KeyRotation{
+				Collection: c.name(),
+				StartedAt:  r.now().UTC(),
+			},
+		})
+
+	case marker.KeyRotation.CompletedAt != nil:
+		marker.KeyRotation = &api.KeyRotation{
+			Collection: c.name(),
+			StartedAt:  r.now().UTC(),
+		}
+		marker, err = r.dbKeyRotation.Update(ctx, marker)
+
+	default:
+		r.log.Infof("%s: resuming after %d documents", c.name(), marker.KeyRotation.Scanned)
+	}
+	if err != nil {
+		return err
+	}
+
+	p := c.list(marker.KeyRotation.Continuation)
+	for {
+		docs, err := p.next(ctx)
+		if err != nil {
+			return err
+		}
+		if docs == nil {
+			break
+		}
+
+		for _, doc := range docs {
+			resealed, err := r.reseal(ctx, c, doc)
+			if err != nil {
+				return fmt.Errorf("%s: %w", c.id(doc), err)
+			}
+
+			marker.KeyRotation.Scanned++
+			if resealed {
+				marker.KeyRotation.Resealed++
+			}
+		}
+
+		// a marker which is updated concurrently fails with
+		// StatusPreconditionFailed, which stops two runs from racing
+		marker.KeyRotation.Continuation = p.continuation()
+		marker, err = r.dbKeyRotation.Update(ctx, marker)
+		if err != nil {
+			return err
+		}
+	}
+
+	now := r.now().UTC()
+	marker.KeyRotation.CompletedAt = &now
+	marker, err = r.dbKeyRotation.Update(ctx, marker)
+	if err != nil {
+		return err
+	}
+
+	dims := map[string]string{
+		"collection": c.name(),
+	}
+	r.m.EmitGauge("reencrypt.documents.scanned", int64(marker.KeyRotation.Scanned), dims)
+	r.m.EmitGauge("reencrypt.documents.resealed", int64(marker.KeyRotation.Resealed), dims)
+	r.m.EmitGauge("reencrypt.completed", 1, dims)
+
+	r.log.Infof("%s: completed, %d documents scanned, %d re-sealed", c.name(), marker.KeyRotation.Scanned, marker.KeyRotation.Resealed)
+
+	return nil
+}
+
+func (r *reencrypter) Check(ctx context.Context) (int, error) {
+	var total int
+
+	for _, c := range r.collections {
+		var stale int
+
+		p := c.list("")
+		for {
+			docs, err := p.next(ctx)
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", c.name(), err)
+			}
+			if docs == nil {
+				break
+			}
+
+			for _, doc := range docs {
+				n, _, err := r.open(c, doc)
+				if err != nil {
+					return 0, fmt.Errorf("%s: %s: %w", c.name(), c.id(doc), err)
+				}
+
+				if n > 0 {
+					r.log.Warnf("%s: %s: %d fields not sealed with the primary key", c.name(), c.id(doc), n)
+					stale++
+				}
+			}
+		}
+
+		r.m.EmitGauge("reencrypt.documents.stale", int64(stale), map[string]string{
+			"collection": c.name(),
+		})
+
+		total += stale
+	}
+
+	return total, nil
+}
+
+// reseal replaces doc, which is held as stored, with a copy whose encrypted
+// fields are sealed with the primary key if any of them were not.  It returns
+// true if the document was replaced.
+func (r *reencrypter) reseal(ctx context.Context, c collection, doc interface{}) (bool, error) {
+	stale, opened, err := r.open(c, doc)
+	if err != nil || stale == 0 {
+		return false, err
+	}
+
+	sealed := c.newDocument()
+	err = convert(r.tracking, opened, r.plain, sealed)
+	if err != nil {
+		return false, err
+	}
+
+	err = c.replace(ctx, sealed)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed):
+		// the document has been written since it was read, and every writer
+		// seals with the primary key
+		return false, nil
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		// the document has been deleted since it was read
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+// open returns a copy of doc, which is held as stored, with its encrypted
+// fields opened.  It also returns the number of fields which were not sealed
+// with the primary key.
+func (r *reencrypter) open(c collection, doc interface{}) (int, interface{}, error) {
+	opened := c.newDocument()
+
+	r.tracker.stale = 0
+	err := convert(r.plain, doc, r.tracking, opened)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return r.tracker.stale, opened, nil
+}
+
+// convert encodes in with inHandle and decodes the result into out with
+// outHandle
+func convert(inHandle *codec.JsonHandle, in interface{}, outHandle *codec.JsonHandle, out interface{}) error {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, inHandle).Encode(in)
+	if err != nil {
+		return err
+	}
+
+	return codec.NewDecoderBytes(b, outHandle).Decode(out)
+}
+
+// tracker opens values with a Rotating AEAD, counting those which were not
+// sealed with its primary key.  It is not safe for concurrent use.
+type tracker struct {
+	encryption.Rotating
+	stale int
+}
+
+func (t *tracker) Open(input []byte) ([]byte, error) {
+	if !t.SealedWithPrimary(input) {
+		t.stale++
+	}
+
+	return t.Rotating.Open(input)
+}
@@ -0,0 +1,241 @@
+package reencrypt
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	mock_keyvault "github.com/Azure/ARO-RP/pkg/util/mocks/keyvault"
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestReencrypter(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+
+	primaryKey := bytes.Repeat([]byte{1}, 64)
+	legacyKey := bytes.Repeat([]byte{2}, 32)
+
+	primary, err := encryption.NewAES256SHA512(ctx, primaryKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, err := encryption.NewXChaCha20Poly1305(ctx, legacyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := database.NewJSONHandle(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sealed returns a cluster document as stored, with its encrypted fields
+	// sealed with aead
+	sealed := func(aead encryption.AEAD, id string) *api.OpenShiftClusterDocument {
+		h, err := database.NewJSONHandle(aead)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		doc := &api.OpenShiftClusterDocument{}
+		err = convert(h, &api.OpenShiftClusterDocument{
+			ID:           id,
+			Key:          "/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/" + id,
+			PartitionKey: "sub",
+			OpenShiftCluster: &api.OpenShiftCluster{
+				Properties: api.OpenShiftClusterProperties{
+					ClusterProfile: api.ClusterProfile{
+						PullSecret: api.SecureString("pull secret " + id),
+					},
+					AdminKubeconfig: api.SecureBytes("kubeconfig " + id),
+				},
+			},
+		}, plain, doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return doc
+	}
+
+	marker := func(name string, completedAt *time.Time) *api.KeyRotationDocument {
+		return &api.KeyRotationDocument{
+			ID: name,
+			KeyRotation: &api.KeyRotation{
+				Collection:  name,
+				StartedAt:   earlier,
+				CompletedAt: completedAt,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name          string
+		markers       []*api.KeyRotationDocument
+		wantResealed  map[string]bool
+		wantStale     int
+		wantCompleted []string
+	}{
+		{
+			name: "legacy documents are re-sealed",
+			wantResealed: map[string]bool{
+				"legacy":  true,
+				"primary": false,
+			},
+			wantCompleted: []string{"OpenShiftClusters", "Portal", "Gateway"},
+		},
+		{
+			name: "completed collections are skipped when resuming",
+			markers: []*api.KeyRotationDocument{
+				marker("openshiftclusters", &earlier),
+				marker("portal", nil),
+			},
+			wantResealed: map[string]bool{
+				"legacy":  false,
+				"primary": false,
+			},
+			wantStale:     1,
+			wantCompleted: []string{"Portal", "Gateway"},
+		},
+		{
+			name: "run restarts once every collection has completed",
+			markers: []*api.KeyRotationDocument{
+				marker("openshiftclusters", &earlier),
+				marker("portal", &earlier),
+				marker("gateway", &earlier),
+			},
+			wantResealed: map[string]bool{
+				"legacy":  true,
+				"primary": false,
+			},
+			wantCompleted: []string{"OpenShiftClusters", "Portal", "Gateway"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			kv := mock_keyvault.NewMockManager(controller)
+			kv.EXPECT().GetBase64Secret(gomock.Any(), "primary", "").Return(primaryKey, nil)
+			kv.EXPECT().GetBase64Secrets(gomock.Any(), "primary").Return([][]byte{primaryKey}, nil)
+			kv.EXPECT().GetBase64Secrets(gomock.Any(), "legacy").Return([][]byte{legacyKey}, nil)
+
+			aead, err := encryption.NewMulti(ctx, kv, "primary", "legacy")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := mock_metrics.NewMockEmitter(controller)
+			for _, name := range tt.wantCompleted {
+				dims := map[string]string{"collection": name}
+				m.EXPECT().EmitGauge("reencrypt.documents.scanned", gomock.Any(), dims)
+				m.EXPECT().EmitGauge("reencrypt.documents.resealed", gomock.Any(), dims)
+				m.EXPECT().EmitGauge("reencrypt.completed", int64(1), dims)
+			}
+			m.EXPECT().EmitGauge("reencrypt.documents.stale", gomock.Any(), gomock.Any()).AnyTimes()
+
+			clustersClient := cosmosdb.NewFakeOpenShiftClusterDocumentClient(plain)
+			stored := map[string]*api.OpenShiftClusterDocument{}
+			for id, aead := range map[string]encryption.AEAD{"legacy": legacy, "primary": primary} {
+				stored[id], err = clustersClient.Create(ctx, "sub", sealed(aead, id), nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			portalClient := cosmosdb.NewFakePortalDocumentClient(plain)
+			_, err = portalClient.Create(ctx, "portal", &api.PortalDocument{ID: "portal", Portal: &api.Portal{Username: "alice"}}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dbKeyRotation, _ := testdatabase.NewFakeKeyRotation()
+			for _, doc := range tt.markers {
+				_, err = dbKeyRotation.Create(ctx, doc)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r, err := newReencrypter(logrus.NewEntry(logrus.StandardLogger()), m, dbKeyRotation, aead, []collection{
+				openShiftClusters{c: clustersClient},
+				portals{c: portalClient},
+				gateways{c: cosmosdb.NewFakeGatewayDocumentClient(plain)},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.now = func() time.Time { return now }
+
+			stale, err := r.Check(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stale != 1 {
+				t.Errorf("stale before run: %d", stale)
+			}
+
+			err = r.Run(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for id, wantResealed := range tt.wantResealed {
+				doc, err := clustersClient.Get(ctx, "sub", id, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if resealed := doc.ETag != stored[id].ETag; resealed != wantResealed {
+					t.Errorf("%s: resealed %v", id, resealed)
+				}
+
+				// the document must still open to the same values
+				_, o, err := r.open(openShiftClusters{}, doc)
+				if err != nil {
+					t.Fatal(err)
+				}
+				opened := o.(*api.OpenShiftClusterDocument)
+				if string(opened.OpenShiftCluster.Properties.ClusterProfile.PullSecret) != "pull secret "+id ||
+					string(opened.OpenShiftCluster.Properties.AdminKubeconfig) != "kubeconfig "+id {
+					t.Errorf("%s: unexpected contents %v", id, opened.OpenShiftCluster.Properties)
+				}
+			}
+
+			for _, name := range tt.wantCompleted {
+				doc, err := dbKeyRotation.Get(ctx, strings.ToLower(name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if doc.KeyRotation.CompletedAt == nil || !doc.KeyRotation.CompletedAt.Equal(now) {
+					t.Errorf("%s: completed at %v", name, doc.KeyRotation.CompletedAt)
+				}
+			}
+
+			stale, err = r.Check(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stale != tt.wantStale {
+				t.Errorf("stale after run: %d", stale)
+			}
+		})
+	}
+}
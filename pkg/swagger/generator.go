@@ -52,6 +52,7 @@ type generator struct {
 	exampleOpenShiftClusterPutOrPatchResponse      func() interface{}
 	exampleOpenShiftClusterCredentialsResponse     func() interface{}
 	exampleOpenShiftClusterAdminKubeconfigResponse func() interface{}
+	exampleOpenShiftClusterUpgradePathsResponse    func() interface{}
 	exampleOpenShiftClusterListResponse            func() interface{}
 	exampleOpenShiftVersionListResponse            func() interface{}
 	exampleOperationListResponse                   func() interface{}
@@ -61,6 +62,7 @@ type generator struct {
 	installVersionList   bool
 	clusterManager       bool
 	workerProfilesStatus bool
+	upgradePaths         bool
 	xmsEnum              []string
 	xmsSecretList        []string
 	xmsIdentifiers       []string
@@ -313,10 +315,11 @@ var apis = map[string]*generator{
 		exampleOpenShiftClusterCredentialsResponse:     v20240812preview.ExampleOpenShiftClusterCredentialsResponse,
 		exampleOpenShiftClusterListResponse:            v20240812preview.ExampleOpenShiftClusterListResponse,
 		exampleOpenShiftClusterAdminKubeconfigResponse: v20240812preview.ExampleOpenShiftClusterAdminKubeconfigResponse,
+		exampleOpenShiftClusterUpgradePathsResponse:    v20240812preview.ExampleOpenShiftClusterUpgradePathsResponse,
 		exampleOpenShiftVersionListResponse:            v20240812preview.ExampleOpenShiftVersionListResponse,
 		exampleOperationListResponse:                   api.ExampleOperationListResponse,
 
-		xmsEnum:              []string{"ProvisioningState", "PreconfiguredNSG", "EncryptionAtHost", "FipsValidatedModules", "SoftwareDefinedNetwork", "Visibility", "OutboundType"},
+		xmsEnum:              []string{"ProvisioningState", "PreconfiguredNSG", "EncryptionAtHost", "FipsValidatedModules", "SoftwareDefinedNetwork", "Visibility", "OutboundType", "DeletionProtection"},
 		xmsSecretList:        []string{"kubeconfig", "kubeadminPassword", "secretResources"},
 		xmsIdentifiers:       []string{},
 		commonTypesVersion:   "v3",
@@ -325,6 +328,7 @@ var apis = map[string]*generator{
 		installVersionList:   true,
 		kubeConfig:           true,
 		workerProfilesStatus: true,
+		upgradePaths:         true,
 	},
 }
 
@@ -116,6 +116,19 @@ func Run(api, outputDir string) error {
 		}
 	}
 
+	if g.upgradePaths {
+		s.Paths["/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.RedHatOpenShift/openShiftClusters/{resourceName}/upgradePaths"] = &PathItem{
+			Get: &Operation{
+				Tags:        []string{"OpenShiftClusters"},
+				Summary:     "Lists the upgrade paths of an OpenShift cluster with the specified subscription, resource group and resource name.",
+				Description: "The operation returns the upgrades recommended from the cluster's current version.",
+				OperationID: "OpenShiftClusters_GetUpgradePaths",
+				Parameters:  g.populateParameters(3, "OpenShiftCluster", "OpenShift cluster"),
+				Responses:   g.populateResponses("OpenShiftClusterUpgradePaths", false, http.StatusOK),
+			},
+		}
+	}
+
 	if g.installVersionList {
 		s.Paths["/subscriptions/{subscriptionId}/providers/Microsoft.RedHatOpenShift/locations/{location}/openshiftversions"] = &PathItem{
 			Get: &Operation{
@@ -146,6 +159,10 @@ func Run(api, outputDir string) error {
 		names = append(names, "OpenShiftClusterAdminKubeconfig")
 	}
 
+	if g.upgradePaths {
+		names = append(names, "OpenShiftClusterUpgradePaths")
+	}
+
 	if g.installVersionList {
 		names = append(names, "OpenShiftVersionList")
 	}
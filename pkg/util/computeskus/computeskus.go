@@ -4,6 +4,8 @@ package computeskus
 // Licensed under the Apache License 2.0.
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
@@ -13,6 +15,8 @@ const (
 	standardDisk          = "StandardSSD_LRS"
 	premiumDisk           = "Premium_LRS"
 	premiumDiskCapability = "PremiumIO"
+	vCPUsCapability       = "vCPUs"
+	memoryGBCapability    = "MemoryGB"
 )
 
 // Zones returns zone information for the resource SKU
@@ -41,6 +45,47 @@ func HasCapability(sku *mgmtcompute.ResourceSku, capabilityName string) bool {
 	return false
 }
 
+// capability returns the raw value of a given capability of the resource SKU
+func capability(sku *mgmtcompute.ResourceSku, capabilityName string) (string, bool) {
+	if sku.Capabilities == nil {
+		return "", false
+	}
+
+	for _, c := range *sku.Capabilities {
+		if c.Name != nil && *c.Name == capabilityName && c.Value != nil {
+			return *c.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// VCPUs returns the number of vCPUs advertised by the resource SKU
+func VCPUs(sku *mgmtcompute.ResourceSku) (int, error) {
+	value, ok := capability(sku, vCPUsCapability)
+	if !ok {
+		return 0, fmt.Errorf("sku does not advertise the %s capability", vCPUsCapability)
+	}
+
+	return strconv.Atoi(value)
+}
+
+// MemoryMiB returns the amount of memory advertised by the resource SKU, in
+// MiB.  The SKU API reports memory in (possibly fractional) GB.
+func MemoryMiB(sku *mgmtcompute.ResourceSku) (int64, error) {
+	value, ok := capability(sku, memoryGBCapability)
+	if !ok {
+		return 0, fmt.Errorf("sku does not advertise the %s capability", memoryGBCapability)
+	}
+
+	gb, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(gb * 1024), nil
+}
+
 // IsRestricted checks whether given resource SKU is restricted in a given location
 func IsRestricted(skus map[string]*mgmtcompute.ResourceSku, location, VMSize string) bool {
 	for _, restriction := range *skus[VMSize].Restrictions {
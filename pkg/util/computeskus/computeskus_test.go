@@ -11,6 +11,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 
 	"github.com/Azure/ARO-RP/pkg/util/cmp"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
 )
 
 func TestZones(t *testing.T) {
@@ -294,3 +295,63 @@ func TestSupportedOSDisk(t *testing.T) {
 		})
 	}
 }
+
+func TestCapacity(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		capabilities  *[]mgmtcompute.ResourceSkuCapabilities
+		wantVCPUs     int
+		wantVCPUsErr  string
+		wantMemoryMiB int64
+		wantMemoryErr string
+	}{
+		{
+			name: "sku advertises capacity",
+			capabilities: &[]mgmtcompute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(vCPUsCapability), Value: to.StringPtr("4")},
+				{Name: to.StringPtr(memoryGBCapability), Value: to.StringPtr("16")},
+			},
+			wantVCPUs:     4,
+			wantMemoryMiB: 16384,
+		},
+		{
+			name: "sku advertises fractional memory",
+			capabilities: &[]mgmtcompute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(vCPUsCapability), Value: to.StringPtr("1")},
+				{Name: to.StringPtr(memoryGBCapability), Value: to.StringPtr("0.75")},
+			},
+			wantVCPUs:     1,
+			wantMemoryMiB: 768,
+		},
+		{
+			name: "sku advertises malformed capacity",
+			capabilities: &[]mgmtcompute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(vCPUsCapability), Value: to.StringPtr("four")},
+				{Name: to.StringPtr(memoryGBCapability), Value: to.StringPtr("sixteen")},
+			},
+			wantVCPUsErr:  `strconv.Atoi: parsing "four": invalid syntax`,
+			wantMemoryErr: `strconv.ParseFloat: parsing "sixteen": invalid syntax`,
+		},
+		{
+			name:          "sku does not advertise capacity",
+			wantVCPUsErr:  "sku does not advertise the vCPUs capability",
+			wantMemoryErr: "sku does not advertise the MemoryGB capability",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sku := &mgmtcompute.ResourceSku{Capabilities: tt.capabilities}
+
+			vcpus, err := VCPUs(sku)
+			utilerror.AssertErrorMessage(t, err, tt.wantVCPUsErr)
+			if vcpus != tt.wantVCPUs {
+				t.Errorf("got %d vCPUs but want %d", vcpus, tt.wantVCPUs)
+			}
+
+			memory, err := MemoryMiB(sku)
+			utilerror.AssertErrorMessage(t, err, tt.wantMemoryErr)
+			if memory != tt.wantMemoryMiB {
+				t.Errorf("got %d MiB but want %d", memory, tt.wantMemoryMiB)
+			}
+		})
+	}
+}
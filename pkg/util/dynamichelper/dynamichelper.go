@@ -260,6 +260,8 @@ func merge(old, new kruntime.Object) (kruntime.Object, bool, string, error) {
 	case *arov1alpha1.Cluster:
 		old, new := old.(*arov1alpha1.Cluster), new.(*arov1alpha1.Cluster)
 		new.Status = old.Status
+		// MachineHealthCheck policies are managed in-cluster, not by the RP
+		new.Spec.MachineHealthCheck = old.Spec.MachineHealthCheck
 
 	case *hivev1.ClusterDeployment:
 		old, new := old.(*hivev1.ClusterDeployment), new.(*hivev1.ClusterDeployment)
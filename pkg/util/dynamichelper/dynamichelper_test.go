@@ -531,6 +531,29 @@ func TestMerge(t *testing.T) {
 			},
 			wantEmptyDiff: true,
 		},
+		{
+			name: "Cluster preserves MachineHealthCheck policies",
+			old: &arov1alpha1.Cluster{
+				Spec: arov1alpha1.ClusterSpec{
+					MachineHealthCheck: arov1alpha1.MachineHealthCheckSpec{
+						Policies: []arov1alpha1.MachineHealthCheckPolicy{
+							{MachineSet: "cluster-worker-eastus1"},
+						},
+					},
+				},
+			},
+			new: &arov1alpha1.Cluster{},
+			want: &arov1alpha1.Cluster{
+				Spec: arov1alpha1.ClusterSpec{
+					MachineHealthCheck: arov1alpha1.MachineHealthCheckSpec{
+						Policies: []arov1alpha1.MachineHealthCheckPolicy{
+							{MachineSet: "cluster-worker-eastus1"},
+						},
+					},
+				},
+			},
+			wantEmptyDiff: true,
+		},
 		{
 			name: "CustomResourceDefinition Betav1 no changes",
 			old: &extensionsv1beta1.CustomResourceDefinition{
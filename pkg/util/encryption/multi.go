@@ -9,12 +9,23 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/keyvault"
 )
 
+// Rotating is an AEAD which seals with a single primary key but can also open
+// values sealed with other keys, such as the legacy secret or older versions
+// of the current secret
+type Rotating interface {
+	AEAD
+
+	// SealedWithPrimary returns true if input was sealed with the key used by
+	// Seal
+	SealedWithPrimary(input []byte) bool
+}
+
 type multi struct {
 	sealer  AEAD
 	openers []AEAD
 }
 
-var _ AEAD = (*multi)(nil)
+var _ Rotating = (*multi)(nil)
 
 func NewMulti(ctx context.Context, serviceKeyvault keyvault.Manager, secretName, legacySecretName string) (AEAD, error) {
 	key, err := serviceKeyvault.GetBase64Secret(ctx, secretName, "")
@@ -70,3 +81,8 @@ func (c *multi) Open(input []byte) (b []byte, err error) {
 func (c *multi) Seal(input []byte) ([]byte, error) {
 	return c.sealer.Seal(input)
 }
+
+func (c *multi) SealedWithPrimary(input []byte) bool {
+	_, err := c.sealer.Open(input)
+	return err == nil
+}
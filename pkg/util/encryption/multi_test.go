@@ -74,3 +74,38 @@ func TestOpen(t *testing.T) {
 		})
 	}
 }
+
+func TestSealedWithPrimary(t *testing.T) {
+	mockInput := []byte("fakeInput")
+
+	for _, tt := range []struct {
+		name  string
+		err   error
+		wantB bool
+	}{
+		{
+			name:  "primary opens input",
+			wantB: true,
+		},
+		{
+			name: "primary does not open input",
+			err:  errors.New("fake error from the sealer"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			sealer := mock_encryption.NewMockAEAD(controller)
+			sealer.EXPECT().Open(mockInput).Return(nil, tt.err)
+
+			multi := multi{
+				sealer: sealer,
+			}
+
+			if b := multi.SealedWithPrimary(mockInput); b != tt.wantB {
+				t.Error(b)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package liveconfig
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"os"
+)
+
+// AlertIngestionURL returns the regional RP endpoint
+// (https://rp.<location>.<rpParentDomainName>), which the RP VMSS deployment
+// writes into the aro-rp service environment. See
+// docs/alert-ingestion.md for the threat model of the endpoint.
+func (p *prod) AlertIngestionURL(ctx context.Context) string {
+	return os.Getenv(alertIngestionURLEnvVar)
+}
@@ -79,3 +79,7 @@ func (d *dev) UseCheckAccess(ctx context.Context) (bool, error) {
 	}
 	return false, nil
 }
+
+func (d *dev) AlertIngestionURL(ctx context.Context) string {
+	return os.Getenv(alertIngestionURLEnvVar)
+}
@@ -18,6 +18,7 @@ const (
 	hiveDefaultPullSpecEnvVar = "ARO_HIVE_DEFAULT_INSTALLER_PULLSPEC"
 	hiveAdoptEnableEnvVar     = "ARO_ADOPT_BY_HIVE"
 	useCheckAccess            = "USE_CHECKACCESS"
+	alertIngestionURLEnvVar   = "ARO_ALERT_INGESTION_URL"
 )
 
 type Manager interface {
@@ -26,6 +27,11 @@ type Manager interface {
 	AdoptByHive(context.Context) (bool, error)
 	UseCheckAccess(context.Context) (bool, error)
 
+	// AlertIngestionURL returns the base URL to which clusters forward
+	// critical platform alerts, or an empty string if alert forwarding is
+	// disabled
+	AlertIngestionURL(context.Context) string
+
 	// Allows overriding the default installer pullspec for Prod, if the OpenShiftVersions database is not populated
 	DefaultInstallerPullSpecOverride(context.Context) string
 }
@@ -5,9 +5,11 @@ package steps
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
@@ -41,13 +43,61 @@ type Step interface {
 	metricsName() string
 }
 
+// ErrDraining is returned by Run when the context it was given was derived
+// from ContextWithDrain and draining started before the next step
+var ErrDraining = errors.New("draining")
+
+type currentStepKey struct{}
+
+type drainKey struct{}
+
+// ContextWithCurrentStep returns a context in which Run records each step as
+// it starts, and a function which returns the last step recorded
+func ContextWithCurrentStep(ctx context.Context) (context.Context, func() string) {
+	var current atomic.Value
+	current.Store("")
+
+	return context.WithValue(ctx, currentStepKey{}, &current), func() string {
+		return current.Load().(string)
+	}
+}
+
+// ContextWithDrain returns a context in which Run stops with ErrDraining
+// between steps once drain is closed.  The steps run so far are not undone;
+// like any other interrupted run, the steps must be safe to run again.
+func ContextWithDrain(ctx context.Context, drain <-chan struct{}) context.Context {
+	return context.WithValue(ctx, drainKey{}, drain)
+}
+
+func draining(ctx context.Context) bool {
+	drain, ok := ctx.Value(drainKey{}).(<-chan struct{})
+	if !ok {
+		return false
+	}
+
+	select {
+	case <-drain:
+		return true
+	default:
+		return false
+	}
+}
+
 // Run executes the provided steps in order until one fails or all steps
 // are completed. Errors from failed steps are returned directly.
 // time cost for each step run will be recorded for metrics usage
 func Run(ctx context.Context, log *logrus.Entry, pollInterval time.Duration, steps []Step, now func() time.Time) (map[string]int64, error) {
 	stepTimeRun := make(map[string]int64)
 	for _, step := range steps {
+		if draining(ctx) {
+			log.Infof("draining before step %s", step)
+			return nil, ErrDraining
+		}
+
 		log.Infof("running step %s", step)
+		if current, ok := ctx.Value(currentStepKey{}).(*atomic.Value); ok {
+			current.Store(step.String())
+		}
 
 		startTime := time.Now()
 		err := step.run(ctx, log)
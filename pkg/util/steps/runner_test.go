@@ -277,3 +277,47 @@ func TestStepMetricsNameFormatting(t *testing.T) {
 		})
 	}
 }
+
+func TestContextWithCurrentStep(t *testing.T) {
+	ctx, currentStep := ContextWithCurrentStep(context.Background())
+	if currentStep() != "" {
+		t.Errorf("got step %q before running", currentStep())
+	}
+
+	_, err := Run(ctx, logrus.NewEntry(logrus.StandardLogger()), 25*time.Millisecond, []Step{
+		Action(successfulFunc),
+		Action(failingFunc),
+		Action(successfulFunc),
+	}, nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	want := "[Action github.com/Azure/ARO-RP/pkg/util/steps.failingFunc]"
+	if currentStep() != want {
+		t.Errorf("got step %q, wanted %q", currentStep(), want)
+	}
+}
+
+func TestContextWithDrain(t *testing.T) {
+	drain := make(chan struct{})
+	ctx := ContextWithDrain(context.Background(), drain)
+
+	var runs int
+	drainingFunc := func(context.Context) error {
+		runs++
+		close(drain)
+		return nil
+	}
+
+	_, err := Run(ctx, logrus.NewEntry(logrus.StandardLogger()), 25*time.Millisecond, []Step{
+		Action(drainingFunc),
+		Action(drainingFunc),
+	}, nil)
+	if err != ErrDraining {
+		t.Errorf("got error %v", err)
+	}
+	if runs != 1 {
+		t.Errorf("got %d runs", runs)
+	}
+}
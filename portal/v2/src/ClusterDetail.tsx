@@ -75,6 +75,8 @@ export const kcmStatisticsKey = "kcmstatistics"
 export const dnsStatisticsKey = "dnsstatistics"
 export const ingressStatisticsKey = "ingressstatistics"
 export const clusterOperatorsKey = "clusteroperators"
+export const alertsKey = "alerts"
+export const auditLogKey = "auditlog"
 
 const errorBarStyles: Partial<IMessageBarStyles> = { root: { marginBottom: 15 } }
 
@@ -182,6 +184,18 @@ export function ClusterDetailPanel(props: {
           url: clusterOperatorsKey,
           icon: 'Shapes',
         },
+        {
+          name: 'Alerts',
+          key: alertsKey,
+          url: alertsKey,
+          icon: 'Warning',
+        },
+        {
+          name: 'AuditLog',
+          key: auditLogKey,
+          url: auditLogKey,
+          icon: 'History',
+        },
       ],
     },
   ]
@@ -7,9 +7,11 @@ import { MachinesWrapper } from "./ClusterDetailListComponents/MachinesWrapper"
 import { MachineSetsWrapper } from "./ClusterDetailListComponents/MachineSetsWrapper"
 import { Statistics } from "./ClusterDetailListComponents/Statistics/Statistics"
 import { ClusterOperatorsWrapper } from "./ClusterDetailListComponents/ClusterOperatorsWrapper";
+import { AlertsWrapper } from "./ClusterDetailListComponents/AlertsWrapper"
+import { AuditLogWrapper } from "./ClusterDetailListComponents/AuditLogWrapper"
 
 import { IClusterCoordinates } from "./App"
-import { apiStatisticsKey, clusterOperatorsKey, dnsStatisticsKey, ingressStatisticsKey, kcmStatisticsKey, machineSetsKey, machinesKey, nodesKey, overviewKey } from "./ClusterDetail"
+import { alertsKey, apiStatisticsKey, auditLogKey, clusterOperatorsKey, dnsStatisticsKey, ingressStatisticsKey, kcmStatisticsKey, machineSetsKey, machinesKey, nodesKey, overviewKey } from "./ClusterDetail"
 
 interface ClusterDetailComponentProps {
   item: IClusterDetails
@@ -57,6 +59,8 @@ export function ClusterDetailComponent(props: ClusterDetailComponentProps) {
       <Route path="dnsstatistics" element={<Statistics currentCluster={props.cluster!} detailPanelSelected={dnsStatisticsKey} loaded={props.isDataLoaded} statisticsType="dns" />} />
       <Route path="ingressstatistics" element={<Statistics currentCluster={props.cluster!} detailPanelSelected={ingressStatisticsKey} loaded={props.isDataLoaded} statisticsType="ingress" />} />
       <Route path="clusteroperators" element={<ClusterOperatorsWrapper currentCluster={props.cluster!} detailPanelSelected={clusterOperatorsKey} loaded={props.isDataLoaded} />} />
+      <Route path="alerts" element={<AlertsWrapper currentCluster={props.cluster!} detailPanelSelected={alertsKey} loaded={props.isDataLoaded} />} />
+      <Route path="auditlog" element={<AuditLogWrapper currentCluster={props.cluster!} detailPanelSelected={auditLogKey} loaded={props.isDataLoaded} />} />
     </Routes>
   )
 }
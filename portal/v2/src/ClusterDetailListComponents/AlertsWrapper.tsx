@@ -0,0 +1,119 @@
+import { useState, useEffect } from "react"
+import { AxiosResponse } from "axios"
+import { fetchAlerts } from "../Request"
+import {
+  IMessageBarStyles,
+  MessageBar,
+  MessageBarType,
+  Stack,
+  CommandBar,
+  ICommandBarItemProps,
+  SelectionMode,
+} from "@fluentui/react"
+import { IColumn } from "@fluentui/react/lib/DetailsList"
+import { ShimmeredDetailsList } from "@fluentui/react/lib/ShimmeredDetailsList"
+import { alertsKey } from "../ClusterDetail"
+import { WrapperProps } from "../ClusterDetailList"
+
+export interface IAlert {
+  name: string
+  status: string
+  severity: string
+  namespace: string
+  summary: string
+  startsAt: string
+  receivedAt: string
+}
+
+const columns: IColumn[] = [
+  { key: "alertName", name: "Name", fieldName: "name", minWidth: 150, maxWidth: 250, isResizable: true },
+  { key: "alertStatus", name: "Status", fieldName: "status", minWidth: 70, maxWidth: 70, isResizable: true },
+  { key: "alertNamespace", name: "Namespace", fieldName: "namespace", minWidth: 150, maxWidth: 250, isResizable: true },
+  { key: "alertSummary", name: "Summary", fieldName: "summary", minWidth: 200, isResizable: true, isMultiline: true },
+  { key: "alertStartsAt", name: "Started", fieldName: "startsAt", minWidth: 150, maxWidth: 150, isResizable: true },
+  { key: "alertReceivedAt", name: "Last received", fieldName: "receivedAt", minWidth: 150, maxWidth: 150, isResizable: true },
+]
+
+export function AlertsWrapper(props: WrapperProps) {
+  const [alerts, setAlerts] = useState<IAlert[]>([])
+  const [error, setError] = useState<AxiosResponse | null>(null)
+  const [fetching, setFetching] = useState("")
+
+  const errorBarStyles: Partial<IMessageBarStyles> = { root: { marginBottom: 15 } }
+
+  const errorBar = (): any => {
+    return (
+      <MessageBar
+        messageBarType={MessageBarType.error}
+        isMultiline={false}
+        onDismiss={() => setError(null)}
+        dismissButtonAriaLabel="Close"
+        styles={errorBarStyles}>
+        {error?.statusText}
+      </MessageBar>
+    )
+  }
+
+  const controlStyles = {
+    root: {
+      paddingLeft: 0,
+      float: "right",
+    },
+  }
+
+  const _items: ICommandBarItemProps[] = [
+    {
+      key: "refresh",
+      text: "Refresh",
+      iconProps: { iconName: "Refresh" },
+      onClick: () => {
+        setAlerts([])
+        setFetching("")
+      },
+    },
+  ]
+
+  useEffect(() => {
+    const onData = (result: AxiosResponse | null) => {
+      if (result?.status === 200) {
+        setAlerts(result.data)
+      } else {
+        setError(result)
+      }
+      if (props.currentCluster) {
+        setFetching(props.currentCluster.name)
+      }
+    }
+
+    if (props.detailPanelSelected.toLowerCase() == alertsKey &&
+        fetching === "" &&
+        props.loaded &&
+        props.currentCluster) {
+      setFetching("FETCHING")
+      fetchAlerts(props.currentCluster).then(onData)
+    }
+  }, [alerts, fetching, props.loaded, props.detailPanelSelected])
+
+  return (
+    <Stack>
+      <Stack.Item grow>{error && errorBar()}</Stack.Item>
+      <Stack>
+        <CommandBar
+          items={_items}
+          ariaLabel="Refresh"
+          styles={controlStyles}
+        />
+        <ShimmeredDetailsList
+          setKey="alertList"
+          compact={true}
+          items={alerts}
+          columns={columns}
+          selectionMode={SelectionMode.none}
+          enableShimmer={fetching === "FETCHING"}
+          ariaLabelForShimmer="Content is being fetched"
+          ariaLabelForGrid="Item details"
+        />
+      </Stack>
+    </Stack>
+  )
+}
@@ -0,0 +1,118 @@
+import { useState, useEffect } from "react"
+import { AxiosResponse } from "axios"
+import { fetchAuditLog } from "../Request"
+import {
+  IMessageBarStyles,
+  MessageBar,
+  MessageBarType,
+  Stack,
+  CommandBar,
+  ICommandBarItemProps,
+  SelectionMode,
+} from "@fluentui/react"
+import { IColumn } from "@fluentui/react/lib/DetailsList"
+import { ShimmeredDetailsList } from "@fluentui/react/lib/ShimmeredDetailsList"
+import { auditLogKey } from "../ClusterDetail"
+import { WrapperProps } from "../ClusterDetailList"
+
+export interface IAuditEntry {
+  callerIdentity: string
+  method: string
+  path: string
+  statusCode: number
+  startedAt: string
+  duration: number
+}
+
+const columns: IColumn[] = [
+  { key: "auditStartedAt", name: "Started", fieldName: "startedAt", minWidth: 150, maxWidth: 150, isResizable: true },
+  { key: "auditCallerIdentity", name: "Caller", fieldName: "callerIdentity", minWidth: 150, maxWidth: 250, isResizable: true },
+  { key: "auditMethod", name: "Method", fieldName: "method", minWidth: 60, maxWidth: 60, isResizable: true },
+  { key: "auditPath", name: "Path", fieldName: "path", minWidth: 200, isResizable: true, isMultiline: true },
+  { key: "auditStatusCode", name: "Result", fieldName: "statusCode", minWidth: 50, maxWidth: 50, isResizable: true },
+  { key: "auditDuration", name: "Duration (s)", fieldName: "duration", minWidth: 80, maxWidth: 80, isResizable: true },
+]
+
+export function AuditLogWrapper(props: WrapperProps) {
+  const [entries, setEntries] = useState<IAuditEntry[]>([])
+  const [error, setError] = useState<AxiosResponse | null>(null)
+  const [fetching, setFetching] = useState("")
+
+  const errorBarStyles: Partial<IMessageBarStyles> = { root: { marginBottom: 15 } }
+
+  const errorBar = (): any => {
+    return (
+      <MessageBar
+        messageBarType={MessageBarType.error}
+        isMultiline={false}
+        onDismiss={() => setError(null)}
+        dismissButtonAriaLabel="Close"
+        styles={errorBarStyles}>
+        {error?.statusText}
+      </MessageBar>
+    )
+  }
+
+  const controlStyles = {
+    root: {
+      paddingLeft: 0,
+      float: "right",
+    },
+  }
+
+  const _items: ICommandBarItemProps[] = [
+    {
+      key: "refresh",
+      text: "Refresh",
+      iconProps: { iconName: "Refresh" },
+      onClick: () => {
+        setEntries([])
+        setFetching("")
+      },
+    },
+  ]
+
+  useEffect(() => {
+    const onData = (result: AxiosResponse | null) => {
+      if (result?.status === 200) {
+        setEntries(result.data)
+      } else {
+        setError(result)
+      }
+      if (props.currentCluster) {
+        setFetching(props.currentCluster.name)
+      }
+    }
+
+    if (props.detailPanelSelected.toLowerCase() == auditLogKey &&
+        fetching === "" &&
+        props.loaded &&
+        props.currentCluster) {
+      setFetching("FETCHING")
+      fetchAuditLog(props.currentCluster).then(onData)
+    }
+  }, [entries, fetching, props.loaded, props.detailPanelSelected])
+
+  return (
+    <Stack>
+      <Stack.Item grow>{error && errorBar()}</Stack.Item>
+      <Stack>
+        <CommandBar
+          items={_items}
+          ariaLabel="Refresh"
+          styles={controlStyles}
+        />
+        <ShimmeredDetailsList
+          setKey="auditLogList"
+          compact={true}
+          items={entries}
+          columns={columns}
+          selectionMode={SelectionMode.none}
+          enableShimmer={fetching === "FETCHING"}
+          ariaLabelForShimmer="Content is being fetched"
+          ariaLabelForGrid="Item details"
+        />
+      </Stack>
+    </Stack>
+  )
+}
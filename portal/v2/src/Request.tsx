@@ -25,6 +25,36 @@ export const fetchClusters = async (): Promise<AxiosResponse | null> => {
   }
 }
 
+export const fetchFleetSummary = async (): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios("/api/fleet/summary")
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
+export const fetchFleetAdminUpdateErrors = async (): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios("/api/fleet/adminupdateerrors")
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
+export const fetchFleetCreating = async (minutes: number): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios("/api/fleet/creating", { params: { minutes: minutes } })
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
 export const fetchClusterInfo = async (cluster: IClusterCoordinates): Promise<AxiosResponse | null> => {
   try {
     const result = await axios(
@@ -95,6 +125,54 @@ export const fetchMachineSets = async (cluster: IClusterCoordinates): Promise<Ax
   }
 }
 
+export const fetchEvents = async (
+  cluster: IClusterCoordinates,
+  filter: { namespace?: string; kind?: string; name?: string }
+): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(
+      `/api/${cluster.subscription}/${cluster.resourceGroup}/${cluster.name}/events`,
+      { params: filter }
+    )
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
+export const fetchPodLogs = async (
+  cluster: IClusterCoordinates,
+  namespace: string,
+  pod: string,
+  options: { container?: string; tail?: number; previous?: boolean }
+): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(
+      `/api/${cluster.subscription}/${cluster.resourceGroup}/${cluster.name}/pods/${namespace}/${pod}/logs`,
+      { params: options, responseType: "text" }
+    )
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
+export const fetchClusterVersionHistory = async (
+  cluster: IClusterCoordinates
+): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(
+      `/api/${cluster.subscription}/${cluster.resourceGroup}/${cluster.name}/clusterversion/history`
+    )
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
 export const fetchClusterOperators = async (cluster: IClusterCoordinates): Promise<AxiosResponse | null> => {
   try {
     const result = await axios(
@@ -106,6 +184,28 @@ export const fetchClusterOperators = async (cluster: IClusterCoordinates): Promi
   }
 }
 
+export const fetchAlerts = async (cluster: IClusterCoordinates): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(
+      ["/api", cluster.subscription, cluster.resourceGroup, cluster.name, "alerts"].join("/"))
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
+export const fetchAuditLog = async (cluster: IClusterCoordinates): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(
+      ["/api", cluster.subscription, cluster.resourceGroup, cluster.name, "auditlog"].join("/"))
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
 export const fetchRegions = async (): Promise<AxiosResponse | null> => {
   try {
     const result = await axios("/api/regions")
@@ -129,12 +229,23 @@ export const ProcessLogOut = async (): Promise<any> => {
 
 export const RequestKubeconfig = async (
   csrfToken: string,
-  resourceID: string
+  resourceID: string,
+  scope?: string,
+  namespaces?: string[]
 ): Promise<AxiosResponse | null> => {
+  const params = new URLSearchParams()
+  if (scope) {
+    params.append("scope", scope)
+  }
+  for (const namespace of namespaces || []) {
+    params.append("namespace", namespace)
+  }
+
   try {
     const result = await axios({
       method: "POST",
       url: resourceID + "/kubeconfig/new",
+      params: params,
       headers: {
         "X-CSRF-Token": csrfToken,
       },
@@ -146,17 +257,33 @@ export const RequestKubeconfig = async (
   }
 }
 
+export const fetchStatisticsCatalog = async (
+  cluster: IClusterCoordinates
+): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(
+      `/api/${cluster.subscription}/${cluster.resourceGroup}/${cluster.name}/statistics`
+    )
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
 export const fetchStatistics = async (
   cluster: IClusterCoordinates,
   statisticsName: string,
   duration: string,
-  endDate: Date
+  endDate: Date,
+  parameters: Record<string, string> = {}
 ): Promise<AxiosResponse | null> => {
   duration = convertTimeToHours(duration)
   let endDateJSON = endDate.toJSON()
+  const params = new URLSearchParams({ ...parameters, duration: duration, endtime: endDateJSON })
   try {
     const result = await axios(
-      `/api/${cluster.subscription}/${cluster.resourceGroup}/${cluster.name}/statistics/${statisticsName}?duration=${duration}&endtime=${endDateJSON}`
+      `/api/${cluster.subscription}/${cluster.resourceGroup}/${cluster.name}/statistics/${statisticsName}?${params.toString()}`
     )
     return result
   } catch (e: any) {
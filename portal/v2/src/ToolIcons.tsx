@@ -24,6 +24,7 @@ export const ToolIcons = forwardRef<any, ToolIconsProps>(
     const [data, setData] = useState<FileDownload>({ name: "", content: "" })
     const [error, setError] = useState<AxiosResponse | null>(null)
     const [fetching, setFetching] = useState("DONE")
+    const [scope, setScope] = useState("")
     const buttonRef = useRef<HTMLAnchorElement | null>(null)
 
     useEffect(() => {
@@ -42,9 +43,9 @@ export const ToolIcons = forwardRef<any, ToolIconsProps>(
 
       if (fetching === "") {
         setFetching("FETCHING")
-        RequestKubeconfig(csrfToken.current, resourceId).then(onData)
+        RequestKubeconfig(csrfToken.current, resourceId, scope).then(onData)
       }
-    }, [fetching, error, data, resourceId, csrfToken])
+    }, [fetching, error, data, resourceId, csrfToken, scope])
 
     const _onCopyResourceID = (resourceId: any) => {
       navigator.clipboard.writeText(resourceId)
@@ -97,12 +98,26 @@ export const ToolIcons = forwardRef<any, ToolIconsProps>(
             iconProps={{ iconName: "kubernetes-svg" }}
             disabled={fetching === "FETCHING"}
             aria-label="Download Kubeconfig"
-            onClick={() => setFetching("")}
+            onClick={() => {
+              setScope("")
+              setFetching("")
+            }}
           />
           <a style={{ display: "none" }} ref={buttonRef} href={"#"}>
             dl
           </a>
         </TooltipHost>
+        <TooltipHost content={`Download Read-only Kubeconfig`}>
+          <IconButton
+            iconProps={{ iconName: "ReadingMode" }}
+            disabled={fetching === "FETCHING"}
+            aria-label="Download Read-only Kubeconfig"
+            onClick={() => {
+              setScope("readonly")
+              setFetching("")
+            }}
+          />
+        </TooltipHost>
       </>
     )
   }
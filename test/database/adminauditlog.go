@@ -0,0 +1,70 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-test/deep"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func fakeAdminAuditLogListByKeyQuery(client cosmosdb.AdminAuditEntryDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.AdminAuditEntryDocumentRawIterator {
+	input, err := client.ListAll(context.Background(), options)
+	if err != nil {
+		// TODO: should this never happen?
+		panic(err)
+	}
+
+	var results []*api.AdminAuditEntryDocument
+	for _, r := range input.AdminAuditEntryDocuments {
+		if r.Key == query.Parameters[0].Value {
+			results = append(results, r)
+		}
+	}
+	return cosmosdb.NewFakeAdminAuditEntryDocumentIterator(results, 0)
+}
+
+func injectAdminAuditLog(c *cosmosdb.FakeAdminAuditEntryDocumentClient) {
+	c.SetQueryHandler(database.AdminAuditLogListByKeyQuery, fakeAdminAuditLogListByKeyQuery)
+}
+
+func (f *Checker) AddAdminAuditEntryDocuments(docs ...*api.AdminAuditEntryDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.adminAuditEntryDocuments = append(f.adminAuditEntryDocuments, docCopy.(*api.AdminAuditEntryDocument))
+	}
+}
+
+func (f *Checker) CheckAdminAuditLog(adminAuditLog *cosmosdb.FakeAdminAuditEntryDocumentClient) (errs []error) {
+	ctx := context.Background()
+
+	all, err := adminAuditLog.ListAll(ctx, nil)
+	if err != nil {
+		return []error{err}
+	}
+
+	sort.Slice(all.AdminAuditEntryDocuments, func(i, j int) bool { return all.AdminAuditEntryDocuments[i].ID < all.AdminAuditEntryDocuments[j].ID })
+
+	if len(f.adminAuditEntryDocuments) != 0 && len(all.AdminAuditEntryDocuments) == len(f.adminAuditEntryDocuments) {
+		diff := deep.Equal(all.AdminAuditEntryDocuments, f.adminAuditEntryDocuments)
+		for _, i := range diff {
+			errs = append(errs, errors.New(i))
+		}
+	} else if len(all.AdminAuditEntryDocuments) != 0 || len(f.adminAuditEntryDocuments) != 0 {
+		errs = append(errs, fmt.Errorf("adminAuditLog length different, %d vs %d", len(all.AdminAuditEntryDocuments), len(f.adminAuditEntryDocuments)))
+	}
+
+	return errs
+}
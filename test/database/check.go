@@ -26,6 +26,9 @@ type Checker struct {
 	gatewayDocuments          []*api.GatewayDocument
 	openShiftVersionDocuments []*api.OpenShiftVersionDocument
 	validationResult          []*api.ValidationResult
+	clusterAlertDocuments     []*api.ClusterAlertDocument
+	pendingActionDocuments    []*api.PendingActionDocument
+	adminAuditEntryDocuments  []*api.AdminAuditEntryDocument
 }
 
 func NewChecker() *Checker {
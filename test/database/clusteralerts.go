@@ -0,0 +1,70 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-test/deep"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func fakeClusterAlertsListByKeyQuery(client cosmosdb.ClusterAlertDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.ClusterAlertDocumentRawIterator {
+	input, err := client.ListAll(context.Background(), options)
+	if err != nil {
+		// TODO: should this never happen?
+		panic(err)
+	}
+
+	var results []*api.ClusterAlertDocument
+	for _, r := range input.ClusterAlertDocuments {
+		if r.Key == query.Parameters[0].Value {
+			results = append(results, r)
+		}
+	}
+	return cosmosdb.NewFakeClusterAlertDocumentIterator(results, 0)
+}
+
+func injectClusterAlerts(c *cosmosdb.FakeClusterAlertDocumentClient) {
+	c.SetQueryHandler(database.ClusterAlertsListByKeyQuery, fakeClusterAlertsListByKeyQuery)
+}
+
+func (f *Checker) AddClusterAlertDocuments(docs ...*api.ClusterAlertDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.clusterAlertDocuments = append(f.clusterAlertDocuments, docCopy.(*api.ClusterAlertDocument))
+	}
+}
+
+func (f *Checker) CheckClusterAlerts(clusterAlerts *cosmosdb.FakeClusterAlertDocumentClient) (errs []error) {
+	ctx := context.Background()
+
+	all, err := clusterAlerts.ListAll(ctx, nil)
+	if err != nil {
+		return []error{err}
+	}
+
+	sort.Slice(all.ClusterAlertDocuments, func(i, j int) bool { return all.ClusterAlertDocuments[i].ID < all.ClusterAlertDocuments[j].ID })
+
+	if len(f.clusterAlertDocuments) != 0 && len(all.ClusterAlertDocuments) == len(f.clusterAlertDocuments) {
+		diff := deep.Equal(all.ClusterAlertDocuments, f.clusterAlertDocuments)
+		for _, i := range diff {
+			errs = append(errs, errors.New(i))
+		}
+	} else if len(all.ClusterAlertDocuments) != 0 || len(f.clusterAlertDocuments) != 0 {
+		errs = append(errs, fmt.Errorf("clusterAlerts length different, %d vs %d", len(all.ClusterAlertDocuments), len(f.clusterAlertDocuments)))
+	}
+
+	return errs
+}
@@ -20,6 +20,8 @@ type Fixture struct {
 	gatewayDocuments                     []*api.GatewayDocument
 	openShiftVersionDocuments            []*api.OpenShiftVersionDocument
 	clusterManagerConfigurationDocuments []*api.ClusterManagerConfigurationDocument
+	pendingActionDocuments               []*api.PendingActionDocument
+	adminAuditEntryDocuments             []*api.AdminAuditEntryDocument
 
 	openShiftClustersDatabase            database.OpenShiftClusters
 	billingDatabase                      database.Billing
@@ -29,6 +31,8 @@ type Fixture struct {
 	gatewayDatabase                      database.Gateway
 	openShiftVersionsDatabase            database.OpenShiftVersions
 	clusterManagerConfigurationsDatabase database.ClusterManagerConfigurations
+	pendingActionsDatabase               database.PendingActions
+	adminAuditLogDatabase                database.AdminAuditLog
 
 	openShiftVersionsUUID uuid.Generator
 }
@@ -67,6 +71,16 @@ func (f *Fixture) WithPortal(db database.Portal) *Fixture {
 	return f
 }
 
+func (f *Fixture) WithPendingActions(db database.PendingActions) *Fixture {
+	f.pendingActionsDatabase = db
+	return f
+}
+
+func (f *Fixture) WithAdminAuditLog(db database.AdminAuditLog) *Fixture {
+	f.adminAuditLogDatabase = db
+	return f
+}
+
 func (f *Fixture) WithGateway(db database.Gateway) *Fixture {
 	f.gatewayDatabase = db
 	return f
@@ -122,6 +136,28 @@ func (f *Fixture) AddAsyncOperationDocuments(docs ...*api.AsyncOperationDocument
 	}
 }
 
+func (f *Fixture) AddPendingActionDocuments(docs ...*api.PendingActionDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.pendingActionDocuments = append(f.pendingActionDocuments, docCopy.(*api.PendingActionDocument))
+	}
+}
+
+func (f *Fixture) AddAdminAuditEntryDocuments(docs ...*api.AdminAuditEntryDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.adminAuditEntryDocuments = append(f.adminAuditEntryDocuments, docCopy.(*api.AdminAuditEntryDocument))
+	}
+}
+
 func (f *Fixture) AddPortalDocuments(docs ...*api.PortalDocument) {
 	for _, doc := range docs {
 		docCopy, err := deepCopy(doc)
@@ -207,6 +243,20 @@ func (f *Fixture) Create() error {
 		}
 	}
 
+	for _, i := range f.pendingActionDocuments {
+		_, err := f.pendingActionsDatabase.Create(ctx, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, i := range f.adminAuditEntryDocuments {
+		_, err := f.adminAuditLogDatabase.Create(ctx, i)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, i := range f.gatewayDocuments {
 		_, err := f.gatewayDatabase.Create(ctx, i)
 		if err != nil {
@@ -55,6 +55,7 @@ func NewFakeAsyncOperations() (db database.AsyncOperations, client *cosmosdb.Fak
 func NewFakePortal() (db database.Portal, client *cosmosdb.FakePortalDocumentClient) {
 	uuid := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.PORTAL)
 	client = cosmosdb.NewFakePortalDocumentClient(jsonHandle)
+	injectPortal(client)
 	db = database.NewPortalWithProvidedClient(client, uuid)
 	return db, client
 }
@@ -80,3 +81,32 @@ func NewFakeClusterManager() (db database.ClusterManagerConfigurations, client *
 	db = database.NewClusterManagerConfigurationsWithProvidedClient(client, coll, "", uuid)
 	return db, client
 }
+
+func NewFakeClusterAlerts() (db database.ClusterAlerts, client *cosmosdb.FakeClusterAlertDocumentClient) {
+	client = cosmosdb.NewFakeClusterAlertDocumentClient(jsonHandle)
+	injectClusterAlerts(client)
+	db = database.NewClusterAlertsWithProvidedClient(client)
+	return db, client
+}
+
+func NewFakePendingActions() (db database.PendingActions, client *cosmosdb.FakePendingActionDocumentClient) {
+	uuid := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.PENDINGACTIONS)
+	client = cosmosdb.NewFakePendingActionDocumentClient(jsonHandle)
+	injectPendingActions(client)
+	db = database.NewPendingActionsWithProvidedClient(client, uuid)
+	return db, client
+}
+
+func NewFakeAdminAuditLog() (db database.AdminAuditLog, client *cosmosdb.FakeAdminAuditEntryDocumentClient) {
+	uuid := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.ADMINAUDITLOG)
+	client = cosmosdb.NewFakeAdminAuditEntryDocumentClient(jsonHandle)
+	injectAdminAuditLog(client)
+	db = database.NewAdminAuditLogWithProvidedClient(client, uuid)
+	return db, client
+}
+
+func NewFakeKeyRotation() (db database.KeyRotation, client *cosmosdb.FakeKeyRotationDocumentClient) {
+	client = cosmosdb.NewFakeKeyRotationDocumentClient(jsonHandle)
+	db = database.NewKeyRotationWithProvidedClient(client)
+	return db, client
+}
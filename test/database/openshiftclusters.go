@@ -45,6 +45,9 @@ func getQueuedOpenShiftDocuments(client cosmosdb.OpenShiftClusterDocumentClient)
 		if include && (r.LeaseExpires > 0 && int64(r.LeaseExpires) < time.Now().Unix()) {
 			include = false
 		}
+		if pd := r.OpenShiftCluster.Properties.PendingDeletion; pd != nil && int64(pd.DeleteAfter) >= time.Now().Unix() {
+			include = false
+		}
 		if include {
 			res = append(res, r)
 		}
@@ -52,6 +55,22 @@ func getQueuedOpenShiftDocuments(client cosmosdb.OpenShiftClusterDocumentClient)
 	return
 }
 
+// getQueuedOpenShiftDocumentsByState returns the queued documents in the
+// provisioning state given by the @provisioningState parameter of query
+func getQueuedOpenShiftDocumentsByState(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query) (res []*api.OpenShiftClusterDocument, err error) {
+	docs, err := getQueuedOpenShiftDocuments(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range docs {
+		if string(r.OpenShiftCluster.Properties.ProvisioningState) == query.Parameters[0].Value {
+			res = append(res, r)
+		}
+	}
+	return
+}
+
 func fakeOpenShiftClustersQueueLengthQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
 	results, err := getQueuedOpenShiftDocuments(client)
 	if err != nil {
@@ -68,6 +87,46 @@ func fakeOpenShiftClustersDequeueQuery(client cosmosdb.OpenShiftClusterDocumentC
 	return cosmosdb.NewFakeOpenShiftClusterDocumentIterator(docs, 0)
 }
 
+func fakeOpenShiftClustersQueueLengthByStateQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
+	results, err := getQueuedOpenShiftDocumentsByState(client, query)
+	if err != nil {
+		return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+	}
+	return &fakeOpenShiftClustersQueueLengthIterator{resultCount: len(results)}
+}
+
+// getDeadLetterOpenShiftDocuments returns the documents which the backend
+// has moved to dead letter
+func getDeadLetterOpenShiftDocuments(client cosmosdb.OpenShiftClusterDocumentClient) (res []*api.OpenShiftClusterDocument, err error) {
+	docs, err := fakeOpenShiftClustersGetAllDocuments(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range docs {
+		if r.DeadLetter != nil {
+			res = append(res, r)
+		}
+	}
+	return
+}
+
+func fakeOpenShiftClustersDeadLetterQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
+	results, err := getDeadLetterOpenShiftDocuments(client)
+	if err != nil {
+		return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+	}
+	return cosmosdb.NewFakeOpenShiftClusterDocumentIterator(results, 0)
+}
+
+func fakeOpenShiftClustersDeadLetterCountQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
+	results, err := getDeadLetterOpenShiftDocuments(client)
+	if err != nil {
+		return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+	}
+	return &fakeOpenShiftClustersQueueLengthIterator{resultCount: len(results)}
+}
+
 func fakeOpenshiftClustersMatchQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
 	var results []*api.OpenShiftClusterDocument
 
@@ -154,6 +213,9 @@ func openShiftClusterConflictChecker(one *api.OpenShiftClusterDocument, two *api
 func injectOpenShiftClusters(c *cosmosdb.FakeOpenShiftClusterDocumentClient) {
 	c.SetQueryHandler(database.OpenShiftClustersDequeueQuery, fakeOpenShiftClustersDequeueQuery)
 	c.SetQueryHandler(database.OpenShiftClustersQueueLengthQuery, fakeOpenShiftClustersQueueLengthQuery)
+	c.SetQueryHandler(database.OpenShiftClustersQueueLengthByStateQuery, fakeOpenShiftClustersQueueLengthByStateQuery)
+	c.SetQueryHandler(database.OpenShiftClustersDeadLetterQuery, fakeOpenShiftClustersDeadLetterQuery)
+	c.SetQueryHandler(database.OpenShiftClustersDeadLetterCountQuery, fakeOpenShiftClustersDeadLetterCountQuery)
 	c.SetQueryHandler(database.OpenShiftClustersGetQuery, fakeOpenshiftClustersMatchQuery)
 	c.SetQueryHandler(database.OpenshiftClustersClientIdQuery, fakeOpenshiftClustersMatchQuery)
 	c.SetQueryHandler(database.OpenshiftClustersResourceGroupQuery, fakeOpenshiftClustersMatchQuery)
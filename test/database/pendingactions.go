@@ -0,0 +1,70 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-test/deep"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func fakePendingActionsListByKeyQuery(client cosmosdb.PendingActionDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.PendingActionDocumentRawIterator {
+	input, err := client.ListAll(context.Background(), options)
+	if err != nil {
+		// TODO: should this never happen?
+		panic(err)
+	}
+
+	var results []*api.PendingActionDocument
+	for _, r := range input.PendingActionDocuments {
+		if r.Key == query.Parameters[0].Value {
+			results = append(results, r)
+		}
+	}
+	return cosmosdb.NewFakePendingActionDocumentIterator(results, 0)
+}
+
+func injectPendingActions(c *cosmosdb.FakePendingActionDocumentClient) {
+	c.SetQueryHandler(database.PendingActionsListByKeyQuery, fakePendingActionsListByKeyQuery)
+}
+
+func (f *Checker) AddPendingActionDocuments(docs ...*api.PendingActionDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.pendingActionDocuments = append(f.pendingActionDocuments, docCopy.(*api.PendingActionDocument))
+	}
+}
+
+func (f *Checker) CheckPendingActions(pendingActions *cosmosdb.FakePendingActionDocumentClient) (errs []error) {
+	ctx := context.Background()
+
+	all, err := pendingActions.ListAll(ctx, nil)
+	if err != nil {
+		return []error{err}
+	}
+
+	sort.Slice(all.PendingActionDocuments, func(i, j int) bool { return all.PendingActionDocuments[i].ID < all.PendingActionDocuments[j].ID })
+
+	if len(f.pendingActionDocuments) != 0 && len(all.PendingActionDocuments) == len(f.pendingActionDocuments) {
+		diff := deep.Equal(all.PendingActionDocuments, f.pendingActionDocuments)
+		for _, i := range diff {
+			errs = append(errs, errors.New(i))
+		}
+	} else if len(all.PendingActionDocuments) != 0 || len(f.pendingActionDocuments) != 0 {
+		errs = append(errs, fmt.Errorf("pendingActions length different, %d vs %d", len(all.PendingActionDocuments), len(f.pendingActionDocuments)))
+	}
+
+	return errs
+}
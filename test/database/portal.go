@@ -0,0 +1,54 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func fakePortalListByResourceIDQuery(client cosmosdb.PortalDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.PortalDocumentRawIterator {
+	input, err := client.ListAll(context.Background(), options)
+	if err != nil {
+		// TODO: should this never happen?
+		panic(err)
+	}
+
+	var results []*api.PortalDocument
+	for _, r := range input.PortalDocuments {
+		if r.Portal != nil && r.Portal.ID == query.Parameters[0].Value {
+			results = append(results, r)
+		}
+	}
+	return cosmosdb.NewFakePortalDocumentIterator(results, 0)
+}
+
+func fakePortalListByUsernameQuery(client cosmosdb.PortalDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.PortalDocumentRawIterator {
+	input, err := client.ListAll(context.Background(), options)
+	if err != nil {
+		// TODO: should this never happen?
+		panic(err)
+	}
+
+	var results []*api.PortalDocument
+	for _, r := range input.PortalDocuments {
+		if r.Portal != nil && r.Portal.Username == query.Parameters[0].Value {
+			results = append(results, r)
+		}
+	}
+	return cosmosdb.NewFakePortalDocumentIterator(results, 0)
+}
+
+func injectPortal(c *cosmosdb.FakePortalDocumentClient) {
+	c.SetQueryHandler(database.PortalListByResourceIDQuery, fakePortalListByResourceIDQuery)
+	c.SetQueryHandler(database.PortalListByUsernameQuery, fakePortalListByUsernameQuery)
+
+	c.SetSorter(func(in []*api.PortalDocument) {
+		sort.Slice(in, func(i, j int) bool { return in[i].ID < in[j].ID })
+	})
+}
@@ -19,6 +19,8 @@ const (
 	GATEWAY
 	OPENSHIFT_VERSIONS
 	CLUSTERMANAGER
+	PENDINGACTIONS
+	ADMINAUDITLOG
 )
 
 type gen struct {
@@ -44,6 +44,10 @@ func (t *testLiveConfig) DefaultInstallerPullSpecOverride(ctx context.Context) s
 	return ""
 }
 
+func (t *testLiveConfig) AlertIngestionURL(ctx context.Context) string {
+	return ""
+}
+
 func NewTestLiveConfig(adoptByHive, installViaHive, useCheckAccess bool) liveconfig.Manager {
 	return &testLiveConfig{
 		adoptByHive:    adoptByHive,